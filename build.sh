@@ -59,8 +59,21 @@ echo "  ✓ Dependencies resolved"
 # Step 3: Build the application
 echo ""
 echo "Step 3: Building application..."
+echo "  - Checking policies honor cancelled contexts..."
+if ! check_output=$(go test -count=1 -run TestRegisteredPoliciesHonorCancellation . 2>&1); then
+    echo "$check_output"
+    exit 1
+fi
 echo "  - Compiling Go binary..."
-CGO_ENABLED=0 GOOS=linux go build -a -installsuffix cgo -o policy-engine .
+
+# Runtime plugins (-plugins) need cgo, so only link against libc when
+# they are asked for
+if [ "${POLICY_ENGINE_PLUGINS:-false}" = "true" ]; then
+    echo "    (with runtime plugin support)"
+    CGO_ENABLED=1 GOOS=linux go build -a -o policy-engine .
+else
+    CGO_ENABLED=0 GOOS=linux go build -a -installsuffix cgo -o policy-engine .
+fi
 
 echo "  ✓ Build complete"
 
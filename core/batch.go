@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"sync"
+)
+
+// ExecuteBatch runs a policy over every input using at most concurrency
+// goroutines, a concurrency below 1 running one input at a time. Results
+// and errors are index-aligned with inputs: results[i] and errs[i] belong
+// to inputs[i]. Once ctx is done no further inputs are started; inputs
+// already running see the cancellation through ctx, and inputs never
+// started get ctx.Err() as their error. A panic in the policy is reported
+// as a *PanicError for that input.
+func ExecuteBatch(ctx context.Context, policy Policy, inputs []interface{}, concurrency int) ([]interface{}, []error) {
+	results := make([]interface{}, len(inputs))
+	errs := make([]error, len(inputs))
+	if len(inputs) == 0 {
+		return results, errs
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if concurrency > len(inputs) {
+		concurrency = len(inputs)
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for w := 0; w < concurrency; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i], errs[i] = safeExecute(ctx, policy, inputs[i])
+			}
+		}()
+	}
+
+	next := 0
+dispatch:
+	for ; next < len(inputs); next++ {
+		// Checked first because select picks randomly when a worker is
+		// also ready
+		if ctx.Err() != nil {
+			break
+		}
+		select {
+		case jobs <- next:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i := next; i < len(inputs); i++ {
+		errs[i] = ctx.Err()
+	}
+	return results, errs
+}
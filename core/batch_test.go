@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestExecuteBatch(t *testing.T) {
+	errOdd := errors.New("odd input")
+	policy := &stubPolicy{name: "even", execute: func(_ context.Context, input interface{}) (interface{}, error) {
+		n := input.(int)
+		if n == 7 {
+			panic("seven")
+		}
+		if n%2 == 1 {
+			return nil, errOdd
+		}
+		return n * 10, nil
+	}}
+
+	inputs := make([]interface{}, 20)
+	for i := range inputs {
+		inputs[i] = i
+	}
+
+	for _, concurrency := range []int{-1, 0, 1, 4, 100} {
+		t.Run(fmt.Sprintf("concurrency %d", concurrency), func(t *testing.T) {
+			results, errs := ExecuteBatch(context.Background(), policy, inputs, concurrency)
+			if len(results) != len(inputs) || len(errs) != len(inputs) {
+				t.Fatalf("got %d results and %d errors for %d inputs", len(results), len(errs), len(inputs))
+			}
+			for i := range inputs {
+				var pe *PanicError
+				switch {
+				case i == 7:
+					if !errors.As(errs[i], &pe) || pe.Value != "seven" {
+						t.Errorf("input 7: error = %v, want a *PanicError", errs[i])
+					}
+				case i%2 == 1:
+					if errs[i] != errOdd || results[i] != nil {
+						t.Errorf("input %d: %v, %v; want the odd input error", i, results[i], errs[i])
+					}
+				default:
+					if errs[i] != nil || results[i] != i*10 {
+						t.Errorf("input %d: %v, %v; want %d", i, results[i], errs[i], i*10)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestExecuteBatchEmpty(t *testing.T) {
+	results, errs := ExecuteBatch(context.Background(), &stubPolicy{name: "p"}, nil, 4)
+	if len(results) != 0 || len(errs) != 0 {
+		t.Errorf("ExecuteBatch(nil) = %v, %v; want empty slices", results, errs)
+	}
+}
+
+func TestExecuteBatchConcurrencyLimit(t *testing.T) {
+	var running, peak atomic.Int64
+	policy := &stubPolicy{name: "slow", execute: func(_ context.Context, input interface{}) (interface{}, error) {
+		n := running.Add(1)
+		for {
+			old := peak.Load()
+			if n <= old || peak.CompareAndSwap(old, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		running.Add(-1)
+		return input, nil
+	}}
+
+	inputs := make([]interface{}, 24)
+	ExecuteBatch(context.Background(), policy, inputs, 3)
+	if p := peak.Load(); p > 3 || p < 2 {
+		t.Errorf("peak concurrency = %d, want up to 3 and some overlap", p)
+	}
+}
+
+func TestExecuteBatchCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var started atomic.Int64
+	policy := &stubPolicy{name: "p", execute: func(ctx context.Context, input interface{}) (interface{}, error) {
+		// The third input cancels the batch and waits for it to stop
+		if started.Add(1) == 3 {
+			cancel()
+		}
+		if input.(int) >= 2 {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return input, nil
+	}}
+
+	inputs := make([]interface{}, 10)
+	for i := range inputs {
+		inputs[i] = i
+	}
+	results, errs := ExecuteBatch(ctx, policy, inputs, 1)
+
+	for i := 0; i < 2; i++ {
+		if errs[i] != nil || results[i] != i {
+			t.Errorf("input %d: %v, %v; want it to complete before the cancel", i, results[i], errs[i])
+		}
+	}
+	for i := 2; i < len(inputs); i++ {
+		if !errors.Is(errs[i], context.Canceled) {
+			t.Errorf("input %d: error = %v, want context.Canceled", i, errs[i])
+		}
+	}
+	if n := started.Load(); n != 3 {
+		t.Errorf("started %d inputs, want no more after the cancel", n)
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"time"
+)
+
+// BenchResult summarizes repeated executions of a single policy
+type BenchResult struct {
+	Policy      string
+	Iterations  int
+	Errors      int
+	Elapsed     time.Duration
+	OpsPerSec   float64
+	P50         time.Duration
+	P95         time.Duration
+	P99         time.Duration
+	AllocsPerOp uint64
+	BytesPerOp  uint64
+}
+
+// Bench executes a policy repeatedly against input until either the
+// iteration count or the duration is reached, whichever comes first. A
+// zero limit is ignored; at least one limit must be set.
+func Bench(ctx context.Context, policy Policy, input interface{}, iterations int, duration time.Duration) (*BenchResult, error) {
+	if iterations <= 0 && duration <= 0 {
+		return nil, fmt.Errorf("either iterations or duration must be positive")
+	}
+
+	var latencies []time.Duration
+	if iterations > 0 {
+		latencies = make([]time.Duration, 0, iterations)
+	}
+	errors := 0
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	start := time.Now()
+	deadline := start.Add(duration)
+	for {
+		if iterations > 0 && len(latencies) >= iterations {
+			break
+		}
+		if duration > 0 && !time.Now().Before(deadline) {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		opStart := time.Now()
+		if _, err := safeExecute(ctx, policy, input); err != nil {
+			errors++
+		}
+		latencies = append(latencies, time.Since(opStart))
+	}
+	elapsed := time.Since(start)
+
+	runtime.ReadMemStats(&after)
+
+	n := len(latencies)
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	result := &BenchResult{
+		Policy:     policy.Name(),
+		Iterations: n,
+		Errors:     errors,
+		Elapsed:    elapsed,
+	}
+	if n > 0 {
+		result.OpsPerSec = float64(n) / elapsed.Seconds()
+		result.P50 = percentile(latencies, 0.50)
+		result.P95 = percentile(latencies, 0.95)
+		result.P99 = percentile(latencies, 0.99)
+		result.AllocsPerOp = (after.Mallocs - before.Mallocs) / uint64(n)
+		result.BytesPerOp = (after.TotalAlloc - before.TotalAlloc) / uint64(n)
+	}
+
+	return result, nil
+}
+
+// percentile returns the nearest-rank percentile of sorted latencies
+func percentile(sorted []time.Duration, q float64) time.Duration {
+	idx := int(q*float64(len(sorted))+0.5) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
+
+// WriteSummary prints the result as "key: value" lines
+func (r *BenchResult) WriteSummary(w io.Writer) {
+	fmt.Fprintf(w, "policy: %s\n", r.Policy)
+	fmt.Fprintf(w, "iterations: %d\n", r.Iterations)
+	fmt.Fprintf(w, "errors: %d\n", r.Errors)
+	fmt.Fprintf(w, "elapsed: %s\n", r.Elapsed)
+	fmt.Fprintf(w, "ops/sec: %.2f\n", r.OpsPerSec)
+	fmt.Fprintf(w, "p50: %s\n", r.P50)
+	fmt.Fprintf(w, "p95: %s\n", r.P95)
+	fmt.Fprintf(w, "p99: %s\n", r.P99)
+	fmt.Fprintf(w, "allocs/op: %d\n", r.AllocsPerOp)
+	fmt.Fprintf(w, "bytes/op: %d\n", r.BytesPerOp)
+}
+
+// runBench implements the "bench <name>" command
+func runBench(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	fs.SetOutput(out)
+	iterations := fs.Int("n", 1000, "number of iterations (0 for no limit)")
+	duration := fs.Duration("duration", 0, "maximum run time, e.g. 10s (0 for no limit)")
+	setup := addSetupFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(out, "Usage: policy-engine bench [flags] <policy-name>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("bench requires exactly one policy name")
+	}
+	if err := setup.apply(registry); err != nil {
+		return err
+	}
+
+	name := fs.Arg(0)
+	policy, err := registry.GetOrError(name)
+	if err != nil {
+		return err
+	}
+
+	result, err := Bench(context.Background(), policy, sampleInput(), *iterations, *duration)
+	if err != nil {
+		return err
+	}
+
+	result.WriteSummary(out)
+	return nil
+}
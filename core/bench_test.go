@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// parseSummary reads the "key: value" lines written by WriteSummary
+func parseSummary(t *testing.T, out string) map[string]string {
+	t.Helper()
+	summary := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ": ")
+		if !ok {
+			t.Fatalf("malformed summary line %q", scanner.Text())
+		}
+		summary[key] = value
+	}
+	return summary
+}
+
+func TestRunBench(t *testing.T) {
+	r := newTestRegistry()
+	calls := 0
+	policy := &stubPolicy{name: "counted", execute: func(_ context.Context, input interface{}) (interface{}, error) {
+		calls++
+		if calls%5 == 0 {
+			return nil, errors.New("every fifth call fails")
+		}
+		return input, nil
+	}}
+	if err := r.Register(policy); err != nil {
+		t.Fatal(err)
+	}
+	useRegistry(t, r)
+
+	var out bytes.Buffer
+	if err := runBench([]string{"-n", "20", "counted"}, &out); err != nil {
+		t.Fatalf("runBench: %v", err)
+	}
+	summary := parseSummary(t, out.String())
+
+	for _, key := range []string{"policy", "iterations", "errors", "elapsed", "ops/sec", "p50", "p95", "p99", "allocs/op", "bytes/op"} {
+		if _, ok := summary[key]; !ok {
+			t.Errorf("summary is missing %q:\n%s", key, out.String())
+		}
+	}
+	if summary["policy"] != "counted" || summary["iterations"] != "20" || summary["errors"] != "4" {
+		t.Errorf("policy, iterations, errors = %s, %s, %s; want counted, 20, 4", summary["policy"], summary["iterations"], summary["errors"])
+	}
+	if calls != 20 {
+		t.Errorf("policy ran %d times, want 20", calls)
+	}
+	if ops, err := strconv.ParseFloat(summary["ops/sec"], 64); err != nil || ops <= 0 {
+		t.Errorf("ops/sec = %q, want a positive number", summary["ops/sec"])
+	}
+
+	var latencies []time.Duration
+	for _, key := range []string{"p50", "p95", "p99"} {
+		d, err := time.ParseDuration(summary[key])
+		if err != nil {
+			t.Fatalf("%s = %q: %v", key, summary[key], err)
+		}
+		latencies = append(latencies, d)
+	}
+	if latencies[0] > latencies[1] || latencies[1] > latencies[2] {
+		t.Errorf("percentiles out of order: %v", latencies)
+	}
+}
+
+func TestRunBenchErrors(t *testing.T) {
+	useRegistry(t, newTestRegistry())
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"no policy name", []string{"-n", "5"}},
+		{"unknown policy", []string{"missing"}},
+		{"no limit", []string{"-n", "0", "missing"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := runBench(tt.args, &bytes.Buffer{}); err == nil {
+				t.Error("runBench succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestBenchDuration(t *testing.T) {
+	result, err := Bench(context.Background(), &stubPolicy{name: "p"}, nil, 0, 20*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Bench: %v", err)
+	}
+	if result.Iterations == 0 || result.Elapsed < 20*time.Millisecond {
+		t.Errorf("iterations = %d, elapsed = %s; want some iterations over at least 20ms", result.Iterations, result.Elapsed)
+	}
+
+	if _, err := Bench(context.Background(), &stubPolicy{name: "p"}, nil, 0, 0); err == nil {
+		t.Error("Bench without a limit succeeded, want error")
+	}
+}
+
+func TestBenchCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Bench(ctx, &stubPolicy{name: "p"}, nil, 10, 0); !errors.Is(err, context.Canceled) {
+		t.Errorf("Bench error = %v, want context.Canceled", err)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := make([]time.Duration, 100)
+	for i := range sorted {
+		sorted[i] = time.Duration(i+1) * time.Millisecond
+	}
+
+	tests := []struct {
+		q    float64
+		want time.Duration
+	}{
+		{0.50, 50 * time.Millisecond},
+		{0.95, 95 * time.Millisecond},
+		{0.99, 99 * time.Millisecond},
+		{0, time.Millisecond},
+		{1, 100 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		if got := percentile(sorted, tt.q); got != tt.want {
+			t.Errorf("percentile(%v) = %s, want %s", tt.q, got, tt.want)
+		}
+	}
+	if got := percentile([]time.Duration{time.Second}, 0.99); got != time.Second {
+		t.Errorf("percentile of one sample = %s, want 1s", got)
+	}
+}
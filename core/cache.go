@@ -0,0 +1,141 @@
+package main
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// CachingPolicy is a decorator for pure policies that returns the cached
+// result of an earlier execution when the input is the same. Inputs are
+// keyed by the SHA-256 hash of their JSON encoding, which sorts map keys,
+// so equal maps share an entry regardless of construction order. Only
+// successful results are cached, and cached results are shared between
+// callers, so they must not be modified. Inputs that cannot be encoded as
+// JSON are executed without caching. It is safe for concurrent use;
+// concurrent misses on the same input each run the wrapped policy.
+type CachingPolicy struct {
+	Inner Policy
+
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[[sha256.Size]byte]*list.Element
+	// order holds *cacheEntry values, most recently used first
+	order *list.List
+}
+
+// cacheEntry is a cached result and when it stops being served
+type cacheEntry struct {
+	key     [sha256.Size]byte
+	result  interface{}
+	expires time.Time
+}
+
+// NewCachingPolicy wraps a policy with a result cache. Entries expire ttl
+// after they are stored; a ttl of zero or less keeps them until evicted.
+// Once maxEntries results are cached the least recently used one is
+// evicted; a maxEntries of zero or less means no limit.
+func NewCachingPolicy(inner Policy, ttl time.Duration, maxEntries int) *CachingPolicy {
+	return &CachingPolicy{
+		Inner:      inner,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[[sha256.Size]byte]*list.Element),
+		order:      list.New(),
+	}
+}
+
+// Name returns the wrapped policy's name so the wrapper can replace it
+func (c *CachingPolicy) Name() string {
+	return c.Inner.Name()
+}
+
+// Validate validates the wrapped policy
+func (c *CachingPolicy) Validate() error {
+	return c.Inner.Validate()
+}
+
+// Unwrap returns the wrapped policy, whose phase, priority, metadata and
+// configuration the wrapper keeps
+func (c *CachingPolicy) Unwrap() Policy {
+	return c.Inner
+}
+
+// Execute returns the cached result for input if there is one that has not
+// expired, and otherwise runs the wrapped policy and caches its result
+func (c *CachingPolicy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return c.Inner.Execute(ctx, input)
+	}
+	key := sha256.Sum256(data)
+
+	if result, ok := c.lookup(key); ok {
+		return result, nil
+	}
+
+	result, err := c.Inner.Execute(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	c.store(key, result)
+	return result, nil
+}
+
+// Purge removes every cached result
+func (c *CachingPolicy) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[[sha256.Size]byte]*list.Element)
+	c.order.Init()
+}
+
+// lookup returns the unexpired result cached under key, dropping it if it
+// has expired
+func (c *CachingPolicy) lookup(key [sha256.Size]byte) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*cacheEntry)
+	if !entry.expires.IsZero() && !time.Now().Before(entry.expires) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return entry.result, true
+}
+
+// store caches result under key, evicting the least recently used entries
+// beyond maxEntries
+func (c *CachingPolicy) store(key [sha256.Size]byte, result interface{}) {
+	entry := &cacheEntry{key: key, result: result}
+	if c.ttl > 0 {
+		entry.expires = time.Now().Add(c.ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.order.PushFront(entry)
+
+	for c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// counting returns a policy that echoes its input and counts its calls
+func counting(name string) (*stubPolicy, *atomic.Int64) {
+	var calls atomic.Int64
+	return &stubPolicy{name: name, execute: func(_ context.Context, input interface{}) (interface{}, error) {
+		calls.Add(1)
+		return input, nil
+	}}, &calls
+}
+
+func TestCachingPolicyHits(t *testing.T) {
+	inner, calls := counting("pure")
+	c := NewCachingPolicy(inner, 0, 0)
+	ctx := context.Background()
+
+	first := map[string]interface{}{"a": 1, "b": []interface{}{"x"}}
+	if _, err := c.Execute(ctx, first); err != nil {
+		t.Fatal(err)
+	}
+	// An equal map built in another order is the same input
+	same := map[string]interface{}{"b": []interface{}{"x"}}
+	same["a"] = 1
+	result, err := c.Execute(ctx, same)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("calls = %d, want 1 for two equal inputs", calls.Load())
+	}
+	if result.(map[string]interface{})["a"] != 1 {
+		t.Errorf("cached result = %v, want the first result", result)
+	}
+
+	c.Execute(ctx, map[string]interface{}{"a": 2})
+	if calls.Load() != 2 {
+		t.Errorf("calls = %d, want 2 after a different input", calls.Load())
+	}
+
+	c.Purge()
+	c.Execute(ctx, first)
+	if calls.Load() != 3 {
+		t.Errorf("calls = %d, want 3 after Purge", calls.Load())
+	}
+}
+
+func TestCachingPolicyDoesNotCacheErrors(t *testing.T) {
+	calls := 0
+	inner := &stubPolicy{name: "flaky", execute: func(_ context.Context, input interface{}) (interface{}, error) {
+		calls++
+		if calls == 1 {
+			return nil, errors.New("transient")
+		}
+		return "ok", nil
+	}}
+	c := NewCachingPolicy(inner, 0, 0)
+
+	if _, err := c.Execute(context.Background(), "in"); err == nil {
+		t.Fatal("first call succeeded, want the transient error")
+	}
+	for i := 0; i < 2; i++ {
+		if result, err := c.Execute(context.Background(), "in"); result != "ok" || err != nil {
+			t.Errorf("Execute = %v, %v; want ok", result, err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2: the error retried, the success cached", calls)
+	}
+}
+
+func TestCachingPolicyTTL(t *testing.T) {
+	inner, calls := counting("pure")
+	c := NewCachingPolicy(inner, 20*time.Millisecond, 0)
+
+	c.Execute(context.Background(), "in")
+	c.Execute(context.Background(), "in")
+	if calls.Load() != 1 {
+		t.Fatalf("calls = %d, want 1 within the TTL", calls.Load())
+	}
+	time.Sleep(40 * time.Millisecond)
+	c.Execute(context.Background(), "in")
+	if calls.Load() != 2 {
+		t.Errorf("calls = %d, want 2 after the TTL", calls.Load())
+	}
+}
+
+func TestCachingPolicyEviction(t *testing.T) {
+	inner, calls := counting("pure")
+	c := NewCachingPolicy(inner, 0, 2)
+	ctx := context.Background()
+
+	c.Execute(ctx, "a")
+	c.Execute(ctx, "b")
+	c.Execute(ctx, "a") // a is now the most recently used
+	c.Execute(ctx, "c") // evicts b
+	if calls.Load() != 3 || c.order.Len() != 2 {
+		t.Fatalf("calls = %d, entries = %d; want 3, 2", calls.Load(), c.order.Len())
+	}
+
+	c.Execute(ctx, "a")
+	if calls.Load() != 3 {
+		t.Errorf("a was evicted, want it kept as recently used")
+	}
+	c.Execute(ctx, "b")
+	if calls.Load() != 4 {
+		t.Errorf("b was still cached, want it evicted")
+	}
+}
+
+func TestCachingPolicyUnencodableInput(t *testing.T) {
+	inner, calls := counting("pure")
+	c := NewCachingPolicy(inner, 0, 0)
+	input := map[string]interface{}{"f": func() {}}
+	c.Execute(context.Background(), input)
+	c.Execute(context.Background(), input)
+	if calls.Load() != 2 || c.order.Len() != 0 {
+		t.Errorf("calls = %d, entries = %d; want every call to run uncached", calls.Load(), c.order.Len())
+	}
+}
+
+func TestCachingPolicyConcurrent(t *testing.T) {
+	inner, _ := counting("pure")
+	c := NewCachingPolicy(inner, time.Minute, 8)
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				input := (w + i) % 16
+				result, err := c.Execute(context.Background(), input)
+				if err != nil || result != input {
+					t.Errorf("Execute(%d) = %v, %v", input, result, err)
+					return
+				}
+				if i%50 == 0 {
+					c.Purge()
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	if c.order.Len() > 8 || len(c.entries) != c.order.Len() {
+		t.Errorf("entries = %d, order = %d; want at most 8 and consistent", len(c.entries), c.order.Len())
+	}
+}
+
+func TestCachingPolicyKeepsIdentity(t *testing.T) {
+	inner := &taggedPolicy{stubPolicy: stubPolicy{name: "inner"}, priority: 3}
+	c := NewCachingPolicy(inner, 0, 0)
+	if c.Name() != "inner" || PhaseOf(c) != PhasePre || PriorityOf(c) != 3 {
+		t.Errorf("name, phase, priority = %s, %s, %d; want the inner policy's", c.Name(), PhaseOf(c), PriorityOf(c))
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+// TestRegisteredPoliciesHonorCancellation covers every policy compiled in
+// through imports.go. The checked-in imports.go registers none, so the
+// build runs this test after generating it for the mounted policies.
+func TestRegisteredPoliciesHonorCancellation(t *testing.T) {
+	names := registry.List()
+	if len(names) == 0 {
+		t.Skip("no policies registered; generate imports.go to cover them")
+	}
+
+	for _, name := range names {
+		policy, ok := registry.Get(name)
+		if !ok {
+			continue
+		}
+		t.Run(name, func(t *testing.T) {
+			assertHonorsCancellation(t, policy, sampleInput())
+		})
+	}
+}
+
+func TestAssertHonorsCancellation(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy Policy
+	}{
+		{
+			name: "checks context first",
+			policy: &stubPolicy{name: "checks", execute: func(ctx context.Context, input interface{}) (interface{}, error) {
+				if err := ctx.Err(); err != nil {
+					return nil, err
+				}
+				return input, nil
+			}},
+		},
+		{
+			name: "waits on context",
+			policy: &stubPolicy{name: "waits", execute: func(ctx context.Context, input interface{}) (interface{}, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertHonorsCancellation(t, tt.policy, sampleInput())
+		})
+	}
+}
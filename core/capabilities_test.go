@@ -0,0 +1,53 @@
+package main
+
+import "testing"
+
+// describedPolicy implements Configurable, Described, Versioned and
+// EngineVersioned, but not Phased, Prioritized or Tagged
+type describedPolicy struct {
+	stubPolicy
+}
+
+func (p *describedPolicy) Configure(map[string]interface{}) error { return nil }
+func (p *describedPolicy) Description() string                    { return "described" }
+func (p *describedPolicy) Version() string                        { return "1.2.3" }
+func (p *describedPolicy) RequiredEngineVersion() string          { return ">=1.0.0" }
+
+func TestCapabilities(t *testing.T) {
+	r := newTestRegistry()
+	if err := r.Register(&stubPolicy{name: "plain"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register(&describedPolicy{stubPolicy{name: "described"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		want PolicyCapabilities
+	}{
+		{"plain", PolicyCapabilities{}},
+		{"described", PolicyCapabilities{
+			Configurable:    true,
+			Described:       true,
+			Versioned:       true,
+			EngineVersioned: true,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := r.Capabilities(tt.name)
+			if !ok {
+				t.Fatalf("Capabilities(%q) not found", tt.name)
+			}
+			if got != tt.want {
+				t.Errorf("Capabilities(%q) = %+v, want %+v", tt.name, got, tt.want)
+			}
+		})
+	}
+
+	if _, ok := r.Capabilities("missing"); ok {
+		t.Error("Capabilities of an unregistered policy reported found")
+	}
+}
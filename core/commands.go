@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// command implements a subcommand; args excludes the subcommand name
+type command func(args []string, stdin io.Reader, out io.Writer) error
+
+// commands maps subcommand names to their implementations. Running the
+// binary without one of them runs the demo loop instead.
+var commands = map[string]command{
+	"bench": func(args []string, _ io.Reader, out io.Writer) error {
+		return runBench(args, out)
+	},
+	"list": func(args []string, _ io.Reader, out io.Writer) error {
+		return runList(args, out)
+	},
+	"run": runPolicy,
+	"serve": func(args []string, _ io.Reader, out io.Writer) error {
+		return runServe(args, out)
+	},
+	"validate": func(args []string, _ io.Reader, out io.Writer) error {
+		return runValidate(args, out)
+	},
+}
+
+// setupFlags are the flags that prepare the registry, accepted by every
+// subcommand and by the demo loop
+type setupFlags struct {
+	config  *string
+	plugins *string
+}
+
+// addSetupFlags defines -config and -plugins on fs
+func addSetupFlags(fs *flag.FlagSet) *setupFlags {
+	return &setupFlags{
+		config:  fs.String("config", "", "YAML file enabling, disabling and configuring policies"),
+		plugins: fs.String("plugins", "", "directory of .so policy plugins to load at startup (requires an engine built with cgo)"),
+	}
+}
+
+// apply loads the plugins and then applies the config to r. Plugins that
+// fail to load are logged and skipped; an engine that cannot load plugins
+// at all, or a config that cannot be loaded or applied, is an error.
+func (s *setupFlags) apply(r *PolicyRegistry) error {
+	if *s.plugins != "" {
+		err := LoadPlugins(*s.plugins, r)
+		if errors.Is(err, ErrPluginsUnsupported) {
+			return fmt.Errorf("cannot load plugins: %w", err)
+		}
+		if err != nil {
+			r.Logger().Error("some plugins failed to load", "error", err.Error())
+		}
+	}
+
+	if *s.config != "" {
+		config, err := LoadConfig(*s.config)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		if err := ApplyConfig(r, config); err != nil {
+			return fmt.Errorf("failed to apply config: %w", err)
+		}
+		r.Logger().Info("applied config", "path", *s.config)
+	}
+	return nil
+}
+
+// dispatch runs the subcommand named by args[0]. It reports false, without
+// running anything, when args does not start with a known subcommand.
+func dispatch(args []string, stdin io.Reader, out io.Writer) (bool, error) {
+	if len(args) == 0 {
+		return false, nil
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return false, nil
+	}
+	return true, cmd(args[1:], stdin, out)
+}
+
+// runList implements the "list" command
+func runList(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	fs.SetOutput(out)
+	setup := addSetupFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(out, "Usage: policy-engine list [flags]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return fmt.Errorf("list takes no arguments")
+	}
+	if err := setup.apply(registry); err != nil {
+		return err
+	}
+
+	// One row per policy, in execution order
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tPHASE\tPRIORITY\tVERSION\tDESCRIPTION")
+	for _, name := range registry.ListByPhase() {
+		policy, ok := registry.Get(name)
+		if !ok {
+			// Unregistered since the list was taken
+			continue
+		}
+		metadata := MetadataOf(policy)
+		priority, _ := registry.Priority(name)
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n", name, metadata.Phase, priority, metadata.Version, metadata.Description)
+	}
+	return w.Flush()
+}
+
+// runPolicy implements the "run <name>" command
+func runPolicy(args []string, stdin io.Reader, out io.Writer) error {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	fs.SetOutput(out)
+	inputPath := fs.String("input", "-", "JSON file holding the input object, or - to read it from stdin")
+	timeout := fs.Duration("timeout", 0, "maximum run time, e.g. 5s (0 for no limit)")
+	setup := addSetupFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(out, "Usage: policy-engine run [flags] <policy-name>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("run requires exactly one policy name")
+	}
+	if err := setup.apply(registry); err != nil {
+		return err
+	}
+
+	policy, err := registry.GetOrError(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	input, err := loadInput(*inputPath, stdin)
+	if err != nil {
+		return err
+	}
+
+	execution := ExecutePolicy(context.Background(), policy, input, ExecuteOptions{
+		Timeout: *timeout,
+		Logger:  registry.Logger(),
+		Metrics: registry.metrics,
+	})
+	if execution.Err != nil {
+		return execution.Err
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(execution.Result)
+}
+
+// runValidate implements the "validate" command
+func runValidate(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	fs.SetOutput(out)
+	setup := addSetupFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(out, "Usage: policy-engine validate [flags]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return fmt.Errorf("validate takes no arguments")
+	}
+	if err := setup.apply(registry); err != nil {
+		return err
+	}
+
+	if err := registry.ValidateAll(); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "%d policies valid\n", len(registry.List()))
+	return nil
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateCommand(t *testing.T) {
+	r := newTestRegistry()
+	useRegistry(t, r)
+	invalid := &stubPolicy{name: "invalid"}
+	for _, p := range []Policy{&stubPolicy{name: "valid"}, invalid} {
+		if err := r.Register(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out bytes.Buffer
+	if ok, err := dispatch([]string{"validate"}, nil, &out); !ok || err != nil {
+		t.Fatalf("dispatch(validate) = %v, %v", ok, err)
+	}
+	if out.String() != "2 policies valid\n" {
+		t.Errorf("output = %q", out.String())
+	}
+
+	invalid.validateErr = errors.New("threshold must be positive")
+	out.Reset()
+	_, err := dispatch([]string{"validate"}, nil, &out)
+	if err == nil || !strings.Contains(err.Error(), "policy invalid: threshold must be positive") {
+		t.Errorf("error = %v, want the invalid policy's error", err)
+	}
+
+	if _, err := dispatch([]string{"validate", "extra"}, nil, &out); err == nil {
+		t.Error("validate with an argument succeeded, want error")
+	}
+}
+
+func TestDispatchUnknown(t *testing.T) {
+	for _, args := range [][]string{nil, {"-input", "x.json"}, {"deploy"}} {
+		if handled, err := dispatch(args, nil, &bytes.Buffer{}); handled || err != nil {
+			t.Errorf("dispatch(%q) = %v, %v; want not handled", args, handled, err)
+		}
+	}
+}
+
+func TestLogOutput(t *testing.T) {
+	tests := []struct {
+		args []string
+		want *os.File
+	}{
+		{nil, os.Stdout},
+		{[]string{"-input", "-"}, os.Stdout},
+		{[]string{"run", "p"}, os.Stderr},
+		{[]string{"list"}, os.Stderr},
+		{[]string{"unknown"}, os.Stderr},
+	}
+	for _, tt := range tests {
+		if got := logOutput(tt.args); got != tt.want {
+			t.Errorf("logOutput(%q) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
+
+// commandRegistry returns a registry for command tests whose logs go to
+// the returned buffer, away from the command's output
+func commandRegistry(t *testing.T, policies ...Policy) (*PolicyRegistry, *bytes.Buffer) {
+	t.Helper()
+	var logs bytes.Buffer
+	r := NewPolicyRegistry()
+	r.SetLogger(NewJSONLogger(&logs, false))
+	for _, p := range policies {
+		if err := r.Register(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	useRegistry(t, r)
+	return r, &logs
+}
+
+func TestListCommand(t *testing.T) {
+	_, logs := commandRegistry(t,
+		&taggedPolicy{stubPolicy: stubPolicy{name: "tagged"}, priority: 5},
+		&describedPolicy{stubPolicy{name: "described"}},
+		&stubPolicy{name: "plain"},
+	)
+	configPath := writeConfig(t, "policies:\n  plain:\n    priority: -1\n    enabled: false\n")
+
+	var out bytes.Buffer
+	if _, err := dispatch([]string{"list", "-config", configPath}, nil, &out); err != nil {
+		t.Fatal(err)
+	}
+	// The config's priority override reorders the list, and the disabled
+	// policy is still listed
+	want := "NAME       PHASE  PRIORITY  VERSION  DESCRIPTION\n" +
+		"tagged     pre    5         2.0.0    tags things\n" +
+		"plain      main   -1                 \n" +
+		"described  main   0         1.2.3    described\n"
+	if out.String() != want {
+		t.Errorf("output:\n%s\nwant:\n%s", out.String(), want)
+	}
+	if !strings.Contains(logs.String(), `"msg":"applied config"`) {
+		t.Errorf("logs = %s, want the applied config entry", logs.String())
+	}
+
+	if _, err := dispatch([]string{"list", "extra"}, nil, &out); err == nil {
+		t.Error("list with an argument succeeded, want error")
+	}
+}
+
+func TestRunCommand(t *testing.T) {
+	configured := &configurablePolicy{stubPolicy: stubPolicy{name: "greet"}}
+	configured.execute = func(_ context.Context, input interface{}) (interface{}, error) {
+		return map[string]interface{}{"greeting": configured.config["greeting"], "input": input}, nil
+	}
+	_, logs := commandRegistry(t, configured, failing("broken", errors.New("upstream down")))
+	configPath := writeConfig(t, "policies:\n  greet:\n    config:\n      greeting: hello\n")
+
+	var out bytes.Buffer
+	stdin := strings.NewReader(`{"user": "ada"}`)
+	if _, err := dispatch([]string{"run", "-config", configPath, "greet"}, stdin, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	// stdout holds only the result; the logs went elsewhere
+	var result map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("output %q is not a single JSON value: %v", out.String(), err)
+	}
+	want := map[string]interface{}{"greeting": "hello", "input": map[string]interface{}{"user": "ada"}}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("result = %v, want %v", result, want)
+	}
+	if !strings.Contains(logs.String(), `"msg":"executed"`) {
+		t.Errorf("logs = %s, want an executed entry", logs.String())
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"policy error", []string{"run", "broken"}, "upstream down"},
+		{"unknown policy", []string{"run", "missing"}, "policy not found: missing"},
+		{"no policy name", []string{"run"}, "run requires exactly one policy name"},
+		{"missing config", []string{"run", "-config", filepath.Join(t.TempDir(), "none.yaml"), "greet"}, "failed to load config"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := dispatch(tt.args, strings.NewReader("{}"), &bytes.Buffer{})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBenchCommand(t *testing.T) {
+	commandRegistry(t, &stubPolicy{name: "echo"})
+
+	var out bytes.Buffer
+	if _, err := dispatch([]string{"bench", "-n", "10", "echo"}, nil, &out); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"policy: echo\n", "iterations: 10\n", "errors: 0\n"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output is missing %q:\n%s", want, out.String())
+		}
+	}
+
+	configPath := writeConfig(t, "policies:\n  missing:\n    enabled: true\n")
+	if _, err := dispatch([]string{"bench", "-config", configPath, "echo"}, nil, &out); err == nil || !strings.Contains(err.Error(), "failed to apply config") {
+		t.Errorf("error = %v, want the config to be applied and fail", err)
+	}
+}
+
+func TestServeCommandAppliesConfig(t *testing.T) {
+	commandRegistry(t, &stubPolicy{name: "echo"})
+
+	// An invalid config stops serve before it listens
+	configPath := writeConfig(t, "policies:\n  echo:\n    enable: true\n")
+	_, err := dispatch([]string{"serve", "-addr", "127.0.0.1:0", "-config", configPath}, nil, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "failed to load config") {
+		t.Errorf("error = %v, want the config to be loaded and fail", err)
+	}
+}
+
+func TestCommandsLoadPlugins(t *testing.T) {
+	commandRegistry(t, &stubPolicy{name: "echo"})
+
+	var out bytes.Buffer
+	_, err := dispatch([]string{"validate", "-plugins", t.TempDir()}, nil, &out)
+	if pluginsSupported {
+		if err != nil || out.String() != "1 policies valid\n" {
+			t.Errorf("validate with an empty plugin directory = %q, %v", out.String(), err)
+		}
+		return
+	}
+	if !errors.Is(err, ErrPluginsUnsupported) {
+		t.Errorf("error = %v, want ErrPluginsUnsupported", err)
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+// ResultTree is the nested result of a composite policy. Leaf nodes hold a
+// single policy's result or error; composite nodes hold their children in
+// execution order.
+type ResultTree struct {
+	Policy   string        `json:"policy"`
+	Result   interface{}   `json:"result,omitempty"`
+	Error    string        `json:"error,omitempty"`
+	Children []*ResultTree `json:"children,omitempty"`
+}
+
+// Failed reports whether this node or any node below it recorded an error
+func (t *ResultTree) Failed() bool {
+	if t.Error != "" {
+		return true
+	}
+	for _, child := range t.Children {
+		if child.Failed() {
+			return true
+		}
+	}
+	return false
+}
+
+// CompositePolicy groups several policies under one name. Every child runs
+// against the same input and the results are returned as a ResultTree
+// that mirrors the composition, including nested composites.
+type CompositePolicy struct {
+	name     string
+	children []Policy
+}
+
+// NewCompositePolicy creates a composite that runs children in order
+func NewCompositePolicy(name string, children ...Policy) *CompositePolicy {
+	return &CompositePolicy{
+		name:     name,
+		children: children,
+	}
+}
+
+// Name returns the composite's name
+func (c *CompositePolicy) Name() string {
+	return c.name
+}
+
+// Children returns the child policies in execution order
+func (c *CompositePolicy) Children() []Policy {
+	return append([]Policy(nil), c.children...)
+}
+
+// Validate checks the composite and every child
+func (c *CompositePolicy) Validate() error {
+	if c.name == "" {
+		return fmt.Errorf("composite policy name must not be empty")
+	}
+	if len(c.children) == 0 {
+		return fmt.Errorf("composite policy %s has no children", c.name)
+	}
+
+	seen := make(map[string]bool, len(c.children))
+	for _, child := range c.children {
+		if seen[child.Name()] {
+			return fmt.Errorf("composite policy %s: duplicate child %s", c.name, child.Name())
+		}
+		seen[child.Name()] = true
+
+		if err := child.Validate(); err != nil {
+			return fmt.Errorf("composite policy %s: child %s: %w", c.name, child.Name(), err)
+		}
+	}
+	return nil
+}
+
+// Execute runs every child and returns a *ResultTree. A failing child is
+// recorded in its node and does not stop its siblings; use Failed to check
+// the outcome. Only cancellation of ctx is returned as an error.
+func (c *CompositePolicy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	return c.execute(ctx, input)
+}
+
+func (c *CompositePolicy) execute(ctx context.Context, input interface{}) (*ResultTree, error) {
+	tree := &ResultTree{
+		Policy:   c.name,
+		Children: make([]*ResultTree, 0, len(c.children)),
+	}
+
+	for _, child := range c.children {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if composite, ok := child.(*CompositePolicy); ok {
+			subtree, err := composite.execute(ctx, input)
+			if err != nil {
+				return nil, err
+			}
+			tree.Children = append(tree.Children, subtree)
+			continue
+		}
+
+		node := &ResultTree{Policy: child.Name()}
+		result, err := safeExecute(ctx, child, input)
+		if err != nil {
+			node.Error = err.Error()
+		} else {
+			node.Result = result
+		}
+		tree.Children = append(tree.Children, node)
+	}
+
+	return tree, nil
+}
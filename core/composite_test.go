@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// constant returns a stubPolicy whose result is always value
+func constant(name string, value interface{}) *stubPolicy {
+	return &stubPolicy{name: name, execute: func(context.Context, interface{}) (interface{}, error) {
+		return value, nil
+	}}
+}
+
+func TestCompositePolicyTwoLevels(t *testing.T) {
+	inner := NewCompositePolicy("checks",
+		constant("schema", "ok"),
+		failing("quota", errors.New("over quota")),
+	)
+	outer := NewCompositePolicy("gate",
+		constant("auth", map[string]interface{}{"user": "ada"}),
+		inner,
+		&stubPolicy{name: "echo"},
+	)
+	if err := outer.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := outer.Execute(context.Background(), "input")
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	tree := got.(*ResultTree)
+	if !tree.Failed() {
+		t.Error("Failed() = false, want the quota failure to propagate")
+	}
+	if inner := tree.Children[1]; !inner.Failed() || inner.Children[0].Failed() {
+		t.Error("Failed() does not reflect the nodes that failed")
+	}
+
+	encoded, err := json.Marshal(tree)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"policy":"gate","children":[` +
+		`{"policy":"auth","result":{"user":"ada"}},` +
+		`{"policy":"checks","children":[{"policy":"schema","result":"ok"},{"policy":"quota","error":"over quota"}]},` +
+		`{"policy":"echo","result":"input"}]}`
+	if string(encoded) != want {
+		t.Errorf("tree =\n%s\nwant\n%s", encoded, want)
+	}
+}
+
+func TestCompositePolicyRecoversPanics(t *testing.T) {
+	panics := &stubPolicy{name: "panics", execute: func(context.Context, interface{}) (interface{}, error) {
+		panic("boom")
+	}}
+	got, err := NewCompositePolicy("group", panics, constant("after", 1)).Execute(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree := got.(*ResultTree)
+	if tree.Children[0].Error != "policy panics panicked: boom" || tree.Children[1].Result != 1 {
+		t.Errorf("children = %+v, %+v; want the panic recorded and the sibling run", tree.Children[0], tree.Children[1])
+	}
+}
+
+func TestCompositePolicyCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	nested := NewCompositePolicy("outer", NewCompositePolicy("inner", &stubPolicy{name: "leaf"}))
+	if _, err := nested.Execute(ctx, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestCompositePolicyValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		composite *CompositePolicy
+		wantErr   bool
+	}{
+		{"valid", NewCompositePolicy("group", &stubPolicy{name: "a"}, &stubPolicy{name: "b"}), false},
+		{"empty name", NewCompositePolicy("", &stubPolicy{name: "a"}), true},
+		{"no children", NewCompositePolicy("group"), true},
+		{"duplicate child", NewCompositePolicy("group", &stubPolicy{name: "a"}, &stubPolicy{name: "a"}), true},
+		{"invalid child", NewCompositePolicy("group", &stubPolicy{name: "a", validateErr: errors.New("bad")}), true},
+		{"invalid nested child", NewCompositePolicy("outer", NewCompositePolicy("inner")), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.composite.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config describes per-environment policy settings, e.g.
+//
+//	policies:
+//	  validator-policy:
+//	    config:
+//	      required_fields: [message, user_id]
+//	  yaml-v2-policy:
+//	    enabled: false
+type Config struct {
+	Policies map[string]PolicyConfig `yaml:"policies"`
+}
+
+// PolicyConfig holds the settings for one policy
+type PolicyConfig struct {
+	// Enabled disables the policy when false and re-enables it when true;
+	// omitted leaves the policy's state unchanged
+	Enabled *bool `yaml:"enabled"`
+
+	// Priority overrides the priority the policy declares
+	Priority *int `yaml:"priority"`
+
+	// Config is passed to the policy's Configure method
+	Config map[string]interface{} `yaml:"config"`
+}
+
+// LoadConfig reads a YAML config file. Unknown keys are rejected so that
+// typos do not silently leave a policy unconfigured.
+func LoadConfig(path string) (*Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+
+	var config Config
+	if err := decoder.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return &config, nil
+}
+
+// ApplyConfig configures the registry's policies, in name order. Every
+// policy named in the config must be registered. Policies with a config
+// map must implement Configurable; a Wrapper passes the config to the
+// policy it wraps. Disabled policies stay registered but
+// are skipped by ExecuteAll. Policies not named in the config are left
+// unchanged.
+func ApplyConfig(registry *PolicyRegistry, config *Config) error {
+	names := make([]string, 0, len(config.Policies))
+	for name := range config.Policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		settings := config.Policies[name]
+		policy, err := registry.GetOrError(name)
+		if err != nil {
+			return err
+		}
+
+		if settings.Enabled != nil {
+			if *settings.Enabled {
+				err = registry.Enable(name)
+			} else {
+				err = registry.Disable(name)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		if settings.Priority != nil {
+			if err := registry.SetPriority(name, *settings.Priority); err != nil {
+				return err
+			}
+		}
+
+		if settings.Config == nil {
+			continue
+		}
+		configurable, ok := unwrapPolicy(policy).(Configurable)
+		if !ok {
+			return fmt.Errorf("policy %s does not accept configuration", name)
+		}
+		if err := configurable.Configure(settings.Config); err != nil {
+			return fmt.Errorf("policy %s: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// configurablePolicy records the configuration it receives
+type configurablePolicy struct {
+	stubPolicy
+	config       map[string]interface{}
+	configureErr error
+}
+
+func (p *configurablePolicy) Configure(config map[string]interface{}) error {
+	p.config = config
+	return p.configureErr
+}
+
+// writeConfig writes a config file and returns its path
+func writeConfig(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const sampleConfig = `
+policies:
+  validator:
+    config:
+      required_fields: [message, user_id]
+      strict: true
+  legacy:
+    enabled: false
+  audit:
+    enabled: true
+    priority: -5
+`
+
+func TestLoadConfig(t *testing.T) {
+	config, err := LoadConfig(writeConfig(t, sampleConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	disabled, enabled, priority := false, true, -5
+	want := &Config{Policies: map[string]PolicyConfig{
+		"validator": {Config: map[string]interface{}{
+			"required_fields": []interface{}{"message", "user_id"},
+			"strict":          true,
+		}},
+		"legacy": {Enabled: &disabled},
+		"audit":  {Enabled: &enabled, Priority: &priority},
+	}}
+	if !reflect.DeepEqual(config, want) {
+		t.Errorf("config = %+v, want %+v", config, want)
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{"unknown policy key", "policies:\n  p:\n    enable: true\n", "field enable not found"},
+		{"unknown top-level key", "policy:\n  p: {}\n", "field policy not found"},
+		{"malformed", "policies: [\n", "invalid config"},
+		{"wrong type", "policies:\n  p:\n    enabled: sometimes\n", "invalid config"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadConfig(writeConfig(t, tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := LoadConfig(filepath.Join(t.TempDir(), "missing.yaml")); !os.IsNotExist(err) {
+		t.Errorf("missing file error = %v, want not exist", err)
+	}
+
+	// An empty file is an empty config
+	config, err := LoadConfig(writeConfig(t, ""))
+	if err != nil || len(config.Policies) != 0 {
+		t.Errorf("empty file = %+v, %v; want an empty config", config, err)
+	}
+}
+
+func TestApplyConfig(t *testing.T) {
+	r := newTestRegistry()
+	validator := &configurablePolicy{stubPolicy: stubPolicy{name: "validator"}}
+	for _, p := range []Policy{validator, &stubPolicy{name: "legacy"}, &stubPolicy{name: "audit"}, &stubPolicy{name: "untouched"}} {
+		if err := r.Register(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := r.Disable("audit"); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := LoadConfig(writeConfig(t, sampleConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ApplyConfig(r, config); err != nil {
+		t.Fatalf("ApplyConfig: %v", err)
+	}
+
+	for name, want := range map[string]bool{"validator": true, "legacy": false, "audit": true, "untouched": true} {
+		if got := r.IsEnabled(name); got != want {
+			t.Errorf("IsEnabled(%s) = %v, want %v", name, got, want)
+		}
+	}
+	if priority, _ := r.Priority("audit"); priority != -5 {
+		t.Errorf("audit priority = %d, want -5", priority)
+	}
+	if want := config.Policies["validator"].Config; !reflect.DeepEqual(validator.config, want) {
+		t.Errorf("validator configured with %v, want %v", validator.config, want)
+	}
+}
+
+func TestApplyConfigErrors(t *testing.T) {
+	errBadField := errors.New("bad field")
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{"unregistered policy", "policies:\n  missing:\n    enabled: true\n", "policy not found: missing"},
+		{"not configurable", "policies:\n  plain:\n    config: {a: 1}\n", "policy plain does not accept configuration"},
+		{"configure fails", "policies:\n  broken:\n    config: {a: 1}\n", "policy broken: bad field"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestRegistry()
+			for _, p := range []Policy{&stubPolicy{name: "plain"}, &configurablePolicy{stubPolicy: stubPolicy{name: "broken"}, configureErr: errBadField}} {
+				if err := r.Register(p); err != nil {
+					t.Fatal(err)
+				}
+			}
+			config, err := LoadConfig(writeConfig(t, tt.config))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := ApplyConfig(r, config); err == nil || err.Error() != tt.wantErr {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
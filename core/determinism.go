@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrNondeterministic is returned by DeterminismPolicy when two runs of the
+// wrapped policy on the same input produce different results
+var ErrNondeterministic = errors.New("nondeterministic policy")
+
+// DeterminismPolicy is a development aid that runs the wrapped policy twice
+// on the same input and fails unless both results serialize to identical
+// JSON. encoding/json sorts map keys, so map iteration order does not count
+// as a difference; time-dependent or random values do. Stateful policies
+// are nondeterministic by design and should not be wrapped.
+type DeterminismPolicy struct {
+	Inner Policy
+}
+
+// NewDeterminismPolicy wraps a policy with a determinism check
+func NewDeterminismPolicy(inner Policy) *DeterminismPolicy {
+	return &DeterminismPolicy{Inner: inner}
+}
+
+// Name returns the wrapped policy's name so the wrapper can replace it
+func (d *DeterminismPolicy) Name() string {
+	return d.Inner.Name()
+}
+
+// Validate validates the wrapped policy
+func (d *DeterminismPolicy) Validate() error {
+	return d.Inner.Validate()
+}
+
+// Unwrap returns the wrapped policy, whose phase, priority, metadata and
+// configuration the wrapper keeps
+func (d *DeterminismPolicy) Unwrap() Policy {
+	return d.Inner
+}
+
+// Execute runs the wrapped policy twice and returns the first result if
+// both runs agree
+func (d *DeterminismPolicy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	first, err := d.Inner.Execute(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	second, err := d.Inner.Execute(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s succeeded on the first run but failed on the second: %v", ErrNondeterministic, d.Name(), err)
+	}
+
+	firstJSON, err := json.Marshal(first)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize result of %s: %w", d.Name(), err)
+	}
+	secondJSON, err := json.Marshal(second)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize result of %s: %w", d.Name(), err)
+	}
+
+	if !bytes.Equal(firstJSON, secondJSON) {
+		offset := firstDifference(firstJSON, secondJSON)
+		return nil, fmt.Errorf("%w: %s results differ at byte %d: %s vs %s",
+			ErrNondeterministic, d.Name(), offset, excerpt(firstJSON, offset), excerpt(secondJSON, offset))
+	}
+	return first, nil
+}
+
+// firstDifference returns the index of the first differing byte
+func firstDifference(a, b []byte) int {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	for i := 0; i < n; i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return n
+}
+
+// excerpt returns a short window of data around offset for error messages
+func excerpt(data []byte, offset int) string {
+	const window = 20
+	start := offset - window
+	if start < 0 {
+		start = 0
+	}
+	end := offset + window
+	if end > len(data) {
+		end = len(data)
+	}
+	return fmt.Sprintf("%q", data[start:end])
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDeterminismPolicy(t *testing.T) {
+	errFlaky := errors.New("flaky")
+
+	// counter returns a policy whose result depends on how often it ran
+	counter := func() Policy {
+		runs := 0
+		return &stubPolicy{name: "counter", execute: func(context.Context, interface{}) (interface{}, error) {
+			runs++
+			return map[string]interface{}{"policy": "counter", "run": runs}, nil
+		}}
+	}
+	failsSecond := func() Policy {
+		runs := 0
+		return &stubPolicy{name: "fails-second", execute: func(_ context.Context, input interface{}) (interface{}, error) {
+			runs++
+			if runs == 2 {
+				return nil, errFlaky
+			}
+			return input, nil
+		}}
+	}
+
+	tests := []struct {
+		name        string
+		inner       Policy
+		wantErr     error
+		wantMessage string
+	}{
+		{
+			name:  "deterministic",
+			inner: &stubPolicy{name: "echo"},
+		},
+		{
+			name: "fresh maps each run",
+			inner: &stubPolicy{name: "fresh", execute: func(context.Context, interface{}) (interface{}, error) {
+				result := make(map[string]interface{})
+				for _, key := range []string{"z", "a", "m", "b"} {
+					result[key] = key
+				}
+				return result, nil
+			}},
+		},
+		{
+			name:        "nondeterministic",
+			inner:       counter(),
+			wantErr:     ErrNondeterministic,
+			wantMessage: `nondeterministic policy: counter results differ at byte 26: "cy\":\"counter\",\"run\":1}" vs "cy\":\"counter\",\"run\":2}"`,
+		},
+		{
+			name:        "fails on the second run",
+			inner:       failsSecond(),
+			wantErr:     ErrNondeterministic,
+			wantMessage: "nondeterministic policy: fails-second succeeded on the first run but failed on the second: flaky",
+		},
+		{
+			name:    "fails on the first run",
+			inner:   failing("broken", errFlaky),
+			wantErr: errFlaky,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := map[string]interface{}{"id": 1.0}
+			got, err := NewDeterminismPolicy(tt.inner).Execute(context.Background(), input)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantMessage != "" && err.Error() != tt.wantMessage {
+				t.Errorf("error = %s, want %s", err, tt.wantMessage)
+			}
+			if tt.wantErr == nil && got == nil {
+				t.Error("result is nil, want the first run's result")
+			}
+		})
+	}
+}
+
+func TestDeterminismPolicyForwardsIdentity(t *testing.T) {
+	inner := &phasedPolicy{stubPolicy: stubPolicy{name: "inner", validateErr: errors.New("bad config")}, phase: PhasePost}
+	d := NewDeterminismPolicy(inner)
+	if d.Name() != "inner" || PhaseOf(d) != PhasePost {
+		t.Errorf("name, phase = %s, %s; want inner, post", d.Name(), PhaseOf(d))
+	}
+	if err := d.Validate(); err == nil || !strings.Contains(err.Error(), "bad config") {
+		t.Errorf("Validate = %v, want the inner policy's error", err)
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	data := []byte(strings.Repeat("a", 30) + "X" + strings.Repeat("b", 30))
+	if got, want := excerpt(data, 30), `"`+strings.Repeat("a", 20)+"X"+strings.Repeat("b", 19)+`"`; got != want {
+		t.Errorf("excerpt = %s, want %s", got, want)
+	}
+	if got := excerpt([]byte("ab"), 1); got != `"ab"` {
+		t.Errorf("excerpt near the edges = %s, want \"ab\"", got)
+	}
+	if got := firstDifference([]byte("abc"), []byte("ab")); got != 2 {
+		t.Errorf("firstDifference of a prefix = %d, want 2", got)
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Error codes carried by PolicyError
+const (
+	ErrCodeInvalidInput     = "INVALID_INPUT"
+	ErrCodeNotFound         = "NOT_FOUND"
+	ErrCodeInternal         = "INTERNAL"
+	ErrCodeMethodNotAllowed = "METHOD_NOT_ALLOWED"
+)
+
+// PolicyError is an error with a machine-readable code, used to report
+// failures to callers outside the engine
+type PolicyError struct {
+	Code    string
+	Message string
+	Err     error
+}
+
+// NewPolicyError creates a PolicyError with the given code and message
+func NewPolicyError(code, message string, err error) *PolicyError {
+	return &PolicyError{
+		Code:    code,
+		Message: message,
+		Err:     err,
+	}
+}
+
+// Error implements the error interface
+func (e *PolicyError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Code, e.Message, e.Err)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// Unwrap returns the underlying error
+func (e *PolicyError) Unwrap() error {
+	return e.Err
+}
+
+// HTTPStatus maps the error code to an HTTP status code
+func (e *PolicyError) HTTPStatus() int {
+	switch e.Code {
+	case ErrCodeInvalidInput:
+		return http.StatusBadRequest
+	case ErrCodeNotFound:
+		return http.StatusNotFound
+	case ErrCodeMethodNotAllowed:
+		return http.StatusMethodNotAllowed
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// errorEnvelope is the JSON body written for every HTTP error response
+type errorEnvelope struct {
+	Error errorBody `json:"error"`
+}
+
+type errorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// writeError writes err as a JSON error envelope. Errors that are not a
+// PolicyError are reported as internal errors.
+func writeError(w http.ResponseWriter, err error) {
+	var pe *PolicyError
+	if !errors.As(err, &pe) {
+		pe = NewPolicyError(ErrCodeInternal, "internal error", err)
+	}
+
+	message := pe.Message
+	if pe.Err != nil {
+		message = fmt.Sprintf("%s: %v", pe.Message, pe.Err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(pe.HTTPStatus())
+	json.NewEncoder(w).Encode(errorEnvelope{
+		Error: errorBody{
+			Code:    pe.Code,
+			Message: message,
+		},
+	})
+}
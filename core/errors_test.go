@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPolicyErrorHTTPStatus(t *testing.T) {
+	tests := []struct {
+		code string
+		want int
+	}{
+		{ErrCodeInvalidInput, http.StatusBadRequest},
+		{ErrCodeNotFound, http.StatusNotFound},
+		{ErrCodeMethodNotAllowed, http.StatusMethodNotAllowed},
+		{ErrCodeInternal, http.StatusInternalServerError},
+		{"SOMETHING_ELSE", http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			if got := NewPolicyError(tt.code, "msg", nil).HTTPStatus(); got != tt.want {
+				t.Errorf("HTTPStatus() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyErrorWrapping(t *testing.T) {
+	cause := errors.New("disk full")
+	err := fmt.Errorf("saving: %w", NewPolicyError(ErrCodeInternal, "write failed", cause))
+
+	if !errors.Is(err, cause) {
+		t.Error("errors.Is does not find the wrapped cause")
+	}
+	var pe *PolicyError
+	if !errors.As(err, &pe) || pe.Code != ErrCodeInternal {
+		t.Errorf("errors.As = %v, want INTERNAL PolicyError", pe)
+	}
+	if got, want := pe.Error(), "INTERNAL: write failed: disk full"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if got, want := NewPolicyError(ErrCodeNotFound, "gone", nil).Error(), "NOT_FOUND: gone"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantStatus  int
+		wantCode    string
+		wantMessage string
+	}{
+		{
+			name:        "invalid input",
+			err:         NewPolicyError(ErrCodeInvalidInput, "malformed JSON body", errors.New("unexpected EOF")),
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    ErrCodeInvalidInput,
+			wantMessage: "malformed JSON body: unexpected EOF",
+		},
+		{
+			name:        "not found",
+			err:         NewPolicyError(ErrCodeNotFound, "policy not found: x", nil),
+			wantStatus:  http.StatusNotFound,
+			wantCode:    ErrCodeNotFound,
+			wantMessage: "policy not found: x",
+		},
+		{
+			name:        "method not allowed",
+			err:         NewPolicyError(ErrCodeMethodNotAllowed, "use GET", nil),
+			wantStatus:  http.StatusMethodNotAllowed,
+			wantCode:    ErrCodeMethodNotAllowed,
+			wantMessage: "use GET",
+		},
+		{
+			name:        "internal",
+			err:         NewPolicyError(ErrCodeInternal, "policy p failed", errors.New("boom")),
+			wantStatus:  http.StatusInternalServerError,
+			wantCode:    ErrCodeInternal,
+			wantMessage: "policy p failed: boom",
+		},
+		{
+			name:        "wrapped policy error",
+			err:         fmt.Errorf("context: %w", NewPolicyError(ErrCodeInvalidInput, "bad", nil)),
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    ErrCodeInvalidInput,
+			wantMessage: "bad",
+		},
+		{
+			name:        "plain error",
+			err:         errors.New("boom"),
+			wantStatus:  http.StatusInternalServerError,
+			wantCode:    ErrCodeInternal,
+			wantMessage: "internal error: boom",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			writeError(rec, tt.err)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("Content-Type = %q, want application/json", ct)
+			}
+
+			// Decode strictly so any field beyond the envelope fails
+			var envelope errorEnvelope
+			decoder := json.NewDecoder(rec.Body)
+			decoder.DisallowUnknownFields()
+			if err := decoder.Decode(&envelope); err != nil {
+				t.Fatalf("decoding envelope: %v", err)
+			}
+			if envelope.Error.Code != tt.wantCode || envelope.Error.Message != tt.wantMessage {
+				t.Errorf("envelope = %+v, want code %s, message %q", envelope.Error, tt.wantCode, tt.wantMessage)
+			}
+		})
+	}
+}
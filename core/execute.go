@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// PolicyExecutionError records the failure of a single policy
+type PolicyExecutionError struct {
+	Policy string
+	Err    error
+}
+
+// Error implements the error interface
+func (e *PolicyExecutionError) Error() string {
+	return fmt.Sprintf("policy %s: %v", e.Policy, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *PolicyExecutionError) Unwrap() error {
+	return e.Err
+}
+
+// MultiError collects the failures of several policies, in execution order
+type MultiError struct {
+	Errors []*PolicyExecutionError
+}
+
+// Error implements the error interface
+func (e *MultiError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("%d policy execution(s) failed: %s", len(e.Errors), strings.Join(messages, "; "))
+}
+
+// Unwrap returns the individual policy errors so errors.Is and errors.As
+// can match any of them
+func (e *MultiError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Failed returns the names of the policies that failed
+func (e *MultiError) Failed() []string {
+	names := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		names[i] = err.Policy
+	}
+	return names
+}
+
+// PanicError is returned in place of a result when a policy panics
+type PanicError struct {
+	Policy string
+	Value  interface{}
+	Stack  []byte
+}
+
+// Error implements the error interface
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("policy %s panicked: %v", e.Policy, e.Value)
+}
+
+// safeExecute runs policy.Execute, converting a panic into a *PanicError
+// so that one misbehaving policy cannot take down the engine
+func safeExecute(ctx context.Context, policy Policy, input interface{}) (result interface{}, err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			result = nil
+			err = &PanicError{Policy: policy.Name(), Value: value, Stack: debug.Stack()}
+		}
+	}()
+	return policy.Execute(ctx, input)
+}
+
+// ExecuteAll runs every enabled policy against input in phase order and
+// returns the results keyed by policy name. A failing policy does not stop
+// the others; failures are collected and returned as a *MultiError
+// alongside the results of the policies that succeeded. Each execution is
+// logged to the registry's logger and recorded in its metrics.
+func (r *PolicyRegistry) ExecuteAll(ctx context.Context, input interface{}) (map[string]interface{}, error) {
+	names := r.ListByPhase()
+	results := make(map[string]interface{}, len(names))
+	var failures []*PolicyExecutionError
+	opts := ExecuteOptions{Logger: r.Logger(), Metrics: r.metrics}
+
+	for _, name := range names {
+		if !r.IsEnabled(name) {
+			continue
+		}
+		policy, ok := r.Get(name)
+		if !ok {
+			// Unregistered since the list was taken
+			continue
+		}
+
+		execution := ExecutePolicy(ctx, policy, input, opts)
+		if execution.Err != nil {
+			failures = append(failures, &PolicyExecutionError{Policy: name, Err: execution.Err})
+			continue
+		}
+		results[name] = execution.Result
+	}
+
+	if len(failures) > 0 {
+		return results, &MultiError{Errors: failures}
+	}
+	return results, nil
+}
+
+// ExecuteWithTimeout runs policy.Execute with a deadline of timeout and
+// returns context.DeadlineExceeded if the policy has not finished by then.
+// The policy keeps running in the background until it returns, but sees
+// its context cancelled; its late result is discarded. A timeout of zero or
+// less runs the policy without a deadline. A panic in the policy is
+// returned as a *PanicError.
+func ExecuteWithTimeout(ctx context.Context, policy Policy, input interface{}, timeout time.Duration) (interface{}, error) {
+	if timeout <= 0 {
+		return safeExecute(ctx, policy, input)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type outcome struct {
+		result interface{}
+		err    error
+	}
+	// Buffered so the goroutine can always deliver and exit, even after
+	// the caller has stopped waiting
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := safeExecute(ctx, policy, input)
+		done <- outcome{result: result, err: err}
+	}()
+
+	select {
+	case o := <-done:
+		return o.result, o.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// ExecuteOptions controls how ExecutePolicy runs a policy
+type ExecuteOptions struct {
+	// Timeout bounds the execution; zero means no limit
+	Timeout time.Duration
+
+	// CollectUsage records heap allocations and CPU time in the result;
+	// see ResourceUsage for the measurement caveats
+	CollectUsage bool
+
+	// Logger receives an "executed" or "execution failed" entry for the
+	// run; nil disables logging
+	Logger Logger
+
+	// Metrics records the execution's duration and outcome; nil disables
+	// recording
+	Metrics *MetricsCollector
+}
+
+// ExecutionResult is the outcome of running one policy through
+// ExecutePolicy
+type ExecutionResult struct {
+	Policy   string         `json:"policy"`
+	Result   interface{}    `json:"result,omitempty"`
+	Err      error          `json:"-"`
+	Error    string         `json:"error,omitempty"`
+	Duration time.Duration  `json:"duration"`
+	Usage    *ResourceUsage `json:"usage,omitempty"`
+}
+
+// ExecutePolicy runs a policy with the given options and records the
+// result, error, wall-clock duration and, when requested, resource usage
+func ExecutePolicy(ctx context.Context, policy Policy, input interface{}, opts ExecuteOptions) *ExecutionResult {
+	var before usageSnapshot
+	if opts.CollectUsage {
+		before = takeUsageSnapshot()
+	}
+
+	start := time.Now()
+	result, err := ExecuteWithTimeout(ctx, policy, input, opts.Timeout)
+	execution := &ExecutionResult{
+		Policy:   policy.Name(),
+		Result:   result,
+		Err:      err,
+		Duration: time.Since(start),
+	}
+	if err != nil {
+		execution.Error = err.Error()
+	}
+	if opts.CollectUsage {
+		execution.Usage = before.since()
+	}
+	if opts.Logger != nil {
+		logExecution(opts.Logger, execution)
+	}
+	if opts.Metrics != nil {
+		opts.Metrics.Record(execution.Policy, execution.Duration, err)
+	}
+	return execution
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// failing returns a stubPolicy whose Execute always fails with err
+func failing(name string, err error) *stubPolicy {
+	return &stubPolicy{name: name, execute: func(context.Context, interface{}) (interface{}, error) {
+		return nil, err
+	}}
+}
+
+func TestExecuteAll(t *testing.T) {
+	errTimeout := errors.New("upstream timeout")
+	errSchema := errors.New("schema mismatch")
+
+	r := newTestRegistry()
+	for _, p := range []Policy{
+		&stubPolicy{name: "a-pass"},
+		failing("b-fail", errTimeout),
+		&stubPolicy{name: "c-pass"},
+		failing("d-fail", errSchema),
+	} {
+		if err := r.Register(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	input := map[string]interface{}{"id": 1.0}
+	results, err := r.ExecuteAll(context.Background(), input)
+
+	wantResults := map[string]interface{}{"a-pass": input, "c-pass": input}
+	if !reflect.DeepEqual(results, wantResults) {
+		t.Errorf("results = %v, want %v", results, wantResults)
+	}
+
+	var multi *MultiError
+	if !errors.As(err, &multi) {
+		t.Fatalf("error = %v, want *MultiError", err)
+	}
+	if failed := multi.Failed(); !reflect.DeepEqual(failed, []string{"b-fail", "d-fail"}) {
+		t.Errorf("Failed() = %v, want [b-fail d-fail]", failed)
+	}
+	if !errors.Is(err, errTimeout) || !errors.Is(err, errSchema) {
+		t.Error("errors.Is does not find the individual policy errors")
+	}
+	var pe *PolicyExecutionError
+	if !errors.As(err, &pe) || pe.Policy != "b-fail" {
+		t.Errorf("errors.As PolicyExecutionError = %v, want b-fail", pe)
+	}
+	if msg := err.Error(); !strings.HasPrefix(msg, "2 policy execution(s) failed: policy b-fail: upstream timeout; policy d-fail") {
+		t.Errorf("Error() = %q", msg)
+	}
+}
+
+func TestExecuteAllAllPass(t *testing.T) {
+	r := newTestRegistry()
+	for _, name := range []string{"a", "b"} {
+		if err := r.Register(&stubPolicy{name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results, err := r.ExecuteAll(context.Background(), "input")
+	if err != nil {
+		t.Fatalf("ExecuteAll: %v", err)
+	}
+	if len(results) != 2 {
+		t.Errorf("results = %v, want two", results)
+	}
+}
+
+func TestExecuteAllEmptyRegistry(t *testing.T) {
+	results, err := newTestRegistry().ExecuteAll(context.Background(), nil)
+	if err != nil || len(results) != 0 {
+		t.Errorf("ExecuteAll = %v, %v; want no results and no error", results, err)
+	}
+}
+
+// slowPolicy returns its input once release is closed, ignoring its
+// context, and closes returned when it does
+func slowPolicy(release <-chan struct{}, returned chan<- struct{}) *stubPolicy {
+	return &stubPolicy{name: "slow", execute: func(_ context.Context, input interface{}) (interface{}, error) {
+		defer close(returned)
+		<-release
+		return input, nil
+	}}
+}
+
+// panicking returns a stubPolicy whose Execute panics with value
+func panicking(name string, value interface{}) *stubPolicy {
+	return &stubPolicy{name: name, execute: func(context.Context, interface{}) (interface{}, error) {
+		panic(value)
+	}}
+}
+
+func TestSafeExecutePanic(t *testing.T) {
+	outOfRange := &stubPolicy{name: "p", execute: func(context.Context, interface{}) (interface{}, error) {
+		var empty []int
+		return empty[3], nil
+	}}
+
+	tests := []struct {
+		name    string
+		policy  Policy
+		wantMsg string
+	}{
+		{"string", panicking("p", "boom"), "policy p panicked: boom"},
+		{"error", panicking("p", errors.New("broken state")), "policy p panicked: broken state"},
+		{"runtime error", outOfRange, "policy p panicked: runtime error: index out of range [3] with length 0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := safeExecute(context.Background(), tt.policy, nil)
+			if result != nil {
+				t.Errorf("result = %v, want nil", result)
+			}
+			var pe *PanicError
+			if !errors.As(err, &pe) {
+				t.Fatalf("error = %v, want *PanicError", err)
+			}
+			if pe.Policy != "p" || err.Error() != tt.wantMsg {
+				t.Errorf("error = %q for policy %s, want %q", err, pe.Policy, tt.wantMsg)
+			}
+			if !strings.Contains(string(pe.Stack), "TestSafeExecutePanic") {
+				t.Error("stack does not show where the policy panicked")
+			}
+		})
+	}
+
+	// Without a panic the policy's own values come through
+	if result, err := safeExecute(context.Background(), &stubPolicy{name: "ok"}, "in"); result != "in" || err != nil {
+		t.Errorf("safeExecute = %v, %v; want in, nil", result, err)
+	}
+}
+
+func TestExecuteAllSurvivesPanic(t *testing.T) {
+	r := newTestRegistry()
+	for _, p := range []Policy{&stubPolicy{name: "a"}, panicking("b", "boom"), &stubPolicy{name: "c"}} {
+		if err := r.Register(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results, err := r.ExecuteAll(context.Background(), "in")
+	if want := map[string]interface{}{"a": "in", "c": "in"}; !reflect.DeepEqual(results, want) {
+		t.Errorf("results = %v, want %v", results, want)
+	}
+	var pe *PanicError
+	if !errors.As(err, &pe) || pe.Policy != "b" || pe.Value != "boom" {
+		t.Fatalf("error = %v, want a *PanicError from b", err)
+	}
+	if stats := r.Metrics()["b"]; stats.Errors != 1 {
+		t.Errorf("b errors = %d, want 1", stats.Errors)
+	}
+}
+
+func TestExecuteWithTimeout(t *testing.T) {
+	fast := &stubPolicy{name: "fast"}
+	waits := &stubPolicy{name: "waits", execute: func(ctx context.Context, _ interface{}) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}}
+
+	tests := []struct {
+		name       string
+		policy     Policy
+		timeout    time.Duration
+		wantErr    error
+		wantResult interface{}
+	}{
+		{"finishes in time", fast, time.Second, nil, "input"},
+		{"no timeout", fast, 0, nil, "input"},
+		{"honors the deadline", waits, 10 * time.Millisecond, context.DeadlineExceeded, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExecuteWithTimeout(context.Background(), tt.policy, "input", tt.timeout)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.wantResult {
+				t.Errorf("result = %v, want %v", got, tt.wantResult)
+			}
+		})
+	}
+}
+
+func TestExecuteWithTimeoutSlowPolicy(t *testing.T) {
+	before := runtime.NumGoroutine()
+	release, returned := make(chan struct{}), make(chan struct{})
+
+	start := time.Now()
+	got, err := ExecuteWithTimeout(context.Background(), slowPolicy(release, returned), "input", 20*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("error = %v, want context.DeadlineExceeded", err)
+	}
+	if got != nil {
+		t.Errorf("result = %v, want nil", got)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("returned after %s, want about 20ms", elapsed)
+	}
+
+	// Once the policy finally returns, its goroutine delivers the late
+	// result to the buffered channel and exits
+	close(release)
+	<-returned
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d goroutines still running, want %d", runtime.NumGoroutine(), before)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestExecuteWithTimeoutParentCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	release, returned := make(chan struct{}), make(chan struct{})
+	defer func() {
+		close(release)
+		<-returned
+	}()
+
+	if _, err := ExecuteWithTimeout(ctx, slowPolicy(release, returned), nil, time.Minute); !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want context.Canceled", err)
+	}
+}
+
+func TestExecuteWithTimeoutPanic(t *testing.T) {
+	panics := panicking("panics", "boom")
+	var pe *PanicError
+	if _, err := ExecuteWithTimeout(context.Background(), panics, nil, time.Second); !errors.As(err, &pe) || pe.Value != "boom" {
+		t.Errorf("error = %v, want a *PanicError for boom", err)
+	}
+}
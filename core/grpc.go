@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/example/policy-engine-core/policyenginepb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative policyenginepb/policyengine.proto
+
+// grpcServer implements the PolicyEngine gRPC service on top of a
+// registry. The stubs in policyenginepb are generated from
+// policyenginepb/policyengine.proto with protoc-gen-go v1.33.0 and
+// protoc-gen-go-grpc v1.3.0.
+type grpcServer struct {
+	policyenginepb.UnimplementedPolicyEngineServer
+	registry *PolicyRegistry
+}
+
+// NewGRPCServer returns a PolicyEngine service backed by registry
+func NewGRPCServer(registry *PolicyRegistry) policyenginepb.PolicyEngineServer {
+	return &grpcServer{registry: registry}
+}
+
+// Execute runs one policy on the JSON input and returns its JSON result
+func (s *grpcServer) Execute(ctx context.Context, req *policyenginepb.ExecuteRequest) (*policyenginepb.ExecuteResponse, error) {
+	policy, err := s.registry.GetOrError(req.GetName())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	var input interface{}
+	if err := json.Unmarshal([]byte(req.GetJsonInput()), &input); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "malformed JSON input: %v", err)
+	}
+
+	execution := ExecutePolicy(ctx, policy, input, ExecuteOptions{
+		Logger:  s.registry.Logger(),
+		Metrics: s.registry.metrics,
+	})
+	if err := execution.Err; err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return nil, status.FromContextError(err).Err()
+		}
+		return nil, status.Errorf(codes.Internal, "policy %s failed: %v", req.GetName(), err)
+	}
+
+	result, err := json.Marshal(execution.Result)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "policy %s returned a result that cannot be encoded as JSON: %v", req.GetName(), err)
+	}
+	return &policyenginepb.ExecuteResponse{JsonResult: string(result)}, nil
+}
+
+// List returns the registered policy names in execution order
+func (s *grpcServer) List(ctx context.Context, req *policyenginepb.ListRequest) (*policyenginepb.ListResponse, error) {
+	return &policyenginepb.ListResponse{Names: s.registry.ListByPhase()}, nil
+}
+
+// startGRPC listens on addr and serves the PolicyEngine service in the
+// background, sending the error that stops the server to errc
+func startGRPC(addr string, registry *PolicyRegistry, errc chan<- error) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	server := grpc.NewServer()
+	policyenginepb.RegisterPolicyEngineServer(server, NewGRPCServer(registry))
+	go func() {
+		errc <- fmt.Errorf("grpc server: %w", server.Serve(listener))
+	}()
+	return nil
+}
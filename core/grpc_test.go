@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/example/policy-engine-core/policyenginepb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// dialGRPC serves the PolicyEngine service for r on an in-process
+// connection and returns a client for it
+func dialGRPC(t *testing.T, r *PolicyRegistry) policyenginepb.PolicyEngineClient {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	policyenginepb.RegisterPolicyEngineServer(server, NewGRPCServer(r))
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return policyenginepb.NewPolicyEngineClient(conn)
+}
+
+func TestGRPCExecute(t *testing.T) {
+	r := newTestRegistry()
+	for _, p := range []Policy{
+		&stubPolicy{name: "echo"},
+		failing("broken", errors.New("upstream down")),
+		&stubPolicy{name: "unencodable", execute: func(context.Context, interface{}) (interface{}, error) {
+			return func() {}, nil
+		}},
+		&stubPolicy{name: "slow", execute: func(ctx context.Context, _ interface{}) (interface{}, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}},
+	} {
+		if err := r.Register(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	client := dialGRPC(t, r)
+
+	resp, err := client.Execute(context.Background(), &policyenginepb.ExecuteRequest{Name: "echo", JsonInput: `{"user": "ada", "n": 1}`})
+	if err != nil {
+		t.Fatalf("Execute(echo): %v", err)
+	}
+	var result interface{}
+	if err := json.Unmarshal([]byte(resp.GetJsonResult()), &result); err != nil {
+		t.Fatalf("result %q is not JSON: %v", resp.GetJsonResult(), err)
+	}
+	if want := map[string]interface{}{"user": "ada", "n": 1.0}; !reflect.DeepEqual(result, want) {
+		t.Errorf("result = %v, want %v", result, want)
+	}
+	if stats := r.Metrics()["echo"]; stats.Count != 1 {
+		t.Errorf("echo executions recorded = %d, want 1", stats.Count)
+	}
+
+	tests := []struct {
+		name     string
+		req      *policyenginepb.ExecuteRequest
+		timeout  time.Duration
+		wantCode codes.Code
+	}{
+		{"unknown policy", &policyenginepb.ExecuteRequest{Name: "missing", JsonInput: "{}"}, 0, codes.NotFound},
+		{"malformed input", &policyenginepb.ExecuteRequest{Name: "echo", JsonInput: "{"}, 0, codes.InvalidArgument},
+		{"policy error", &policyenginepb.ExecuteRequest{Name: "broken", JsonInput: "{}"}, 0, codes.Internal},
+		{"unencodable result", &policyenginepb.ExecuteRequest{Name: "unencodable", JsonInput: "{}"}, 0, codes.Internal},
+		{"deadline", &policyenginepb.ExecuteRequest{Name: "slow", JsonInput: "{}"}, 50 * time.Millisecond, codes.DeadlineExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+			_, err := client.Execute(ctx, tt.req)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Errorf("code = %s (%v), want %s", code, err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestGRPCList(t *testing.T) {
+	r := newTestRegistry()
+	client := dialGRPC(t, r)
+
+	resp, err := client.List(context.Background(), &policyenginepb.ListRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.GetNames()) != 0 {
+		t.Errorf("names = %v, want none", resp.GetNames())
+	}
+
+	for _, p := range []Policy{
+		&stubPolicy{name: "b"},
+		&phasedPolicy{stubPolicy: stubPolicy{name: "z"}, phase: PhasePre},
+		&stubPolicy{name: "a"},
+	} {
+		if err := r.Register(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	resp, err = client.List(context.Background(), &policyenginepb.ListRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"z", "a", "b"}; !reflect.DeepEqual(resp.GetNames(), want) {
+		t.Errorf("names = %v, want %v", resp.GetNames(), want)
+	}
+}
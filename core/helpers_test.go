@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// stubPolicy is a Policy whose behavior is set per test. By default it
+// validates and returns its input unchanged.
+type stubPolicy struct {
+	name        string
+	validateErr error
+	execute     func(ctx context.Context, input interface{}) (interface{}, error)
+}
+
+func (s *stubPolicy) Name() string {
+	return s.name
+}
+
+func (s *stubPolicy) Validate() error {
+	return s.validateErr
+}
+
+func (s *stubPolicy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	if s.execute == nil {
+		return input, nil
+	}
+	return s.execute(ctx, input)
+}
+
+// newTestRegistry returns an empty registry that discards its logs
+func newTestRegistry() *PolicyRegistry {
+	r := NewPolicyRegistry()
+	r.SetLogger(NopLogger())
+	return r
+}
+
+// useRegistry points the global registry, which the commands use, at r
+// for the rest of the test
+func useRegistry(t *testing.T, r *PolicyRegistry) {
+	t.Helper()
+	saved := registry
+	registry = r
+	t.Cleanup(func() { registry = saved })
+}
+
+// assertHonorsCancellation runs policy with an already-cancelled context
+// and fails unless it returns context.Canceled promptly
+func assertHonorsCancellation(t *testing.T, policy Policy, input interface{}) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := safeExecute(ctx, policy, input)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("%s: Execute error = %v, want context.Canceled", policy.Name(), err)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("%s: Execute still running a second after cancellation", policy.Name())
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxRequestBody caps the size of an execute request body
+const maxRequestBody = 10 << 20
+
+// policyHandler serves the policy HTTP API
+type policyHandler struct {
+	registry *PolicyRegistry
+}
+
+// NewPolicyHTTPHandler returns an http.Handler exposing the registry:
+//
+//	GET  /policies                      lists the registered policy names
+//	GET  /policies/{name}/capabilities  reports the optional interfaces a policy implements
+//	POST /policies/{name}/execute       runs a policy with the JSON body as input
+//
+// Errors are returned as a JSON error envelope with 400 for malformed
+// bodies, 404 for unknown policies and 500 for failed executions.
+func NewPolicyHTTPHandler(registry *PolicyRegistry) http.Handler {
+	return &policyHandler{registry: registry}
+}
+
+// ServeHTTP implements http.Handler
+func (h *policyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(r.URL.Path, "/")
+	segments := strings.Split(path, "/")
+
+	switch {
+	case path == "policies":
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, http.MethodGet)
+			return
+		}
+		h.list(w)
+	case len(segments) == 3 && segments[0] == "policies" && segments[2] == "execute" && segments[1] != "":
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w, http.MethodPost)
+			return
+		}
+		h.execute(w, r, segments[1])
+	case len(segments) == 3 && segments[0] == "policies" && segments[2] == "capabilities" && segments[1] != "":
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, http.MethodGet)
+			return
+		}
+		h.capabilities(w, segments[1])
+	default:
+		writeError(w, NewPolicyError(ErrCodeNotFound, fmt.Sprintf("no route for %s", r.URL.Path), nil))
+	}
+}
+
+func (h *policyHandler) list(w http.ResponseWriter) {
+	writeJSON(w, http.StatusOK, h.registry.ListByPhase())
+}
+
+func (h *policyHandler) capabilities(w http.ResponseWriter, name string) {
+	capabilities, ok := h.registry.Capabilities(name)
+	if !ok {
+		writeError(w, NewPolicyError(ErrCodeNotFound, fmt.Sprintf("%v: %s", ErrPolicyNotFound, name), nil))
+		return
+	}
+	writeJSON(w, http.StatusOK, capabilities)
+}
+
+func (h *policyHandler) execute(w http.ResponseWriter, r *http.Request, name string) {
+	policy, err := h.registry.GetOrError(name)
+	if err != nil {
+		writeError(w, NewPolicyError(ErrCodeNotFound, err.Error(), nil))
+		return
+	}
+
+	var input interface{}
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody))
+	if err = decoder.Decode(&input); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = errors.New("request body is empty")
+		}
+		writeError(w, NewPolicyError(ErrCodeInvalidInput, "malformed JSON body", err))
+		return
+	}
+	if decoder.More() {
+		writeError(w, NewPolicyError(ErrCodeInvalidInput, "malformed JSON body", errors.New("unexpected data after JSON value")))
+		return
+	}
+
+	execution := ExecutePolicy(r.Context(), policy, input, ExecuteOptions{
+		Logger:  h.registry.Logger(),
+		Metrics: h.registry.metrics,
+	})
+	if execution.Err != nil {
+		writeError(w, NewPolicyError(ErrCodeInternal, fmt.Sprintf("policy %s failed", name), execution.Err))
+		return
+	}
+	writeJSON(w, http.StatusOK, execution.Result)
+}
+
+func methodNotAllowed(w http.ResponseWriter, allowed string) {
+	w.Header().Set("Allow", allowed)
+	writeError(w, NewPolicyError(ErrCodeMethodNotAllowed, fmt.Sprintf("method not allowed, use %s", allowed), nil))
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// runServe implements the "serve" command
+func runServe(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	fs.SetOutput(out)
+	addr := fs.String("addr", ":8080", "address to listen on")
+	grpcAddr := fs.String("grpc-addr", "", "address to serve the PolicyEngine gRPC service on (empty to disable)")
+	metricsPath := fs.String("metrics-path", "/metrics", "path serving Prometheus metrics (empty to disable)")
+	setup := addSetupFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(out, "Usage: policy-engine serve [flags]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return fmt.Errorf("serve takes no arguments")
+	}
+	if err := setup.apply(registry); err != nil {
+		return err
+	}
+
+	var handler http.Handler = NewPolicyHTTPHandler(registry)
+	if *metricsPath != "" {
+		mux := http.NewServeMux()
+		mux.Handle(*metricsPath, newMetricsHandler(registry))
+		mux.Handle("/", handler)
+		handler = mux
+	}
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errc := make(chan error, 2)
+	if *grpcAddr != "" {
+		if err := startGRPC(*grpcAddr, registry, errc); err != nil {
+			return err
+		}
+		registry.Logger().Info("serving grpc", "addr", *grpcAddr)
+	}
+
+	registry.Logger().Info("serving", "policies", len(registry.List()), "addr", *addr)
+	go func() {
+		errc <- server.ListenAndServe()
+	}()
+	return <-errc
+}
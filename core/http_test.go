@@ -0,0 +1,256 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// serve sends a request to a policy HTTP handler for r and returns the
+// recorded response
+func serve(t *testing.T, r *PolicyRegistry, method, target, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	var req *http.Request
+	if body == "" {
+		req = httptest.NewRequest(method, target, nil)
+	} else {
+		req = httptest.NewRequest(method, target, strings.NewReader(body))
+	}
+	rec := httptest.NewRecorder()
+	NewPolicyHTTPHandler(r).ServeHTTP(rec, req)
+	return rec
+}
+
+// decodeBody decodes a JSON response body into a generic value
+func decodeBody(t *testing.T, rec *httptest.ResponseRecorder) interface{} {
+	t.Helper()
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+	var body interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decoding body %q: %v", rec.Body.String(), err)
+	}
+	return body
+}
+
+func TestHTTPListPolicies(t *testing.T) {
+	r := newTestRegistry()
+	for _, p := range []Policy{
+		&stubPolicy{name: "b"},
+		&stubPolicy{name: "a"},
+		&phasedPolicy{stubPolicy: stubPolicy{name: "z-pre"}, phase: PhasePre},
+	} {
+		if err := r.Register(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rec := serve(t, r, http.MethodGet, "/policies", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	want := []interface{}{"z-pre", "a", "b"}
+	if got := decodeBody(t, rec); !reflect.DeepEqual(got, want) {
+		t.Errorf("body = %v, want %v", got, want)
+	}
+
+	// An empty registry lists an empty array, not null
+	rec = serve(t, newTestRegistry(), http.MethodGet, "/policies/", "")
+	if got := decodeBody(t, rec); !reflect.DeepEqual(got, []interface{}{}) {
+		t.Errorf("empty registry body = %v, want []", got)
+	}
+}
+
+func TestHTTPExecute(t *testing.T) {
+	r := newTestRegistry()
+	double := &stubPolicy{name: "double", execute: func(_ context.Context, input interface{}) (interface{}, error) {
+		n := input.(map[string]interface{})["n"].(float64)
+		return &PolicyResult{Policy: "double", Action: "doubling", Status: "PASSED", Data: map[string]interface{}{"n": n * 2}}, nil
+	}}
+	if err := r.Register(double); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := serve(t, r, http.MethodPost, "/policies/double/execute", `{"n": 21}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	want := map[string]interface{}{
+		"policy": "double",
+		"action": "doubling",
+		"status": "PASSED",
+		"data":   map[string]interface{}{"n": 42.0},
+	}
+	if got := decodeBody(t, rec); !reflect.DeepEqual(got, want) {
+		t.Errorf("body = %v, want %v", got, want)
+	}
+}
+
+func TestHTTPRoutes(t *testing.T) {
+	r := newTestRegistry()
+	if err := r.Register(&stubPolicy{name: "echo"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		wantStatus int
+		wantAllow  string
+	}{
+		{"list with POST", http.MethodPost, "/policies", http.StatusMethodNotAllowed, http.MethodGet},
+		{"execute with GET", http.MethodGet, "/policies/echo/execute", http.StatusMethodNotAllowed, http.MethodPost},
+		{"unknown route", http.MethodGet, "/health", http.StatusNotFound, ""},
+		{"unknown action", http.MethodPost, "/policies/echo/run", http.StatusNotFound, ""},
+		{"empty name", http.MethodPost, "/policies//execute", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(t, r, tt.method, tt.target, "{}")
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if allow := rec.Header().Get("Allow"); allow != tt.wantAllow {
+				t.Errorf("Allow = %q, want %q", allow, tt.wantAllow)
+			}
+		})
+	}
+}
+
+// TestHTTPServer drives the handler through a real HTTP server
+func TestHTTPServer(t *testing.T) {
+	r := newTestRegistry()
+	if err := r.Register(&stubPolicy{name: "echo"}); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(NewPolicyHTTPHandler(r))
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/policies/echo/execute", "application/json", strings.NewReader(`{"a":[1,2]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var got interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{"a": []interface{}{1.0, 2.0}}
+	if resp.StatusCode != http.StatusOK || !reflect.DeepEqual(got, want) {
+		t.Errorf("response = %d %v, want 200 %v", resp.StatusCode, got, want)
+	}
+}
+
+func TestHTTPCapabilities(t *testing.T) {
+	r := newTestRegistry()
+	if err := r.Register(&describedPolicy{stubPolicy{name: "described"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := serve(t, r, http.MethodGet, "/policies/described/capabilities", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	want := map[string]interface{}{
+		"configurable":     true,
+		"phased":           false,
+		"prioritized":      false,
+		"described":        true,
+		"versioned":        true,
+		"tagged":           false,
+		"engine_versioned": true,
+	}
+	if got := decodeBody(t, rec); !reflect.DeepEqual(got, want) {
+		t.Errorf("body = %v, want %v", got, want)
+	}
+}
+
+func TestHTTPCapabilitiesErrors(t *testing.T) {
+	r := newTestRegistry()
+	if err := r.Register(&stubPolicy{name: "plain"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		wantStatus int
+		wantCode   string
+	}{
+		{"unknown policy", http.MethodGet, "/policies/missing/capabilities", http.StatusNotFound, ErrCodeNotFound},
+		{"wrong method", http.MethodPost, "/policies/plain/capabilities", http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed},
+		{"empty name", http.MethodGet, "/policies//capabilities", http.StatusNotFound, ErrCodeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(t, r, tt.method, tt.target, "")
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			body, _ := decodeBody(t, rec).(map[string]interface{})
+			envelope, _ := body["error"].(map[string]interface{})
+			if envelope["code"] != tt.wantCode {
+				t.Errorf("error code = %v, want %s", envelope["code"], tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestHTTPExecuteErrors(t *testing.T) {
+	r := newTestRegistry()
+	failing := &stubPolicy{
+		name: "failing",
+		execute: func(context.Context, interface{}) (interface{}, error) {
+			return nil, errors.New("boom")
+		},
+	}
+	for _, p := range []Policy{&stubPolicy{name: "echo"}, failing} {
+		if err := r.Register(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		target     string
+		body       string
+		wantStatus int
+		wantCode   string
+	}{
+		{"malformed body", "/policies/echo/execute", `{"a":`, http.StatusBadRequest, ErrCodeInvalidInput},
+		{"empty body", "/policies/echo/execute", "", http.StatusBadRequest, ErrCodeInvalidInput},
+		{"trailing data", "/policies/echo/execute", `{} {}`, http.StatusBadRequest, ErrCodeInvalidInput},
+		{"unknown policy", "/policies/missing/execute", `{}`, http.StatusNotFound, ErrCodeNotFound},
+		{"failed execution", "/policies/failing/execute", `{}`, http.StatusInternalServerError, ErrCodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(t, r, http.MethodPost, tt.target, tt.body)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			body, _ := decodeBody(t, rec).(map[string]interface{})
+			if len(body) != 1 {
+				t.Errorf("body = %v, want only an error envelope", body)
+			}
+			envelope, _ := body["error"].(map[string]interface{})
+			if envelope["code"] != tt.wantCode {
+				t.Errorf("error code = %v, want %s", envelope["code"], tt.wantCode)
+			}
+			if message, _ := envelope["message"].(string); message == "" {
+				t.Error("error message is empty")
+			}
+		})
+	}
+}
@@ -2,8 +2,22 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
 )
 
+// Execution phases, run in this order
+const (
+	PhasePre  = "pre"
+	PhaseMain = "main"
+	PhasePost = "post"
+)
+
+// phaseOrder lists the phases in execution order
+var phaseOrder = []string{PhasePre, PhaseMain, PhasePost}
+
 // Policy defines the interface that all policy plugins must implement
 type Policy interface {
 	// Name returns the unique identifier for this policy
@@ -16,38 +30,396 @@ type Policy interface {
 	Validate() error
 }
 
-// PolicyRegistry manages all registered policies
+// Configurable is implemented by policies that accept runtime configuration
+type Configurable interface {
+	// Configure applies the given configuration and validates the result
+	Configure(config map[string]interface{}) error
+}
+
+// Phased is implemented by policies that must run in a specific execution
+// phase. Policies that do not implement it run in the main phase.
+type Phased interface {
+	// Phase returns one of PhasePre, PhaseMain or PhasePost
+	Phase() string
+}
+
+// DefaultPriority is the priority of policies that do not set one. It is
+// the middle of the range, so policies can be ordered both before and
+// after the default.
+const DefaultPriority = 0
+
+// Prioritized is implemented by policies that set their execution order
+// within a phase. Lower priorities run first; policies with equal
+// priorities run in name order.
+type Prioritized interface {
+	// Priority returns the policy's position relative to DefaultPriority
+	Priority() int
+}
+
+// Wrapper is implemented by policies that decorate another policy, such
+// as CachingPolicy. The engine reads the optional interfaces, such as
+// Phased, Described or Configurable, from the innermost wrapped policy, so
+// a wrapped policy keeps its phase, priority, metadata, configuration and
+// required engine version.
+type Wrapper interface {
+	// Unwrap returns the wrapped policy
+	Unwrap() Policy
+}
+
+// unwrapPolicy returns the innermost policy wrapped by p, or p itself if
+// it is not a Wrapper. A policy registered through RegisterV2 unwraps to
+// the PolicyV2 it adapts, so the result is only used to look up optional
+// interfaces.
+func unwrapPolicy(p Policy) interface{} {
+	var inner interface{} = p
+	for {
+		switch wrapper := inner.(type) {
+		case Wrapper:
+			inner = wrapper.Unwrap()
+		case *v2Adapter:
+			inner = wrapper.Unwrap()
+		default:
+			return inner
+		}
+	}
+}
+
+// PolicyCapabilities reports which optional interfaces a policy implements
+type PolicyCapabilities struct {
+	Configurable    bool `json:"configurable"`
+	Phased          bool `json:"phased"`
+	Prioritized     bool `json:"prioritized"`
+	Described       bool `json:"described"`
+	Versioned       bool `json:"versioned"`
+	Tagged          bool `json:"tagged"`
+	EngineVersioned bool `json:"engine_versioned"`
+}
+
+// PhaseOf returns the execution phase of a policy
+func PhaseOf(p Policy) string {
+	if phased, ok := unwrapPolicy(p).(Phased); ok {
+		return phased.Phase()
+	}
+	return PhaseMain
+}
+
+// PriorityOf returns the priority a policy declares, or DefaultPriority
+func PriorityOf(p Policy) int {
+	if prioritized, ok := unwrapPolicy(p).(Prioritized); ok {
+		return prioritized.Priority()
+	}
+	return DefaultPriority
+}
+
+// ErrDuplicatePolicy is returned by Register when a policy with the same
+// name is already registered
+var ErrDuplicatePolicy = errors.New("duplicate policy")
+
+// ErrPolicyNotFound is returned by GetOrError when no policy has the
+// requested name
+var ErrPolicyNotFound = errors.New("policy not found")
+
+// PolicyRegistry manages all registered policies. It is safe for
+// concurrent use.
 type PolicyRegistry struct {
-	policies map[string]Policy
+	mu         sync.RWMutex
+	policies   map[string]Policy
+	disabled   map[string]bool
+	priorities map[string]int
+	logger     Logger
+	metrics    *MetricsCollector
 }
 
-// NewPolicyRegistry creates a new policy registry
+// NewPolicyRegistry creates a new policy registry that logs as JSON to
+// stdout
 func NewPolicyRegistry() *PolicyRegistry {
 	return &PolicyRegistry{
-		policies: make(map[string]Policy),
+		policies:   make(map[string]Policy),
+		disabled:   make(map[string]bool),
+		priorities: make(map[string]int),
+		logger:     defaultLogger(),
+		metrics:    NewMetricsCollector(),
 	}
 }
 
-// Register adds a policy to the registry
+// Metrics returns execution statistics keyed by policy name for the
+// executions run through ExecuteAll and the HTTP API
+func (r *PolicyRegistry) Metrics() map[string]PolicyStats {
+	return r.metrics.Metrics()
+}
+
+// SetLogger replaces the registry's logger; nil discards all messages
+func (r *PolicyRegistry) SetLogger(logger Logger) {
+	if logger == nil {
+		logger = NopLogger()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.logger = logger
+}
+
+// Logger returns the registry's logger
+func (r *PolicyRegistry) Logger() Logger {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.logger
+}
+
+// Register adds a policy to the registry. It fails with ErrDuplicatePolicy
+// if the name is already taken, and with ErrIncompatibleEngine if the
+// policy requires an engine version other than EngineVersion.
 func (r *PolicyRegistry) Register(p Policy) error {
+	return r.register(p, false)
+}
+
+// RegisterForce adds a policy to the registry, replacing any policy already
+// registered under the same name
+func (r *PolicyRegistry) RegisterForce(p Policy) error {
+	return r.register(p, true)
+}
+
+func (r *PolicyRegistry) register(p Policy, replace bool) error {
 	if err := p.Validate(); err != nil {
 		return err
 	}
+	if phase := PhaseOf(p); !validPhase(phase) {
+		return fmt.Errorf("invalid phase %q for policy %s", phase, p.Name())
+	}
+	if err := checkEngineVersion(p); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, exists := r.policies[p.Name()]
+	if exists && !replace {
+		return fmt.Errorf("%w: %s", ErrDuplicatePolicy, p.Name())
+	}
 	r.policies[p.Name()] = p
+	r.logger.Info("registered", "policy", p.Name(), "phase", PhaseOf(p), "replaced", exists)
+	return nil
+}
+
+// Unregister removes a policy and reports whether it was registered
+func (r *PolicyRegistry) Unregister(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, exists := r.policies[name]
+	delete(r.policies, name)
+	delete(r.disabled, name)
+	delete(r.priorities, name)
+	return exists
+}
+
+// Clear removes all registered policies
+func (r *PolicyRegistry) Clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.policies = make(map[string]Policy)
+	r.disabled = make(map[string]bool)
+	r.priorities = make(map[string]int)
+}
+
+// SetPriority overrides the priority a registered policy declares
+func (r *PolicyRegistry) SetPriority(name string, priority int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.policies[name]; !exists {
+		return fmt.Errorf("%w: %s", ErrPolicyNotFound, name)
+	}
+	r.priorities[name] = priority
 	return nil
 }
 
+// Priority returns the effective priority of a registered policy: the
+// value set with SetPriority, else the one the policy declares
+func (r *PolicyRegistry) Priority(name string) (int, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if _, exists := r.policies[name]; !exists {
+		return 0, false
+	}
+	return r.priorityLocked(name), true
+}
+
+// priorityLocked returns the effective priority of a registered policy.
+// The caller must hold r.mu.
+func (r *PolicyRegistry) priorityLocked(name string) int {
+	if priority, ok := r.priorities[name]; ok {
+		return priority
+	}
+	return PriorityOf(r.policies[name])
+}
+
+// sortLocked orders names by priority, then name. The caller must hold
+// r.mu.
+func (r *PolicyRegistry) sortLocked(names []string) {
+	priorities := make(map[string]int, len(names))
+	for _, name := range names {
+		priorities[name] = r.priorityLocked(name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if priorities[names[i]] != priorities[names[j]] {
+			return priorities[names[i]] < priorities[names[j]]
+		}
+		return names[i] < names[j]
+	})
+}
+
+// Disable keeps a policy registered but stops ExecuteAll and the CLI from
+// running it until Enable is called. Disabling a policy twice has no
+// further effect.
+func (r *PolicyRegistry) Disable(name string) error {
+	return r.setEnabled(name, false)
+}
+
+// Enable lets a disabled policy run again
+func (r *PolicyRegistry) Enable(name string) error {
+	return r.setEnabled(name, true)
+}
+
+func (r *PolicyRegistry) setEnabled(name string, enabled bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.policies[name]; !exists {
+		return fmt.Errorf("%w: %s", ErrPolicyNotFound, name)
+	}
+	if enabled {
+		delete(r.disabled, name)
+	} else {
+		r.disabled[name] = true
+	}
+	return nil
+}
+
+// IsEnabled reports whether a policy is registered and not disabled
+func (r *PolicyRegistry) IsEnabled(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, exists := r.policies[name]
+	return exists && !r.disabled[name]
+}
+
 // Get retrieves a policy by name
 func (r *PolicyRegistry) Get(name string) (Policy, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	p, ok := r.policies[name]
 	return p, ok
 }
 
-// List returns all registered policy names
+// GetOrError retrieves a policy by name, failing with ErrPolicyNotFound
+// if it is not registered
+func (r *PolicyRegistry) GetOrError(name string) (Policy, error) {
+	p, ok := r.Get(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrPolicyNotFound, name)
+	}
+	return p, nil
+}
+
+// List returns all registered policy names, including disabled ones,
+// sorted by priority and then name
 func (r *PolicyRegistry) List() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	names := make([]string, 0, len(r.policies))
 	for name := range r.policies {
 		names = append(names, name)
 	}
+	r.sortLocked(names)
+	return names
+}
+
+// ListEnabled returns the names of registered policies that are not
+// disabled, sorted by priority and then name
+func (r *PolicyRegistry) ListEnabled() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.policies))
+	for name := range r.policies {
+		if !r.disabled[name] {
+			names = append(names, name)
+		}
+	}
+	r.sortLocked(names)
+	return names
+}
+
+// Capabilities reports which optional interfaces a registered policy
+// implements, looking through wrappers to the policy they wrap
+func (r *PolicyRegistry) Capabilities(name string) (PolicyCapabilities, bool) {
+	p, ok := r.Get(name)
+	if !ok {
+		return PolicyCapabilities{}, false
+	}
+	inner := unwrapPolicy(p)
+
+	_, configurable := inner.(Configurable)
+	_, phased := inner.(Phased)
+	_, prioritized := inner.(Prioritized)
+	_, described := inner.(Described)
+	_, versioned := inner.(Versioned)
+	_, tagged := inner.(Tagged)
+	_, engineVersioned := inner.(EngineVersioned)
+	return PolicyCapabilities{
+		Configurable:    configurable,
+		Phased:          phased,
+		Prioritized:     prioritized,
+		Described:       described,
+		Versioned:       versioned,
+		Tagged:          tagged,
+		EngineVersioned: engineVersioned,
+	}, true
+}
+
+// ValidateAll calls Validate on every registered policy, in name order,
+// and returns the failures joined into one error, each prefixed with the
+// policy name. Nothing is executed.
+func (r *PolicyRegistry) ValidateAll() error {
+	names := r.List()
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		p, ok := r.Get(name)
+		if !ok {
+			// Unregistered since the list was taken
+			continue
+		}
+		if err := p.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("policy %s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ListByPhase returns all registered policy names in execution order: every
+// pre policy, then main, then post. Within each phase names are sorted by
+// priority and then name.
+func (r *PolicyRegistry) ListByPhase() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	byPhase := make(map[string][]string, len(phaseOrder))
+	for name, p := range r.policies {
+		phase := PhaseOf(p)
+		byPhase[phase] = append(byPhase[phase], name)
+	}
+
+	names := make([]string, 0, len(r.policies))
+	for _, phase := range phaseOrder {
+		r.sortLocked(byPhase[phase])
+		names = append(names, byPhase[phase]...)
+	}
 	return names
 }
+
+func validPhase(phase string) bool {
+	for _, known := range phaseOrder {
+		if phase == known {
+			return true
+		}
+	}
+	return false
+}
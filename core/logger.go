@@ -0,0 +1,71 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+)
+
+// Logger receives structured log messages. Fields are alternating
+// key-value pairs, e.g. Info("executed", "policy", name).
+type Logger interface {
+	Debug(msg string, fields ...interface{})
+	Info(msg string, fields ...interface{})
+	Error(msg string, fields ...interface{})
+}
+
+// NewJSONLogger returns a Logger that writes one JSON object per message
+// to w, with "time", "level" and "msg" keys followed by the fields. Debug
+// messages are dropped unless debug is true.
+func NewJSONLogger(w io.Writer, debug bool) Logger {
+	level := slog.LevelInfo
+	if debug {
+		level = slog.LevelDebug
+	}
+	handler := slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level})
+	return &jsonLogger{logger: slog.New(handler)}
+}
+
+type jsonLogger struct {
+	logger *slog.Logger
+}
+
+func (l *jsonLogger) Debug(msg string, fields ...interface{}) { l.logger.Debug(msg, fields...) }
+func (l *jsonLogger) Info(msg string, fields ...interface{})  { l.logger.Info(msg, fields...) }
+func (l *jsonLogger) Error(msg string, fields ...interface{}) { l.logger.Error(msg, fields...) }
+
+// NopLogger returns a Logger that discards every message
+func NopLogger() Logger {
+	return nopLogger{}
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...interface{}) {}
+func (nopLogger) Info(string, ...interface{})  {}
+func (nopLogger) Error(string, ...interface{}) {}
+
+// defaultLogger is used by registries that have not been given a logger
+func defaultLogger() Logger {
+	return NewJSONLogger(os.Stdout, false)
+}
+
+// logExecution records the outcome of an ExecutePolicy call
+func logExecution(logger Logger, execution *ExecutionResult) {
+	if execution.Err != nil {
+		fields := []interface{}{"policy", execution.Policy, "duration", execution.Duration, "error", execution.Error}
+		var panicErr *PanicError
+		if errors.As(execution.Err, &panicErr) {
+			fields = append(fields, "stack", string(panicErr.Stack))
+		}
+		logger.Error("execution failed", fields...)
+		return
+	}
+
+	fields := []interface{}{"policy", execution.Policy, "duration", execution.Duration}
+	if usage := execution.Usage; usage != nil {
+		fields = append(fields, "allocs", usage.Allocs, "bytes", usage.Bytes, "cpu_time", usage.CPUTime)
+	}
+	logger.Info("executed", fields...)
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// logEntries decodes the JSON lines a JSON logger wrote to buf
+func logEntries(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var entries []map[string]interface{}
+	decoder := json.NewDecoder(buf)
+	for decoder.More() {
+		var entry map[string]interface{}
+		if err := decoder.Decode(&entry); err != nil {
+			t.Fatalf("decoding log line: %v", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// findEntry returns the first entry with the given message, or nil
+func findEntry(entries []map[string]interface{}, msg string) map[string]interface{} {
+	for _, entry := range entries {
+		if entry["msg"] == msg {
+			return entry
+		}
+	}
+	return nil
+}
+
+func TestExecutePolicyLogsExecution(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewJSONLogger(&buf, false)
+
+	ExecutePolicy(context.Background(), &stubPolicy{name: "echo"}, "input", ExecuteOptions{Logger: logger})
+	ExecutePolicy(context.Background(), failing("broken", errors.New("boom")), "input", ExecuteOptions{Logger: logger})
+
+	entries := logEntries(t, &buf)
+	executed := findEntry(entries, "executed")
+	if executed == nil {
+		t.Fatalf("no executed entry in %v", entries)
+	}
+	if executed["policy"] != "echo" || executed["level"] != "INFO" {
+		t.Errorf("executed entry = %v, want policy echo at INFO", executed)
+	}
+	for _, key := range []string{"time", "duration"} {
+		if _, ok := executed[key]; !ok {
+			t.Errorf("executed entry has no %s field: %v", key, executed)
+		}
+	}
+
+	failed := findEntry(entries, "execution failed")
+	if failed == nil {
+		t.Fatalf("no execution failed entry in %v", entries)
+	}
+	if failed["policy"] != "broken" || failed["error"] != "boom" || failed["level"] != "ERROR" {
+		t.Errorf("execution failed entry = %v, want policy broken, error boom at ERROR", failed)
+	}
+}
+
+func TestRegistryLogger(t *testing.T) {
+	var buf bytes.Buffer
+	r := NewPolicyRegistry()
+	r.SetLogger(NewJSONLogger(&buf, false))
+
+	if err := r.Register(&stubPolicy{name: "echo"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.ExecuteAll(context.Background(), "input"); err != nil {
+		t.Fatal(err)
+	}
+
+	entries := logEntries(t, &buf)
+	if registered := findEntry(entries, "registered"); registered == nil || registered["policy"] != "echo" {
+		t.Errorf("registered entry = %v, want policy echo", registered)
+	}
+	if executed := findEntry(entries, "executed"); executed == nil || executed["policy"] != "echo" {
+		t.Errorf("executed entry = %v, want policy echo", executed)
+	}
+
+	// A nil logger discards messages instead of panicking
+	r.SetLogger(nil)
+	if _, err := r.ExecuteAll(context.Background(), "input"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestJSONLoggerDebug(t *testing.T) {
+	tests := []struct {
+		debug bool
+		want  int
+	}{
+		{false, 1},
+		{true, 2},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		logger := NewJSONLogger(&buf, tt.debug)
+		logger.Debug("details", "k", "v")
+		logger.Info("summary")
+		if entries := logEntries(t, &buf); len(entries) != tt.want {
+			t.Errorf("debug %v: wrote %d entries, want %d", tt.debug, len(entries), tt.want)
+		}
+	}
+}
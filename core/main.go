@@ -3,60 +3,227 @@ package main
 import (
 	"context"
 	"encoding/json"
-	"log"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
 	"os"
+	"strings"
 )
 
-var registry = NewPolicyRegistry()
+// registry holds the policies the binary runs. Subcommands print their
+// results to stdout, so when one is given the registry logs to stderr,
+// starting with the registrations made by imports.go.
+var registry = newRegistry(os.Args[1:])
+
+// newRegistry returns a registry logging to logOutput(args)
+func newRegistry(args []string) *PolicyRegistry {
+	r := NewPolicyRegistry()
+	r.SetLogger(NewJSONLogger(logOutput(args), false))
+	return r
+}
+
+// logOutput returns stderr when args start with a subcommand and stdout
+// otherwise. The demo loop takes no positional arguments, so a leading
+// argument that is not a flag always names a subcommand, or is rejected.
+func logOutput(args []string) io.Writer {
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		return os.Stderr
+	}
+	return os.Stdout
+}
 
 // RegisterPolicy is called by the generated imports.go to register policies
 func RegisterPolicy(p Policy) {
 	if err := registry.Register(p); err != nil {
-		log.Fatalf("Failed to register policy %s: %v", p.Name(), err)
+		fatal("registration failed", "policy", p.Name(), "error", err.Error())
 	}
-	log.Printf("Registered policy: %s", p.Name())
+}
+
+// fatal logs an error through the registry's logger and exits
+func fatal(msg string, fields ...interface{}) {
+	registry.Logger().Error(msg, fields...)
+	os.Exit(1)
 }
 
 func main() {
-	log.Println("Policy Engine Starting...")
+	if handled, err := dispatch(os.Args[1:], os.Stdin, os.Stdout); handled {
+		if err != nil {
+			fatal("command failed", "command", os.Args[1], "error", err.Error())
+		}
+		return
+	}
+
+	// Without a subcommand, run every policy against the demo input
+
+	inputFlag := flag.String("input", "", "JSON file holding the input object, or - to read it from stdin (default: demo input)")
+	setup := addSetupFlags(flag.CommandLine)
+	mergeFlag := flag.String("merge", "", "deep-merge the outputs of all policies into one object using strategy: last-wins or error")
+	timeoutFlag := flag.Duration("timeout", 0, "maximum run time for each policy, e.g. 5s (0 for no limit)")
+	usageFlag := flag.Bool("usage", false, "report heap allocations and CPU time for each policy")
+	determinismFlag := flag.Bool("check-determinism", false, "run each policy twice and fail it if the results differ")
+	summaryFlag := flag.Bool("summary", false, "print a summary of each policy's status and the fields it changed")
+	debugFlag := flag.Bool("debug", false, "include debug messages in the log")
+	validateOnlyFlag := flag.Bool("validate-only", false, "validate every registered policy and exit without executing any")
+	flag.Parse()
+	if flag.NArg() > 0 {
+		fatal("unknown command", "command", flag.Arg(0))
+	}
+
+	if *debugFlag {
+		registry.SetLogger(NewJSONLogger(os.Stdout, true))
+	}
+	logger := registry.Logger()
+
+	var mergeStrategy MergeStrategy
+	if *mergeFlag != "" {
+		strategy, err := ParseMergeStrategy(*mergeFlag)
+		if err != nil {
+			fatal("invalid -merge flag", "error", err.Error())
+		}
+		mergeStrategy = strategy
+	}
+
+	input, err := loadInput(*inputFlag, os.Stdin)
+	if err != nil {
+		fatal("invalid input", "error", err.Error())
+	}
 
-	// List all registered policies
-	policies := registry.List()
-	log.Printf("Loaded %d policies: %v", len(policies), policies)
+	logger.Info("policy engine starting")
+
+	if err := setup.apply(registry); err != nil {
+		fatal("setup failed", "error", err.Error())
+	}
+
+	// List all registered policies in phase order
+	policies := registry.ListByPhase()
+	logger.Info("loaded policies", "count", len(policies), "policies", policies)
+
+	if *validateOnlyFlag {
+		if err := registry.ValidateAll(); err != nil {
+			fatal("validation failed", "error", err.Error())
+		}
+		logger.Info("all policies valid", "count", len(policies))
+		return
+	}
 
 	if len(policies) == 0 {
-		log.Println("Warning: No policies registered")
+		logger.Info("no policies registered")
 		return
 	}
 
 	// Example: Execute all policies with sample input
 	ctx := context.Background()
-	input := map[string]interface{}{
-		"message": "Hello from policy engine",
-		"data":    []string{"item1", "item2", "item3"},
+	opts := ExecuteOptions{
+		Timeout:      *timeoutFlag,
+		CollectUsage: *usageFlag,
+		Logger:       logger,
 	}
 
-	log.Println("\nExecuting policies...")
+	results := make(map[string]interface{}, len(policies))
+	var stages []StageSummary
+
 	for _, name := range policies {
+		if !registry.IsEnabled(name) {
+			logger.Info("skipped disabled policy", "policy", name)
+			continue
+		}
 		policy, _ := registry.Get(name)
-		log.Printf("\n--- Executing policy: %s ---", name)
+		logger.Debug("executing", "policy", name, "phase", PhaseOf(policy), "description", MetadataOf(policy).Description)
 
-		result, err := policy.Execute(ctx, input)
+		if *determinismFlag {
+			policy = NewDeterminismPolicy(policy)
+		}
+		execution := ExecutePolicy(ctx, policy, input, opts)
+		result, err := execution.Result, execution.Err
+		if *summaryFlag {
+			stages = append(stages, SummarizeStage(policy, input, result, err))
+		}
 		if err != nil {
-			log.Printf("Error executing policy %s: %v", name, err)
 			continue
 		}
 
-		// Pretty print the result
-		resultJSON, _ := json.MarshalIndent(result, "", "  ")
-		log.Printf("Result: %s", string(resultJSON))
+		results[name] = result
+		logger.Info("result", "policy", name, "result", result)
 	}
 
-	log.Println("\nPolicy Engine Completed Successfully")
+	if mergeStrategy != "" {
+		merged, err := MergeResults(policies, results, mergeStrategy)
+		if err != nil {
+			fatal("failed to merge results", "error", err.Error())
+		}
+		logger.Info("merged result", "result", merged)
+	}
+
+	if *summaryFlag {
+		var summary strings.Builder
+		WriteRunSummary(&summary, stages)
+		logger.Info("run summary", "summary", summary.String())
+	}
+
+	logger.Info("policy engine completed")
 }
 
-func init() {
-	// Configure logging
-	log.SetOutput(os.Stdout)
-	log.SetFlags(log.Ldate | log.Ltime | log.Lshortfile)
+// loadInput reads the input object from the file at path, or from stdin
+// when path is "-". An empty path selects the demo input.
+func loadInput(path string, stdin io.Reader) (map[string]interface{}, error) {
+	var r io.Reader
+	switch path {
+	case "":
+		return sampleInput(), nil
+	case "-":
+		r = stdin
+		path = "stdin"
+	default:
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var value interface{}
+	decoder := json.NewDecoder(r)
+	if err := decoder.Decode(&value); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%s: no JSON input", path)
+		}
+		return nil, fmt.Errorf("%s: malformed JSON: %w", path, err)
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("%s: unexpected data after JSON value", path)
+	}
+
+	input, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: input must be a JSON object, got %s", path, jsonKind(value))
+	}
+	return input, nil
+}
+
+// jsonKind names the JSON type of a decoded value
+func jsonKind(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// sampleInput returns the demo input used when no real input is supplied
+func sampleInput() map[string]interface{} {
+	return map[string]interface{}{
+		"message": "Hello from policy engine",
+		"data":    []string{"item1", "item2", "item3"},
+	}
 }
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadInputFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "input.json")
+	if err := os.WriteFile(path, []byte(`{"user": "ada", "age": 36}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	input, err := loadInput(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]interface{}{"user": "ada", "age": 36.0}; !reflect.DeepEqual(input, want) {
+		t.Errorf("input = %v, want %v", input, want)
+	}
+
+	if _, err := loadInput(filepath.Join(t.TempDir(), "missing.json"), nil); !os.IsNotExist(err) {
+		t.Errorf("missing file error = %v, want not exist", err)
+	}
+}
+
+func TestLoadInputStdin(t *testing.T) {
+	input, err := loadInput("-", strings.NewReader("{\"k\": [1, 2]}\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]interface{}{"k": []interface{}{1.0, 2.0}}; !reflect.DeepEqual(input, want) {
+		t.Errorf("input = %v, want %v", input, want)
+	}
+}
+
+func TestLoadInputDemo(t *testing.T) {
+	input, err := loadInput("", strings.NewReader("ignored"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(input, sampleInput()) {
+		t.Errorf("input = %v, want the demo input", input)
+	}
+}
+
+func TestLoadInputInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{"empty", "", "stdin: no JSON input"},
+		{"malformed", `{"k": `, "stdin: malformed JSON"},
+		{"trailing data", `{} {}`, "stdin: unexpected data after JSON value"},
+		{"array", `[1, 2]`, "stdin: input must be a JSON object, got array"},
+		{"string", `"text"`, "stdin: input must be a JSON object, got string"},
+		{"number", `7`, "stdin: input must be a JSON object, got number"},
+		{"null", `null`, "stdin: input must be a JSON object, got null"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadInput("-", strings.NewReader(tt.data))
+			if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// MergeStrategy controls how MergeResults resolves conflicting values
+type MergeStrategy string
+
+const (
+	// MergeLastWins keeps the value from the policy that ran last
+	MergeLastWins MergeStrategy = "last-wins"
+
+	// MergeError fails the merge on the first conflict
+	MergeError MergeStrategy = "error"
+)
+
+// ErrMergeConflict is returned by MergeResults under MergeError when two
+// policies produce different values for the same field
+var ErrMergeConflict = errors.New("merge conflict")
+
+// ParseMergeStrategy validates a strategy name
+func ParseMergeStrategy(s string) (MergeStrategy, error) {
+	switch strategy := MergeStrategy(s); strategy {
+	case MergeLastWins, MergeError:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("unknown merge strategy %q, expected %s or %s", s, MergeLastWins, MergeError)
+	}
+}
+
+// MergeResults deep-merges the outputs of several policies into a single
+// object, visiting them in the given order. Only the output object of each
+// result is merged, so the policy, action and status every result carries
+// never conflict; results without an output, such as those of policies
+// that only check their input, are skipped. Nested objects are merged key
+// by key; any other value that differs between policies is a conflict,
+// resolved according to strategy.
+func MergeResults(order []string, results map[string]interface{}, strategy MergeStrategy) (map[string]interface{}, error) {
+	merged := make(map[string]interface{})
+	owners := make(map[string]string)
+
+	for _, name := range order {
+		result, ok := results[name]
+		if !ok {
+			continue
+		}
+
+		output, ok := resultOutput(result)
+		if !ok {
+			continue
+		}
+
+		if err := mergeInto(merged, output, nil, name, owners, strategy); err != nil {
+			return nil, err
+		}
+	}
+
+	return merged, nil
+}
+
+// mergeInto merges src into dst, tracking which policy last set each leaf
+// path so conflicts can name both parties
+func mergeInto(dst, src map[string]interface{}, path []string, name string, owners map[string]string, strategy MergeStrategy) error {
+	for key, value := range src {
+		keyPath := append(path[:len(path):len(path)], key)
+		joined := strings.Join(keyPath, ".")
+
+		srcObj, srcIsObj := value.(map[string]interface{})
+
+		existing, exists := dst[key]
+		if !exists && srcIsObj {
+			// Merge into a fresh object so nested leaves get owners and the
+			// policy's own result is never modified
+			existing = make(map[string]interface{}, len(srcObj))
+			dst[key] = existing
+		} else if !exists {
+			dst[key] = value
+			owners[joined] = name
+			continue
+		}
+
+		dstObj, dstIsObj := existing.(map[string]interface{})
+		if dstIsObj && srcIsObj {
+			if err := mergeInto(dstObj, srcObj, keyPath, name, owners, strategy); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if reflect.DeepEqual(existing, value) {
+			continue
+		}
+
+		if strategy == MergeError {
+			return fmt.Errorf("%w at %s: policies %s and %s disagree", ErrMergeConflict, joined, owners[joined], name)
+		}
+		dst[key] = copyValue(value)
+		owners[joined] = name
+	}
+
+	return nil
+}
+
+// copyValue copies nested objects so later merges never modify a policy's
+// own result
+func copyValue(value interface{}) interface{} {
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return value
+	}
+	out := make(map[string]interface{}, len(obj))
+	for key, v := range obj {
+		out[key] = copyValue(v)
+	}
+	return out
+}
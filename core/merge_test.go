@@ -0,0 +1,148 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMergeResults(t *testing.T) {
+	// Every result carries its own policy, action and status, which must not
+	// count as conflicts
+	enrich := &PolicyResult{
+		Policy: "enrich",
+		Action: "enrichment",
+		Status: "PASSED",
+		Output: map[string]interface{}{
+			"id":   "42",
+			"user": map[string]interface{}{"name": "ada"},
+		},
+	}
+	geo := &PolicyResult{
+		Policy: "geo",
+		Action: "geolocation",
+		Status: "PASSED",
+		Output: map[string]interface{}{
+			"id":   "42",
+			"user": map[string]interface{}{"country": "LK"},
+		},
+	}
+	rename := map[string]interface{}{
+		"policy": "rename",
+		"output": map[string]interface{}{
+			"user": map[string]interface{}{"name": "grace"},
+		},
+	}
+	check := &PolicyResult{Policy: "check", Action: "validation", Status: "FAILED"}
+
+	tests := []struct {
+		name     string
+		order    []string
+		strategy MergeStrategy
+		want     map[string]interface{}
+		// wantErr is a substring of the expected error
+		wantErr string
+	}{
+		{
+			name:     "non-conflicting outputs",
+			order:    []string{"enrich", "geo"},
+			strategy: MergeError,
+			want: map[string]interface{}{
+				"id":   "42",
+				"user": map[string]interface{}{"name": "ada", "country": "LK"},
+			},
+		},
+		{
+			name:     "results without output are skipped",
+			order:    []string{"check", "enrich", "missing"},
+			strategy: MergeError,
+			want: map[string]interface{}{
+				"id":   "42",
+				"user": map[string]interface{}{"name": "ada"},
+			},
+		},
+		{
+			name:     "nothing to merge",
+			order:    []string{"check"},
+			strategy: MergeError,
+			want:     map[string]interface{}{},
+		},
+		{
+			name:     "conflict resolved by last policy",
+			order:    []string{"enrich", "geo", "rename"},
+			strategy: MergeLastWins,
+			want: map[string]interface{}{
+				"id":   "42",
+				"user": map[string]interface{}{"name": "grace", "country": "LK"},
+			},
+		},
+		{
+			name:     "order decides the winner",
+			order:    []string{"rename", "geo", "enrich"},
+			strategy: MergeLastWins,
+			want: map[string]interface{}{
+				"id":   "42",
+				"user": map[string]interface{}{"name": "ada", "country": "LK"},
+			},
+		},
+		{
+			name:     "conflict fails under error",
+			order:    []string{"enrich", "geo", "rename"},
+			strategy: MergeError,
+			wantErr:  "at user.name: policies enrich and rename disagree",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := map[string]interface{}{"enrich": enrich, "geo": geo, "rename": rename, "check": check}
+			got, err := MergeResults(tt.order, results, tt.strategy)
+			if tt.wantErr != "" {
+				if !errors.Is(err, ErrMergeConflict) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("MergeResults error = %v, want ErrMergeConflict %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("MergeResults: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MergeResults = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeResultsLeavesResultsUntouched(t *testing.T) {
+	first := map[string]interface{}{"output": map[string]interface{}{"user": map[string]interface{}{"name": "ada"}}}
+	second := map[string]interface{}{"output": map[string]interface{}{"user": map[string]interface{}{"name": "grace", "age": 36}}}
+
+	if _, err := MergeResults([]string{"first", "second"}, map[string]interface{}{"first": first, "second": second}, MergeLastWins); err != nil {
+		t.Fatalf("MergeResults: %v", err)
+	}
+	want := map[string]interface{}{"output": map[string]interface{}{"user": map[string]interface{}{"name": "ada"}}}
+	if !reflect.DeepEqual(first, want) {
+		t.Errorf("first result modified: %v", first)
+	}
+}
+
+func TestParseMergeStrategy(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    MergeStrategy
+		wantErr bool
+	}{
+		{"last-wins", MergeLastWins, false},
+		{"error", MergeError, false},
+		{"first-wins", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseMergeStrategy(tt.in)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("ParseMergeStrategy(%q) = %q, %v; want %q, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+// Described is implemented by policies that provide a human-readable
+// description
+type Described interface {
+	Description() string
+}
+
+// Versioned is implemented by policies that report their version
+type Versioned interface {
+	Version() string
+}
+
+// Tagged is implemented by policies that carry category tags
+type Tagged interface {
+	Tags() []string
+}
+
+// PolicyMetadata describes a registered policy for operators. Policies
+// cannot import the core package, so metadata is gathered from the optional
+// Described, Versioned and Tagged interfaces, which use only builtin types;
+// policies that implement none of them get empty metadata and keep working.
+type PolicyMetadata struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Version     string   `json:"version,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Phase       string   `json:"phase"`
+	Priority    int      `json:"priority"`
+}
+
+// MetadataOf collects the metadata a policy provides. A Wrapper reports
+// the metadata of the policy it wraps.
+func MetadataOf(p Policy) PolicyMetadata {
+	metadata := PolicyMetadata{
+		Name:     p.Name(),
+		Phase:    PhaseOf(p),
+		Priority: PriorityOf(p),
+	}
+	inner := unwrapPolicy(p)
+	if d, ok := inner.(Described); ok {
+		metadata.Description = d.Description()
+	}
+	if v, ok := inner.(Versioned); ok {
+		metadata.Version = v.Version()
+	}
+	if t, ok := inner.(Tagged); ok {
+		metadata.Tags = append([]string(nil), t.Tags()...)
+	}
+	return metadata
+}
+
+// ListWithMetadata returns the metadata of every registered policy, sorted
+// by priority and then name, as List orders names. Priority is the
+// effective one, including SetPriority overrides.
+func (r *PolicyRegistry) ListWithMetadata() []PolicyMetadata {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.policies))
+	for name := range r.policies {
+		names = append(names, name)
+	}
+	r.sortLocked(names)
+
+	list := make([]PolicyMetadata, 0, len(names))
+	for _, name := range names {
+		metadata := MetadataOf(r.policies[name])
+		metadata.Priority = r.priorityLocked(name)
+		list = append(list, metadata)
+	}
+	return list
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// taggedPolicy implements Described, Versioned, Tagged, Phased and
+// Prioritized
+type taggedPolicy struct {
+	stubPolicy
+	tags     []string
+	priority int
+}
+
+func (p *taggedPolicy) Description() string { return "tags things" }
+func (p *taggedPolicy) Version() string     { return "2.0.0" }
+func (p *taggedPolicy) Tags() []string      { return p.tags }
+func (p *taggedPolicy) Phase() string       { return PhasePre }
+func (p *taggedPolicy) Priority() int       { return p.priority }
+
+func TestMetadataOf(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy Policy
+		want   PolicyMetadata
+	}{
+		{
+			name:   "no optional interfaces",
+			policy: &stubPolicy{name: "plain"},
+			want:   PolicyMetadata{Name: "plain", Phase: PhaseMain, Priority: DefaultPriority},
+		},
+		{
+			name:   "described only",
+			policy: &describedPolicy{stubPolicy{name: "described"}},
+			want:   PolicyMetadata{Name: "described", Description: "described", Version: "1.2.3", Phase: PhaseMain},
+		},
+		{
+			name:   "all metadata",
+			policy: &taggedPolicy{stubPolicy: stubPolicy{name: "tagged"}, tags: []string{"a", "b"}, priority: 5},
+			want: PolicyMetadata{
+				Name:        "tagged",
+				Description: "tags things",
+				Version:     "2.0.0",
+				Tags:        []string{"a", "b"},
+				Phase:       PhasePre,
+				Priority:    5,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MetadataOf(tt.policy); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MetadataOf = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMetadataOfCopiesTags(t *testing.T) {
+	p := &taggedPolicy{stubPolicy: stubPolicy{name: "tagged"}, tags: []string{"a"}}
+	MetadataOf(p).Tags[0] = "changed"
+	if p.tags[0] != "a" {
+		t.Error("changing the returned tags changed the policy's tags")
+	}
+}
+
+func TestListWithMetadata(t *testing.T) {
+	r := newTestRegistry()
+	for _, p := range []Policy{
+		&stubPolicy{name: "plain"},
+		&describedPolicy{stubPolicy{name: "described"}},
+		&taggedPolicy{stubPolicy: stubPolicy{name: "tagged"}, tags: []string{"x"}},
+	} {
+		if err := r.Register(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := r.SetPriority("plain", 7); err != nil {
+		t.Fatal(err)
+	}
+
+	list := r.ListWithMetadata()
+	if len(list) != 3 {
+		t.Fatalf("ListWithMetadata returned %d entries, want 3", len(list))
+	}
+	byName := make(map[string]PolicyMetadata, len(list))
+	for _, metadata := range list {
+		byName[metadata.Name] = metadata
+	}
+	if got := byName["described"].Description; got != "described" {
+		t.Errorf("described description = %q, want described", got)
+	}
+	if got := byName["tagged"].Tags; !reflect.DeepEqual(got, []string{"x"}) {
+		t.Errorf("tagged tags = %v, want [x]", got)
+	}
+	if got := byName["plain"].Priority; got != 7 {
+		t.Errorf("plain priority = %d, want the SetPriority override 7", got)
+	}
+}
+
+func TestListWithMetadataOrder(t *testing.T) {
+	r := newTestRegistry()
+	for _, p := range []Policy{
+		&taggedPolicy{stubPolicy: stubPolicy{name: "a-late"}, priority: 10},
+		&taggedPolicy{stubPolicy: stubPolicy{name: "b-early"}, priority: -10},
+		&stubPolicy{name: "c-default"},
+		&stubPolicy{name: "d-default"},
+		&stubPolicy{name: "e-overridden"},
+	} {
+		if err := r.Register(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := r.SetPriority("e-overridden", -20); err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	var priorities []int
+	for _, metadata := range r.ListWithMetadata() {
+		names = append(names, metadata.Name)
+		priorities = append(priorities, metadata.Priority)
+	}
+
+	// Priority first, then name for equal priorities, as List orders them
+	want := []string{"e-overridden", "b-early", "c-default", "d-default", "a-late"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("order = %v, want %v", names, want)
+	}
+	if want := []int{-20, -10, DefaultPriority, DefaultPriority, 10}; !reflect.DeepEqual(priorities, want) {
+		t.Errorf("priorities = %v, want %v", priorities, want)
+	}
+	if list := r.List(); !reflect.DeepEqual(list, names) {
+		t.Errorf("List = %v, want the same order as ListWithMetadata %v", list, names)
+	}
+}
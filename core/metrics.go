@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// PolicyStats summarizes the executions of one policy
+type PolicyStats struct {
+	Count           uint64        `json:"count"`
+	Errors          uint64        `json:"errors"`
+	TotalDuration   time.Duration `json:"total_duration"`
+	AverageDuration time.Duration `json:"average_duration"`
+}
+
+// MetricsCollector records execution counts, durations and errors per
+// policy name. It is safe for concurrent use; recording an execution of a
+// policy that has been seen before takes a read lock and a few atomic adds
+// and does not allocate.
+type MetricsCollector struct {
+	mu       sync.RWMutex
+	counters map[string]*policyCounters
+}
+
+// durationBuckets are the upper bounds, in seconds, of the duration
+// histogram kept for each policy. They match the Prometheus client's
+// default buckets.
+var durationBuckets = [...]float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+type policyCounters struct {
+	count    atomic.Uint64
+	errors   atomic.Uint64
+	duration atomic.Int64
+	// buckets[i] counts executions no longer than durationBuckets[i] and
+	// longer than the previous bound; slower executions are only counted
+	// in count
+	buckets [len(durationBuckets)]atomic.Uint64
+}
+
+// NewMetricsCollector creates an empty collector
+func NewMetricsCollector() *MetricsCollector {
+	return &MetricsCollector{
+		counters: make(map[string]*policyCounters),
+	}
+}
+
+// Record adds one execution of the named policy
+func (m *MetricsCollector) Record(policy string, duration time.Duration, err error) {
+	c := m.countersFor(policy)
+	c.count.Add(1)
+	c.duration.Add(int64(duration))
+	if err != nil {
+		c.errors.Add(1)
+	}
+
+	seconds := duration.Seconds()
+	for i, bound := range durationBuckets {
+		if seconds <= bound {
+			c.buckets[i].Add(1)
+			break
+		}
+	}
+}
+
+func (m *MetricsCollector) countersFor(policy string) *policyCounters {
+	m.mu.RLock()
+	c, ok := m.counters[policy]
+	m.mu.RUnlock()
+	if ok {
+		return c
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if c, ok := m.counters[policy]; ok {
+		return c
+	}
+	c = &policyCounters{}
+	m.counters[policy] = c
+	return c
+}
+
+// Metrics returns a snapshot of the statistics keyed by policy name
+func (m *MetricsCollector) Metrics() map[string]PolicyStats {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	stats := make(map[string]PolicyStats, len(m.counters))
+	for name, c := range m.counters {
+		s := PolicyStats{
+			Count:         c.count.Load(),
+			Errors:        c.errors.Load(),
+			TotalDuration: time.Duration(c.duration.Load()),
+		}
+		if s.Count > 0 {
+			s.AverageDuration = s.TotalDuration / time.Duration(s.Count)
+		}
+		stats[name] = s
+	}
+	return stats
+}
+
+// Reset discards all recorded statistics
+func (m *MetricsCollector) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counters = make(map[string]*policyCounters)
+}
+
+// ExecuteWithMetrics runs a policy and records the execution in metrics
+func ExecuteWithMetrics(ctx context.Context, policy Policy, input interface{}, metrics *MetricsCollector) (interface{}, error) {
+	start := time.Now()
+	result, err := safeExecute(ctx, policy, input)
+	metrics.Record(policy.Name(), time.Since(start), err)
+	return result, err
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMetricsCollectorRecord(t *testing.T) {
+	m := NewMetricsCollector()
+	m.Record("a", 10*time.Millisecond, nil)
+	m.Record("a", 30*time.Millisecond, errors.New("boom"))
+	m.Record("a", 20*time.Millisecond, nil)
+	m.Record("b", time.Second, nil)
+
+	stats := m.Metrics()
+	want := map[string]PolicyStats{
+		"a": {Count: 3, Errors: 1, TotalDuration: 60 * time.Millisecond, AverageDuration: 20 * time.Millisecond},
+		"b": {Count: 1, TotalDuration: time.Second, AverageDuration: time.Second},
+	}
+	if len(stats) != len(want) {
+		t.Fatalf("stats for %d policies, want %d: %v", len(stats), len(want), stats)
+	}
+	for name, w := range want {
+		if stats[name] != w {
+			t.Errorf("stats[%s] = %+v, want %+v", name, stats[name], w)
+		}
+	}
+
+	m.Reset()
+	if stats := m.Metrics(); len(stats) != 0 {
+		t.Errorf("stats after Reset = %v, want none", stats)
+	}
+}
+
+func TestExecuteWithMetrics(t *testing.T) {
+	m := NewMetricsCollector()
+	ok := &stubPolicy{name: "ok"}
+	for i := 0; i < 5; i++ {
+		if _, err := ExecuteWithMetrics(context.Background(), ok, nil, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := ExecuteWithMetrics(context.Background(), failing("bad", errors.New("boom")), nil, m); err == nil {
+			t.Fatal("failing policy succeeded")
+		}
+	}
+
+	stats := m.Metrics()
+	if s := stats["ok"]; s.Count != 5 || s.Errors != 0 {
+		t.Errorf("ok: count = %d, errors = %d; want 5, 0", s.Count, s.Errors)
+	}
+	if s := stats["bad"]; s.Count != 2 || s.Errors != 2 {
+		t.Errorf("bad: count = %d, errors = %d; want 2, 2", s.Count, s.Errors)
+	}
+	for name, s := range stats {
+		if s.AverageDuration != s.TotalDuration/time.Duration(s.Count) {
+			t.Errorf("%s: average %v is not total %v over %d", name, s.AverageDuration, s.TotalDuration, s.Count)
+		}
+	}
+}
+
+func TestExecuteAllRecordsMetrics(t *testing.T) {
+	r := newTestRegistry()
+	for _, p := range []Policy{&stubPolicy{name: "ok"}, failing("bad", errors.New("boom"))} {
+		if err := r.Register(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 3; i++ {
+		r.ExecuteAll(context.Background(), nil)
+	}
+
+	stats := r.Metrics()
+	if s := stats["ok"]; s.Count != 3 || s.Errors != 0 {
+		t.Errorf("ok: count = %d, errors = %d; want 3, 0", s.Count, s.Errors)
+	}
+	if s := stats["bad"]; s.Count != 3 || s.Errors != 3 {
+		t.Errorf("bad: count = %d, errors = %d; want 3, 3", s.Count, s.Errors)
+	}
+}
+
+func TestMetricsCollectorConcurrent(t *testing.T) {
+	const workers, runs = 8, 200
+	m := NewMetricsCollector()
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < runs; i++ {
+				var err error
+				if i%4 == 0 {
+					err = errors.New("boom")
+				}
+				m.Record("shared", time.Millisecond, err)
+				if i%50 == 0 {
+					m.Metrics()
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	s := m.Metrics()["shared"]
+	want := PolicyStats{
+		Count:           workers * runs,
+		Errors:          workers * runs / 4,
+		TotalDuration:   workers * runs * time.Millisecond,
+		AverageDuration: time.Millisecond,
+	}
+	if s != want {
+		t.Errorf("stats = %+v, want %+v", s, want)
+	}
+}
+
+func TestMetricsRecordDoesNotAllocate(t *testing.T) {
+	m := NewMetricsCollector()
+	m.Record("p", time.Millisecond, nil)
+	if allocs := testing.AllocsPerRun(100, func() { m.Record("p", time.Millisecond, nil) }); allocs != 0 {
+		t.Errorf("Record allocates %v times per call, want 0", allocs)
+	}
+}
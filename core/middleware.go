@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+// ExecuteFunc has the signature of Policy.Execute
+type ExecuteFunc func(ctx context.Context, input interface{}) (interface{}, error)
+
+// Middleware wraps an ExecuteFunc with cross-cutting behavior such as
+// logging, metrics or access checks. It may run code before and after
+// calling next, or return without calling it to short-circuit execution.
+type Middleware func(next ExecuteFunc) ExecuteFunc
+
+// MiddlewarePolicy runs the wrapped policy's Execute through a chain of
+// middleware. It keeps the wrapped policy's name, and through Unwrap its
+// phase, priority, metadata and configuration, so it can be registered in
+// its place.
+type MiddlewarePolicy struct {
+	Inner   Policy
+	execute ExecuteFunc
+}
+
+// WithMiddleware wraps a policy with middleware. The first middleware is
+// the outermost: it runs first on the way in and last on the way out.
+func WithMiddleware(inner Policy, middleware ...Middleware) *MiddlewarePolicy {
+	execute := ExecuteFunc(inner.Execute)
+	for i := len(middleware) - 1; i >= 0; i-- {
+		execute = middleware[i](execute)
+	}
+	return &MiddlewarePolicy{Inner: inner, execute: execute}
+}
+
+// Name returns the wrapped policy's name so the wrapper can replace it
+func (m *MiddlewarePolicy) Name() string {
+	return m.Inner.Name()
+}
+
+// Validate validates the wrapped policy
+func (m *MiddlewarePolicy) Validate() error {
+	return m.Inner.Validate()
+}
+
+// Unwrap returns the wrapped policy, whose phase, priority, metadata and
+// configuration the wrapper keeps
+func (m *MiddlewarePolicy) Unwrap() Policy {
+	return m.Inner
+}
+
+// Execute runs the middleware chain around the wrapped policy
+func (m *MiddlewarePolicy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	return m.execute(ctx, input)
+}
+
+// LoggingMiddleware logs each execution of the named policy the same way
+// ExecutePolicy does: "executed" with its duration, or "execution failed"
+// with the error
+func LoggingMiddleware(logger Logger, policy string) Middleware {
+	return func(next ExecuteFunc) ExecuteFunc {
+		return func(ctx context.Context, input interface{}) (interface{}, error) {
+			start := time.Now()
+			result, err := next(ctx, input)
+
+			execution := &ExecutionResult{Policy: policy, Err: err, Duration: time.Since(start)}
+			if err != nil {
+				execution.Error = err.Error()
+			}
+			logExecution(logger, execution)
+			return result, err
+		}
+	}
+}
+
+// TimingMiddleware records the duration and outcome of each execution of
+// the named policy in metrics
+func TimingMiddleware(metrics *MetricsCollector, policy string) Middleware {
+	return func(next ExecuteFunc) ExecuteFunc {
+		return func(ctx context.Context, input interface{}) (interface{}, error) {
+			start := time.Now()
+			result, err := next(ctx, input)
+			metrics.Record(policy, time.Since(start), err)
+			return result, err
+		}
+	}
+}
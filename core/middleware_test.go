@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// tracing returns middleware that appends label to trace on the way in
+// and on the way out
+func tracing(trace *[]string, label string) Middleware {
+	return func(next ExecuteFunc) ExecuteFunc {
+		return func(ctx context.Context, input interface{}) (interface{}, error) {
+			*trace = append(*trace, label+" in")
+			result, err := next(ctx, input)
+			*trace = append(*trace, label+" out")
+			return result, err
+		}
+	}
+}
+
+func TestWithMiddlewareOrder(t *testing.T) {
+	var trace []string
+	inner := &stubPolicy{name: "inner", execute: func(_ context.Context, input interface{}) (interface{}, error) {
+		trace = append(trace, "policy")
+		return input, nil
+	}}
+
+	policy := WithMiddleware(inner, tracing(&trace, "first"), tracing(&trace, "second"))
+	result, err := policy.Execute(context.Background(), "x")
+	if err != nil || result != "x" {
+		t.Fatalf("Execute = %v, %v; want x", result, err)
+	}
+	want := []string{"first in", "second in", "policy", "second out", "first out"}
+	if !reflect.DeepEqual(trace, want) {
+		t.Errorf("trace = %v, want %v", trace, want)
+	}
+}
+
+func TestWithMiddlewareShortCircuit(t *testing.T) {
+	errUnauthorized := errors.New("unauthorized")
+	ran := false
+	inner := &stubPolicy{name: "inner", execute: func(context.Context, interface{}) (interface{}, error) {
+		ran = true
+		return nil, nil
+	}}
+	deny := func(ExecuteFunc) ExecuteFunc {
+		return func(context.Context, interface{}) (interface{}, error) {
+			return nil, errUnauthorized
+		}
+	}
+
+	if _, err := WithMiddleware(inner, deny).Execute(context.Background(), nil); err != errUnauthorized {
+		t.Errorf("error = %v, want %v", err, errUnauthorized)
+	}
+	if ran {
+		t.Error("the policy ran despite the middleware stopping the execution")
+	}
+}
+
+func TestWithMiddlewareRewritesInput(t *testing.T) {
+	double := func(next ExecuteFunc) ExecuteFunc {
+		return func(ctx context.Context, input interface{}) (interface{}, error) {
+			return next(ctx, input.(int)*2)
+		}
+	}
+	if result, _ := WithMiddleware(&stubPolicy{name: "echo"}, double, double).Execute(context.Background(), 3); result != 12 {
+		t.Errorf("result = %v, want 12", result)
+	}
+}
+
+func TestMiddlewarePolicyKeepsIdentity(t *testing.T) {
+	errInvalid := errors.New("invalid")
+	inner := &taggedPolicy{stubPolicy: stubPolicy{name: "inner", validateErr: errInvalid}, priority: 5}
+	policy := WithMiddleware(inner)
+
+	if policy.Name() != "inner" || PhaseOf(policy) != PhasePre || PriorityOf(policy) != 5 {
+		t.Errorf("name, phase, priority = %s, %s, %d; want the inner policy's", policy.Name(), PhaseOf(policy), PriorityOf(policy))
+	}
+	if err := policy.Validate(); err != errInvalid {
+		t.Errorf("Validate = %v, want the inner policy's error", err)
+	}
+
+	// Without middleware the policy runs directly
+	inner.validateErr = nil
+	if result, err := policy.Execute(context.Background(), "x"); result != "x" || err != nil {
+		t.Errorf("Execute = %v, %v; want x", result, err)
+	}
+
+	// The wrapper can replace the policy it wraps
+	r := newTestRegistry()
+	if err := r.Register(inner); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.RegisterForce(policy); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := r.Get("inner"); got != Policy(policy) {
+		t.Error("RegisterForce did not replace the policy with its wrapper")
+	}
+}
+
+func TestLoggingMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewJSONLogger(&buf, false)
+
+	WithMiddleware(&stubPolicy{name: "echo"}, LoggingMiddleware(logger, "echo")).Execute(context.Background(), nil)
+	WithMiddleware(failing("broken", errors.New("boom")), LoggingMiddleware(logger, "broken")).Execute(context.Background(), nil)
+
+	entries := logEntries(t, &buf)
+	if executed := findEntry(entries, "executed"); executed == nil || executed["policy"] != "echo" {
+		t.Errorf("executed entry = %v, want one for echo", executed)
+	}
+	if failed := findEntry(entries, "execution failed"); failed == nil || failed["policy"] != "broken" || failed["error"] != "boom" {
+		t.Errorf("failed entry = %v, want one for broken with its error", failed)
+	}
+}
+
+func TestTimingMiddleware(t *testing.T) {
+	metrics := NewMetricsCollector()
+	ok := WithMiddleware(&stubPolicy{name: "echo"}, TimingMiddleware(metrics, "echo"))
+	bad := WithMiddleware(failing("broken", errors.New("boom")), TimingMiddleware(metrics, "broken"))
+	for i := 0; i < 3; i++ {
+		ok.Execute(context.Background(), nil)
+	}
+	bad.Execute(context.Background(), nil)
+
+	stats := metrics.Metrics()
+	if s := stats["echo"]; s.Count != 3 || s.Errors != 0 {
+		t.Errorf("echo: count = %d, errors = %d; want 3, 0", s.Count, s.Errors)
+	}
+	if s := stats["broken"]; s.Count != 1 || s.Errors != 1 {
+		t.Errorf("broken: count = %d, errors = %d; want 1, 1", s.Count, s.Errors)
+	}
+}
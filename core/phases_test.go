@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// phasedPolicy is a stubPolicy that declares an execution phase
+type phasedPolicy struct {
+	stubPolicy
+	phase string
+}
+
+func (p *phasedPolicy) Phase() string {
+	return p.phase
+}
+
+func TestExecuteAllRunsPhasesInOrder(t *testing.T) {
+	r := newTestRegistry()
+
+	// ExecuteAll runs policies one at a time, so no locking is needed
+	var order []string
+
+	// Names sort against phase order, so only the phases can put them in
+	// the expected order
+	policies := []struct {
+		name, phase string
+	}{
+		{"a-post", PhasePost},
+		{"b-main", PhaseMain},
+		{"c-pre", PhasePre},
+		{"d-post", PhasePost},
+		{"e-pre", PhasePre},
+		{"f-default", ""},
+	}
+	for _, tt := range policies {
+		name := tt.name
+		stub := stubPolicy{name: name, execute: func(_ context.Context, input interface{}) (interface{}, error) {
+			order = append(order, name)
+			return input, nil
+		}}
+		var p Policy = &stub
+		if tt.phase != "" {
+			p = &phasedPolicy{stubPolicy: stub, phase: tt.phase}
+		}
+		if err := r.Register(p); err != nil {
+			t.Fatalf("Register(%s): %v", name, err)
+		}
+	}
+
+	want := []string{"c-pre", "e-pre", "b-main", "f-default", "a-post", "d-post"}
+	if got := r.ListByPhase(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ListByPhase() = %v, want %v", got, want)
+	}
+
+	if _, err := r.ExecuteAll(context.Background(), map[string]interface{}{}); err != nil {
+		t.Fatalf("ExecuteAll: %v", err)
+	}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("execution order = %v, want %v", order, want)
+	}
+}
+
+func TestPhasePriorityWithinPhase(t *testing.T) {
+	r := newTestRegistry()
+	for _, p := range []Policy{
+		&phasedPolicy{stubPolicy: stubPolicy{name: "late-pre"}, phase: PhasePre},
+		&phasedPolicy{stubPolicy: stubPolicy{name: "early-pre"}, phase: PhasePre},
+		&stubPolicy{name: "main"},
+	} {
+		if err := r.Register(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := r.SetPriority("late-pre", 10); err != nil {
+		t.Fatal(err)
+	}
+	// Even a very low priority cannot move a main policy before pre ones
+	if err := r.SetPriority("main", -100); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"early-pre", "late-pre", "main"}
+	if got := r.ListByPhase(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ListByPhase() = %v, want %v", got, want)
+	}
+}
+
+func TestPhaseOf(t *testing.T) {
+	if got := PhaseOf(&stubPolicy{name: "p"}); got != PhaseMain {
+		t.Errorf("PhaseOf(unphased) = %q, want %q", got, PhaseMain)
+	}
+	if got := PhaseOf(&phasedPolicy{phase: PhasePost}); got != PhasePost {
+		t.Errorf("PhaseOf(post) = %q, want %q", got, PhasePost)
+	}
+}
+
+func TestRegisterRejectsUnknownPhase(t *testing.T) {
+	r := newTestRegistry()
+	if err := r.Register(&phasedPolicy{stubPolicy: stubPolicy{name: "p"}, phase: "cleanup"}); err == nil {
+		t.Fatal("Register accepted an unknown phase")
+	}
+	if _, ok := r.Get("p"); ok {
+		t.Error("policy with an unknown phase was registered")
+	}
+}
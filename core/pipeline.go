@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+// Pipeline runs an ordered list of policies where each stage's output
+// becomes the next stage's input
+type Pipeline struct {
+	// OnStage, when set, is called with a summary of each stage as it
+	// finishes, including a stage that fails
+	OnStage func(StageSummary)
+
+	names    []string
+	policies []Policy
+}
+
+// NewPipeline builds a pipeline from policies in the registry, in the given
+// order. Every name must be registered.
+func NewPipeline(registry *PolicyRegistry, names []string) (*Pipeline, error) {
+	if len(names) == 0 {
+		return nil, fmt.Errorf("pipeline needs at least one policy")
+	}
+
+	policies := make([]Policy, len(names))
+	for i, name := range names {
+		policy, err := registry.GetOrError(name)
+		if err != nil {
+			return nil, fmt.Errorf("pipeline stage %d: %w", i+1, err)
+		}
+		policies[i] = policy
+	}
+
+	return &Pipeline{
+		names:    append([]string(nil), names...),
+		policies: policies,
+	}, nil
+}
+
+// Stages returns the policy names in execution order
+func (p *Pipeline) Stages() []string {
+	return append([]string(nil), p.names...)
+}
+
+// Execute runs every stage in order and returns the last stage's result,
+// stopping at the first error. A stage result that carries an "output"
+// object, the convention transforming policies use to return their
+// rewritten input, passes that object on; any other result is passed on
+// as is.
+func (p *Pipeline) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	var result interface{}
+	for i, policy := range p.policies {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		var err error
+		result, err = safeExecute(ctx, policy, input)
+		if p.OnStage != nil {
+			p.OnStage(SummarizeStage(policy, input, result, err))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("pipeline stage %d (%s): %w", i+1, p.names[i], err)
+		}
+		input = stageOutput(result)
+	}
+	return result, nil
+}
+
+// stageOutput returns the value handed to the next stage
+func stageOutput(result interface{}) interface{} {
+	if output, ok := resultOutput(result); ok {
+		return output
+	}
+	return result
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// uppercase mimics uppercase-policy: it returns the input with every
+// string value upper-cased as the result's output
+var uppercase = &stubPolicy{name: "uppercase", execute: func(_ context.Context, input interface{}) (interface{}, error) {
+	output := copyObject(input)
+	for key, value := range output {
+		if s, ok := value.(string); ok {
+			output[key] = strings.ToUpper(s)
+		}
+	}
+	return &PolicyResult{Policy: "uppercase", Action: "transformation", Status: "PASSED", Output: output}, nil
+}}
+
+// stamp adds a field to its map input and returns it as a plain map
+var stamp = &stubPolicy{name: "stamp", execute: func(_ context.Context, input interface{}) (interface{}, error) {
+	output := copyObject(input)
+	output["stamped"] = true
+	return map[string]interface{}{"status": "PASSED", "output": output}, nil
+}}
+
+// requireUpper mimics validator-policy: it fails unless name is upper case
+var requireUpper = &stubPolicy{name: "require-upper", execute: func(_ context.Context, input interface{}) (interface{}, error) {
+	name, _ := input.(map[string]interface{})["name"].(string)
+	status := "PASSED"
+	if name != strings.ToUpper(name) {
+		status = "FAILED"
+	}
+	return &PolicyResult{Policy: "require-upper", Action: "validation", Status: status, Data: map[string]interface{}{"input": input}}, nil
+}}
+
+func newPipelineRegistry(t *testing.T, policies ...Policy) *PolicyRegistry {
+	t.Helper()
+	r := newTestRegistry()
+	for _, p := range policies {
+		if err := r.Register(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return r
+}
+
+func TestPipelineDataFlow(t *testing.T) {
+	r := newPipelineRegistry(t, uppercase, stamp, requireUpper)
+
+	tests := []struct {
+		name       string
+		stages     []string
+		wantStatus string
+		wantInput  map[string]interface{}
+	}{
+		{
+			name:       "transform then validate",
+			stages:     []string{"uppercase", "require-upper"},
+			wantStatus: "PASSED",
+			wantInput:  map[string]interface{}{"name": "ADA", "age": 36.0},
+		},
+		{
+			name:       "three stages",
+			stages:     []string{"uppercase", "stamp", "require-upper"},
+			wantStatus: "PASSED",
+			wantInput:  map[string]interface{}{"name": "ADA", "age": 36.0, "stamped": true},
+		},
+		{
+			name:       "validate before transform",
+			stages:     []string{"require-upper"},
+			wantStatus: "FAILED",
+			wantInput:  map[string]interface{}{"name": "ada", "age": 36.0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipeline, err := NewPipeline(r, tt.stages)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := pipeline.Execute(context.Background(), map[string]interface{}{"name": "ada", "age": 36.0})
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*PolicyResult)
+			if result.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", result.Status, tt.wantStatus)
+			}
+			if !reflect.DeepEqual(result.Data["input"], tt.wantInput) {
+				t.Errorf("last stage input = %v, want %v", result.Data["input"], tt.wantInput)
+			}
+		})
+	}
+}
+
+func TestPipelinePassesResultsWithoutOutput(t *testing.T) {
+	count := &stubPolicy{name: "count", execute: func(_ context.Context, input interface{}) (interface{}, error) {
+		return len(input.(map[string]interface{})), nil
+	}}
+	double := &stubPolicy{name: "double", execute: func(_ context.Context, input interface{}) (interface{}, error) {
+		return input.(int) * 2, nil
+	}}
+
+	pipeline, err := NewPipeline(newPipelineRegistry(t, count, double), []string{"count", "double"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := pipeline.Execute(context.Background(), map[string]interface{}{"a": 1, "b": 2})
+	if err != nil || got != 4 {
+		t.Errorf("Execute = %v, %v; want 4", got, err)
+	}
+}
+
+func TestNewPipelineErrors(t *testing.T) {
+	r := newPipelineRegistry(t, uppercase)
+
+	_, err := NewPipeline(r, []string{"uppercase", "missing"})
+	if !errors.Is(err, ErrPolicyNotFound) {
+		t.Fatalf("error = %v, want ErrPolicyNotFound", err)
+	}
+	if want := "pipeline stage 2: policy not found: missing"; err.Error() != want {
+		t.Errorf("error = %q, want %q", err, want)
+	}
+
+	if _, err := NewPipeline(r, nil); err == nil {
+		t.Error("empty pipeline accepted, want error")
+	}
+}
+
+func TestPipelineStopsAtFirstError(t *testing.T) {
+	errBroken := errors.New("broken")
+	ran := false
+	after := &stubPolicy{name: "after", execute: func(_ context.Context, input interface{}) (interface{}, error) {
+		ran = true
+		return input, nil
+	}}
+
+	pipeline, err := NewPipeline(newPipelineRegistry(t, uppercase, failing("broken", errBroken), after), []string{"uppercase", "broken", "after"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var summarized []string
+	pipeline.OnStage = func(stage StageSummary) {
+		summarized = append(summarized, stage.Policy+":"+stage.Status)
+	}
+	_, err = pipeline.Execute(context.Background(), map[string]interface{}{"name": "ada"})
+	if !errors.Is(err, errBroken) {
+		t.Fatalf("error = %v, want the stage error", err)
+	}
+	if want := "pipeline stage 2 (broken): broken"; err.Error() != want {
+		t.Errorf("error = %q, want %q", err, want)
+	}
+	if ran {
+		t.Error("stage after the failure ran")
+	}
+	if want := []string{"uppercase:PASSED", "broken:ERROR"}; !reflect.DeepEqual(summarized, want) {
+		t.Errorf("summarized stages = %v, want %v", summarized, want)
+	}
+}
+
+func TestPipelineCancelled(t *testing.T) {
+	pipeline, err := NewPipeline(newPipelineRegistry(t, uppercase), []string{"uppercase"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := pipeline.Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// pluginSymbol is the constructor every runtime plugin must export:
+//
+//	func NewPolicy() interface{}
+//
+// Plugins cannot import this package, so the constructor returns an
+// interface{} whose dynamic type must implement Policy.
+const pluginSymbol = "NewPolicy"
+
+// ErrPluginsUnsupported is returned by LoadPlugins when the engine was
+// built without plugin support
+var ErrPluginsUnsupported = errors.New("plugins are not supported by this build")
+
+// LoadPlugins opens every .so file in dir with the plugin package, calls its
+// exported NewPolicy constructor and registers the returned policy. Files
+// that fail to load do not stop the others; their errors are joined into
+// the returned error.
+//
+// Go plugins require cgo on Linux, FreeBSD or macOS, and must be built with
+// the same Go version and dependency versions as the engine. Other builds,
+// including the default CGO_ENABLED=0 one, fail with ErrPluginsUnsupported.
+func LoadPlugins(dir string, registry *PolicyRegistry) error {
+	if !pluginsSupported {
+		return fmt.Errorf("%w: rebuild the engine with CGO_ENABLED=1", ErrPluginsUnsupported)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read plugin directory: %w", err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".so") {
+			files = append(files, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(files)
+
+	var errs []error
+	for _, file := range files {
+		if err := loadPlugin(file, registry); err != nil {
+			errs = append(errs, fmt.Errorf("plugin %s: %w", filepath.Base(file), err))
+		}
+	}
+	return errors.Join(errs...)
+}
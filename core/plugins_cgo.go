@@ -0,0 +1,34 @@
+//go:build cgo && (linux || darwin || freebsd)
+
+package main
+
+import (
+	"fmt"
+	"plugin"
+)
+
+// pluginsSupported reports whether this build can open Go plugins
+const pluginsSupported = true
+
+func loadPlugin(path string, registry *PolicyRegistry) error {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return err
+	}
+
+	sym, err := p.Lookup(pluginSymbol)
+	if err != nil {
+		return err
+	}
+	constructor, ok := sym.(func() interface{})
+	if !ok {
+		return fmt.Errorf("symbol %s has type %T, expected func() interface{}", pluginSymbol, sym)
+	}
+
+	value := constructor()
+	policy, ok := value.(Policy)
+	if !ok {
+		return fmt.Errorf("%s returned %T, which does not implement Policy", pluginSymbol, value)
+	}
+	return registry.Register(policy)
+}
@@ -0,0 +1,11 @@
+//go:build !cgo || !(linux || darwin || freebsd)
+
+package main
+
+// pluginsSupported reports whether this build can open Go plugins
+const pluginsSupported = false
+
+// loadPlugin is never reached because LoadPlugins checks pluginsSupported
+func loadPlugin(string, *PolicyRegistry) error {
+	return ErrPluginsUnsupported
+}
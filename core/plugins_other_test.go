@@ -0,0 +1,15 @@
+//go:build !cgo || !(linux || darwin || freebsd)
+
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLoadPluginsUnsupported(t *testing.T) {
+	r := newTestRegistry()
+	if err := LoadPlugins(t.TempDir(), r); !errors.Is(err, ErrPluginsUnsupported) {
+		t.Errorf("error = %v, want ErrPluginsUnsupported", err)
+	}
+}
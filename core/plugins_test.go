@@ -0,0 +1,118 @@
+//go:build cgo && (linux || darwin || freebsd)
+
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"testing"
+)
+
+// pluginSource is a minimal runtime plugin exporting a policy
+const pluginSource = `package main
+
+import "context"
+
+type policy struct{}
+
+func (p *policy) Name() string    { return "plugin-policy" }
+func (p *policy) Validate() error { return nil }
+
+func (p *policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	return map[string]interface{}{"from": "plugin", "input": input}, nil
+}
+
+func NewPolicy() interface{} { return &policy{} }
+`
+
+// buildPlugin compiles source as a plugin into dir/name. It skips the test
+// when the go tool is unavailable or -short is set, since building takes a
+// few seconds.
+func buildPlugin(t *testing.T, dir, name, source string) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skipping plugin build in short mode")
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not found")
+	}
+
+	// Every plugin needs its own module path, which the runtime uses to
+	// tell loaded plugins apart
+	src := t.TempDir()
+	module := "module example.com/" + strings.TrimSuffix(name, ".so") + "\n\ngo 1.21\n"
+	if err := os.WriteFile(filepath.Join(src, "go.mod"), []byte(module), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "main.go"), []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The plugin must be built the same way as this test binary
+	args := []string{"build", "-buildmode=plugin"}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			if setting.Key == "-race" && setting.Value == "true" {
+				args = append(args, "-race")
+			}
+		}
+	}
+	args = append(args, "-o", filepath.Join(dir, name), ".")
+
+	cmd := exec.Command(goTool, args...)
+	cmd.Dir = src
+	cmd.Env = append(os.Environ(), "GOFLAGS=", "GOWORK=off", "CGO_ENABLED=1")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("building plugin: %v\n%s", err, out)
+	}
+}
+
+func TestLoadPlugins(t *testing.T) {
+	dir := t.TempDir()
+	buildPlugin(t, dir, "policy.so", pluginSource)
+	buildPlugin(t, dir, "nosymbol.so", "package main\n\nfunc Other() {}\n")
+	if err := os.WriteFile(filepath.Join(dir, "broken.so"), []byte("not a plugin"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.txt"), []byte("ignored"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := newTestRegistry()
+	err := LoadPlugins(dir, r)
+
+	// The two bad files are reported without stopping the good one
+	if err == nil {
+		t.Fatal("LoadPlugins succeeded, want errors for broken.so and nosymbol.so")
+	}
+	for _, want := range []string{"plugin broken.so:", "plugin nosymbol.so:"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+
+	policy, ok := r.Get("plugin-policy")
+	if !ok {
+		t.Fatalf("plugin-policy not registered; registered %v", r.List())
+	}
+	got, err := policy.Execute(context.Background(), "hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := got.(map[string]interface{}); result["from"] != "plugin" || result["input"] != "hello" {
+		t.Errorf("result = %v, want the plugin's result", result)
+	}
+}
+
+func TestLoadPluginsMissingDirectory(t *testing.T) {
+	err := LoadPlugins(filepath.Join(t.TempDir(), "missing"), newTestRegistry())
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("error = %v, want os.ErrNotExist", err)
+	}
+}
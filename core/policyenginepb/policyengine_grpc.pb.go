@@ -0,0 +1,152 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: policyenginepb/policyengine.proto
+
+package policyenginepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	PolicyEngine_Execute_FullMethodName = "/policyengine.v1.PolicyEngine/Execute"
+	PolicyEngine_List_FullMethodName    = "/policyengine.v1.PolicyEngine/List"
+)
+
+// PolicyEngineClient is the client API for PolicyEngine service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PolicyEngineClient interface {
+	// Execute runs one policy. Unknown policies fail with NOT_FOUND and
+	// malformed JSON input with INVALID_ARGUMENT.
+	Execute(ctx context.Context, in *ExecuteRequest, opts ...grpc.CallOption) (*ExecuteResponse, error)
+	// List returns the registered policy names in execution order.
+	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error)
+}
+
+type policyEngineClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPolicyEngineClient(cc grpc.ClientConnInterface) PolicyEngineClient {
+	return &policyEngineClient{cc}
+}
+
+func (c *policyEngineClient) Execute(ctx context.Context, in *ExecuteRequest, opts ...grpc.CallOption) (*ExecuteResponse, error) {
+	out := new(ExecuteResponse)
+	err := c.cc.Invoke(ctx, PolicyEngine_Execute_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *policyEngineClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error) {
+	out := new(ListResponse)
+	err := c.cc.Invoke(ctx, PolicyEngine_List_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PolicyEngineServer is the server API for PolicyEngine service.
+// All implementations must embed UnimplementedPolicyEngineServer
+// for forward compatibility
+type PolicyEngineServer interface {
+	// Execute runs one policy. Unknown policies fail with NOT_FOUND and
+	// malformed JSON input with INVALID_ARGUMENT.
+	Execute(context.Context, *ExecuteRequest) (*ExecuteResponse, error)
+	// List returns the registered policy names in execution order.
+	List(context.Context, *ListRequest) (*ListResponse, error)
+	mustEmbedUnimplementedPolicyEngineServer()
+}
+
+// UnimplementedPolicyEngineServer must be embedded to have forward compatible implementations.
+type UnimplementedPolicyEngineServer struct {
+}
+
+func (UnimplementedPolicyEngineServer) Execute(context.Context, *ExecuteRequest) (*ExecuteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Execute not implemented")
+}
+func (UnimplementedPolicyEngineServer) List(context.Context, *ListRequest) (*ListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (UnimplementedPolicyEngineServer) mustEmbedUnimplementedPolicyEngineServer() {}
+
+// UnsafePolicyEngineServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PolicyEngineServer will
+// result in compilation errors.
+type UnsafePolicyEngineServer interface {
+	mustEmbedUnimplementedPolicyEngineServer()
+}
+
+func RegisterPolicyEngineServer(s grpc.ServiceRegistrar, srv PolicyEngineServer) {
+	s.RegisterService(&PolicyEngine_ServiceDesc, srv)
+}
+
+func _PolicyEngine_Execute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecuteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PolicyEngineServer).Execute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PolicyEngine_Execute_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PolicyEngineServer).Execute(ctx, req.(*ExecuteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PolicyEngine_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PolicyEngineServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PolicyEngine_List_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PolicyEngineServer).List(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PolicyEngine_ServiceDesc is the grpc.ServiceDesc for PolicyEngine service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PolicyEngine_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "policyengine.v1.PolicyEngine",
+	HandlerType: (*PolicyEngineServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Execute",
+			Handler:    _PolicyEngine_Execute_Handler,
+		},
+		{
+			MethodName: "List",
+			Handler:    _PolicyEngine_List_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "policyenginepb/policyengine.proto",
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+// Request is the input to a PolicyV2 execution
+type Request struct {
+	// Input is the value a v1 policy receives as its input
+	Input interface{}
+
+	// Metadata carries caller-supplied attributes such as request IDs or
+	// tenant names alongside the input
+	Metadata map[string]string
+}
+
+// Response is the outcome of a PolicyV2 execution
+type Response struct {
+	// Output is the value a v1 policy returns as its result
+	Output interface{}
+
+	// Metadata carries attributes back to the caller. Adapted v1 policies
+	// return the request metadata unchanged.
+	Metadata map[string]string
+}
+
+// PolicyV2 is the next revision of the Policy interface. Execute takes a
+// Request and returns a Response so that metadata can travel with the
+// input and result. The registry stores v1 policies; v2 policies are
+// registered through RegisterV2, which adapts them, and any registered
+// policy can be retrieved as a v2 policy with GetV2.
+type PolicyV2 interface {
+	// Name returns the unique identifier for this policy
+	Name() string
+
+	// Execute runs the policy logic with the given request
+	Execute(ctx context.Context, req *Request) (*Response, error)
+
+	// Validate checks if the policy configuration is valid
+	Validate() error
+}
+
+type metadataKey struct{}
+
+// ContextWithMetadata returns a context carrying request metadata, which
+// is how metadata reaches a v2 policy that is executed through the v1
+// interface
+func ContextWithMetadata(ctx context.Context, metadata map[string]string) context.Context {
+	return context.WithValue(ctx, metadataKey{}, metadata)
+}
+
+// MetadataFromContext returns the metadata stored by ContextWithMetadata,
+// or nil
+func MetadataFromContext(ctx context.Context) map[string]string {
+	metadata, _ := ctx.Value(metadataKey{}).(map[string]string)
+	return metadata
+}
+
+// AdaptV1 presents a v1 policy as a PolicyV2. The request metadata is
+// stored in the context passed to the policy and returned unchanged in the
+// response. Adapting a policy returned by AdaptV2 unwraps it.
+func AdaptV1(p Policy) PolicyV2 {
+	if adapted, ok := p.(*v2Adapter); ok {
+		return adapted.inner
+	}
+	return &v1Adapter{inner: p}
+}
+
+// AdaptV2 presents a PolicyV2 as a v1 Policy. The request is built from
+// the input and the metadata in the context, if any; the response metadata
+// is discarded. Adapting a policy returned by AdaptV1 unwraps it.
+func AdaptV2(p PolicyV2) Policy {
+	if adapted, ok := p.(*v1Adapter); ok {
+		return adapted.inner
+	}
+	return &v2Adapter{inner: p}
+}
+
+// v1Adapter wraps a v1 Policy as a PolicyV2
+type v1Adapter struct {
+	inner Policy
+}
+
+func (a *v1Adapter) Name() string {
+	return a.inner.Name()
+}
+
+func (a *v1Adapter) Validate() error {
+	return a.inner.Validate()
+}
+
+// Unwrap returns the adapted v1 policy, whose optional interfaces such as
+// Phased or Described the adapter does not implement itself
+func (a *v1Adapter) Unwrap() Policy {
+	return a.inner
+}
+
+func (a *v1Adapter) Execute(ctx context.Context, req *Request) (*Response, error) {
+	if req == nil {
+		req = &Request{}
+	}
+	if req.Metadata != nil {
+		ctx = ContextWithMetadata(ctx, req.Metadata)
+	}
+
+	output, err := a.inner.Execute(ctx, req.Input)
+	if err != nil {
+		return nil, err
+	}
+	return &Response{Output: output, Metadata: copyMetadata(req.Metadata)}, nil
+}
+
+// v2Adapter wraps a PolicyV2 as a v1 Policy
+type v2Adapter struct {
+	inner PolicyV2
+}
+
+func (a *v2Adapter) Name() string {
+	return a.inner.Name()
+}
+
+func (a *v2Adapter) Validate() error {
+	return a.inner.Validate()
+}
+
+// Unwrap returns the adapted v2 policy. The engine reads optional
+// interfaces such as Phased, Described or Configurable from it.
+func (a *v2Adapter) Unwrap() PolicyV2 {
+	return a.inner
+}
+
+func (a *v2Adapter) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	resp, err := a.inner.Execute(ctx, &Request{Input: input, Metadata: MetadataFromContext(ctx)})
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, fmt.Errorf("policy %s returned no response", a.inner.Name())
+	}
+	return resp.Output, nil
+}
+
+func copyMetadata(metadata map[string]string) map[string]string {
+	if metadata == nil {
+		return nil
+	}
+	copied := make(map[string]string, len(metadata))
+	for key, value := range metadata {
+		copied[key] = value
+	}
+	return copied
+}
+
+// RegisterV2 adds a v2 policy to the registry, failing with
+// ErrDuplicatePolicy if the name is already taken
+func (r *PolicyRegistry) RegisterV2(p PolicyV2) error {
+	return r.Register(AdaptV2(p))
+}
+
+// GetV2 retrieves a policy by name as a PolicyV2, adapting v1 policies
+func (r *PolicyRegistry) GetV2(name string) (PolicyV2, bool) {
+	p, ok := r.Get(name)
+	if !ok {
+		return nil, false
+	}
+	return AdaptV1(p), true
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// v2Echo is a native PolicyV2 that reports the request it received
+type v2Echo struct {
+	name string
+}
+
+func (p *v2Echo) Name() string    { return p.name }
+func (p *v2Echo) Validate() error { return nil }
+
+func (p *v2Echo) Execute(_ context.Context, req *Request) (*Response, error) {
+	metadata := copyMetadata(req.Metadata)
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+	metadata["handled-by"] = p.name
+	return &Response{Output: map[string]interface{}{"input": req.Input, "tenant": req.Metadata["tenant"]}, Metadata: metadata}, nil
+}
+
+func TestV1PolicyThroughV2(t *testing.T) {
+	// The v1 policy reads the request metadata from its context
+	var seen map[string]string
+	v1 := &stubPolicy{name: "v1", execute: func(ctx context.Context, input interface{}) (interface{}, error) {
+		seen = MetadataFromContext(ctx)
+		return map[string]interface{}{"echo": input}, nil
+	}}
+
+	r := newTestRegistry()
+	if err := r.Register(v1); err != nil {
+		t.Fatal(err)
+	}
+	p, ok := r.GetV2("v1")
+	if !ok {
+		t.Fatal("GetV2(v1) not found")
+	}
+
+	metadata := map[string]string{"request-id": "r-1", "tenant": "acme"}
+	resp, err := p.Execute(context.Background(), &Request{Input: "hello", Metadata: metadata})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if want := map[string]interface{}{"echo": "hello"}; !reflect.DeepEqual(resp.Output, want) {
+		t.Errorf("output = %v, want %v", resp.Output, want)
+	}
+	if !reflect.DeepEqual(seen, metadata) {
+		t.Errorf("policy saw metadata %v, want %v", seen, metadata)
+	}
+	if !reflect.DeepEqual(resp.Metadata, metadata) {
+		t.Errorf("response metadata = %v, want %v", resp.Metadata, metadata)
+	}
+
+	// The response carries a copy, so the caller's map is not shared
+	resp.Metadata["tenant"] = "other"
+	if metadata["tenant"] != "acme" {
+		t.Error("changing the response metadata changed the request metadata")
+	}
+}
+
+func TestV1PolicyThroughV2WithoutRequest(t *testing.T) {
+	resp, err := AdaptV1(&stubPolicy{name: "echo"}).Execute(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Output != nil || resp.Metadata != nil {
+		t.Errorf("response = %+v, want empty", resp)
+	}
+}
+
+func TestV2PolicyThroughV1(t *testing.T) {
+	r := newTestRegistry()
+	if err := r.RegisterV2(&v2Echo{name: "v2"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.RegisterV2(&v2Echo{name: "v2"}); !errors.Is(err, ErrDuplicatePolicy) {
+		t.Errorf("duplicate RegisterV2 error = %v, want ErrDuplicatePolicy", err)
+	}
+
+	p, _ := r.Get("v2")
+	ctx := ContextWithMetadata(context.Background(), map[string]string{"tenant": "acme"})
+	got, err := p.Execute(ctx, "hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]interface{}{"input": "hello", "tenant": "acme"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("result = %v, want %v", got, want)
+	}
+
+	// ExecuteAll runs v2 policies like any other
+	results, err := r.ExecuteAll(context.Background(), "x")
+	if err != nil || results["v2"] == nil {
+		t.Errorf("ExecuteAll = %v, %v; want the v2 result", results, err)
+	}
+}
+
+func TestAdaptersUnwrap(t *testing.T) {
+	v1 := &stubPolicy{name: "v1"}
+	if got := AdaptV2(AdaptV1(v1)); got != Policy(v1) {
+		t.Errorf("AdaptV2(AdaptV1(p)) = %T, want the original policy", got)
+	}
+	v2 := &v2Echo{name: "v2"}
+	if got := AdaptV1(AdaptV2(v2)); got != PolicyV2(v2) {
+		t.Errorf("AdaptV1(AdaptV2(p)) = %T, want the original policy", got)
+	}
+}
+
+// fullV2 is a PolicyV2 implementing every optional interface
+type fullV2 struct {
+	v2Echo
+	required string
+	config   map[string]interface{}
+}
+
+func (p *fullV2) Phase() string                 { return PhasePre }
+func (p *fullV2) Priority() int                 { return 5 }
+func (p *fullV2) Description() string           { return "answers in v2" }
+func (p *fullV2) Version() string               { return "2.1.0" }
+func (p *fullV2) Tags() []string                { return []string{"v2"} }
+func (p *fullV2) RequiredEngineVersion() string { return p.required }
+func (p *fullV2) Configure(config map[string]interface{}) error {
+	p.config = config
+	return nil
+}
+
+func TestV2AdapterExposesOptionalInterfaces(t *testing.T) {
+	full := &fullV2{v2Echo: v2Echo{name: "full"}, required: ">=1.0.0"}
+	r := newTestRegistry()
+	if err := r.RegisterV2(full); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.RegisterV2(&v2Echo{name: "plain"}); err != nil {
+		t.Fatal(err)
+	}
+
+	all := PolicyCapabilities{Configurable: true, Phased: true, Prioritized: true, Described: true, Versioned: true, Tagged: true, EngineVersioned: true}
+	if got, _ := r.Capabilities("full"); got != all {
+		t.Errorf("full Capabilities = %+v, want %+v", got, all)
+	}
+	if got, _ := r.Capabilities("plain"); got != (PolicyCapabilities{}) {
+		t.Errorf("plain Capabilities = %+v, want none", got)
+	}
+
+	p, _ := r.Get("full")
+	want := PolicyMetadata{Name: "full", Description: "answers in v2", Version: "2.1.0", Tags: []string{"v2"}, Phase: PhasePre, Priority: 5}
+	if got := MetadataOf(p); !reflect.DeepEqual(got, want) {
+		t.Errorf("MetadataOf = %+v, want %+v", got, want)
+	}
+	plain, _ := r.Get("plain")
+	if want := (PolicyMetadata{Name: "plain", Phase: PhaseMain, Priority: DefaultPriority}); !reflect.DeepEqual(MetadataOf(plain), want) {
+		t.Errorf("plain MetadataOf = %+v, want %+v", MetadataOf(plain), want)
+	}
+
+	config := &Config{Policies: map[string]PolicyConfig{"full": {Config: map[string]interface{}{"limit": 3}}}}
+	if err := ApplyConfig(r, config); err != nil {
+		t.Fatalf("ApplyConfig: %v", err)
+	}
+	if !reflect.DeepEqual(full.config, map[string]interface{}{"limit": 3}) {
+		t.Errorf("v2 policy configured with %v, want the config", full.config)
+	}
+}
+
+func TestV1AdapterUnwraps(t *testing.T) {
+	phased := &phasedPolicy{stubPolicy: stubPolicy{name: "pre"}, phase: PhasePre}
+	r := newTestRegistry()
+	if err := r.Register(phased); err != nil {
+		t.Fatal(err)
+	}
+	p, _ := r.GetV2("pre")
+	if wrapper, ok := p.(Wrapper); !ok || wrapper.Unwrap() != Policy(phased) {
+		t.Errorf("GetV2 returned %T, want a Wrapper around the v1 policy", p)
+	}
+	if adapted := AdaptV2(p); PhaseOf(adapted) != PhasePre {
+		t.Errorf("phase = %s, want pre", PhaseOf(adapted))
+	}
+}
+
+func TestV2PolicyWithoutResponse(t *testing.T) {
+	p := AdaptV2(&nilResponse{})
+	if _, err := p.Execute(context.Background(), nil); err == nil || err.Error() != "policy nil-response returned no response" {
+		t.Errorf("error = %v, want a no-response error", err)
+	}
+}
+
+// nilResponse is a PolicyV2 that returns neither a response nor an error
+type nilResponse struct{}
+
+func (nilResponse) Name() string    { return "nil-response" }
+func (nilResponse) Validate() error { return nil }
+
+func (nilResponse) Execute(context.Context, *Request) (*Response, error) {
+	return nil, nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// PrometheusCollector exports the statistics of a MetricsCollector as
+// Prometheus metrics labeled by policy name. Values are read from the
+// collector at scrape time, so no separate instrumentation is needed.
+type PrometheusCollector struct {
+	metrics    *MetricsCollector
+	executions *prometheus.Desc
+	errors     *prometheus.Desc
+	duration   *prometheus.Desc
+}
+
+// NewPrometheusCollector creates a prometheus.Collector reading from metrics
+func NewPrometheusCollector(metrics *MetricsCollector) *PrometheusCollector {
+	labels := []string{"policy"}
+	return &PrometheusCollector{
+		metrics: metrics,
+		executions: prometheus.NewDesc("policy_executions_total",
+			"Number of policy executions.", labels, nil),
+		errors: prometheus.NewDesc("policy_execution_errors_total",
+			"Number of policy executions that returned an error.", labels, nil),
+		duration: prometheus.NewDesc("policy_execution_duration_seconds",
+			"Policy execution duration in seconds.", labels, nil),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *PrometheusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.executions
+	ch <- c.errors
+	ch <- c.duration
+}
+
+// Collect implements prometheus.Collector
+func (c *PrometheusCollector) Collect(ch chan<- prometheus.Metric) {
+	c.metrics.mu.RLock()
+	defer c.metrics.mu.RUnlock()
+
+	for name, counters := range c.metrics.counters {
+		count := counters.count.Load()
+		ch <- prometheus.MustNewConstMetric(c.executions, prometheus.CounterValue, float64(count), name)
+		ch <- prometheus.MustNewConstMetric(c.errors, prometheus.CounterValue, float64(counters.errors.Load()), name)
+
+		// Prometheus buckets are cumulative
+		buckets := make(map[float64]uint64, len(durationBuckets))
+		var cumulative uint64
+		for i, bound := range durationBuckets {
+			cumulative += counters.buckets[i].Load()
+			buckets[bound] = cumulative
+		}
+		sum := float64(counters.duration.Load()) / 1e9
+		ch <- prometheus.MustNewConstHistogram(c.duration, count, sum, buckets, name)
+	}
+}
+
+// newMetricsHandler serves the registry's metrics in the Prometheus
+// exposition format
+func newMetricsHandler(registry *PolicyRegistry) http.Handler {
+	promRegistry := prometheus.NewRegistry()
+	promRegistry.MustRegister(NewPrometheusCollector(registry.metrics))
+	return promhttp.HandlerFor(promRegistry, promhttp.HandlerOpts{})
+}
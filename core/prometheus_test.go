@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetricsHandlerScrape(t *testing.T) {
+	r := newTestRegistry()
+	for _, p := range []Policy{&stubPolicy{name: "ok"}, failing("bad", errors.New("boom"))} {
+		if err := r.Register(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r.ExecuteAll(context.Background(), nil)
+	r.ExecuteAll(context.Background(), nil)
+
+	server := httptest.NewServer(newMetricsHandler(r))
+	defer server.Close()
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := string(data)
+
+	for _, want := range []string{
+		"# TYPE policy_executions_total counter",
+		"# TYPE policy_execution_errors_total counter",
+		"# TYPE policy_execution_duration_seconds histogram",
+		`policy_executions_total{policy="ok"} 2`,
+		`policy_executions_total{policy="bad"} 2`,
+		`policy_execution_errors_total{policy="ok"} 0`,
+		`policy_execution_errors_total{policy="bad"} 2`,
+		`policy_execution_duration_seconds_count{policy="ok"} 2`,
+		`policy_execution_duration_seconds_bucket{policy="ok",le="+Inf"} 2`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("scrape is missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestMetricsHandlerEmpty(t *testing.T) {
+	rec := httptest.NewRecorder()
+	newMetricsHandler(newTestRegistry()).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", rec.Code)
+	}
+	if strings.Contains(rec.Body.String(), "policy_executions_total{") {
+		t.Errorf("scrape of an unused registry has samples:\n%s", rec.Body.String())
+	}
+}
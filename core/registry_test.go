@@ -0,0 +1,278 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// TestRegistryConcurrentAccess is meant to run under -race: writers
+// register distinct policies while readers look them up and list them
+func TestRegistryConcurrentAccess(t *testing.T) {
+	const writers, perWriter = 8, 50
+	r := newTestRegistry()
+
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(2)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				name := fmt.Sprintf("policy-%d-%d", w, i)
+				if err := r.Register(&stubPolicy{name: name}); err != nil {
+					t.Errorf("Register(%s): %v", name, err)
+				}
+			}
+		}(w)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				r.Get(fmt.Sprintf("policy-%d-%d", w, i))
+				r.List()
+				r.ListByPhase()
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	if got := len(r.List()); got != writers*perWriter {
+		t.Fatalf("List returned %d names, want %d", got, writers*perWriter)
+	}
+	for w := 0; w < writers; w++ {
+		for i := 0; i < perWriter; i++ {
+			if _, ok := r.Get(fmt.Sprintf("policy-%d-%d", w, i)); !ok {
+				t.Errorf("policy-%d-%d not registered", w, i)
+			}
+		}
+	}
+}
+
+func TestRegisterDuplicate(t *testing.T) {
+	r := newTestRegistry()
+	first := &stubPolicy{name: "validator-policy"}
+	if err := r.Register(first); err != nil {
+		t.Fatal(err)
+	}
+
+	err := r.Register(&stubPolicy{name: "validator-policy"})
+	if !errors.Is(err, ErrDuplicatePolicy) {
+		t.Fatalf("second Register error = %v, want ErrDuplicatePolicy", err)
+	}
+	if got, _ := r.Get("validator-policy"); got != first {
+		t.Error("duplicate Register replaced the registered policy")
+	}
+}
+
+func TestRegisterForce(t *testing.T) {
+	r := newTestRegistry()
+	if err := r.Register(&stubPolicy{name: "validator-policy"}); err != nil {
+		t.Fatal(err)
+	}
+
+	second := &stubPolicy{name: "validator-policy"}
+	if err := r.RegisterForce(second); err != nil {
+		t.Fatalf("RegisterForce: %v", err)
+	}
+	if got, _ := r.Get("validator-policy"); got != second {
+		t.Error("RegisterForce did not replace the registered policy")
+	}
+	if names := r.List(); len(names) != 1 {
+		t.Errorf("List = %v, want one policy", names)
+	}
+
+	// RegisterForce still validates the policy
+	invalid := &stubPolicy{name: "validator-policy", validateErr: errors.New("bad config")}
+	if err := r.RegisterForce(invalid); err == nil {
+		t.Error("RegisterForce accepted an invalid policy")
+	}
+	if got, _ := r.Get("validator-policy"); got != second {
+		t.Error("failed RegisterForce replaced the registered policy")
+	}
+}
+
+func TestUnregister(t *testing.T) {
+	r := newTestRegistry()
+	for _, name := range []string{"a", "b"} {
+		if err := r.Register(&stubPolicy{name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := r.Disable("a"); err != nil {
+		t.Fatal(err)
+	}
+
+	if !r.Unregister("a") {
+		t.Error("Unregister(a) = false, want true")
+	}
+	if r.Unregister("a") {
+		t.Error("second Unregister(a) = true, want false")
+	}
+	if _, ok := r.Get("a"); ok {
+		t.Error("Get(a) still finds the removed policy")
+	}
+	if names := r.List(); len(names) != 1 || names[0] != "b" {
+		t.Errorf("List = %v, want [b]", names)
+	}
+
+	// Registering the name again starts from a clean slate
+	if err := r.Register(&stubPolicy{name: "a"}); err != nil {
+		t.Fatalf("re-Register(a): %v", err)
+	}
+	if !r.IsEnabled("a") {
+		t.Error("re-registered policy is still disabled")
+	}
+}
+
+func TestDisable(t *testing.T) {
+	r := newTestRegistry()
+	var ran []string
+	for _, name := range []string{"a", "b", "c"} {
+		name := name
+		p := &stubPolicy{name: name, execute: func(context.Context, interface{}) (interface{}, error) {
+			ran = append(ran, name)
+			return name, nil
+		}}
+		if err := r.Register(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		// Disabling twice has no further effect
+		if err := r.Disable("b"); err != nil {
+			t.Fatalf("Disable(b): %v", err)
+		}
+	}
+	results, err := r.ExecuteAll(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "c"}; !reflect.DeepEqual(ran, want) {
+		t.Errorf("ran %v, want %v", ran, want)
+	}
+	if _, exists := results["b"]; exists {
+		t.Error("ExecuteAll has a result for the disabled policy")
+	}
+
+	// The disabled policy stays discoverable
+	if r.IsEnabled("b") {
+		t.Error("IsEnabled(b) = true after Disable")
+	}
+	if _, ok := r.Get("b"); !ok {
+		t.Error("Get(b) does not find the disabled policy")
+	}
+	if names := r.List(); len(names) != 3 {
+		t.Errorf("List = %v, want all three policies", names)
+	}
+	if names := r.ListByPhase(); len(names) != 3 {
+		t.Errorf("ListByPhase = %v, want all three policies", names)
+	}
+	if names := r.ListEnabled(); !reflect.DeepEqual(names, []string{"a", "c"}) {
+		t.Errorf("ListEnabled = %v, want [a c]", names)
+	}
+
+	if err := r.Enable("b"); err != nil {
+		t.Fatalf("Enable(b): %v", err)
+	}
+	ran = nil
+	if _, err := r.ExecuteAll(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(ran, want) {
+		t.Errorf("after Enable ran %v, want %v", ran, want)
+	}
+	if names := r.ListEnabled(); len(names) != 3 {
+		t.Errorf("ListEnabled after Enable = %v, want all three policies", names)
+	}
+
+	for _, toggle := range []func(string) error{r.Disable, r.Enable} {
+		if err := toggle("missing"); !errors.Is(err, ErrPolicyNotFound) {
+			t.Errorf("toggling an unregistered policy = %v, want ErrPolicyNotFound", err)
+		}
+	}
+}
+
+func TestClear(t *testing.T) {
+	r := newTestRegistry()
+	for _, name := range []string{"a", "b"} {
+		if err := r.Register(&stubPolicy{name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r.Clear()
+	if names := r.List(); len(names) != 0 {
+		t.Errorf("List after Clear = %v, want none", names)
+	}
+	if _, ok := r.Get("a"); ok {
+		t.Error("Get(a) finds a policy after Clear")
+	}
+	if err := r.Register(&stubPolicy{name: "a"}); err != nil {
+		t.Errorf("Register after Clear: %v", err)
+	}
+}
+
+func TestGetOrError(t *testing.T) {
+	r := newTestRegistry()
+	registered := &stubPolicy{name: "present"}
+	if err := r.Register(registered); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := r.GetOrError("present")
+	if err != nil || got != registered {
+		t.Errorf("GetOrError(present) = %v, %v; want the registered policy", got, err)
+	}
+
+	got, err = r.GetOrError("absent")
+	if got != nil {
+		t.Errorf("GetOrError(absent) policy = %v, want nil", got)
+	}
+	if !errors.Is(err, ErrPolicyNotFound) {
+		t.Fatalf("GetOrError(absent) error = %v, want ErrPolicyNotFound", err)
+	}
+	if want := "policy not found: absent"; err.Error() != want {
+		t.Errorf("error = %q, want %q", err, want)
+	}
+
+	// The sentinel survives further wrapping
+	wrapped := fmt.Errorf("loading pipeline: %w", err)
+	if !errors.Is(wrapped, ErrPolicyNotFound) {
+		t.Error("errors.Is does not find ErrPolicyNotFound through %w")
+	}
+}
+
+func TestValidateAll(t *testing.T) {
+	r := newTestRegistry()
+	if err := r.ValidateAll(); err != nil {
+		t.Errorf("ValidateAll on an empty registry = %v, want nil", err)
+	}
+
+	// Policies validate when registered, so break them afterwards
+	errBadURL := errors.New("bad url")
+	broken := []*stubPolicy{{name: "zeta"}, {name: "alpha"}}
+	for _, p := range append(broken, &stubPolicy{name: "fine"}) {
+		if err := r.Register(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := r.ValidateAll(); err != nil {
+		t.Fatalf("ValidateAll = %v, want nil", err)
+	}
+	broken[0].validateErr = errors.New("missing field")
+	broken[1].validateErr = errBadURL
+
+	err := r.ValidateAll()
+	if err == nil {
+		t.Fatal("ValidateAll succeeded with two invalid policies")
+	}
+	if want := "policy alpha: bad url\npolicy zeta: missing field"; err.Error() != want {
+		t.Errorf("error = %q, want %q", err, want)
+	}
+	if !errors.Is(err, errBadURL) {
+		t.Error("errors.Is does not find a policy's own error")
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import "encoding/json"
+
+// PolicyResult is the recommended shape for policy results. Execute still
+// returns interface{}, so policies returning plain maps keep working.
+// Policies cannot import this package, so they declare a struct with the
+// same fields and JSON tags; the engine reads any result through its JSON
+// encoding, so such a struct is handled exactly like this one.
+type PolicyResult struct {
+	// Policy is the name of the policy that produced the result
+	Policy string `json:"policy"`
+
+	// Action briefly describes what the policy did
+	Action string `json:"action"`
+
+	// Status is the outcome, such as "PASSED" or "FAILED"
+	Status string `json:"status,omitempty"`
+
+	// Message explains the outcome for humans
+	Message string `json:"message,omitempty"`
+
+	// Output is the rewritten input of a transforming policy; pipelines
+	// pass it on to the next stage
+	Output map[string]interface{} `json:"output,omitempty"`
+
+	// Data holds any further policy-specific details
+	Data map[string]interface{} `json:"data,omitempty"`
+}
+
+// resultObject returns a policy result as a JSON-style object. Maps are
+// returned unchanged; other values, such as PolicyResult structs, are
+// converted through their JSON encoding. ok is false for results that are
+// not JSON objects.
+func resultObject(result interface{}) (map[string]interface{}, bool) {
+	if obj, ok := result.(map[string]interface{}); ok {
+		return obj, true
+	}
+	if result == nil {
+		return nil, false
+	}
+	return jsonObject(result)
+}
+
+// resultOutput returns the output object of a result, the rewritten input
+// of a transforming policy. ok is false for results without one.
+func resultOutput(result interface{}) (map[string]interface{}, bool) {
+	resultMap, ok := resultObject(result)
+	if !ok {
+		return nil, false
+	}
+	output, ok := resultMap["output"].(map[string]interface{})
+	return output, ok
+}
+
+// jsonObject converts value to an object through its JSON encoding, so
+// nested values take their decoded forms, such as float64 for numbers
+func jsonObject(value interface{}) (map[string]interface{}, bool) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, false
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal(data, &obj); err != nil || obj == nil {
+		return nil, false
+	}
+	return obj, true
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestPolicyResultJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		result PolicyResult
+		want   string
+	}{
+		{
+			name:   "empty fields are omitted",
+			result: PolicyResult{Policy: "p", Action: "check"},
+			want:   `{"policy":"p","action":"check"}`,
+		},
+		{
+			name: "all fields",
+			result: PolicyResult{
+				Policy:  "p",
+				Action:  "check",
+				Status:  "PASSED",
+				Message: "ok",
+				Output:  map[string]interface{}{"a": "A"},
+				Data:    map[string]interface{}{"count": 1},
+			},
+			want: `{"policy":"p","action":"check","status":"PASSED","message":"ok","output":{"a":"A"},"data":{"count":1}}`,
+		},
+		{
+			name:   "empty maps are omitted",
+			result: PolicyResult{Policy: "p", Action: "check", Output: map[string]interface{}{}, Data: map[string]interface{}{}},
+			want:   `{"policy":"p","action":"check"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.result)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("Marshal = %s, want %s", data, tt.want)
+			}
+
+			var decoded PolicyResult
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			again, _ := json.Marshal(decoded)
+			if string(again) != tt.want {
+				t.Errorf("round trip = %s, want %s", again, tt.want)
+			}
+		})
+	}
+}
+
+func TestResultObject(t *testing.T) {
+	// A policy's own result type with the same JSON tags
+	type localResult struct {
+		Policy string                 `json:"policy"`
+		Output map[string]interface{} `json:"output,omitempty"`
+	}
+
+	original := map[string]interface{}{"policy": "p"}
+	tests := []struct {
+		name   string
+		result interface{}
+		want   map[string]interface{}
+		wantOK bool
+	}{
+		{"map is returned as is", original, original, true},
+		{"struct pointer", &PolicyResult{Policy: "p", Action: "a"}, map[string]interface{}{"policy": "p", "action": "a"}, true},
+		{"policy-local struct", localResult{Policy: "p", Output: map[string]interface{}{"n": 1}}, map[string]interface{}{"policy": "p", "output": map[string]interface{}{"n": float64(1)}}, true},
+		{"nil", nil, nil, false},
+		{"string", "text", nil, false},
+		{"slice", []int{1}, nil, false},
+		{"unencodable", make(chan int), nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := resultObject(tt.result)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resultObject = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStageOutputOfStructResult(t *testing.T) {
+	result := &PolicyResult{Policy: "p", Action: "a", Output: map[string]interface{}{"k": "v"}}
+	got := stageOutput(result)
+	want := map[string]interface{}{"k": "v"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("stageOutput = %#v, want %#v", got, want)
+	}
+}
+
+func TestSummarizeStageOfStructResult(t *testing.T) {
+	input := map[string]interface{}{"n": 3, "s": "x"}
+	result := &PolicyResult{
+		Policy: "p",
+		Action: "a",
+		Status: "PASSED",
+		Output: map[string]interface{}{"n": 3, "s": "X", "added": true},
+	}
+
+	stage := SummarizeStage(&stubPolicy{name: "p"}, input, result, nil)
+	if stage.Status != "PASSED" {
+		t.Errorf("Status = %q, want PASSED", stage.Status)
+	}
+	// n went through JSON as a float64 but must not count as changed
+	if !reflect.DeepEqual(stage.Changed, []string{"s"}) {
+		t.Errorf("Changed = %v, want [s]", stage.Changed)
+	}
+	if !reflect.DeepEqual(stage.Added, []string{"added"}) {
+		t.Errorf("Added = %v, want [added]", stage.Added)
+	}
+}
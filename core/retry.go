@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ExecuteWithRetry runs policy.Execute up to attempts times, stopping at
+// the first success. See ExecuteWithRetryIf.
+func ExecuteWithRetry(ctx context.Context, policy Policy, input interface{}, attempts int, backoff time.Duration) (interface{}, error) {
+	return ExecuteWithRetryIf(ctx, policy, input, attempts, backoff, nil)
+}
+
+// ExecuteWithRetryIf runs policy.Execute up to attempts times, stopping at
+// the first success or at the first error that retryable rejects. A nil
+// retryable retries every error. The wait before each retry starts at
+// backoff and doubles after every attempt. Cancelling ctx stops the
+// retries, including during a wait, and returns the context's error. When
+// every attempt fails, the last error is returned, wrapped with the
+// attempt count.
+func ExecuteWithRetryIf(ctx context.Context, policy Policy, input interface{}, attempts int, backoff time.Duration, retryable func(error) bool) (interface{}, error) {
+	if attempts < 1 {
+		return nil, fmt.Errorf("attempts must be at least 1, got %d", attempts)
+	}
+
+	wait := backoff
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		result, err := safeExecute(ctx, policy, input)
+		if err == nil {
+			return result, nil
+		}
+		if retryable != nil && !retryable(err) {
+			return nil, err
+		}
+		if attempt == attempts {
+			return nil, fmt.Errorf("policy %s failed after %d attempt(s): %w", policy.Name(), attempts, err)
+		}
+
+		if wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, ctx.Err()
+			case <-timer.C:
+			}
+			wait *= 2
+		}
+	}
+}
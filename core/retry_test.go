@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// flaky returns a policy that fails with err on its first failures calls,
+// then succeeds, and a pointer to its call count
+func flaky(failures int, err error) (*stubPolicy, *int) {
+	calls := 0
+	return &stubPolicy{name: "flaky", execute: func(context.Context, interface{}) (interface{}, error) {
+		calls++
+		if calls <= failures {
+			return nil, err
+		}
+		return "ok", nil
+	}}, &calls
+}
+
+func TestExecuteWithRetry(t *testing.T) {
+	errTransient := errors.New("connection reset")
+	tests := []struct {
+		name      string
+		failures  int
+		attempts  int
+		wantCalls int
+		wantErr   string
+	}{
+		{"first try", 0, 3, 1, ""},
+		{"succeeds after failures", 2, 3, 3, ""},
+		{"always fails", 5, 3, 3, "policy flaky failed after 3 attempt(s): connection reset"},
+		{"single attempt", 1, 1, 1, "policy flaky failed after 1 attempt(s): connection reset"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, calls := flaky(tt.failures, errTransient)
+			result, err := ExecuteWithRetry(context.Background(), policy, nil, tt.attempts, time.Millisecond)
+			if *calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", *calls, tt.wantCalls)
+			}
+			if tt.wantErr == "" {
+				if err != nil || result != "ok" {
+					t.Errorf("ExecuteWithRetry = %v, %v; want ok", result, err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+			if !errors.Is(err, errTransient) {
+				t.Error("errors.Is does not find the policy's error")
+			}
+		})
+	}
+}
+
+func TestExecuteWithRetryBackoff(t *testing.T) {
+	policy, _ := flaky(3, errors.New("fail"))
+	start := time.Now()
+	if _, err := ExecuteWithRetry(context.Background(), policy, nil, 4, 10*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	// The waits double: 10ms, 20ms and 40ms
+	if elapsed := time.Since(start); elapsed < 70*time.Millisecond {
+		t.Errorf("three retries took %v, want at least 70ms", elapsed)
+	}
+}
+
+func TestExecuteWithRetryIf(t *testing.T) {
+	errPermanent := errors.New("invalid input")
+	policy, calls := flaky(5, errPermanent)
+	retryable := func(err error) bool { return !errors.Is(err, errPermanent) }
+
+	_, err := ExecuteWithRetryIf(context.Background(), policy, nil, 5, 0, retryable)
+	if err != errPermanent {
+		t.Errorf("error = %v, want the unwrapped permanent error", err)
+	}
+	if *calls != 1 {
+		t.Errorf("calls = %d, want 1", *calls)
+	}
+}
+
+func TestExecuteWithRetryCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	policy := &stubPolicy{name: "p", execute: func(context.Context, interface{}) (interface{}, error) {
+		calls++
+		cancel()
+		return nil, errors.New("fail")
+	}}
+
+	// The cancel interrupts the hour-long wait before the second attempt
+	done := make(chan error, 1)
+	go func() {
+		_, err := ExecuteWithRetry(ctx, policy, nil, 3, time.Hour)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("error = %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("ExecuteWithRetry kept waiting after cancellation")
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+
+	// An already cancelled context runs nothing
+	calls = 0
+	if _, err := ExecuteWithRetry(ctx, policy, nil, 3, 0); !errors.Is(err, context.Canceled) || calls != 0 {
+		t.Errorf("error = %v after %d calls, want context.Canceled and no calls", err, calls)
+	}
+}
+
+func TestExecuteWithRetryPanic(t *testing.T) {
+	var pe *PanicError
+	if _, err := ExecuteWithRetry(context.Background(), panicking("p", "boom"), nil, 2, 0); !errors.As(err, &pe) {
+		t.Errorf("error = %v, want a *PanicError", err)
+	}
+}
+
+func TestExecuteWithRetryInvalidAttempts(t *testing.T) {
+	if _, err := ExecuteWithRetry(context.Background(), &stubPolicy{name: "p"}, nil, 0, 0); err == nil {
+		t.Error("zero attempts succeeded, want error")
+	}
+}
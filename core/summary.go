@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// StageSummary describes what one policy did during a run
+type StageSummary struct {
+	Policy  string
+	Phase   string
+	Status  string
+	Added   []string
+	Removed []string
+	Changed []string
+	Err     error
+}
+
+// SummarizeStage builds the summary for one policy execution. The status is
+// taken from the result's "status" field when present. Transformations are
+// found by diffing the input against the result's "output" object, the
+// convention transforming policies use to return their rewritten input.
+func SummarizeStage(policy Policy, input, result interface{}, err error) StageSummary {
+	stage := StageSummary{
+		Policy: policy.Name(),
+		Phase:  PhaseOf(policy),
+	}
+
+	if err != nil {
+		stage.Status = "ERROR"
+		stage.Err = err
+		return stage
+	}
+
+	stage.Status = "OK"
+	resultMap, ok := resultObject(result)
+	if !ok {
+		return stage
+	}
+	if status, ok := resultMap["status"].(string); ok && status != "" {
+		stage.Status = status
+	}
+
+	before, beforeIsObj := input.(map[string]interface{})
+	if _, isMap := result.(map[string]interface{}); !isMap && beforeIsObj {
+		// The output went through JSON, so compare it with the input in
+		// the same form rather than reporting every number as changed
+		before, beforeIsObj = jsonObject(input)
+	}
+	after, afterIsObj := resultMap["output"].(map[string]interface{})
+	if beforeIsObj && afterIsObj {
+		diffObjects(&stage, before, after, nil)
+		sort.Strings(stage.Added)
+		sort.Strings(stage.Removed)
+		sort.Strings(stage.Changed)
+	}
+	return stage
+}
+
+// diffObjects records added, removed and changed fields as dotted paths,
+// descending into objects present on both sides
+func diffObjects(stage *StageSummary, before, after map[string]interface{}, path []string) {
+	for key, afterValue := range after {
+		keyPath := append(path[:len(path):len(path)], key)
+		joined := strings.Join(keyPath, ".")
+
+		beforeValue, exists := before[key]
+		if !exists {
+			stage.Added = append(stage.Added, joined)
+			continue
+		}
+
+		beforeObj, beforeIsObj := beforeValue.(map[string]interface{})
+		afterObj, afterIsObj := afterValue.(map[string]interface{})
+		if beforeIsObj && afterIsObj {
+			diffObjects(stage, beforeObj, afterObj, keyPath)
+			continue
+		}
+
+		if !reflect.DeepEqual(beforeValue, afterValue) {
+			stage.Changed = append(stage.Changed, joined)
+		}
+	}
+
+	for key := range before {
+		if _, exists := after[key]; !exists {
+			stage.Removed = append(stage.Removed, strings.Join(append(path[:len(path):len(path)], key), "."))
+		}
+	}
+}
+
+// WriteRunSummary prints a human-readable summary of a run, one block per
+// stage in execution order
+func WriteRunSummary(w io.Writer, stages []StageSummary) {
+	fmt.Fprintf(w, "Run summary (%d stages)\n", len(stages))
+	for i, stage := range stages {
+		fmt.Fprintf(w, "%d. %s [%s phase]: %s\n", i+1, stage.Policy, stage.Phase, stage.Status)
+		if stage.Err != nil {
+			fmt.Fprintf(w, "   error: %v\n", stage.Err)
+			continue
+		}
+		if len(stage.Added)+len(stage.Removed)+len(stage.Changed) == 0 {
+			fmt.Fprintln(w, "   no fields changed")
+			continue
+		}
+		writeFieldList(w, "added", stage.Added)
+		writeFieldList(w, "removed", stage.Removed)
+		writeFieldList(w, "changed", stage.Changed)
+	}
+}
+
+func writeFieldList(w io.Writer, label string, fields []string) {
+	if len(fields) > 0 {
+		fmt.Fprintf(w, "   %s: %s\n", label, strings.Join(fields, ", "))
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// copyObject returns a shallow copy of a map input
+func copyObject(input interface{}) map[string]interface{} {
+	out := make(map[string]interface{})
+	for key, value := range input.(map[string]interface{}) {
+		out[key] = value
+	}
+	return out
+}
+
+func TestRunSummaryReflectsPipeline(t *testing.T) {
+	stages := []Policy{
+		&phasedPolicy{phase: PhasePre, stubPolicy: stubPolicy{name: "normalize", execute: func(_ context.Context, input interface{}) (interface{}, error) {
+			output := copyObject(input)
+			output["name"] = strings.ToUpper(output["name"].(string))
+			delete(output, "tmp")
+			return &PolicyResult{Policy: "normalize", Action: "normalization", Status: "PASSED", Output: output}, nil
+		}}},
+		&stubPolicy{name: "enrich", execute: func(_ context.Context, input interface{}) (interface{}, error) {
+			output := copyObject(input)
+			output["user"] = map[string]interface{}{"id": "u1", "country": "LK"}
+			output["score"] = 0.9
+			return map[string]interface{}{"status": "TRANSFORMED", "output": output}, nil
+		}},
+		&stubPolicy{name: "relocate", execute: func(_ context.Context, input interface{}) (interface{}, error) {
+			output := copyObject(input)
+			output["user"] = map[string]interface{}{"id": "u1", "country": "SG"}
+			return map[string]interface{}{"output": output}, nil
+		}},
+		&stubPolicy{name: "check", execute: func(context.Context, interface{}) (interface{}, error) {
+			return &PolicyResult{Policy: "check", Action: "validation", Status: "FAILED"}, nil
+		}},
+		&phasedPolicy{phase: PhasePost, stubPolicy: stubPolicy{name: "publish", execute: func(context.Context, interface{}) (interface{}, error) {
+			return nil, errors.New("broker unavailable")
+		}}},
+	}
+
+	r := newPipelineRegistry(t, stages...)
+	pipeline, err := NewPipeline(r, []string{"normalize", "enrich", "relocate", "check", "publish"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var summaries []StageSummary
+	pipeline.OnStage = func(stage StageSummary) {
+		summaries = append(summaries, stage)
+	}
+
+	input := map[string]interface{}{"name": "ada", "age": 36, "tmp": true}
+	if _, err := pipeline.Execute(context.Background(), input); err == nil || !strings.Contains(err.Error(), "broker unavailable") {
+		t.Fatalf("Execute error = %v, want the publish stage error", err)
+	}
+
+	var out strings.Builder
+	WriteRunSummary(&out, summaries)
+	want := `Run summary (5 stages)
+1. normalize [pre phase]: PASSED
+   removed: tmp
+   changed: name
+2. enrich [main phase]: TRANSFORMED
+   added: score, user
+3. relocate [main phase]: OK
+   changed: user.country
+4. check [main phase]: FAILED
+   no fields changed
+5. publish [post phase]: ERROR
+   error: broker unavailable
+`
+	if out.String() != want {
+		t.Errorf("summary:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestSummarizeStage(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  interface{}
+		result interface{}
+		want   StageSummary
+	}{
+		{
+			name:   "nested changes",
+			input:  map[string]interface{}{"a": map[string]interface{}{"b": 1, "c": 2}, "d": 1},
+			result: map[string]interface{}{"output": map[string]interface{}{"a": map[string]interface{}{"b": 2, "e": 3}, "d": 1}},
+			want:   StageSummary{Policy: "p", Phase: PhaseMain, Status: "OK", Added: []string{"a.e"}, Removed: []string{"a.c"}, Changed: []string{"a.b"}},
+		},
+		{
+			// The struct result reaches the summary through JSON, so 36 and
+			// 36.0 must not count as a change
+			name:   "struct result numbers",
+			input:  map[string]interface{}{"age": 36},
+			result: &PolicyResult{Status: "PASSED", Output: map[string]interface{}{"age": 36}},
+			want:   StageSummary{Policy: "p", Phase: PhaseMain, Status: "PASSED"},
+		},
+		{
+			name:   "object replaced by a value",
+			input:  map[string]interface{}{"a": map[string]interface{}{"b": 1}},
+			result: map[string]interface{}{"output": map[string]interface{}{"a": "flat"}},
+			want:   StageSummary{Policy: "p", Phase: PhaseMain, Status: "OK", Changed: []string{"a"}},
+		},
+		{
+			name:   "result without output",
+			input:  map[string]interface{}{"a": 1},
+			result: map[string]interface{}{"status": "PASSED"},
+			want:   StageSummary{Policy: "p", Phase: PhaseMain, Status: "PASSED"},
+		},
+		{
+			name:   "result not an object",
+			input:  map[string]interface{}{"a": 1},
+			result: "done",
+			want:   StageSummary{Policy: "p", Phase: PhaseMain, Status: "OK"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SummarizeStage(&stubPolicy{name: "p"}, tt.input, tt.result, nil)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SummarizeStage = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"runtime"
+	"time"
+)
+
+// ResourceUsage is the heap allocation and CPU cost of one policy execution.
+//
+// The figures are process-wide deltas taken before and after the call:
+// allocation counts come from runtime.ReadMemStats and CPU time from the
+// operating system's usage counters for the whole process. They are only
+// attributable to the policy when nothing else runs at the same time; with
+// concurrent executions or background goroutines every measurement also
+// includes their work. ReadMemStats briefly stops the world, so collecting
+// usage itself adds overhead and should not be enabled on hot paths.
+type ResourceUsage struct {
+	Allocs  uint64        `json:"allocs"`
+	Bytes   uint64        `json:"bytes"`
+	CPUTime time.Duration `json:"cpu_time"`
+}
+
+// usageSnapshot is a point-in-time reading of the counters behind
+// ResourceUsage
+type usageSnapshot struct {
+	mallocs    uint64
+	totalAlloc uint64
+	cpu        time.Duration
+}
+
+func takeUsageSnapshot() usageSnapshot {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	return usageSnapshot{
+		mallocs:    mem.Mallocs,
+		totalAlloc: mem.TotalAlloc,
+		cpu:        processCPUTime(),
+	}
+}
+
+// since returns the usage accumulated between s and now
+func (s usageSnapshot) since() *ResourceUsage {
+	now := takeUsageSnapshot()
+	return &ResourceUsage{
+		Allocs:  now.mallocs - s.mallocs,
+		Bytes:   now.totalAlloc - s.totalAlloc,
+		CPUTime: now.cpu - s.cpu,
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// sink keeps the test allocations from being optimized away
+var sink [][]byte
+
+func TestExecutePolicyCollectsUsage(t *testing.T) {
+	const allocations, size = 100, 1024
+	heavy := &stubPolicy{name: "heavy", execute: func(_ context.Context, input interface{}) (interface{}, error) {
+		for i := 0; i < allocations; i++ {
+			sink = append(sink, make([]byte, size))
+		}
+		// Spin until the process CPU time moves on, which it does in
+		// clock ticks on some systems
+		start, deadline := processCPUTime(), time.Now().Add(time.Second)
+		for processCPUTime() == start && time.Now().Before(deadline) {
+		}
+		return input, nil
+	}}
+	t.Cleanup(func() { sink = nil })
+
+	execution := ExecutePolicy(context.Background(), heavy, nil, ExecuteOptions{CollectUsage: true})
+	if execution.Err != nil {
+		t.Fatal(execution.Err)
+	}
+	usage := execution.Usage
+	if usage == nil {
+		t.Fatal("Usage is nil with CollectUsage set")
+	}
+	if usage.Allocs < allocations {
+		t.Errorf("Allocs = %d, want at least %d", usage.Allocs, allocations)
+	}
+	if usage.Bytes < allocations*size {
+		t.Errorf("Bytes = %d, want at least %d", usage.Bytes, allocations*size)
+	}
+	// Platforms without process CPU counters always report zero
+	if processCPUTime() > 0 && usage.CPUTime <= 0 {
+		t.Errorf("CPUTime = %s, want a positive duration", usage.CPUTime)
+	}
+}
+
+func TestExecutePolicyWithoutUsage(t *testing.T) {
+	execution := ExecutePolicy(context.Background(), &stubPolicy{name: "light"}, "input", ExecuteOptions{})
+	if execution.Usage != nil {
+		t.Errorf("Usage = %+v without CollectUsage, want nil", execution.Usage)
+	}
+	if execution.Result != "input" || execution.Policy != "light" {
+		t.Errorf("execution = %+v, want the light policy's result", execution)
+	}
+}
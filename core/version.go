@@ -0,0 +1,233 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// EngineVersion is the semantic version of the policy API the engine
+// provides. The major version changes whenever a change could break
+// existing policies.
+const EngineVersion = "1.0.0"
+
+// EngineVersioned is implemented by policies that only work with some
+// engine versions. Register rejects the policy unless EngineVersion
+// satisfies the constraint it returns; an empty constraint accepts any
+// version.
+//
+// A constraint is a list of comparisons separated by commas or spaces, all
+// of which must hold, such as ">=1.2.0, <2.0.0". The operators are =, !=,
+// >, >=, <, <=, ^ (same major version, or same minor version below 1.0.0)
+// and ~ (same minor version); a bare version means =. Versions are
+// complete MAJOR.MINOR.PATCH semantic versions with an optional leading v,
+// and compare by semantic version precedence.
+type EngineVersioned interface {
+	RequiredEngineVersion() string
+}
+
+// ErrIncompatibleEngine is returned by Register when a policy requires an
+// engine version that EngineVersion does not satisfy
+var ErrIncompatibleEngine = errors.New("incompatible engine version")
+
+// checkEngineVersion fails if the policy's required engine version is
+// malformed or not satisfied by EngineVersion. Wrapped policies are
+// checked against the requirement of the policy they wrap.
+func checkEngineVersion(p Policy) error {
+	versioned, ok := unwrapPolicy(p).(EngineVersioned)
+	if !ok {
+		return nil
+	}
+	constraint := versioned.RequiredEngineVersion()
+
+	ok, err := satisfies(EngineVersion, constraint)
+	if err != nil {
+		return fmt.Errorf("policy %s: invalid required engine version %q: %w", p.Name(), constraint, err)
+	}
+	if !ok {
+		return fmt.Errorf("%w: policy %s requires %q, engine is %s", ErrIncompatibleEngine, p.Name(), constraint, EngineVersion)
+	}
+	return nil
+}
+
+// semver is a parsed semantic version. Build metadata is dropped because
+// it does not affect precedence.
+type semver struct {
+	major, minor, patch uint64
+	pre                 []string
+}
+
+// parseSemver parses MAJOR.MINOR.PATCH[-PRERELEASE][+BUILD], with an
+// optional leading v
+func parseSemver(s string) (semver, error) {
+	var v semver
+	rest := strings.TrimPrefix(s, "v")
+	if i := strings.IndexByte(rest, '+'); i >= 0 {
+		if rest[i+1:] == "" {
+			return v, fmt.Errorf("version %q: empty build metadata", s)
+		}
+		rest = rest[:i]
+	}
+	if i := strings.IndexByte(rest, '-'); i >= 0 {
+		v.pre = strings.Split(rest[i+1:], ".")
+		for _, id := range v.pre {
+			if id == "" {
+				return v, fmt.Errorf("version %q: empty pre-release identifier", s)
+			}
+		}
+		rest = rest[:i]
+	}
+
+	parts := strings.Split(rest, ".")
+	if len(parts) != 3 {
+		return v, fmt.Errorf("version %q: expected MAJOR.MINOR.PATCH", s)
+	}
+	numbers := [3]*uint64{&v.major, &v.minor, &v.patch}
+	for i, part := range parts {
+		n, err := strconv.ParseUint(part, 10, 64)
+		if err != nil || (len(part) > 1 && part[0] == '0') {
+			return v, fmt.Errorf("version %q: invalid number %q", s, part)
+		}
+		*numbers[i] = n
+	}
+	return v, nil
+}
+
+// compareSemver returns -1, 0 or 1 as a has lower, equal or higher
+// precedence than b
+func compareSemver(a, b semver) int {
+	for _, pair := range [][2]uint64{{a.major, b.major}, {a.minor, b.minor}, {a.patch, b.patch}} {
+		if pair[0] != pair[1] {
+			if pair[0] < pair[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	// A pre-release has lower precedence than the release itself
+	switch {
+	case len(a.pre) == 0 && len(b.pre) == 0:
+		return 0
+	case len(a.pre) == 0:
+		return 1
+	case len(b.pre) == 0:
+		return -1
+	}
+	for i := 0; i < len(a.pre) && i < len(b.pre); i++ {
+		if c := comparePrerelease(a.pre[i], b.pre[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(a.pre) < len(b.pre):
+		return -1
+	case len(a.pre) > len(b.pre):
+		return 1
+	}
+	return 0
+}
+
+// comparePrerelease compares pre-release identifiers: numeric identifiers
+// numerically and below alphanumeric ones, which compare as strings
+func comparePrerelease(a, b string) int {
+	an, aErr := strconv.ParseUint(a, 10, 64)
+	bn, bErr := strconv.ParseUint(b, 10, 64)
+	switch {
+	case aErr == nil && bErr == nil:
+		if an != bn {
+			if an < bn {
+				return -1
+			}
+			return 1
+		}
+		return 0
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// satisfies reports whether version meets every comparison in constraint
+func satisfies(version, constraint string) (bool, error) {
+	v, err := parseSemver(version)
+	if err != nil {
+		return false, err
+	}
+
+	// Split on commas and spaces, joining a lone operator such as ">=" in
+	// ">= 1.0.0" with the version after it
+	var comparisons []string
+	pending := ""
+	for _, field := range strings.FieldsFunc(constraint, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	}) {
+		if strings.Trim(field, "=!<>^~") == "" {
+			pending += field
+			continue
+		}
+		comparisons = append(comparisons, pending+field)
+		pending = ""
+	}
+	if pending != "" {
+		return false, fmt.Errorf("operator %q has no version", pending)
+	}
+
+	for _, comparison := range comparisons {
+		ok, err := compareWith(v, comparison)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// compareWith reports whether v meets a single comparison such as ">=1.0.0"
+func compareWith(v semver, comparison string) (bool, error) {
+	// Longer operators first so ">=" is not read as ">"
+	op := ""
+	for _, candidate := range []string{">=", "<=", "!=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(comparison, candidate) {
+			op = candidate
+			break
+		}
+	}
+	target, err := parseSemver(comparison[len(op):])
+	if err != nil {
+		return false, err
+	}
+
+	c := compareSemver(v, target)
+	switch op {
+	case "", "=":
+		return c == 0, nil
+	case "!=":
+		return c != 0, nil
+	case ">":
+		return c > 0, nil
+	case ">=":
+		return c >= 0, nil
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case "~":
+		upper := semver{major: target.major, minor: target.minor + 1}
+		return c >= 0 && compareSemver(v, upper) < 0, nil
+	default: // "^"
+		var upper semver
+		switch {
+		case target.major > 0:
+			upper = semver{major: target.major + 1}
+		case target.minor > 0:
+			upper = semver{minor: target.minor + 1}
+		default:
+			upper = semver{patch: target.patch + 1}
+		}
+		return c >= 0 && compareSemver(v, upper) < 0, nil
+	}
+}
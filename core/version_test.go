@@ -0,0 +1,193 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// requiresEngine is a policy requiring the engine versions in required
+type requiresEngine struct {
+	stubPolicy
+	required string
+}
+
+func (p *requiresEngine) RequiredEngineVersion() string { return p.required }
+
+func TestSatisfies(t *testing.T) {
+	tests := []struct {
+		version    string
+		constraint string
+		want       bool
+	}{
+		{"1.0.0", "", true},
+		{"1.0.0", "1.0.0", true},
+		{"1.0.0", "v1.0.0", true},
+		{"1.0.0", "=1.0.1", false},
+		{"1.0.0", "!=1.0.1", true},
+		{"1.2.0", ">=1.0.0", true},
+		{"1.2.0", ">= 1.0.0", true},
+		{"0.9.0", ">=1.0.0", false},
+		{"1.10.0", ">1.9.0", true},
+		{"2.0.0", "<2.0.0", false},
+		{"1.9.9", "<=1.9.9", true},
+		{"1.2.0", ">=1.0.0, <2.0.0", true},
+		{"2.0.0", ">=1.0.0 <2.0.0", false},
+		{"1.9.0", "^1.2.0", true},
+		{"2.0.0", "^1.2.0", false},
+		{"0.2.5", "^0.2.0", true},
+		{"0.3.0", "^0.2.0", false},
+		{"0.0.3", "^0.0.3", true},
+		{"0.0.4", "^0.0.3", false},
+		{"1.2.9", "~1.2.0", true},
+		{"1.3.0", "~1.2.0", false},
+		{"1.0.0-rc.1", "<1.0.0", true},
+		{"1.0.0-alpha", "<1.0.0-beta", true},
+		{"1.0.0-2", "<1.0.0-10", true},
+		{"1.0.0-alpha.1", ">1.0.0-alpha", true},
+		{"1.0.0+build.5", "=1.0.0", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version+" "+tt.constraint, func(t *testing.T) {
+			got, err := satisfies(tt.version, tt.constraint)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("satisfies(%q, %q) = %v, want %v", tt.version, tt.constraint, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSatisfiesInvalid(t *testing.T) {
+	for _, constraint := range []string{"1.0", "latest", ">=", "1.0.0-", "01.0.0", "1.0.0+", ">=1.0.x", ">=1.0.0, <"} {
+		if _, err := satisfies("1.0.0", constraint); err == nil {
+			t.Errorf("satisfies(1.0.0, %q) succeeded, want error", constraint)
+		}
+	}
+}
+
+func TestRegisterChecksEngineVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		required string
+		wantErr  string
+	}{
+		{"no requirement", "", ""},
+		{"compatible", ">=1.0.0, <2.0.0", ""},
+		{"caret", "^" + EngineVersion, ""},
+		{"too old", ">=2.0.0", `incompatible engine version: policy p requires ">=2.0.0", engine is ` + EngineVersion},
+		{"too new", "<1.0.0", `incompatible engine version: policy p requires "<1.0.0", engine is ` + EngineVersion},
+		{"malformed", ">=one", `policy p: invalid required engine version ">=one"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newTestRegistry().Register(&requiresEngine{stubPolicy: stubPolicy{name: "p"}, required: tt.required})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Register = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+				t.Errorf("Register = %v, want %q", err, tt.wantErr)
+			}
+			if strings.HasPrefix(tt.wantErr, "incompatible") && !errors.Is(err, ErrIncompatibleEngine) {
+				t.Error("errors.Is does not find ErrIncompatibleEngine")
+			}
+		})
+	}
+}
+
+// wrappers returns each of the engine's policy wrappers around inner
+func wrappers(inner Policy) map[string]Policy {
+	return map[string]Policy{
+		"caching":     NewCachingPolicy(inner, time.Minute, 10),
+		"middleware":  WithMiddleware(inner),
+		"determinism": NewDeterminismPolicy(inner),
+		"nested":      NewCachingPolicy(WithMiddleware(NewDeterminismPolicy(inner)), 0, 0),
+	}
+}
+
+func TestWrappersCheckEngineVersion(t *testing.T) {
+	inner := &requiresEngine{stubPolicy: stubPolicy{name: "p"}, required: ">=2.0.0"}
+	for name, wrapped := range wrappers(inner) {
+		t.Run(name, func(t *testing.T) {
+			if unwrapPolicy(wrapped) != Policy(inner) {
+				t.Fatal("unwrapPolicy does not return the innermost policy")
+			}
+			if err := newTestRegistry().Register(wrapped); !errors.Is(err, ErrIncompatibleEngine) {
+				t.Errorf("Register = %v, want ErrIncompatibleEngine from the wrapped policy", err)
+			}
+		})
+	}
+}
+
+func TestRegisterV2ChecksEngineVersion(t *testing.T) {
+	r := newTestRegistry()
+	err := r.RegisterV2(&fullV2{v2Echo: v2Echo{name: "future"}, required: ">=9.0.0"})
+	if !errors.Is(err, ErrIncompatibleEngine) {
+		t.Fatalf("RegisterV2 = %v, want ErrIncompatibleEngine", err)
+	}
+	if _, ok := r.Get("future"); ok {
+		t.Error("incompatible v2 policy was registered")
+	}
+	if err := r.RegisterV2(&fullV2{v2Echo: v2Echo{name: "current"}, required: ">=1.0.0"}); err != nil {
+		t.Errorf("RegisterV2 compatible = %v, want nil", err)
+	}
+}
+
+func TestWrappersKeepMetadata(t *testing.T) {
+	inner := &taggedPolicy{stubPolicy: stubPolicy{name: "tagged"}, tags: []string{"x"}, priority: 4}
+	want := MetadataOf(inner)
+
+	for name, wrapped := range wrappers(inner) {
+		t.Run(name, func(t *testing.T) {
+			if got := MetadataOf(wrapped); !reflect.DeepEqual(got, want) {
+				t.Errorf("MetadataOf = %+v, want %+v", got, want)
+			}
+
+			r := newTestRegistry()
+			if err := r.Register(wrapped); err != nil {
+				t.Fatal(err)
+			}
+			got, _ := r.Capabilities("tagged")
+			if want := (PolicyCapabilities{Phased: true, Prioritized: true, Described: true, Versioned: true, Tagged: true}); got != want {
+				t.Errorf("Capabilities = %+v, want %+v", got, want)
+			}
+		})
+	}
+
+	// A wrapper around a plain policy has no capabilities of its own
+	r := newTestRegistry()
+	if err := r.Register(NewCachingPolicy(&stubPolicy{name: "plain"}, 0, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := r.Capabilities("plain"); got != (PolicyCapabilities{}) {
+		t.Errorf("Capabilities = %+v, want none", got)
+	}
+}
+
+func TestWrappersAcceptConfig(t *testing.T) {
+	for name := range wrappers(&stubPolicy{}) {
+		t.Run(name, func(t *testing.T) {
+			inner := &configurablePolicy{stubPolicy: stubPolicy{name: "p"}}
+			r := newTestRegistry()
+			if err := r.Register(wrappers(inner)[name]); err != nil {
+				t.Fatal(err)
+			}
+			config := &Config{Policies: map[string]PolicyConfig{"p": {Config: map[string]interface{}{"limit": 3}}}}
+			if err := ApplyConfig(r, config); err != nil {
+				t.Fatalf("ApplyConfig: %v", err)
+			}
+			if !reflect.DeepEqual(inner.config, map[string]interface{}{"limit": 3}) {
+				t.Errorf("wrapped policy configured with %v, want the config", inner.config)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package arrayscalarpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Policy implements the policy engine interface
+// It normalizes inconsistent API shapes by wrapping scalar fields into
+// single-element arrays or unwrapping single-element arrays into scalars
+type Policy struct {
+	// Wrap lists fields whose scalar values should become one-element arrays
+	Wrap []string `json:"wrap"`
+
+	// Unwrap lists fields whose one-element arrays should become scalars
+	Unwrap []string `json:"unwrap"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "arrayscalar-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	result := policyresult.New(p.Name(), "array/scalar normalization")
+
+	output := make(map[string]interface{}, len(inputMap))
+	for key, value := range inputMap {
+		output[key] = value
+	}
+
+	wrapped := []string{}
+	for _, field := range p.Wrap {
+		value, exists := output[field]
+		if !exists || isSlice(value) {
+			continue
+		}
+		output[field] = []interface{}{value}
+		wrapped = append(wrapped, field)
+	}
+
+	unwrapped := []string{}
+	notUnwrappable := []map[string]interface{}{}
+	for _, field := range p.Unwrap {
+		value, exists := output[field]
+		if !exists || !isSlice(value) {
+			continue
+		}
+
+		v := reflect.ValueOf(value)
+		if v.Len() != 1 {
+			notUnwrappable = append(notUnwrappable, map[string]interface{}{
+				"field":  field,
+				"length": v.Len(),
+			})
+			continue
+		}
+		output[field] = v.Index(0).Interface()
+		unwrapped = append(unwrapped, field)
+	}
+
+	result.Data["input"] = inputMap
+	result.Output = output
+	result.Data["wrapped"] = wrapped
+	result.Data["unwrapped"] = unwrapped
+	result.Data["not_unwrappable"] = notUnwrappable
+
+	if len(notUnwrappable) > 0 {
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("%d field(s) could not be unwrapped", len(notUnwrappable))
+	} else {
+		result.Status = "PASSED"
+		result.Message = "All fields normalized"
+	}
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	wrap := make(map[string]bool, len(p.Wrap))
+	for _, field := range p.Wrap {
+		wrap[field] = true
+	}
+	for _, field := range p.Unwrap {
+		if wrap[field] {
+			return fmt.Errorf("field %q cannot be both wrapped and unwrapped", field)
+		}
+	}
+	return nil
+}
+
+// isSlice reports whether the value is an array or slice of any element type
+func isSlice(value interface{}) bool {
+	if value == nil {
+		return false
+	}
+	kind := reflect.TypeOf(value).Kind()
+	return kind == reflect.Slice || kind == reflect.Array
+}
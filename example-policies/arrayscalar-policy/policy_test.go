@@ -0,0 +1,125 @@
+package arrayscalarpolicy
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+func TestExecute(t *testing.T) {
+	p := &Policy{Wrap: []string{"tags"}, Unwrap: []string{"owner"}}
+
+	tests := []struct {
+		name           string
+		input          map[string]interface{}
+		wantOutput     map[string]interface{}
+		wantStatus     string
+		wantWrapped    []string
+		wantUnwrapped  []string
+		wantNotUnwrapd int
+	}{
+		{
+			name:        "wrap scalar",
+			input:       map[string]interface{}{"tags": "a"},
+			wantOutput:  map[string]interface{}{"tags": []interface{}{"a"}},
+			wantStatus:  "PASSED",
+			wantWrapped: []string{"tags"},
+		},
+		{
+			name:       "array left unwrapped",
+			input:      map[string]interface{}{"tags": []interface{}{"a", "b"}},
+			wantOutput: map[string]interface{}{"tags": []interface{}{"a", "b"}},
+			wantStatus: "PASSED",
+		},
+		{
+			name:          "unwrap single element",
+			input:         map[string]interface{}{"owner": []interface{}{"ann"}},
+			wantOutput:    map[string]interface{}{"owner": "ann"},
+			wantStatus:    "PASSED",
+			wantUnwrapped: []string{"owner"},
+		},
+		{
+			name:          "unwrap typed slice",
+			input:         map[string]interface{}{"owner": []string{"ann"}},
+			wantOutput:    map[string]interface{}{"owner": "ann"},
+			wantStatus:    "PASSED",
+			wantUnwrapped: []string{"owner"},
+		},
+		{
+			name:           "multi-element array cannot be unwrapped",
+			input:          map[string]interface{}{"owner": []interface{}{"ann", "bob"}},
+			wantOutput:     map[string]interface{}{"owner": []interface{}{"ann", "bob"}},
+			wantStatus:     "FAILED",
+			wantNotUnwrapd: 1,
+		},
+		{
+			name:           "empty array cannot be unwrapped",
+			input:          map[string]interface{}{"owner": []interface{}{}},
+			wantOutput:     map[string]interface{}{"owner": []interface{}{}},
+			wantStatus:     "FAILED",
+			wantNotUnwrapd: 1,
+		},
+		{
+			name:       "missing fields are ignored",
+			input:      map[string]interface{}{"other": 1},
+			wantOutput: map[string]interface{}{"other": 1},
+			wantStatus: "PASSED",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.Execute(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if result.Status != tt.wantStatus {
+				t.Errorf("Status = %q, want %q", result.Status, tt.wantStatus)
+			}
+			if !reflect.DeepEqual(result.Output, tt.wantOutput) {
+				t.Errorf("Output = %#v, want %#v", result.Output, tt.wantOutput)
+			}
+			if wrapped := result.Data["wrapped"].([]string); len(wrapped) != len(tt.wantWrapped) {
+				t.Errorf("wrapped = %v, want %v", wrapped, tt.wantWrapped)
+			}
+			if unwrapped := result.Data["unwrapped"].([]string); len(unwrapped) != len(tt.wantUnwrapped) {
+				t.Errorf("unwrapped = %v, want %v", unwrapped, tt.wantUnwrapped)
+			}
+			if bad := result.Data["not_unwrappable"].([]map[string]interface{}); len(bad) != tt.wantNotUnwrapd {
+				t.Errorf("not_unwrappable = %v, want %d entries", bad, tt.wantNotUnwrapd)
+			}
+		})
+	}
+}
+
+func TestExecuteDoesNotModifyInput(t *testing.T) {
+	p := &Policy{Wrap: []string{"tags"}}
+	input := map[string]interface{}{"tags": "a"}
+	if _, err := p.Execute(context.Background(), input); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if input["tags"] != "a" {
+		t.Errorf("input was modified: %v", input)
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Policy{}).Validate(); err != nil {
+		t.Errorf("zero value: %v", err)
+	}
+	if err := (&Policy{Wrap: []string{"a"}, Unwrap: []string{"a"}}).Validate(); err == nil {
+		t.Error("field both wrapped and unwrapped passed validation")
+	}
+}
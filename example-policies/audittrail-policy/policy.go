@@ -0,0 +1,183 @@
+package audittrailpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Policy implements the policy engine interface
+// It compares the old and new versions of an entity and produces a change
+// list suitable for an audit log. Nested objects are compared field by
+// field and reported with dotted paths; arrays and other values are
+// compared whole. Fields listed in Ignore, and anything beneath them, are
+// not tracked.
+type Policy struct {
+	// OldField names the input field with the previous version (default "old")
+	OldField string `json:"old_field"`
+
+	// NewField names the input field with the current version (default "new")
+	NewField string `json:"new_field"`
+
+	// Ignore lists dotted field paths that are not tracked, e.g. "updated_at"
+	Ignore []string `json:"ignore"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "audittrail-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	result := policyresult.New(p.Name(), "audit trail")
+
+	oldValue, hasOld := inputMap[p.oldField()]
+	newValue, hasNew := inputMap[p.newField()]
+	if !hasOld && !hasNew {
+		result.Status = "PASSED"
+		result.Message = "No entity versions to compare"
+		return result, nil
+	}
+
+	oldObj, err := asObject(oldValue, p.oldField())
+	if err != nil {
+		return nil, err
+	}
+	newObj, err := asObject(newValue, p.newField())
+	if err != nil {
+		return nil, err
+	}
+
+	ignored := make(map[string]bool, len(p.Ignore))
+	for _, path := range p.Ignore {
+		ignored[path] = true
+	}
+
+	changes := []map[string]interface{}{}
+	diff(oldObj, newObj, nil, ignored, &changes)
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i]["field"].(string) < changes[j]["field"].(string)
+	})
+
+	result.Status = "PASSED"
+	result.Data["changes"] = changes
+	result.Message = fmt.Sprintf("%d field(s) changed", len(changes))
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	if p.oldField() == p.newField() {
+		return fmt.Errorf("old_field and new_field must differ, both are %q", p.oldField())
+	}
+	return nil
+}
+
+func (p *Policy) oldField() string {
+	if p.OldField == "" {
+		return "old"
+	}
+	return p.OldField
+}
+
+func (p *Policy) newField() string {
+	if p.NewField == "" {
+		return "new"
+	}
+	return p.NewField
+}
+
+// asObject treats a missing version as an empty entity, so creations and
+// deletions show up as additions and removals
+func asObject(value interface{}, field string) (map[string]interface{}, error) {
+	if value == nil {
+		return map[string]interface{}{}, nil
+	}
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("field %q: expected object, got %T", field, value)
+	}
+	return obj, nil
+}
+
+// diff appends a change entry for every added, modified or removed field
+func diff(oldObj, newObj map[string]interface{}, path []string, ignored map[string]bool, changes *[]map[string]interface{}) {
+	for key, newValue := range newObj {
+		keyPath := append(path[:len(path):len(path)], key)
+		joined := strings.Join(keyPath, ".")
+		if ignored[joined] {
+			continue
+		}
+
+		oldValue, exists := oldObj[key]
+		if !exists {
+			*changes = append(*changes, map[string]interface{}{
+				"field": joined,
+				"op":    "added",
+				"new":   newValue,
+			})
+			continue
+		}
+
+		oldNested, oldIsObj := oldValue.(map[string]interface{})
+		newNested, newIsObj := newValue.(map[string]interface{})
+		if oldIsObj && newIsObj {
+			diff(oldNested, newNested, keyPath, ignored, changes)
+			continue
+		}
+
+		if !reflect.DeepEqual(oldValue, newValue) {
+			*changes = append(*changes, map[string]interface{}{
+				"field": joined,
+				"op":    "modified",
+				"old":   oldValue,
+				"new":   newValue,
+			})
+		}
+	}
+
+	for key, oldValue := range oldObj {
+		if _, exists := newObj[key]; exists {
+			continue
+		}
+		joined := strings.Join(append(path[:len(path):len(path)], key), ".")
+		if ignored[joined] {
+			continue
+		}
+		*changes = append(*changes, map[string]interface{}{
+			"field": joined,
+			"op":    "removed",
+			"old":   oldValue,
+		})
+	}
+}
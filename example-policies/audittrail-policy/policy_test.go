@@ -0,0 +1,167 @@
+package audittrailpolicy
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      *Policy
+		input       map[string]interface{}
+		wantChanges []map[string]interface{}
+	}{
+		{
+			name:        "unchanged",
+			policy:      &Policy{},
+			input:       map[string]interface{}{"old": map[string]interface{}{"a": 1, "tags": []interface{}{"x"}}, "new": map[string]interface{}{"a": 1, "tags": []interface{}{"x"}}},
+			wantChanges: []map[string]interface{}{},
+		},
+		{
+			name:   "addition",
+			policy: &Policy{},
+			input:  map[string]interface{}{"old": map[string]interface{}{"a": 1}, "new": map[string]interface{}{"a": 1, "b": "x"}},
+			wantChanges: []map[string]interface{}{
+				{"field": "b", "op": "added", "new": "x"},
+			},
+		},
+		{
+			name:   "modification",
+			policy: &Policy{},
+			input: map[string]interface{}{
+				"old": map[string]interface{}{"email": "a@x", "tags": []interface{}{"x"}},
+				"new": map[string]interface{}{"email": "b@x", "tags": []interface{}{"x", "y"}},
+			},
+			wantChanges: []map[string]interface{}{
+				{"field": "email", "op": "modified", "old": "a@x", "new": "b@x"},
+				{"field": "tags", "op": "modified", "old": []interface{}{"x"}, "new": []interface{}{"x", "y"}},
+			},
+		},
+		{
+			name:   "removal",
+			policy: &Policy{},
+			input:  map[string]interface{}{"old": map[string]interface{}{"a": 1, "b": nil}, "new": map[string]interface{}{"a": 1}},
+			wantChanges: []map[string]interface{}{
+				{"field": "b", "op": "removed", "old": nil},
+			},
+		},
+		{
+			name:   "nested fields use dotted paths",
+			policy: &Policy{},
+			input: map[string]interface{}{
+				"old": map[string]interface{}{"address": map[string]interface{}{"city": "Colombo", "zip": "00100"}, "profile": "basic"},
+				"new": map[string]interface{}{"address": map[string]interface{}{"city": "Kandy", "country": "LK"}, "profile": map[string]interface{}{"tier": "gold"}},
+			},
+			wantChanges: []map[string]interface{}{
+				{"field": "address.city", "op": "modified", "old": "Colombo", "new": "Kandy"},
+				{"field": "address.country", "op": "added", "new": "LK"},
+				{"field": "address.zip", "op": "removed", "old": "00100"},
+				{"field": "profile", "op": "modified", "old": "basic", "new": map[string]interface{}{"tier": "gold"}},
+			},
+		},
+		{
+			name:   "ignored fields and their subtrees",
+			policy: &Policy{Ignore: []string{"updated_at", "meta", "address.geo"}},
+			input: map[string]interface{}{
+				"old": map[string]interface{}{"updated_at": 1, "meta": map[string]interface{}{"v": 1}, "address": map[string]interface{}{"geo": "a"}, "name": "ada"},
+				"new": map[string]interface{}{"updated_at": 2, "meta": map[string]interface{}{"v": 2}, "address": map[string]interface{}{}, "name": "grace"},
+			},
+			wantChanges: []map[string]interface{}{
+				{"field": "name", "op": "modified", "old": "ada", "new": "grace"},
+			},
+		},
+		{
+			name:   "creation",
+			policy: &Policy{},
+			input:  map[string]interface{}{"old": nil, "new": map[string]interface{}{"id": 1}},
+			wantChanges: []map[string]interface{}{
+				{"field": "id", "op": "added", "new": 1},
+			},
+		},
+		{
+			name:   "deletion with custom fields",
+			policy: &Policy{OldField: "before", NewField: "after"},
+			input:  map[string]interface{}{"before": map[string]interface{}{"id": 1}},
+			wantChanges: []map[string]interface{}{
+				{"field": "id", "op": "removed", "old": 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.policy.Execute(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if result.Status != "PASSED" {
+				t.Errorf("status = %s, want PASSED", result.Status)
+			}
+			if !reflect.DeepEqual(result.Data["changes"], tt.wantChanges) {
+				t.Errorf("changes = %v, want %v", result.Data["changes"], tt.wantChanges)
+			}
+		})
+	}
+}
+
+func TestExecuteNoVersions(t *testing.T) {
+	got, err := (&Policy{}).Execute(context.Background(), map[string]interface{}{"other": 1})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	result := got.(*policyresult.Result)
+	if result.Status != "PASSED" || result.Data["changes"] != nil {
+		t.Errorf("result = %s %v, want PASSED without changes", result.Status, result.Data["changes"])
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input interface{}
+	}{
+		{"not a map", []interface{}{}},
+		{"old not an object", map[string]interface{}{"old": "x", "new": map[string]interface{}{}}},
+		{"new not an object", map[string]interface{}{"old": map[string]interface{}{}, "new": []interface{}{}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := (&Policy{}).Execute(context.Background(), tt.input); err == nil {
+				t.Error("Execute succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	if err := (&Policy{}).Configure(map[string]interface{}{"old_field": "prev", "ignore": []interface{}{"updated_at"}}); err != nil {
+		t.Errorf("Configure: %v", err)
+	}
+	if err := (&Policy{}).Configure(map[string]interface{}{"old_field": "v", "new_field": "v"}); err == nil {
+		t.Error("Configure accepted the same old and new field")
+	}
+	if err := (&Policy{}).Configure(map[string]interface{}{"old_field": "new"}); err == nil {
+		t.Error("Configure accepted an old field equal to the default new field")
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Policy{}).Validate(); err != nil {
+		t.Errorf("zero value: %v", err)
+	}
+}
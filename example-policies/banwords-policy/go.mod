@@ -0,0 +1,7 @@
+module github.com/example/policies/banwords-policy
+
+go 1.21
+
+require github.com/example/policies/policyresult v0.0.0
+
+replace github.com/example/policies/policyresult => ../policyresult
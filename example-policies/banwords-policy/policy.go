@@ -0,0 +1,268 @@
+package banwordspolicy
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Policy implements the policy engine interface
+// It scans string fields for banned terms and reports which terms were
+// found in which fields. Matching is case-insensitive unless configured
+// otherwise. In whole-word mode a term only matches when it is not
+// directly preceded or followed by a letter, digit or underscore, so
+// "ass" does not match "class". In masking mode every character of a match
+// is replaced in the output.
+type Policy struct {
+	// Fields lists the input fields to scan; missing fields are skipped
+	Fields []string `json:"fields"`
+
+	// Words lists the banned terms
+	Words []string `json:"words"`
+
+	// WordsFile names a file of further banned terms, one per line, loaded
+	// by Configure. Blank lines and lines starting with '#' are ignored.
+	WordsFile string `json:"words_file"`
+
+	// CaseSensitive disables case-insensitive matching
+	CaseSensitive bool `json:"case_sensitive"`
+
+	// WholeWord only matches terms that stand as whole words
+	WholeWord bool `json:"whole_word"`
+
+	// Mask replaces matches in the output instead of failing
+	Mask bool `json:"mask"`
+
+	// MaskChar is the replacement character used when masking (default "*")
+	MaskChar string `json:"mask_char"`
+
+	fileWords []string
+	matchers  []*matcher
+}
+
+// matcher finds one banned term
+type matcher struct {
+	term string
+	re   *regexp.Regexp
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "banwords-policy"
+}
+
+// Configure applies the given configuration to the policy and loads the
+// banned terms from the configured file
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	p.fileWords = nil
+	if p.WordsFile != "" {
+		words, err := loadWords(p.WordsFile)
+		if err != nil {
+			return fmt.Errorf("failed to load words: %w", err)
+		}
+		p.fileWords = words
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	output := make(map[string]interface{}, len(inputMap))
+	for key, value := range inputMap {
+		output[key] = value
+	}
+
+	found := map[string][]string{}
+	for _, field := range p.Fields {
+		value, exists := inputMap[field]
+		if !exists {
+			continue
+		}
+		text, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("field %q: expected string, got %T", field, value)
+		}
+
+		terms, spans := p.scan(text)
+		if len(terms) == 0 {
+			continue
+		}
+		found[field] = terms
+		if p.Mask {
+			output[field] = mask(text, spans, p.maskChar())
+		}
+	}
+
+	result := policyresult.New(p.Name(), "banned word scan")
+	result.Data["found"] = found
+
+	switch {
+	case len(found) == 0:
+		result.Status = "PASSED"
+		result.Message = "No banned terms found"
+	case p.Mask:
+		result.Status = "MASKED"
+		result.Message = fmt.Sprintf("Masked banned terms in %d field(s)", len(found))
+		result.Output = output
+	default:
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("Banned terms found in %d field(s)", len(found))
+	}
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid and compiles the
+// banned terms
+func (p *Policy) Validate() error {
+	if utf8.RuneCountInString(p.MaskChar) > 1 {
+		return fmt.Errorf("mask_char must be a single character, got %q", p.MaskChar)
+	}
+
+	terms := append(append([]string(nil), p.Words...), p.fileWords...)
+	seen := make(map[string]bool, len(terms))
+	p.matchers = make([]*matcher, 0, len(terms))
+	for _, term := range terms {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			return fmt.Errorf("banned terms must not be empty")
+		}
+		key := term
+		if !p.CaseSensitive {
+			key = strings.ToLower(term)
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		pattern := regexp.QuoteMeta(term)
+		if !p.CaseSensitive {
+			pattern = `(?i)` + pattern
+		}
+		p.matchers = append(p.matchers, &matcher{term: term, re: regexp.MustCompile(pattern)})
+	}
+	return nil
+}
+
+func (p *Policy) maskChar() string {
+	if p.MaskChar == "" {
+		return "*"
+	}
+	return p.MaskChar
+}
+
+// scan returns the banned terms found in text, sorted, and the byte spans
+// of every match
+func (p *Policy) scan(text string) ([]string, [][]int) {
+	var terms []string
+	var spans [][]int
+	for _, m := range p.matchers {
+		matched := false
+		for _, span := range m.re.FindAllStringIndex(text, -1) {
+			if p.WholeWord && !isWholeWord(text, span[0], span[1]) {
+				continue
+			}
+			matched = true
+			spans = append(spans, span)
+		}
+		if matched {
+			terms = append(terms, m.term)
+		}
+	}
+	sort.Strings(terms)
+	return terms, spans
+}
+
+// isWholeWord reports whether text[start:end] is not joined to a word
+// character on either side
+func isWholeWord(text string, start, end int) bool {
+	if start > 0 {
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		if isWordChar(before) {
+			return false
+		}
+	}
+	if end < len(text) {
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if isWordChar(after) {
+			return false
+		}
+	}
+	return true
+}
+
+func isWordChar(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// mask replaces every character inside the spans with maskChar
+func mask(text string, spans [][]int, maskChar string) string {
+	masked := make([]bool, len(text))
+	for _, span := range spans {
+		for i := span[0]; i < span[1]; i++ {
+			masked[i] = true
+		}
+	}
+
+	var b strings.Builder
+	for i, r := range text {
+		if masked[i] {
+			b.WriteString(maskChar)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// loadWords reads banned terms from a file, one per line
+func loadWords(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return words, nil
+}
@@ -0,0 +1,186 @@
+package banwordspolicy
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+// configure returns a policy configured with config
+func configure(t *testing.T, config map[string]interface{}) *Policy {
+	t.Helper()
+	p := &Policy{}
+	if err := p.Configure(config); err != nil {
+		t.Fatalf("Configure(%v): %v", config, err)
+	}
+	return p
+}
+
+func TestExecuteMatching(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    map[string]interface{}
+		text      string
+		wantFound []string
+	}{
+		{"substring", map[string]interface{}{"words": []interface{}{"ass"}}, "first class", []string{"ass"}},
+		{"whole word skips substrings", map[string]interface{}{"words": []interface{}{"ass"}, "whole_word": true}, "first class", nil},
+		{"whole word at punctuation", map[string]interface{}{"words": []interface{}{"spam"}, "whole_word": true}, "no spam, please", []string{"spam"}},
+		{"whole word joined by underscore", map[string]interface{}{"words": []interface{}{"spam"}, "whole_word": true}, "spam_filter", nil},
+		{"case-insensitive", map[string]interface{}{"words": []interface{}{"spam"}}, "SPAM here", []string{"spam"}},
+		{"case-sensitive", map[string]interface{}{"words": []interface{}{"spam"}, "case_sensitive": true}, "SPAM here", nil},
+		{"several terms sorted", map[string]interface{}{"words": []interface{}{"zap", "bad"}}, "bad zap bad", []string{"bad", "zap"}},
+		{"duplicate terms once", map[string]interface{}{"words": []interface{}{"bad", "BAD"}}, "bad", []string{"bad"}},
+		{"regexp characters literal", map[string]interface{}{"words": []interface{}{"a.b"}}, "axb", nil},
+		{"clean", map[string]interface{}{"words": []interface{}{"bad"}}, "all good", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config["fields"] = []interface{}{"comment"}
+			p := configure(t, tt.config)
+			got, err := p.Execute(context.Background(), map[string]interface{}{"comment": tt.text})
+			if err != nil {
+				t.Fatal(err)
+			}
+			result := got.(*policyresult.Result)
+			found := result.Data["found"].(map[string][]string)
+			if !reflect.DeepEqual(found["comment"], tt.wantFound) {
+				t.Errorf("found = %v, want %v", found["comment"], tt.wantFound)
+			}
+			wantStatus := "PASSED"
+			if tt.wantFound != nil {
+				wantStatus = "FAILED"
+			}
+			if result.Status != wantStatus {
+				t.Errorf("status = %s, want %s", result.Status, wantStatus)
+			}
+		})
+	}
+}
+
+func TestExecuteReportsFields(t *testing.T) {
+	p := configure(t, map[string]interface{}{"fields": []interface{}{"title", "body", "absent"}, "words": []interface{}{"bad", "worse"}})
+	got, err := p.Execute(context.Background(), map[string]interface{}{"title": "bad", "body": "worse and bad", "other": "bad"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]string{"title": {"bad"}, "body": {"bad", "worse"}}
+	if found := got.(*policyresult.Result).Data["found"]; !reflect.DeepEqual(found, want) {
+		t.Errorf("found = %v, want %v", found, want)
+	}
+}
+
+func TestExecuteMask(t *testing.T) {
+	tests := []struct {
+		name   string
+		config map[string]interface{}
+		text   string
+		want   string
+	}{
+		{"substring", map[string]interface{}{}, "Bad badge", "*** ***ge"},
+		{"whole word", map[string]interface{}{"whole_word": true}, "Bad badge", "*** badge"},
+		{"mask char", map[string]interface{}{"mask_char": "#"}, "bad", "###"},
+		{"multibyte", map[string]interface{}{"mask_char": "•"}, "café bad", "café •••"},
+		{"overlapping terms", map[string]interface{}{"words": []interface{}{"bad", "adx"}}, "badx", "****"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config["fields"] = []interface{}{"comment"}
+			tt.config["mask"] = true
+			if tt.config["words"] == nil {
+				tt.config["words"] = []interface{}{"bad"}
+			}
+			p := configure(t, tt.config)
+			input := map[string]interface{}{"comment": tt.text, "id": 1}
+			got, err := p.Execute(context.Background(), input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result := got.(*policyresult.Result)
+			if result.Status != "MASKED" || result.Output["comment"] != tt.want {
+				t.Errorf("status = %s, comment = %q; want MASKED, %q", result.Status, result.Output["comment"], tt.want)
+			}
+			if result.Output["id"] != 1 || input["comment"] != tt.text {
+				t.Error("masking lost other fields or changed the input")
+			}
+		})
+	}
+
+	// Clean input is passed without an output
+	p := configure(t, map[string]interface{}{"fields": []interface{}{"comment"}, "words": []interface{}{"bad"}, "mask": true})
+	got, err := p.Execute(context.Background(), map[string]interface{}{"comment": "fine"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := got.(*policyresult.Result); result.Status != "PASSED" || result.Output != nil {
+		t.Errorf("status = %s, output = %v; want PASSED and no output", result.Status, result.Output)
+	}
+}
+
+func TestConfigureWordsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "words.txt")
+	if err := os.WriteFile(path, []byte("# banned\nfoo\n\n  bar  \n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p := configure(t, map[string]interface{}{"fields": []interface{}{"text"}, "words": []interface{}{"baz"}, "words_file": path})
+	got, err := p.Execute(context.Background(), map[string]interface{}{"text": "foo bar baz # banned"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"bar", "baz", "foo"}
+	if found := got.(*policyresult.Result).Data["found"].(map[string][]string)["text"]; !reflect.DeepEqual(found, want) {
+		t.Errorf("found = %v, want %v", found, want)
+	}
+
+	if err := (&Policy{}).Configure(map[string]interface{}{"words_file": filepath.Join(t.TempDir(), "missing.txt")}); err == nil {
+		t.Error("missing words file succeeded, want error")
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	p := configure(t, map[string]interface{}{"fields": []interface{}{"n"}, "words": []interface{}{"bad"}})
+	if _, err := p.Execute(context.Background(), map[string]interface{}{"n": 7}); err == nil {
+		t.Error("non-string field succeeded, want error")
+	}
+	if _, err := p.Execute(context.Background(), "text"); err == nil {
+		t.Error("non-object input succeeded, want error")
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr bool
+	}{
+		{"zero value", map[string]interface{}{}, false},
+		{"words", map[string]interface{}{"fields": []interface{}{"a"}, "words": []interface{}{"x", "y"}, "whole_word": true}, false},
+		{"mask char", map[string]interface{}{"mask": true, "mask_char": "#"}, false},
+		{"long mask char", map[string]interface{}{"mask": true, "mask_char": "##"}, true},
+		{"empty word", map[string]interface{}{"words": []interface{}{" "}}, true},
+		{"words not a list", map[string]interface{}{"words": "bad"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := (&Policy{}).Configure(tt.config); (err != nil) != tt.wantErr {
+				t.Errorf("Configure(%v) = %v, wantErr %v", tt.config, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package batchconsistencypolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Policy implements the policy engine interface
+// It checks invariants that span every item of a batch rather than any one
+// item: fields whose values must be unique across the batch, and a field
+// whose sum must equal an expected total. The batch is an array of objects
+// in the input; the expected total is either configured or read from
+// another input field, such as a header total.
+type Policy struct {
+	// ItemsField names the input array holding the batch (default "items")
+	ItemsField string `json:"items_field"`
+
+	// UniqueFields lists item fields whose values must not repeat
+	UniqueFields []string `json:"unique_fields"`
+
+	// SumField names the numeric item field that is totalled
+	SumField string `json:"sum_field"`
+
+	// ExpectedTotal is the required sum of SumField
+	ExpectedTotal *float64 `json:"expected_total"`
+
+	// ExpectedTotalField names an input field holding the required sum; it
+	// is used when ExpectedTotal is not set
+	ExpectedTotalField string `json:"expected_total_field"`
+
+	// Tolerance is the allowed difference between sum and total (default 1e-9)
+	Tolerance float64 `json:"tolerance"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "batchconsistency-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	result := policyresult.New(p.Name(), "batch consistency")
+
+	rawItems, exists := inputMap[p.itemsField()]
+	if !exists {
+		result.Status = "PASSED"
+		result.Message = "No batch to check"
+		return result, nil
+	}
+	rawList, ok := rawItems.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("field %q: expected array of objects, got %T", p.itemsField(), rawItems)
+	}
+	items := make([]map[string]interface{}, len(rawList))
+	for i, raw := range rawList {
+		item, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("item %d: expected object, got %T", i, raw)
+		}
+		items[i] = item
+	}
+
+	violations := []map[string]interface{}{}
+	violations = append(violations, p.checkUnique(items)...)
+
+	if p.SumField != "" {
+		violation, err := p.checkSum(inputMap, items)
+		if err != nil {
+			return nil, err
+		}
+		if violation != nil {
+			violations = append(violations, violation)
+		}
+	}
+
+	result.Data["items"] = len(items)
+	result.Data["violations"] = violations
+
+	if len(violations) > 0 {
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("Batch violates %d invariant(s)", len(violations))
+	} else {
+		result.Status = "PASSED"
+		result.Message = fmt.Sprintf("Batch of %d item(s) is consistent", len(items))
+	}
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	if p.Tolerance < 0 {
+		return fmt.Errorf("tolerance must not be negative, got %v", p.Tolerance)
+	}
+	if p.SumField != "" && p.ExpectedTotal == nil && p.ExpectedTotalField == "" {
+		return fmt.Errorf("sum_field %q requires expected_total or expected_total_field", p.SumField)
+	}
+	return nil
+}
+
+func (p *Policy) itemsField() string {
+	if p.ItemsField == "" {
+		return "items"
+	}
+	return p.ItemsField
+}
+
+func (p *Policy) tolerance() float64 {
+	if p.Tolerance == 0 {
+		return 1e-9
+	}
+	return p.Tolerance
+}
+
+// checkUnique reports each value that appears in more than one item, with
+// the indexes of the items sharing it
+func (p *Policy) checkUnique(items []map[string]interface{}) []map[string]interface{} {
+	violations := []map[string]interface{}{}
+	for _, field := range p.UniqueFields {
+		seen := make(map[string][]int)
+		var order []string
+		for i, item := range items {
+			value, exists := item[field]
+			if !exists {
+				continue
+			}
+			key := fmt.Sprintf("%T:%v", value, value)
+			if _, ok := seen[key]; !ok {
+				order = append(order, key)
+			}
+			seen[key] = append(seen[key], i)
+		}
+
+		for _, key := range order {
+			indexes := seen[key]
+			if len(indexes) < 2 {
+				continue
+			}
+			violations = append(violations, map[string]interface{}{
+				"invariant": "unique",
+				"field":     field,
+				"value":     items[indexes[0]][field],
+				"items":     indexes,
+			})
+		}
+	}
+	return violations
+}
+
+// checkSum compares the total of SumField across items with the expected
+// total, returning a violation when they differ
+func (p *Policy) checkSum(inputMap map[string]interface{}, items []map[string]interface{}) (map[string]interface{}, error) {
+	var expected float64
+	if p.ExpectedTotal != nil {
+		expected = *p.ExpectedTotal
+	} else {
+		value, ok := toFloat(inputMap[p.ExpectedTotalField])
+		if !ok {
+			return nil, fmt.Errorf("field %q: expected a number, got %T", p.ExpectedTotalField, inputMap[p.ExpectedTotalField])
+		}
+		expected = value
+	}
+
+	var total float64
+	for i, item := range items {
+		value, exists := item[p.SumField]
+		if !exists {
+			continue
+		}
+		number, ok := toFloat(value)
+		if !ok {
+			return nil, fmt.Errorf("item %d field %q: expected a number, got %T", i, p.SumField, value)
+		}
+		total += number
+	}
+
+	if math.Abs(total-expected) <= p.tolerance() {
+		return nil, nil
+	}
+	return map[string]interface{}{
+		"invariant": "sum",
+		"field":     p.SumField,
+		"expected":  expected,
+		"actual":    total,
+	}, nil
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
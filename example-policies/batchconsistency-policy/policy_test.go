@@ -0,0 +1,179 @@
+package batchconsistencypolicy
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+func total(v float64) *float64 {
+	return &v
+}
+
+// orders is a batch of three order lines totalling 60
+func orders() []interface{} {
+	return []interface{}{
+		map[string]interface{}{"id": "o1", "sku": "A", "amount": 10.0},
+		map[string]interface{}{"id": "o2", "sku": "B", "amount": 20.0},
+		map[string]interface{}{"id": "o3", "sku": "A", "amount": 30.0},
+	}
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name           string
+		policy         *Policy
+		input          map[string]interface{}
+		wantStatus     string
+		wantViolations []map[string]interface{}
+	}{
+		{
+			name:           "consistent batch",
+			policy:         &Policy{UniqueFields: []string{"id"}, SumField: "amount", ExpectedTotal: total(60)},
+			input:          map[string]interface{}{"items": orders()},
+			wantStatus:     "PASSED",
+			wantViolations: []map[string]interface{}{},
+		},
+		{
+			name:           "total read from a header field",
+			policy:         &Policy{SumField: "amount", ExpectedTotalField: "total"},
+			input:          map[string]interface{}{"items": orders(), "total": 60},
+			wantStatus:     "PASSED",
+			wantViolations: []map[string]interface{}{},
+		},
+		{
+			name:       "duplicate values",
+			policy:     &Policy{UniqueFields: []string{"id", "sku"}},
+			input:      map[string]interface{}{"items": orders()},
+			wantStatus: "FAILED",
+			wantViolations: []map[string]interface{}{
+				{"invariant": "unique", "field": "sku", "value": "A", "items": []int{0, 2}},
+			},
+		},
+		{
+			name:       "sum differs from the expected total",
+			policy:     &Policy{UniqueFields: []string{"id"}, SumField: "amount", ExpectedTotal: total(50)},
+			input:      map[string]interface{}{"items": orders()},
+			wantStatus: "FAILED",
+			wantViolations: []map[string]interface{}{
+				{"invariant": "sum", "field": "amount", "expected": 50.0, "actual": 60.0},
+			},
+		},
+		{
+			name:           "sum within tolerance",
+			policy:         &Policy{SumField: "amount", ExpectedTotal: total(60.004), Tolerance: 0.01},
+			input:          map[string]interface{}{"items": orders()},
+			wantStatus:     "PASSED",
+			wantViolations: []map[string]interface{}{},
+		},
+		{
+			name:   "both invariants violated",
+			policy: &Policy{ItemsField: "lines", UniqueFields: []string{"id"}, SumField: "qty", ExpectedTotal: total(3)},
+			input: map[string]interface{}{"lines": []interface{}{
+				map[string]interface{}{"id": 1, "qty": 1},
+				map[string]interface{}{"id": 1, "qty": 1},
+				map[string]interface{}{"id": 2},
+				map[string]interface{}{"id": 1, "qty": 2},
+			}},
+			wantStatus: "FAILED",
+			wantViolations: []map[string]interface{}{
+				{"invariant": "unique", "field": "id", "value": 1, "items": []int{0, 1, 3}},
+				{"invariant": "sum", "field": "qty", "expected": 3.0, "actual": 4.0},
+			},
+		},
+		{
+			name:           "values of different types are distinct",
+			policy:         &Policy{UniqueFields: []string{"id"}},
+			input:          map[string]interface{}{"items": []interface{}{map[string]interface{}{"id": "1"}, map[string]interface{}{"id": 1.0}}},
+			wantStatus:     "PASSED",
+			wantViolations: []map[string]interface{}{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.policy.Execute(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if result.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s (%s)", result.Status, tt.wantStatus, result.Message)
+			}
+			if !reflect.DeepEqual(result.Data["violations"], tt.wantViolations) {
+				t.Errorf("violations = %v, want %v", result.Data["violations"], tt.wantViolations)
+			}
+		})
+	}
+}
+
+func TestExecuteNoBatch(t *testing.T) {
+	got, err := (&Policy{UniqueFields: []string{"id"}}).Execute(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result := got.(*policyresult.Result); result.Status != "PASSED" {
+		t.Errorf("status = %s, want PASSED", result.Status)
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	sum := &Policy{SumField: "amount", ExpectedTotalField: "total"}
+	tests := []struct {
+		name   string
+		policy *Policy
+		input  interface{}
+	}{
+		{"not a map", &Policy{}, []interface{}{}},
+		{"items not an array", &Policy{}, map[string]interface{}{"items": "x"}},
+		{"item not an object", &Policy{}, map[string]interface{}{"items": []interface{}{1}}},
+		{"missing expected total", sum, map[string]interface{}{"items": orders()}},
+		{"non-numeric amount", sum, map[string]interface{}{"total": 1, "items": []interface{}{map[string]interface{}{"amount": "ten"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.policy.Execute(context.Background(), tt.input); err == nil {
+				t.Error("Execute succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr bool
+	}{
+		{"valid", map[string]interface{}{"unique_fields": []interface{}{"id"}, "sum_field": "amount", "expected_total": 10}, false},
+		{"sum without total", map[string]interface{}{"sum_field": "amount"}, true},
+		{"negative tolerance", map[string]interface{}{"tolerance": -0.1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&Policy{}).Configure(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Configure error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Policy{}).Validate(); err != nil {
+		t.Errorf("zero value: %v", err)
+	}
+}
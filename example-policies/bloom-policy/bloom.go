@@ -0,0 +1,65 @@
+package bloompolicy
+
+import (
+	"hash/fnv"
+	"math"
+)
+
+// bloomFilter is a fixed-size bloom filter using double hashing to derive
+// its k bit positions from a single 64-bit FNV-1a hash
+type bloomFilter struct {
+	bits []uint64
+	m    uint64
+	k    uint64
+}
+
+// newBloomFilter sizes a filter for n items at the given false positive rate
+func newBloomFilter(n int, falsePositiveRate float64) *bloomFilter {
+	if n < 1 {
+		n = 1
+	}
+	m := uint64(math.Ceil(-float64(n) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	if m < 64 {
+		m = 64
+	}
+	k := uint64(math.Round(float64(m) / float64(n) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+	return &bloomFilter{
+		bits: make([]uint64, (m+63)/64),
+		m:    m,
+		k:    k,
+	}
+}
+
+// Add inserts a value into the filter
+func (f *bloomFilter) Add(value string) {
+	h1, h2 := hashes(value)
+	for i := uint64(0); i < f.k; i++ {
+		pos := (h1 + i*h2) % f.m
+		f.bits[pos/64] |= 1 << (pos % 64)
+	}
+}
+
+// Test reports whether the value may be in the filter. False positives are
+// possible, false negatives are not.
+func (f *bloomFilter) Test(value string) bool {
+	h1, h2 := hashes(value)
+	for i := uint64(0); i < f.k; i++ {
+		pos := (h1 + i*h2) % f.m
+		if f.bits[pos/64]&(1<<(pos%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// hashes splits a 64-bit FNV-1a hash into the two halves used for double
+// hashing. The second half is forced odd so it never degenerates to zero.
+func hashes(value string) (uint64, uint64) {
+	h := fnv.New64a()
+	h.Write([]byte(value))
+	sum := h.Sum64()
+	return sum & 0xffffffff, (sum >> 32) | 1
+}
@@ -0,0 +1,176 @@
+package bloompolicy
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Policy implements the policy engine interface
+// It tests configured field values against a bloom filter built from a
+// member list file, trading a small false positive rate for a memory
+// footprint far below that of the full set
+type Policy struct {
+	// Fields lists the input fields whose values are tested
+	Fields []string `json:"fields"`
+
+	// FilterFile is a newline-separated list of set members, loaded by Configure
+	FilterFile string `json:"filter_file"`
+
+	// FalsePositiveRate is the target false positive rate (default 0.01)
+	FalsePositiveRate float64 `json:"false_positive_rate"`
+
+	// Mode is "report" (default), "allow" to fail non-members, or "deny" to
+	// fail probable members
+	Mode string `json:"mode"`
+
+	filter *bloomFilter
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "bloom-policy"
+}
+
+// Configure applies the given configuration to the policy and loads the
+// bloom filter from the configured file
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	p.filter = nil
+	if p.FilterFile == "" {
+		return nil
+	}
+
+	filter, err := loadFilter(p.FilterFile, p.falsePositiveRate())
+	if err != nil {
+		return fmt.Errorf("failed to load bloom filter: %w", err)
+	}
+	p.filter = filter
+	return nil
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	result := policyresult.New(p.Name(), "bloom filter membership")
+	result.Data["mode"] = p.mode()
+
+	if p.filter == nil {
+		result.Status = "PASSED"
+		result.Message = "No bloom filter loaded"
+		return result, nil
+	}
+
+	probableMembers := []string{}
+	nonMembers := []string{}
+	for _, field := range p.Fields {
+		value, ok := inputMap[field].(string)
+		if !ok {
+			continue
+		}
+
+		if p.filter.Test(value) {
+			probableMembers = append(probableMembers, field)
+		} else {
+			nonMembers = append(nonMembers, field)
+		}
+	}
+
+	result.Data["probable_members"] = probableMembers
+	result.Data["non_members"] = nonMembers
+
+	switch {
+	case p.mode() == "allow" && len(nonMembers) > 0:
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("Values not in allow set: %v", nonMembers)
+	case p.mode() == "deny" && len(probableMembers) > 0:
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("Values probably in deny set: %v", probableMembers)
+	default:
+		result.Status = "PASSED"
+		result.Message = "Membership checked"
+	}
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	switch p.mode() {
+	case "report", "allow", "deny":
+	default:
+		return fmt.Errorf("unsupported mode %q, expected report, allow or deny", p.Mode)
+	}
+	if rate := p.falsePositiveRate(); rate <= 0 || rate >= 1 {
+		return fmt.Errorf("false_positive_rate must be between 0 and 1, got %v", rate)
+	}
+	return nil
+}
+
+func (p *Policy) mode() string {
+	if p.Mode == "" {
+		return "report"
+	}
+	return strings.ToLower(p.Mode)
+}
+
+func (p *Policy) falsePositiveRate() float64 {
+	if p.FalsePositiveRate == 0 {
+		return 0.01
+	}
+	return p.FalsePositiveRate
+}
+
+// loadFilter builds a bloom filter from a file with one member per line.
+// Blank lines and lines starting with '#' are ignored.
+func loadFilter(path string, falsePositiveRate float64) (*bloomFilter, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var members []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		members = append(members, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	filter := newBloomFilter(len(members), falsePositiveRate)
+	for _, member := range members {
+		filter.Add(member)
+	}
+	return filter, nil
+}
@@ -0,0 +1,178 @@
+package bloompolicy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+// writeMembers writes a member file holding the given lines and returns its path
+func writeMembers(t *testing.T, lines string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "members.txt")
+	if err := os.WriteFile(path, []byte(lines), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestBloomFilterHasNoFalseNegatives(t *testing.T) {
+	filter := newBloomFilter(1000, 0.01)
+	for i := 0; i < 1000; i++ {
+		filter.Add(fmt.Sprintf("member-%d", i))
+	}
+	for i := 0; i < 1000; i++ {
+		if !filter.Test(fmt.Sprintf("member-%d", i)) {
+			t.Fatalf("member-%d reported as non-member", i)
+		}
+	}
+
+	// The false positive rate is probabilistic, so only guard against a
+	// filter that accepts far more than the configured 1%
+	falsePositives := 0
+	for i := 0; i < 10000; i++ {
+		if filter.Test(fmt.Sprintf("stranger-%d", i)) {
+			falsePositives++
+		}
+	}
+	if falsePositives > 500 {
+		t.Errorf("%d of 10000 non-members reported as members", falsePositives)
+	}
+}
+
+func TestExecute(t *testing.T) {
+	path := writeMembers(t, "# known users\nalice\n\nbob\n  carol  \n")
+
+	tests := []struct {
+		name        string
+		mode        string
+		input       map[string]interface{}
+		wantStatus  string
+		wantMembers []string
+		wantNon     []string
+	}{
+		{
+			name:        "report members and non-members",
+			input:       map[string]interface{}{"user": "alice", "owner": "mallory-the-intruder"},
+			wantStatus:  "PASSED",
+			wantMembers: []string{"user"},
+			wantNon:     []string{"owner"},
+		},
+		{
+			name:        "allow set passes members",
+			mode:        "allow",
+			input:       map[string]interface{}{"user": "bob", "owner": "carol"},
+			wantStatus:  "PASSED",
+			wantMembers: []string{"user", "owner"},
+			wantNon:     []string{},
+		},
+		{
+			name:        "allow set fails non-members",
+			mode:        "allow",
+			input:       map[string]interface{}{"user": "bob", "owner": "mallory-the-intruder"},
+			wantStatus:  "FAILED",
+			wantMembers: []string{"user"},
+			wantNon:     []string{"owner"},
+		},
+		{
+			name:        "deny set fails members",
+			mode:        "deny",
+			input:       map[string]interface{}{"user": "alice"},
+			wantStatus:  "FAILED",
+			wantMembers: []string{"user"},
+			wantNon:     []string{},
+		},
+		{
+			name:        "comments and non-strings are skipped",
+			mode:        "deny",
+			input:       map[string]interface{}{"user": "# known users", "owner": 42},
+			wantStatus:  "PASSED",
+			wantMembers: []string{},
+			wantNon:     []string{"user"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Policy{}
+			err := p.Configure(map[string]interface{}{
+				"fields":      []string{"user", "owner"},
+				"filter_file": path,
+				"mode":        tt.mode,
+			})
+			if err != nil {
+				t.Fatalf("Configure: %v", err)
+			}
+
+			got, err := p.Execute(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if result.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s (%s)", result.Status, tt.wantStatus, result.Message)
+			}
+			if !reflect.DeepEqual(result.Data["probable_members"], tt.wantMembers) {
+				t.Errorf("probable_members = %v, want %v", result.Data["probable_members"], tt.wantMembers)
+			}
+			if !reflect.DeepEqual(result.Data["non_members"], tt.wantNon) {
+				t.Errorf("non_members = %v, want %v", result.Data["non_members"], tt.wantNon)
+			}
+		})
+	}
+}
+
+func TestExecuteWithoutFilter(t *testing.T) {
+	p := &Policy{Fields: []string{"user"}, Mode: "allow"}
+	got, err := p.Execute(context.Background(), map[string]interface{}{"user": "anyone"})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result := got.(*policyresult.Result); result.Status != "PASSED" {
+		t.Errorf("status = %s, want PASSED", result.Status)
+	}
+}
+
+func TestConfigureMissingFile(t *testing.T) {
+	p := &Policy{}
+	err := p.Configure(map[string]interface{}{"filter_file": filepath.Join(t.TempDir(), "missing.txt")})
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Configure error = %v, want os.ErrNotExist", err)
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  Policy
+		wantErr bool
+	}{
+		{"zero value", Policy{}, false},
+		{"deny mode", Policy{Mode: "DENY"}, false},
+		{"unknown mode", Policy{Mode: "block"}, true},
+		{"rate of one", Policy{FalsePositiveRate: 1}, true},
+		{"negative rate", Policy{FalsePositiveRate: -0.1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policy.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package boolexprpolicy
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// node is a parsed boolean expression
+type node interface {
+	eval(vars map[string]bool) (bool, error)
+	String() string
+}
+
+type identNode struct{ name string }
+
+type notNode struct{ operand node }
+
+type binaryNode struct {
+	op          string
+	left, right node
+}
+
+func (n identNode) eval(vars map[string]bool) (bool, error) {
+	v, ok := vars[n.name]
+	if !ok {
+		return false, fmt.Errorf("unknown variable %q", n.name)
+	}
+	return v, nil
+}
+
+func (n identNode) String() string { return n.name }
+
+func (n notNode) eval(vars map[string]bool) (bool, error) {
+	v, err := n.operand.eval(vars)
+	return !v, err
+}
+
+func (n notNode) String() string { return "NOT " + n.operand.String() }
+
+func (n binaryNode) eval(vars map[string]bool) (bool, error) {
+	left, err := n.left.eval(vars)
+	if err != nil {
+		return false, err
+	}
+	// Short-circuit like the equivalent Go operators
+	if n.op == "AND" && !left || n.op == "OR" && left {
+		return left, nil
+	}
+	return n.right.eval(vars)
+}
+
+func (n binaryNode) String() string {
+	return "(" + n.left.String() + " " + n.op + " " + n.right.String() + ")"
+}
+
+// token is a lexical token with its byte offset in the source
+type token struct {
+	kind  string // "ident", "AND", "OR", "NOT", "(", ")" or "EOF"
+	value string
+	pos   int
+}
+
+// tokenize splits an expression into tokens. Keywords are case-insensitive
+// and && / || / ! are accepted as aliases.
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(src); {
+		c := rune(src[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, token{kind: string(c), pos: i})
+			i++
+		case c == '!':
+			tokens = append(tokens, token{kind: "NOT", pos: i})
+			i++
+		case strings.HasPrefix(src[i:], "&&"):
+			tokens = append(tokens, token{kind: "AND", pos: i})
+			i += 2
+		case strings.HasPrefix(src[i:], "||"):
+			tokens = append(tokens, token{kind: "OR", pos: i})
+			i += 2
+		case c == '_' || unicode.IsLetter(c) || unicode.IsDigit(c):
+			start := i
+			for i < len(src) && (src[i] == '_' || src[i] == '.' || src[i] == '-' ||
+				unicode.IsLetter(rune(src[i])) || unicode.IsDigit(rune(src[i]))) {
+				i++
+			}
+			word := src[start:i]
+			switch upper := strings.ToUpper(word); upper {
+			case "AND", "OR", "NOT":
+				tokens = append(tokens, token{kind: upper, pos: start})
+			default:
+				tokens = append(tokens, token{kind: "ident", value: word, pos: start})
+			}
+		default:
+			return nil, fmt.Errorf("unexpected character %q at position %d", c, i)
+		}
+	}
+	return append(tokens, token{kind: "EOF", pos: len(src)}), nil
+}
+
+// maxDepth bounds how deeply NOT operators and parentheses may nest, so
+// hostile input cannot exhaust the stack of the recursive descent parser
+const maxDepth = 256
+
+// parser is a recursive descent parser with the usual precedence:
+// NOT binds tighter than AND, which binds tighter than OR
+type parser struct {
+	tokens []token
+	pos    int
+	depth  int
+}
+
+// parse parses a complete boolean expression
+func parse(src string) (node, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != "EOF" {
+		return nil, fmt.Errorf("unexpected %s at position %d", describe(tok), tok.pos)
+	}
+	return n, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != "EOF" {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == "OR" {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: "OR", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == "AND" {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: "AND", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	tok := p.next()
+	if tok.kind == "NOT" || tok.kind == "(" {
+		if p.depth++; p.depth > maxDepth {
+			return nil, fmt.Errorf("expression nested deeper than %d at position %d", maxDepth, tok.pos)
+		}
+		defer func() { p.depth-- }()
+	}
+	switch tok.kind {
+	case "NOT":
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand: operand}, nil
+	case "(":
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != ")" {
+			return nil, fmt.Errorf("expected ) at position %d, got %s", closing.pos, describe(closing))
+		}
+		return inner, nil
+	case "ident":
+		return identNode{name: tok.value}, nil
+	default:
+		return nil, fmt.Errorf("unexpected %s at position %d", describe(tok), tok.pos)
+	}
+}
+
+func describe(tok token) string {
+	switch tok.kind {
+	case "EOF":
+		return "end of expression"
+	case "ident":
+		return fmt.Sprintf("identifier %q", tok.value)
+	default:
+		return fmt.Sprintf("%q", tok.kind)
+	}
+}
@@ -0,0 +1,106 @@
+package boolexprpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Policy implements the policy engine interface
+// It parses a boolean expression such as "a AND (b OR NOT c)" from a
+// configured field and evaluates it against boolean-valued input fields
+type Policy struct {
+	// Field names the input field holding the expression (default "expression")
+	Field string `json:"field"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "boolexpr-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	result := policyresult.New(p.Name(), "boolean expression evaluation")
+
+	value, exists := inputMap[p.field()]
+	if !exists {
+		result.Status = "PASSED"
+		result.Message = "No expression to evaluate"
+		return result, nil
+	}
+
+	src, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("field %q: expected string, got %T", p.field(), value)
+	}
+
+	result.Data["expression"] = src
+
+	expr, err := parse(src)
+	if err != nil {
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("Syntax error: %v", err)
+		return result, nil
+	}
+
+	result.Data["normalized"] = expr.String()
+
+	// Every boolean input field is available as a variable
+	vars := make(map[string]bool)
+	for key, v := range inputMap {
+		if b, ok := v.(bool); ok {
+			vars[key] = b
+		}
+	}
+
+	evaluated, err := expr.eval(vars)
+	if err != nil {
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("Evaluation error: %v", err)
+		return result, nil
+	}
+
+	result.Status = "PASSED"
+	result.Message = "Expression evaluated"
+	result.Data["result"] = evaluated
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	return nil
+}
+
+func (p *Policy) field() string {
+	if p.Field == "" {
+		return "expression"
+	}
+	return p.Field
+}
@@ -0,0 +1,161 @@
+package boolexprpolicy
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+func TestExecute(t *testing.T) {
+	vars := map[string]interface{}{"a": true, "b": false, "c": true, "count": 3}
+
+	tests := []struct {
+		expr           string
+		want           bool
+		wantNormalized string
+	}{
+		{"a", true, "a"},
+		{"a AND b", false, "(a AND b)"},
+		{"a and (b or c)", true, "(a AND (b OR c))"},
+		{"NOT b", true, "NOT b"},
+		{"!a || !c", false, "(NOT a OR NOT c)"},
+		{"a && b || c", true, "((a AND b) OR c)"},
+		{"b OR a AND NOT c", false, "(b OR (a AND NOT c))"},
+		{"NOT (a AND b)", true, "NOT (a AND b)"},
+		{"NOT NOT a", true, "NOT NOT a"},
+		{"((a))", true, "a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			input := map[string]interface{}{"expression": tt.expr}
+			for k, v := range vars {
+				input[k] = v
+			}
+
+			got, err := (&Policy{}).Execute(context.Background(), input)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if result.Status != "PASSED" {
+				t.Fatalf("status = %s (%s), want PASSED", result.Status, result.Message)
+			}
+			if result.Data["result"] != tt.want {
+				t.Errorf("result = %v, want %v", result.Data["result"], tt.want)
+			}
+			if result.Data["normalized"] != tt.wantNormalized {
+				t.Errorf("normalized = %v, want %s", result.Data["normalized"], tt.wantNormalized)
+			}
+		})
+	}
+}
+
+func TestExecuteShortCircuits(t *testing.T) {
+	// The unknown variable is never evaluated
+	for _, expr := range []string{"no AND missing", "yes OR missing"} {
+		input := map[string]interface{}{"rule": expr, "yes": true, "no": false}
+		got, err := (&Policy{Field: "rule"}).Execute(context.Background(), input)
+		if err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+		if result := got.(*policyresult.Result); result.Status != "PASSED" {
+			t.Errorf("%s: status = %s (%s), want PASSED", expr, result.Status, result.Message)
+		}
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		expr        string
+		wantMessage string
+	}{
+		{"empty", "", "Syntax error: unexpected end of expression at position 0"},
+		{"dangling operator", "a AND", "Syntax error: unexpected end of expression at position 5"},
+		{"missing operator", "a b", `Syntax error: unexpected identifier "b" at position 2`},
+		{"unclosed parenthesis", "(a OR c", "Syntax error: expected ) at position 7"},
+		{"extra parenthesis", "a)", `Syntax error: unexpected ")" at position 1`},
+		{"invalid character", "a & c", "Syntax error: unexpected character '&' at position 2"},
+		{"unknown variable", "a AND count", `Evaluation error: unknown variable "count"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := map[string]interface{}{"expression": tt.expr, "a": true, "c": false, "count": 3}
+			got, err := (&Policy{}).Execute(context.Background(), input)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if result.Status != "FAILED" {
+				t.Errorf("status = %s, want FAILED", result.Status)
+			}
+			if !strings.HasPrefix(result.Message, tt.wantMessage) {
+				t.Errorf("message = %q, want prefix %q", result.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestExecuteNestingLimit(t *testing.T) {
+	tests := []struct {
+		name       string
+		expr       string
+		wantStatus string
+	}{
+		{"NOT at limit", strings.Repeat("!", maxDepth) + "a", "PASSED"},
+		{"parentheses at limit", strings.Repeat("(", maxDepth) + "a" + strings.Repeat(")", maxDepth), "PASSED"},
+		{"deep NOT chain", strings.Repeat("!", 1<<20) + "a", "FAILED"},
+		{"deep parentheses", strings.Repeat("(", 1<<20) + "a" + strings.Repeat(")", 1<<20), "FAILED"},
+		{"mixed nesting", strings.Repeat("!(", maxDepth/2+1) + "a" + strings.Repeat(")", maxDepth/2+1), "FAILED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := map[string]interface{}{"expression": tt.expr, "a": true}
+			got, err := (&Policy{}).Execute(context.Background(), input)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if result.Status != tt.wantStatus {
+				t.Fatalf("status = %s (%s), want %s", result.Status, result.Message, tt.wantStatus)
+			}
+			if tt.wantStatus == "FAILED" && !strings.HasPrefix(result.Message, "Syntax error: expression nested deeper than 256") {
+				t.Errorf("message = %q, want nesting syntax error", result.Message)
+			}
+		})
+	}
+}
+
+func TestExecuteMissingAndInvalid(t *testing.T) {
+	got, err := (&Policy{}).Execute(context.Background(), map[string]interface{}{"a": true})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result := got.(*policyresult.Result); result.Status != "PASSED" {
+		t.Errorf("missing expression status = %s, want PASSED", result.Status)
+	}
+
+	if _, err := (&Policy{}).Execute(context.Background(), map[string]interface{}{"expression": true}); err == nil {
+		t.Error("non-string expression succeeded, want error")
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Policy{}).Validate(); err != nil {
+		t.Errorf("zero value: %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+package bytesizepolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Policy implements the policy engine interface
+// It checks that configured string fields fit within a maximum number of
+// bytes (not runes), which is what database column limits are measured in
+type Policy struct {
+	// Fields lists the input fields to check
+	Fields []string `json:"fields"`
+
+	// MaxBytes is the maximum encoded UTF-8 length allowed for each field
+	MaxBytes int `json:"max_bytes"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "bytesize-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	result := policyresult.New(p.Name(), "byte size validation")
+	result.Data["max_bytes"] = p.MaxBytes
+
+	violations := []map[string]interface{}{}
+	for _, field := range p.Fields {
+		value, exists := inputMap[field]
+		if !exists {
+			continue
+		}
+
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("field %q: expected string, got %T", field, value)
+		}
+
+		// len() on a string counts bytes, so multibyte runes are counted in full
+		if size := len(s); size > p.MaxBytes {
+			violations = append(violations, map[string]interface{}{
+				"field": field,
+				"bytes": size,
+				"limit": p.MaxBytes,
+			})
+		}
+	}
+
+	result.Data["violations"] = violations
+
+	if len(violations) > 0 {
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("%d field(s) exceed %d bytes", len(violations), p.MaxBytes)
+	} else {
+		result.Status = "PASSED"
+		result.Message = "All fields within byte limit"
+	}
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	if p.MaxBytes < 0 {
+		return fmt.Errorf("max_bytes must not be negative, got %d", p.MaxBytes)
+	}
+	if len(p.Fields) > 0 && p.MaxBytes == 0 {
+		return fmt.Errorf("max_bytes must be set when fields are configured")
+	}
+	return nil
+}
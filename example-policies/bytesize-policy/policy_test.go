@@ -0,0 +1,122 @@
+package bytesizepolicy
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+func TestExecuteByteBoundary(t *testing.T) {
+	p := &Policy{Fields: []string{"name"}, MaxBytes: 6}
+
+	tests := []struct {
+		name       string
+		value      string
+		wantStatus string
+		wantBytes  int
+	}{
+		{"ascii at limit", "abcdef", "PASSED", 0},
+		{"ascii one over", "abcdefg", "FAILED", 7},
+		// "é" is two bytes, so three of them fill the limit exactly
+		{"multibyte at limit", "ééé", "PASSED", 0},
+		{"multibyte one byte over", "éééa", "FAILED", 7},
+		// Two runes, but each takes four bytes
+		{"few runes many bytes", "😀😀", "FAILED", 8},
+		{"empty", "", "PASSED", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.Execute(context.Background(), map[string]interface{}{"name": tt.value})
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if result.Status != tt.wantStatus {
+				t.Errorf("Status = %q, want %q", result.Status, tt.wantStatus)
+			}
+
+			violations := result.Data["violations"].([]map[string]interface{})
+			if tt.wantBytes == 0 {
+				if len(violations) != 0 {
+					t.Errorf("violations = %v, want none", violations)
+				}
+				return
+			}
+			if len(violations) != 1 {
+				t.Fatalf("violations = %v, want one", violations)
+			}
+			if violations[0]["bytes"] != tt.wantBytes || violations[0]["limit"] != 6 {
+				t.Errorf("violation = %v, want bytes %d and limit 6", violations[0], tt.wantBytes)
+			}
+		})
+	}
+}
+
+func TestExecuteSkipsMissingFieldsAndRejectsNonStrings(t *testing.T) {
+	p := &Policy{Fields: []string{"name", "bio"}, MaxBytes: 4}
+
+	got, err := p.Execute(context.Background(), map[string]interface{}{"bio": "ok"})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if status := got.(*policyresult.Result).Status; status != "PASSED" {
+		t.Errorf("Status = %q, want PASSED", status)
+	}
+
+	if _, err := p.Execute(context.Background(), map[string]interface{}{"name": 42}); err == nil {
+		t.Error("Execute with a number field succeeded, want an error")
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  Policy
+		wantErr string
+	}{
+		{"zero value", Policy{}, ""},
+		{"configured", Policy{Fields: []string{"a"}, MaxBytes: 10}, ""},
+		{"negative limit", Policy{MaxBytes: -1}, "must not be negative"},
+		{"fields without limit", Policy{Fields: []string{"a"}}, "must be set"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	p := &Policy{}
+	if err := p.Configure(map[string]interface{}{"fields": []interface{}{"name"}, "max_bytes": 8}); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+	if p.MaxBytes != 8 || len(p.Fields) != 1 {
+		t.Errorf("configured policy = %+v", p)
+	}
+	if err := (&Policy{}).Configure(map[string]interface{}{"fields": []interface{}{"name"}}); err == nil {
+		t.Error("Configure without max_bytes succeeded, want an error")
+	}
+}
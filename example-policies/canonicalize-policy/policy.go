@@ -0,0 +1,82 @@
+package canonicalizepolicy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Policy implements the policy engine interface
+// It re-serializes the input as canonical JSON so that two semantically
+// equal payloads produce byte-identical output for diffing or signing. The
+// SHA-256 digest of the canonical form is reported alongside it.
+type Policy struct {
+	// Field optionally names a single input field to canonicalize; when
+	// empty the whole input is used
+	Field string `json:"field"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "canonicalize-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	value := input
+	if p.Field != "" {
+		// Convert input to map
+		inputMap, ok := input.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+		}
+		fieldValue, exists := inputMap[p.Field]
+		if !exists {
+			return nil, fmt.Errorf("field %q not found in input", p.Field)
+		}
+		value = fieldValue
+	}
+
+	canonical, err := Canonicalize(value)
+	if err != nil {
+		return nil, fmt.Errorf("canonicalize input: %w", err)
+	}
+	digest := sha256.Sum256(canonical)
+
+	return &policyresult.Result{
+		Policy:  p.Name(),
+		Action:  "canonical serialization",
+		Status:  "PASSED",
+		Message: fmt.Sprintf("Canonicalized %d bytes", len(canonical)),
+		Data: map[string]interface{}{
+			"canonical": string(canonical),
+			"sha256":    hex.EncodeToString(digest[:]),
+		},
+	}, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	return nil
+}
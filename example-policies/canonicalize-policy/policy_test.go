@@ -0,0 +1,147 @@
+package canonicalizepolicy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+// decode parses JSON text the way the engine does
+func decode(t *testing.T, text string) interface{} {
+	t.Helper()
+	var v interface{}
+	if err := json.Unmarshal([]byte(text), &v); err != nil {
+		t.Fatalf("decode %s: %v", text, err)
+	}
+	return v
+}
+
+func TestReorderedKeysCanonicalizeIdentically(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+	}{
+		{
+			name: "top-level keys",
+			a:    `{"b":2,"a":1,"c":3}`,
+			b:    `{"c":3,"a":1,"b":2}`,
+		},
+		{
+			name: "nested keys and whitespace",
+			a:    `{"user":{"name":"ada","roles":["admin",{"z":1,"y":2}]},"id":7}`,
+			b: `{
+				"id" : 7,
+				"user" : { "roles" : [ "admin", { "y" : 2, "z" : 1 } ], "name" : "ada" }
+			}`,
+		},
+		{
+			name: "number formatting",
+			a:    `{"n":1,"f":0.5,"big":1e21,"zero":0}`,
+			b:    `{"zero":-0.0,"big":1000000000000000000000,"f":5e-1,"n":1.0}`,
+		},
+	}
+
+	p := &Policy{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotA, err := p.Execute(context.Background(), decode(t, tt.a))
+			if err != nil {
+				t.Fatalf("Execute a: %v", err)
+			}
+			gotB, err := p.Execute(context.Background(), decode(t, tt.b))
+			if err != nil {
+				t.Fatalf("Execute b: %v", err)
+			}
+
+			a, b := gotA.(*policyresult.Result).Data, gotB.(*policyresult.Result).Data
+			if a["canonical"] != b["canonical"] {
+				t.Errorf("canonical forms differ:\n%s\n%s", a["canonical"], b["canonical"])
+			}
+			if a["sha256"] != b["sha256"] {
+				t.Errorf("digests differ: %s, %s", a["sha256"], b["sha256"])
+			}
+		})
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	tests := []struct {
+		name  string
+		input interface{}
+		want  string
+	}{
+		{"sorted keys", map[string]interface{}{"b": 1, "a": []interface{}{true, nil}}, `{"a":[true,null],"b":1}`},
+		{"whole float", 2.0, `2`},
+		{"fraction", 0.1, `0.1`},
+		{"large integer exact", json.Number("9007199254740993"), `9007199254740993`},
+		{"tiny float", 1e-7, `1e-07`},
+		{"huge float", 1.5e300, `1.5e+300`},
+		{"html unescaped", "<a href='x'>&</a>", `"<a href='x'>&</a>"`},
+		{"unicode kept", "café", `"café"`},
+		{"control characters escaped", "a\nb", `"a\nb"`},
+		{"struct", struct {
+			B string `json:"b"`
+			A int    `json:"a"`
+		}{"x", 1}, `{"a":1,"b":"x"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Canonicalize(tt.input)
+			if err != nil {
+				t.Fatalf("Canonicalize: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Canonicalize = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Canonicalize(math.Inf(1)); err == nil {
+		t.Error("Canonicalize accepted +Inf")
+	}
+}
+
+func TestExecuteField(t *testing.T) {
+	p := &Policy{Field: "payload"}
+	got, err := p.Execute(context.Background(), map[string]interface{}{
+		"payload": map[string]interface{}{"y": 1, "x": 2},
+		"ignored": "value",
+	})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	result := got.(*policyresult.Result)
+	if want := `{"x":2,"y":1}`; result.Data["canonical"] != want {
+		t.Errorf("canonical = %v, want %s", result.Data["canonical"], want)
+	}
+	// SHA-256 of the canonical form above
+	if want := "8c056c3399fce447330a0e90971addc793e4171b16f384a70db3519e0ad4958c"; result.Data["sha256"] != want {
+		t.Errorf("sha256 = %v, want %s", result.Data["sha256"], want)
+	}
+
+	if _, err := p.Execute(context.Background(), map[string]interface{}{}); err == nil {
+		t.Error("Execute accepted an input without the field")
+	}
+	if _, err := p.Execute(context.Background(), "payload"); err == nil {
+		t.Error("Execute accepted a non-map input with a field configured")
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Policy{}).Validate(); err != nil {
+		t.Errorf("zero value: %v", err)
+	}
+}
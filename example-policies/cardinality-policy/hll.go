@@ -0,0 +1,70 @@
+package cardinalitypolicy
+
+import (
+	"hash/fnv"
+	"math"
+	"math/bits"
+)
+
+// hyperLogLog estimates the number of distinct values added to it using
+// 2^precision one-byte registers
+type hyperLogLog struct {
+	precision uint8
+	registers []uint8
+}
+
+func newHyperLogLog(precision uint8) *hyperLogLog {
+	return &hyperLogLog{
+		precision: precision,
+		registers: make([]uint8, 1<<precision),
+	}
+}
+
+// Add records a value
+func (h *hyperLogLog) Add(value string) {
+	x := hash64(value)
+	idx := x >> (64 - h.precision)
+	// Rank is the position of the first set bit in the remaining bits
+	rank := uint8(bits.LeadingZeros64(x<<h.precision|1<<(h.precision-1))) + 1
+	if rank > h.registers[idx] {
+		h.registers[idx] = rank
+	}
+}
+
+// Estimate returns the approximate number of distinct values added
+func (h *hyperLogLog) Estimate() uint64 {
+	m := float64(len(h.registers))
+	alpha := 0.7213 / (1 + 1.079/m)
+
+	sum := 0.0
+	zeros := 0
+	for _, r := range h.registers {
+		sum += 1 / float64(uint64(1)<<r)
+		if r == 0 {
+			zeros++
+		}
+	}
+
+	estimate := alpha * m * m / sum
+
+	// Small cardinalities are estimated more accurately by linear counting
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+
+	return uint64(estimate + 0.5)
+}
+
+// hash64 hashes a value with FNV-1a and mixes the result with the
+// splitmix64 finalizer so the high bits are well distributed
+func hash64(value string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(value))
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
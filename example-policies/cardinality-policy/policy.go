@@ -0,0 +1,242 @@
+package cardinalitypolicy
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/example/policies/policyresult"
+)
+
+// defaultMaxStreams bounds the number of streams tracked at once when
+// MaxStreams is not set. At the default precision this caps counter memory
+// at about 16MB.
+const defaultMaxStreams = 1000
+
+// Policy implements the policy engine interface
+// It tracks the number of distinct values of a configured field across
+// successive inputs and flags when that exceeds a configured limit. Counts
+// are approximate (HyperLogLog) so memory stays fixed however many values
+// are seen. Stream IDs come from the input, so the number of streams
+// tracked is capped and, optionally, idle streams are dropped; a dropped
+// stream starts counting again from zero.
+type Policy struct {
+	// Field names the input field whose distinct values are counted
+	Field string `json:"field"`
+
+	// Limit is the maximum number of distinct values allowed
+	Limit uint64 `json:"limit"`
+
+	// StreamField names the input field identifying the stream; each stream
+	// is counted separately (default "stream")
+	StreamField string `json:"stream_field"`
+
+	// Precision sets 2^precision registers, trading memory for accuracy
+	// (4-18, default 14: about 16KB per stream and 0.8% standard error)
+	Precision uint8 `json:"precision"`
+
+	// MaxStreams caps the number of streams tracked (default 1000). Once
+	// reached, the least recently updated stream is dropped.
+	MaxStreams int `json:"max_streams"`
+
+	// IdleTimeout drops streams not updated within this Go duration when set
+	IdleTimeout string `json:"idle_timeout"`
+
+	// Now returns the current time; it defaults to time.Now
+	Now func() time.Time `json:"-"`
+
+	mu      sync.Mutex
+	streams map[string]*list.Element
+	// order holds *stream values, most recently updated first
+	order *list.List
+}
+
+// stream is the distinct value counter for one stream
+type stream struct {
+	id       string
+	counter  *hyperLogLog
+	lastSeen time.Time
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "cardinality-policy"
+}
+
+// Configure applies the given configuration to the policy and resets all
+// counters
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	p.mu.Lock()
+	p.streams = nil
+	p.order = nil
+	p.mu.Unlock()
+
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	result := policyresult.New(p.Name(), "cardinality tracking")
+
+	value, exists := inputMap[p.Field]
+	if p.Field == "" || !exists {
+		result.Status = "PASSED"
+		result.Message = "No value to count"
+		return result, nil
+	}
+
+	// Values are keyed by their JSON encoding so 1 and "1" stay distinct
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("field %q: %w", p.Field, err)
+	}
+	streamID := p.streamID(inputMap)
+	idleTimeout, err := p.idleTimeout()
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	counter := p.stream(streamID, p.now(), idleTimeout).counter
+	counter.Add(string(encoded))
+	estimate := counter.Estimate()
+	p.mu.Unlock()
+
+	result.Data["stream"] = streamID
+	result.Data["estimated_cardinality"] = estimate
+	result.Data["limit"] = p.Limit
+
+	if p.Limit > 0 && estimate > p.Limit {
+		result.Status = "EXCEEDED"
+		result.Message = fmt.Sprintf("About %d distinct %s values, limit is %d", estimate, p.Field, p.Limit)
+	} else {
+		result.Status = "PASSED"
+		result.Message = fmt.Sprintf("About %d distinct %s values", estimate, p.Field)
+	}
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	if prec := p.precision(); prec < 4 || prec > 18 {
+		return fmt.Errorf("precision must be between 4 and 18, got %d", prec)
+	}
+	if p.MaxStreams < 0 {
+		return fmt.Errorf("max_streams must not be negative, got %d", p.MaxStreams)
+	}
+	if _, err := p.idleTimeout(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// stream returns the state for streamID, creating it if needed, and marks
+// it as updated at now. Streams idle for longer than idleTimeout are
+// dropped first, then the least recently updated ones beyond MaxStreams.
+// The caller must hold p.mu.
+func (p *Policy) stream(streamID string, now time.Time, idleTimeout time.Duration) *stream {
+	if p.streams == nil {
+		p.streams = make(map[string]*list.Element)
+		p.order = list.New()
+	}
+
+	if idleTimeout > 0 {
+		cutoff := now.Add(-idleTimeout)
+		for oldest := p.order.Back(); oldest != nil && oldest.Value.(*stream).lastSeen.Before(cutoff); oldest = p.order.Back() {
+			p.order.Remove(oldest)
+			delete(p.streams, oldest.Value.(*stream).id)
+		}
+	}
+
+	elem, ok := p.streams[streamID]
+	if ok {
+		p.order.MoveToFront(elem)
+	} else {
+		elem = p.order.PushFront(&stream{id: streamID, counter: newHyperLogLog(p.precision())})
+		p.streams[streamID] = elem
+		for p.order.Len() > p.maxStreams() {
+			oldest := p.order.Back()
+			p.order.Remove(oldest)
+			delete(p.streams, oldest.Value.(*stream).id)
+		}
+	}
+
+	s := elem.Value.(*stream)
+	s.lastSeen = now
+	return s
+}
+
+func (p *Policy) streamField() string {
+	if p.StreamField == "" {
+		return "stream"
+	}
+	return p.StreamField
+}
+
+// streamID returns the stream an input belongs to, or "default" when the
+// input does not name one
+func (p *Policy) streamID(inputMap map[string]interface{}) string {
+	value, exists := inputMap[p.streamField()]
+	if !exists || value == nil {
+		return "default"
+	}
+	return fmt.Sprint(value)
+}
+
+func (p *Policy) maxStreams() int {
+	if p.MaxStreams == 0 {
+		return defaultMaxStreams
+	}
+	return p.MaxStreams
+}
+
+func (p *Policy) idleTimeout() (time.Duration, error) {
+	if p.IdleTimeout == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(p.IdleTimeout)
+	if err != nil {
+		return 0, fmt.Errorf("invalid idle_timeout %q: %w", p.IdleTimeout, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("idle_timeout must be positive, got %s", p.IdleTimeout)
+	}
+	return d, nil
+}
+
+func (p *Policy) now() time.Time {
+	if p.Now != nil {
+		return p.Now()
+	}
+	return time.Now()
+}
+
+func (p *Policy) precision() uint8 {
+	if p.Precision == 0 {
+		return 14
+	}
+	return p.Precision
+}
@@ -0,0 +1,168 @@
+package cardinalitypolicy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/example/policies/policyresult"
+)
+
+// clock is a settable time source for tests
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+// feed executes p with value on stream and returns the result
+func feed(t *testing.T, p *Policy, stream string, value interface{}) *policyresult.Result {
+	t.Helper()
+	got, err := p.Execute(context.Background(), map[string]interface{}{"user": value, "stream": stream})
+	if err != nil {
+		t.Fatalf("Execute(%v): %v", value, err)
+	}
+	return got.(*policyresult.Result)
+}
+
+func TestExecuteLowCardinality(t *testing.T) {
+	p := &Policy{Field: "user", Limit: 20}
+
+	var result *policyresult.Result
+	for i := 0; i < 1000; i++ {
+		result = feed(t, p, "web", fmt.Sprintf("user-%d", i%10))
+	}
+
+	if estimate := result.Data["estimated_cardinality"]; estimate != uint64(10) {
+		t.Errorf("estimated_cardinality = %v, want 10", estimate)
+	}
+	if result.Status != "PASSED" {
+		t.Errorf("status = %s, want PASSED", result.Status)
+	}
+}
+
+func TestExecuteHighCardinality(t *testing.T) {
+	p := &Policy{Field: "user", Limit: 5000}
+
+	exceededAt := -1
+	var result *policyresult.Result
+	for i := 0; i < 20000; i++ {
+		result = feed(t, p, "web", fmt.Sprintf("user-%d", i))
+		if exceededAt < 0 && result.Status == "EXCEEDED" {
+			exceededAt = i + 1
+		}
+	}
+
+	// HyperLogLog at the default precision has a standard error under 1%,
+	// so allow a generous 5% either way
+	estimate := float64(result.Data["estimated_cardinality"].(uint64))
+	if math.Abs(estimate-20000)/20000 > 0.05 {
+		t.Errorf("estimated_cardinality = %v, want about 20000", estimate)
+	}
+	if exceededAt < 4750 || exceededAt > 5250 {
+		t.Errorf("limit first exceeded after %d distinct values, want about 5000", exceededAt)
+	}
+	if result.Status != "EXCEEDED" {
+		t.Errorf("status = %s, want EXCEEDED", result.Status)
+	}
+}
+
+func TestExecuteDistinguishesTypes(t *testing.T) {
+	p := &Policy{Field: "user"}
+	feed(t, p, "", 1)
+	if result := feed(t, p, "", "1"); result.Data["estimated_cardinality"] != uint64(2) {
+		t.Errorf("estimated_cardinality = %v, want 2 for 1 and \"1\"", result.Data["estimated_cardinality"])
+	}
+}
+
+func TestExecuteStreamsAreIndependent(t *testing.T) {
+	p := &Policy{Field: "user", Limit: 3}
+	for i := 0; i < 5; i++ {
+		feed(t, p, "busy", i)
+	}
+	result := feed(t, p, "quiet", 0)
+	if result.Data["estimated_cardinality"] != uint64(1) || result.Status != "PASSED" {
+		t.Errorf("quiet stream: estimate = %v, status = %s; want 1, PASSED", result.Data["estimated_cardinality"], result.Status)
+	}
+}
+
+func TestExecuteMaxStreams(t *testing.T) {
+	p := &Policy{Field: "user", MaxStreams: 2}
+	feed(t, p, "a", 1)
+	feed(t, p, "b", 1)
+	feed(t, p, "a", 2) // a is now the most recently updated
+	feed(t, p, "c", 1) // evicts b
+
+	if len(p.streams) != 2 {
+		t.Errorf("tracking %d streams, want 2", len(p.streams))
+	}
+	if result := feed(t, p, "a", 3); result.Data["estimated_cardinality"] != uint64(3) {
+		t.Errorf("stream a estimate = %v, want 3 (kept)", result.Data["estimated_cardinality"])
+	}
+	if result := feed(t, p, "b", 2); result.Data["estimated_cardinality"] != uint64(1) {
+		t.Errorf("stream b estimate = %v, want 1 (started over)", result.Data["estimated_cardinality"])
+	}
+}
+
+func TestExecuteIdleTimeout(t *testing.T) {
+	c := &clock{now: time.Date(2024, time.January, 15, 10, 0, 0, 0, time.UTC)}
+	p := &Policy{Field: "user", IdleTimeout: "10m", Now: c.Now}
+	feed(t, p, "idle", 1)
+	c.now = c.now.Add(5 * time.Minute)
+	feed(t, p, "busy", 1)
+
+	c.now = c.now.Add(6 * time.Minute)
+	feed(t, p, "busy", 2)
+	if _, ok := p.streams["idle"]; ok {
+		t.Error("stream idle for 11m still tracked with a 10m idle timeout")
+	}
+	if result := feed(t, p, "busy", 3); result.Data["estimated_cardinality"] != uint64(3) {
+		t.Errorf("busy estimate = %v, want 3", result.Data["estimated_cardinality"])
+	}
+}
+
+func TestExecuteMissingField(t *testing.T) {
+	got, err := (&Policy{Field: "user"}).Execute(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result := got.(*policyresult.Result); result.Status != "PASSED" {
+		t.Errorf("status = %s, want PASSED", result.Status)
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, precision := range []uint8{0, 4, 18} {
+		if err := (&Policy{Precision: precision}).Validate(); err != nil {
+			t.Errorf("precision %d: %v", precision, err)
+		}
+	}
+	for _, precision := range []uint8{3, 19} {
+		if err := (&Policy{Precision: precision}).Validate(); err == nil {
+			t.Errorf("precision %d passed validation", precision)
+		}
+	}
+	invalid := []*Policy{
+		{MaxStreams: -1},
+		{IdleTimeout: "soon"},
+		{IdleTimeout: "0s"},
+	}
+	for _, p := range invalid {
+		if err := p.Validate(); err == nil {
+			t.Errorf("Validate(%+v) succeeded, want an error", p)
+		}
+	}
+}
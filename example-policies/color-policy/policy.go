@@ -0,0 +1,148 @@
+package colorpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Policy implements the policy engine interface
+// It checks that configured fields hold a hex color (#rgb or #rrggbb) or a
+// CSS named color and normalizes valid values to lowercase #rrggbb
+type Policy struct {
+	// Fields lists the input fields holding colors
+	Fields []string `json:"fields"`
+
+	// AllowNamed accepts CSS named colors; defaults to true
+	AllowNamed *bool `json:"allow_named"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "color-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	output := make(map[string]interface{}, len(inputMap))
+	for key, value := range inputMap {
+		output[key] = value
+	}
+
+	invalid := []map[string]interface{}{}
+	for _, field := range p.Fields {
+		value, exists := inputMap[field]
+		if !exists {
+			continue
+		}
+
+		s, ok := value.(string)
+		if !ok {
+			invalid = append(invalid, map[string]interface{}{
+				"field":  field,
+				"reason": fmt.Sprintf("expected string, got %T", value),
+			})
+			continue
+		}
+
+		normalized, err := p.normalize(s)
+		if err != nil {
+			invalid = append(invalid, map[string]interface{}{
+				"field":  field,
+				"value":  s,
+				"reason": err.Error(),
+			})
+			continue
+		}
+		output[field] = normalized
+	}
+
+	result := policyresult.New(p.Name(), "color normalization")
+	result.Data["invalid"] = invalid
+	result.Output = output
+
+	if len(invalid) > 0 {
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("%d invalid color value(s)", len(invalid))
+	} else {
+		result.Status = "PASSED"
+		result.Message = "All colors valid"
+	}
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	return nil
+}
+
+func (p *Policy) allowNamed() bool {
+	return p.AllowNamed == nil || *p.AllowNamed
+}
+
+// normalize converts a hex or named color to lowercase #rrggbb
+func (p *Policy) normalize(value string) (string, error) {
+	s := strings.ToLower(strings.TrimSpace(value))
+
+	if strings.HasPrefix(s, "#") {
+		hex := s[1:]
+		if !isHex(hex) {
+			return "", fmt.Errorf("invalid hex digits in %q", value)
+		}
+		switch len(hex) {
+		case 3:
+			return "#" + string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]}), nil
+		case 6:
+			return s, nil
+		default:
+			return "", fmt.Errorf("hex color %q must have 3 or 6 digits", value)
+		}
+	}
+
+	if !p.allowNamed() {
+		return "", fmt.Errorf("%q is not a hex color", value)
+	}
+	if hex, ok := namedColors[s]; ok {
+		return hex, nil
+	}
+	return "", fmt.Errorf("unknown color %q", value)
+}
+
+func isHex(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,114 @@
+package colorpolicy
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+func TestExecute(t *testing.T) {
+	noNamed := false
+
+	tests := []struct {
+		name       string
+		policy     *Policy
+		value      interface{}
+		wantStatus string
+		want       interface{}
+		// wantReason is a substring of the reported reason for invalid values
+		wantReason string
+	}{
+		{name: "shorthand hex", policy: &Policy{}, value: "#F0a", wantStatus: "PASSED", want: "#ff00aa"},
+		{name: "full hex", policy: &Policy{}, value: "#1E90FF", wantStatus: "PASSED", want: "#1e90ff"},
+		{name: "surrounding space", policy: &Policy{}, value: "  #abc ", wantStatus: "PASSED", want: "#aabbcc"},
+		{name: "named color", policy: &Policy{}, value: "CornflowerBlue", wantStatus: "PASSED", want: "#6495ed"},
+		{name: "named grey spelling", policy: &Policy{}, value: "grey", wantStatus: "PASSED", want: "#808080"},
+		{name: "hex when named disallowed", policy: &Policy{AllowNamed: &noNamed}, value: "#FFF", wantStatus: "PASSED", want: "#ffffff"},
+		{name: "named when disallowed", policy: &Policy{AllowNamed: &noNamed}, value: "white", wantStatus: "FAILED", want: "white", wantReason: "is not a hex color"},
+		{name: "unknown name", policy: &Policy{}, value: "blurple", wantStatus: "FAILED", want: "blurple", wantReason: "unknown color"},
+		{name: "missing hash", policy: &Policy{}, value: "ffffff", wantStatus: "FAILED", want: "ffffff", wantReason: "unknown color"},
+		{name: "bad digits", policy: &Policy{}, value: "#ggg", wantStatus: "FAILED", want: "#ggg", wantReason: "invalid hex digits"},
+		{name: "hash only", policy: &Policy{}, value: "#", wantStatus: "FAILED", want: "#", wantReason: "invalid hex digits"},
+		{name: "wrong length", policy: &Policy{}, value: "#abcd", wantStatus: "FAILED", want: "#abcd", wantReason: "must have 3 or 6 digits"},
+		{name: "alpha channel", policy: &Policy{}, value: "#aabbccdd", wantStatus: "FAILED", want: "#aabbccdd", wantReason: "must have 3 or 6 digits"},
+		{name: "not a string", policy: &Policy{}, value: 0xffffff, wantStatus: "FAILED", want: 0xffffff, wantReason: "expected string, got int"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.policy.Fields = []string{"color", "absent"}
+			got, err := tt.policy.Execute(context.Background(), map[string]interface{}{"color": tt.value, "label": "x"})
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if result.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s (%s)", result.Status, tt.wantStatus, result.Message)
+			}
+			if result.Output["color"] != tt.want || result.Output["label"] != "x" {
+				t.Errorf("output = %v, want color %v", result.Output, tt.want)
+			}
+			if _, exists := result.Output["absent"]; exists {
+				t.Error("absent field added to the output")
+			}
+
+			invalid := result.Data["invalid"].([]map[string]interface{})
+			if tt.wantReason == "" {
+				if len(invalid) != 0 {
+					t.Errorf("invalid = %v, want none", invalid)
+				}
+				return
+			}
+			if len(invalid) != 1 || invalid[0]["field"] != "color" || !strings.Contains(invalid[0]["reason"].(string), tt.wantReason) {
+				t.Errorf("invalid = %v, want color with reason %q", invalid, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestExecuteSeveralFields(t *testing.T) {
+	p := &Policy{}
+	if err := p.Configure(map[string]interface{}{"fields": []interface{}{"fg", "bg", "border"}}); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+	got, err := p.Execute(context.Background(), map[string]interface{}{"fg": "black", "bg": "#FFF", "border": "#12"})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	result := got.(*policyresult.Result)
+	if result.Status != "FAILED" || result.Output["fg"] != "#000000" || result.Output["bg"] != "#ffffff" {
+		t.Errorf("result = %s %v", result.Status, result.Output)
+	}
+	if invalid := result.Data["invalid"].([]map[string]interface{}); len(invalid) != 1 || invalid[0]["field"] != "border" {
+		t.Errorf("invalid = %v, want border only", invalid)
+	}
+
+	if _, err := p.Execute(context.Background(), "#fff"); err == nil {
+		t.Error("Execute accepted a non-map input")
+	}
+}
+
+func TestNamedColorsAreCanonical(t *testing.T) {
+	for name, hex := range namedColors {
+		if name != strings.ToLower(name) || len(hex) != 7 || hex[0] != '#' || !isHex(hex[1:]) {
+			t.Errorf("named color %q = %q, want a lowercase name and #rrggbb", name, hex)
+		}
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Policy{}).Validate(); err != nil {
+		t.Errorf("zero value: %v", err)
+	}
+}
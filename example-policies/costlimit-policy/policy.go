@@ -0,0 +1,237 @@
+package costlimitpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/example/policies/policyresult"
+)
+
+// ErrBudgetExceeded is returned when an input would push a client past its
+// cost budget for the current window
+var ErrBudgetExceeded = errors.New("cost budget exceeded")
+
+// Policy implements the policy engine interface
+// It throttles on payload cost rather than request count. Each input costs
+// BaseCost plus, for every weighted field, the field's size times its
+// weight, where size is an array's length, a string's length in characters
+// or a non-negative number's value. Inputs are rejected once a client's
+// spend for the current window would exceed Budget.
+type Policy struct {
+	// Budget is the cost allowed per client per window; zero disables the
+	// limit
+	Budget float64 `json:"budget"`
+
+	// Window is the budget window as a Go duration string (default "1m")
+	Window string `json:"window"`
+
+	// BaseCost is charged for every input (default 1)
+	BaseCost *float64 `json:"base_cost"`
+
+	// Weights maps input fields to their cost per unit of size
+	Weights map[string]float64 `json:"weights"`
+
+	// ClientField names the input field identifying the client; inputs
+	// without it share the "default" client (default "client")
+	ClientField string `json:"client_field"`
+
+	// Store holds the spend per window; it defaults to a MemoryStore
+	Store Store `json:"-"`
+
+	// Now returns the current time; it defaults to time.Now
+	Now func() time.Time `json:"-"`
+
+	once         sync.Once
+	defaultStore Store
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "costlimit-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	result := policyresult.New(p.Name(), "cost budget enforcement")
+
+	if p.Budget == 0 {
+		result.Status = "PASSED"
+		result.Message = "No cost budget configured"
+		return result, nil
+	}
+
+	cost, breakdown := p.cost(inputMap)
+	client := p.client(inputMap)
+
+	window, err := p.window()
+	if err != nil {
+		return nil, err
+	}
+	windowStart := p.now().Truncate(window)
+
+	spent, accepted, err := p.store().Spend(client, windowStart, cost, p.Budget)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record cost for client %s: %w", client, err)
+	}
+	if !accepted {
+		return nil, fmt.Errorf("client %s input costs %g but only %g of %g remains in window starting %s: %w",
+			client, cost, p.Budget-spent, p.Budget, windowStart.Format(time.RFC3339), ErrBudgetExceeded)
+	}
+
+	result.Status = "PASSED"
+	result.Data["client"] = client
+	result.Data["cost"] = cost
+	result.Data["breakdown"] = breakdown
+	result.Data["spent"] = spent
+	result.Data["budget"] = p.Budget
+	result.Data["remaining"] = p.Budget - spent
+	result.Data["window_start"] = windowStart.Format(time.RFC3339)
+	result.Message = fmt.Sprintf("Client %s has %g of %g cost remaining", client, p.Budget-spent, p.Budget)
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	if p.Budget < 0 {
+		return fmt.Errorf("budget must not be negative, got %v", p.Budget)
+	}
+	if p.baseCost() < 0 {
+		return fmt.Errorf("base_cost must not be negative, got %v", p.baseCost())
+	}
+	for field, weight := range p.Weights {
+		if weight < 0 {
+			return fmt.Errorf("weight for field %q must not be negative, got %v", field, weight)
+		}
+	}
+	if _, err := p.window(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// cost computes the input's cost and the contribution of each weighted field
+func (p *Policy) cost(inputMap map[string]interface{}) (float64, map[string]float64) {
+	fields := make([]string, 0, len(p.Weights))
+	for field := range p.Weights {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	total := p.baseCost()
+	breakdown := map[string]float64{"base": total}
+	for _, field := range fields {
+		// A negative number would otherwise refund part of the budget
+		size, ok := measure(inputMap[field])
+		if !ok || size < 0 {
+			continue
+		}
+		contribution := size * p.Weights[field]
+		breakdown[field] = contribution
+		total += contribution
+	}
+	return total, breakdown
+}
+
+func (p *Policy) baseCost() float64 {
+	if p.BaseCost == nil {
+		return 1
+	}
+	return *p.BaseCost
+}
+
+func (p *Policy) window() (time.Duration, error) {
+	if p.Window == "" {
+		return time.Minute, nil
+	}
+	d, err := time.ParseDuration(p.Window)
+	if err != nil {
+		return 0, fmt.Errorf("invalid window %q: %w", p.Window, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("window must be positive, got %s", p.Window)
+	}
+	return d, nil
+}
+
+func (p *Policy) client(inputMap map[string]interface{}) string {
+	field := p.ClientField
+	if field == "" {
+		field = "client"
+	}
+	if client, ok := inputMap[field].(string); ok && client != "" {
+		return client
+	}
+	return "default"
+}
+
+func (p *Policy) store() Store {
+	if p.Store != nil {
+		return p.Store
+	}
+	p.once.Do(func() {
+		p.defaultStore = NewMemoryStore()
+	})
+	return p.defaultStore
+}
+
+func (p *Policy) now() time.Time {
+	if p.Now != nil {
+		return p.Now()
+	}
+	return time.Now()
+}
+
+// measure returns an array's length, a string's length in characters or a
+// number's value
+func measure(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case []interface{}:
+		return float64(len(v)), true
+	case []string:
+		return float64(len(v)), true
+	case map[string]interface{}:
+		return float64(len(v)), true
+	case string:
+		return float64(len([]rune(v))), true
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,212 @@
+package costlimitpolicy
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/example/policies/policyresult"
+)
+
+// clock is a settable time source
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+// items returns an input for client with n items
+func items(client string, n int) map[string]interface{} {
+	list := make([]interface{}, n)
+	for i := range list {
+		list[i] = i
+	}
+	return map[string]interface{}{"client": client, "items": list}
+}
+
+func TestExecuteBudget(t *testing.T) {
+	c := &clock{now: time.Date(2024, 1, 15, 10, 0, 30, 0, time.UTC)}
+	p := &Policy{Budget: 10, Weights: map[string]float64{"items": 1, "note": 0.5}, Now: c.Now}
+
+	steps := []struct {
+		name          string
+		input         map[string]interface{}
+		advance       time.Duration
+		wantCost      float64
+		wantRemaining float64
+		wantRejected  bool
+	}{
+		{name: "three items", input: items("a", 3), wantCost: 4, wantRemaining: 6},
+		{name: "four items", input: items("a", 4), wantCost: 5, wantRemaining: 1},
+		{name: "too costly for what remains", input: items("a", 1), wantRejected: true},
+		{name: "base cost fits exactly", input: map[string]interface{}{"client": "a"}, wantCost: 1, wantRemaining: 0},
+		{name: "budget exhausted", input: map[string]interface{}{"client": "a"}, wantRejected: true},
+		{name: "other client has its own budget", input: items("b", 9), wantCost: 10, wantRemaining: 0},
+		{name: "string field weighted by length", input: map[string]interface{}{"client": "c", "note": "abcd"}, wantCost: 3, wantRemaining: 7},
+		{name: "larger than the whole budget", input: items("d", 10), wantRejected: true},
+		{name: "next window resets", input: items("a", 2), advance: 30 * time.Second, wantCost: 3, wantRemaining: 7},
+	}
+
+	for _, step := range steps {
+		c.now = c.now.Add(step.advance)
+		got, err := p.Execute(context.Background(), step.input)
+		if step.wantRejected {
+			if !errors.Is(err, ErrBudgetExceeded) {
+				t.Errorf("%s: Execute error = %v, want ErrBudgetExceeded", step.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: Execute: %v", step.name, err)
+		}
+		result := got.(*policyresult.Result)
+		if result.Data["cost"] != step.wantCost || result.Data["remaining"] != step.wantRemaining {
+			t.Errorf("%s: cost %v remaining %v, want %v and %v", step.name, result.Data["cost"], result.Data["remaining"], step.wantCost, step.wantRemaining)
+		}
+	}
+}
+
+func TestExecuteBreakdown(t *testing.T) {
+	base := 2.0
+	p := &Policy{
+		Budget:      1000,
+		BaseCost:    &base,
+		Weights:     map[string]float64{"rows": 3, "tokens": 0.5, "tags": 1, "absent": 100, "flag": 7},
+		ClientField: "tenant",
+	}
+	got, err := p.Execute(context.Background(), map[string]interface{}{
+		"tenant": "acme",
+		"rows":   []interface{}{1, 2, 3, 4},
+		"tokens": 100,
+		"tags":   []string{"x", "y"},
+		"flag":   true,
+	})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	result := got.(*policyresult.Result)
+	want := map[string]float64{"base": 2, "rows": 12, "tokens": 50, "tags": 2}
+	if !reflect.DeepEqual(result.Data["breakdown"], want) {
+		t.Errorf("breakdown = %v, want %v", result.Data["breakdown"], want)
+	}
+	if result.Data["cost"] != 66.0 || result.Data["client"] != "acme" {
+		t.Errorf("cost %v for %v, want 66 for acme", result.Data["cost"], result.Data["client"])
+	}
+}
+
+func TestExecuteNegativeSizeIsFree(t *testing.T) {
+	p := &Policy{Budget: 5, Weights: map[string]float64{"count": 1}}
+	for i := 0; i < 5; i++ {
+		if _, err := p.Execute(context.Background(), map[string]interface{}{"count": -100}); err != nil {
+			t.Fatalf("call %d: Execute: %v", i, err)
+		}
+	}
+	// Each call cost only its base cost, so the budget is now spent
+	if _, err := p.Execute(context.Background(), map[string]interface{}{"count": -100}); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("Execute error = %v, want ErrBudgetExceeded", err)
+	}
+}
+
+// failingStore is a Store that always fails
+type failingStore struct{}
+
+func (failingStore) Spend(string, time.Time, float64, float64) (float64, bool, error) {
+	return 0, false, errors.New("store unavailable")
+}
+
+func TestExecuteErrors(t *testing.T) {
+	p := &Policy{Budget: 10, Store: failingStore{}}
+	if _, err := p.Execute(context.Background(), map[string]interface{}{}); err == nil || errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("Execute error = %v, want the store error", err)
+	}
+	if _, err := p.Execute(context.Background(), []interface{}{}); err == nil {
+		t.Error("Execute accepted a non-map input")
+	}
+}
+
+func TestExecuteNoBudget(t *testing.T) {
+	p := &Policy{Weights: map[string]float64{"items": 1e9}}
+	got, err := p.Execute(context.Background(), items("a", 100))
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result := got.(*policyresult.Result); result.Status != "PASSED" {
+		t.Errorf("status = %s, want PASSED", result.Status)
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr bool
+	}{
+		{"valid", map[string]interface{}{"budget": 100, "window": "1h", "base_cost": 0, "weights": map[string]interface{}{"items": 2}}, false},
+		{"negative budget", map[string]interface{}{"budget": -1}, true},
+		{"negative base cost", map[string]interface{}{"base_cost": -1}, true},
+		{"negative weight", map[string]interface{}{"weights": map[string]interface{}{"items": -1}}, true},
+		{"invalid window", map[string]interface{}{"window": "soon"}, true},
+		{"zero window", map[string]interface{}{"window": "0s"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&Policy{}).Configure(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Configure error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMemoryStorePrunesExpiredWindows(t *testing.T) {
+	s := NewMemoryStore()
+	first := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+	second := first.Add(time.Minute)
+
+	for _, client := range []string{"acme", "globex", "initech"} {
+		if _, _, err := s.Spend(client, first, 1, 10); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(s.windows) != 3 {
+		t.Fatalf("windows = %d, want 3", len(s.windows))
+	}
+
+	// The first call in a later window drops every client idle since the first
+	if _, _, err := s.Spend("acme", second, 1, 10); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.windows) != 1 {
+		t.Errorf("windows after new window = %d, want 1", len(s.windows))
+	}
+
+	// A late call for an earlier window is kept until the next window begins
+	if _, _, err := s.Spend("globex", first, 1, 10); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := s.Spend("acme", second, 1, 10); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.windows) != 2 {
+		t.Errorf("windows = %d, want 2", len(s.windows))
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Policy{}).Validate(); err != nil {
+		t.Errorf("zero value: %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package costlimitpolicy
+
+import (
+	"sync"
+	"time"
+)
+
+// Store records the cost spent per client for fixed time windows
+type Store interface {
+	// Spend charges cost to client in the window starting at windowStart if
+	// doing so keeps the window total within budget. It returns the window
+	// total after the call and whether the charge was accepted; rejected
+	// charges leave the total unchanged.
+	Spend(client string, windowStart time.Time, cost, budget float64) (spent float64, accepted bool, err error)
+}
+
+// MemoryStore is an in-process Store that keeps only the current window
+// for each client. Windows that have ended are dropped the first time a later
+// window is seen, so idle clients do not accumulate. It is safe for concurrent
+// use.
+type MemoryStore struct {
+	mu      sync.Mutex
+	windows map[string]window
+	current time.Time // latest window start seen
+}
+
+type window struct {
+	start time.Time
+	spent float64
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		windows: make(map[string]window),
+	}
+}
+
+// Spend implements Store
+func (s *MemoryStore) Spend(client string, windowStart time.Time, cost, budget float64) (float64, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prune(windowStart)
+	w := s.windows[client]
+	if !w.start.Equal(windowStart) {
+		w = window{start: windowStart}
+	}
+	if w.spent+cost > budget {
+		s.windows[client] = w
+		return w.spent, false, nil
+	}
+	w.spent += cost
+	s.windows[client] = w
+
+	return w.spent, true, nil
+}
+
+// prune drops every window that started before windowStart. It only scans
+// the map when a new window begins, so the cost is paid once per window.
+func (s *MemoryStore) prune(windowStart time.Time) {
+	if !windowStart.After(s.current) {
+		return
+	}
+	s.current = windowStart
+	for client, w := range s.windows {
+		if w.start.Before(windowStart) {
+			delete(s.windows, client)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package cronpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Policy implements the policy engine interface
+// It validates that configured fields hold standard five-field cron
+// expressions (or descriptors such as @daily) and can list upcoming runs
+type Policy struct {
+	// Fields lists the input fields holding cron expressions
+	Fields []string `json:"fields"`
+
+	// NextRuns is the number of upcoming run times to compute per field
+	NextRuns int `json:"next_runs"`
+
+	// Now returns the current time; it defaults to time.Now and can be
+	// replaced for deterministic schedules
+	Now func() time.Time `json:"-"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "cron-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	result := policyresult.New(p.Name(), "cron expression validation")
+
+	now := p.now()
+	validFields := []string{}
+	invalid := []map[string]interface{}{}
+	nextRuns := make(map[string][]string)
+	for _, field := range p.Fields {
+		value, exists := inputMap[field]
+		if !exists {
+			continue
+		}
+
+		expr, ok := value.(string)
+		if !ok {
+			invalid = append(invalid, map[string]interface{}{
+				"field": field,
+				"error": fmt.Sprintf("expected string, got %T", value),
+			})
+			continue
+		}
+
+		schedule, err := cron.ParseStandard(expr)
+		if err != nil {
+			invalid = append(invalid, map[string]interface{}{
+				"field": field,
+				"value": expr,
+				"error": err.Error(),
+			})
+			continue
+		}
+
+		validFields = append(validFields, field)
+		if p.NextRuns > 0 {
+			nextRuns[field] = upcoming(schedule, now, p.NextRuns)
+		}
+	}
+
+	result.Data["valid_fields"] = validFields
+	result.Data["invalid_fields"] = invalid
+	if p.NextRuns > 0 {
+		result.Data["next_runs"] = nextRuns
+	}
+
+	if len(invalid) > 0 {
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("%d invalid cron expression(s)", len(invalid))
+	} else {
+		result.Status = "PASSED"
+		result.Message = "All cron expressions valid"
+	}
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	if p.NextRuns < 0 {
+		return fmt.Errorf("next_runs must not be negative, got %d", p.NextRuns)
+	}
+	return nil
+}
+
+func (p *Policy) now() time.Time {
+	if p.Now != nil {
+		return p.Now()
+	}
+	return time.Now()
+}
+
+// upcoming returns the next n activation times after from, in RFC 3339
+func upcoming(schedule cron.Schedule, from time.Time, n int) []string {
+	runs := make([]string, 0, n)
+	next := from
+	for i := 0; i < n; i++ {
+		next = schedule.Next(next)
+		if next.IsZero() {
+			break
+		}
+		runs = append(runs, next.Format(time.RFC3339))
+	}
+	return runs
+}
@@ -0,0 +1,117 @@
+package cronpolicy
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/example/policies/policyresult"
+)
+
+// fixedNow is the clock used by every test: Monday 2024-01-15 10:30 UTC
+func fixedNow() time.Time {
+	return time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name        string
+		expr        interface{}
+		wantStatus  string
+		wantRuns    []string
+		wantInvalid bool
+	}{
+		{
+			name:       "every fifteen minutes",
+			expr:       "*/15 * * * *",
+			wantStatus: "PASSED",
+			wantRuns:   []string{"2024-01-15T10:45:00Z", "2024-01-15T11:00:00Z", "2024-01-15T11:15:00Z"},
+		},
+		{
+			name:       "weekdays at nine",
+			expr:       "0 9 * * MON-FRI",
+			wantStatus: "PASSED",
+			wantRuns:   []string{"2024-01-16T09:00:00Z", "2024-01-17T09:00:00Z", "2024-01-18T09:00:00Z"},
+		},
+		{
+			name:       "descriptor",
+			expr:       "@monthly",
+			wantStatus: "PASSED",
+			wantRuns:   []string{"2024-02-01T00:00:00Z", "2024-03-01T00:00:00Z", "2024-04-01T00:00:00Z"},
+		},
+		{
+			name:       "explicit time zone",
+			expr:       "CRON_TZ=Asia/Tokyo 0 0 * * *",
+			wantStatus: "PASSED",
+			wantRuns:   []string{"2024-01-15T15:00:00Z", "2024-01-16T15:00:00Z", "2024-01-17T15:00:00Z"},
+		},
+		{name: "too few fields", expr: "* * * *", wantStatus: "FAILED", wantInvalid: true},
+		{name: "seconds field not allowed", expr: "0 */5 * * * *", wantStatus: "FAILED", wantInvalid: true},
+		{name: "minute out of range", expr: "60 * * * *", wantStatus: "FAILED", wantInvalid: true},
+		{name: "unknown descriptor", expr: "@fortnightly", wantStatus: "FAILED", wantInvalid: true},
+		{name: "not a string", expr: 5, wantStatus: "FAILED", wantInvalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Policy{Fields: []string{"schedule"}, NextRuns: 3, Now: fixedNow}
+			got, err := p.Execute(context.Background(), map[string]interface{}{"schedule": tt.expr})
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if result.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s (%s)", result.Status, tt.wantStatus, result.Message)
+			}
+
+			invalid := result.Data["invalid_fields"].([]map[string]interface{})
+			if tt.wantInvalid {
+				if len(invalid) != 1 || invalid[0]["field"] != "schedule" {
+					t.Errorf("invalid_fields = %v, want schedule reported", invalid)
+				}
+				return
+			}
+			runs := result.Data["next_runs"].(map[string][]string)
+			if !reflect.DeepEqual(runs["schedule"], tt.wantRuns) {
+				t.Errorf("next_runs = %v, want %v", runs["schedule"], tt.wantRuns)
+			}
+		})
+	}
+}
+
+func TestExecuteWithoutNextRuns(t *testing.T) {
+	p := &Policy{Fields: []string{"a", "b", "missing"}}
+	got, err := p.Execute(context.Background(), map[string]interface{}{"a": "@hourly", "b": "5 4 * * *"})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	result := got.(*policyresult.Result)
+	if result.Status != "PASSED" {
+		t.Errorf("status = %s, want PASSED", result.Status)
+	}
+	if want := []string{"a", "b"}; !reflect.DeepEqual(result.Data["valid_fields"], want) {
+		t.Errorf("valid_fields = %v, want %v", result.Data["valid_fields"], want)
+	}
+	if _, ok := result.Data["next_runs"]; ok {
+		t.Error("next_runs reported without next_runs configured")
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Policy{}).Validate(); err != nil {
+		t.Errorf("zero value: %v", err)
+	}
+	if err := (&Policy{NextRuns: -1}).Validate(); err == nil {
+		t.Error("negative next_runs passed validation")
+	}
+}
@@ -0,0 +1,243 @@
+package csvlookuppolicy
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Policy implements the policy engine interface
+// It checks numeric input fields against allowed ranges loaded from a CSV
+// file, so thresholds can be maintained in a spreadsheet. Each row is
+// "key,min,max"; an empty min or max leaves that side unbounded and an
+// optional "key,min,max" header row is skipped.
+type Policy struct {
+	// RangesFile is the CSV file of ranges, loaded by Configure
+	RangesFile string `json:"ranges_file"`
+
+	ranges map[string]valueRange
+}
+
+type valueRange struct {
+	min *float64
+	max *float64
+}
+
+// String formats the range for violation messages
+func (r valueRange) String() string {
+	bound := func(v *float64, unbounded string) string {
+		if v == nil {
+			return unbounded
+		}
+		return strconv.FormatFloat(*v, 'f', -1, 64)
+	}
+	return fmt.Sprintf("[%s, %s]", bound(r.min, "-inf"), bound(r.max, "+inf"))
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "csvlookup-policy"
+}
+
+// Configure applies the given configuration to the policy and loads the
+// ranges from the configured CSV file
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	p.ranges = nil
+	if p.RangesFile == "" {
+		return nil
+	}
+
+	ranges, err := loadRanges(p.RangesFile)
+	if err != nil {
+		return fmt.Errorf("failed to load ranges: %w", err)
+	}
+	p.ranges = ranges
+	return nil
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	result := policyresult.New(p.Name(), "range lookup")
+
+	if len(p.ranges) == 0 {
+		result.Status = "PASSED"
+		result.Message = "No ranges loaded"
+		return result, nil
+	}
+
+	keys := make([]string, 0, len(p.ranges))
+	for key := range p.ranges {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	checked := []string{}
+	violations := []map[string]interface{}{}
+	for _, key := range keys {
+		value, exists := inputMap[key]
+		if !exists {
+			continue
+		}
+		checked = append(checked, key)
+
+		expected := p.ranges[key]
+		number, ok := toFloat(value)
+		if !ok {
+			violations = append(violations, map[string]interface{}{
+				"field":    key,
+				"value":    value,
+				"expected": expected.String(),
+				"reason":   fmt.Sprintf("expected a number, got %T", value),
+			})
+			continue
+		}
+
+		if (expected.min != nil && number < *expected.min) || (expected.max != nil && number > *expected.max) {
+			violations = append(violations, map[string]interface{}{
+				"field":    key,
+				"value":    number,
+				"expected": expected.String(),
+				"reason":   "out of range",
+			})
+		}
+	}
+
+	result.Data["checked"] = checked
+	result.Data["violations"] = violations
+
+	if len(violations) > 0 {
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("%d field(s) outside allowed range", len(violations))
+	} else {
+		result.Status = "PASSED"
+		result.Message = "All fields within allowed range"
+	}
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	return nil
+}
+
+// loadRanges reads "key,min,max" rows from a CSV file
+func loadRanges(path string) (map[string]valueRange, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	ranges := make(map[string]valueRange)
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		row, _ := reader.FieldPos(0)
+
+		key := strings.TrimSpace(record[0])
+		if first && strings.EqualFold(key, "key") {
+			continue
+		}
+		if key == "" {
+			return nil, fmt.Errorf("line %d: empty key", row)
+		}
+		if _, dup := ranges[key]; dup {
+			return nil, fmt.Errorf("line %d: duplicate key %q", row, key)
+		}
+
+		min, err := parseBound(record[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: min: %w", row, err)
+		}
+		max, err := parseBound(record[2])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: max: %w", row, err)
+		}
+		if min != nil && max != nil && *min > *max {
+			return nil, fmt.Errorf("line %d: min %v is greater than max %v", row, *min, *max)
+		}
+
+		ranges[key] = valueRange{min: min, max: max}
+	}
+	return ranges, nil
+}
+
+// parseBound parses one side of a range; an empty cell means unbounded
+func parseBound(cell string) (*float64, error) {
+	cell = strings.TrimSpace(cell)
+	if cell == "" {
+		return nil, nil
+	}
+	v, err := strconv.ParseFloat(cell, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,185 @@
+package csvlookuppolicy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+// fixture returns a policy configured with testdata/ranges.csv
+func fixture(t *testing.T) *Policy {
+	t.Helper()
+	p := &Policy{}
+	if err := p.Configure(map[string]interface{}{"ranges_file": filepath.Join("testdata", "ranges.csv")}); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+	return p
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       map[string]interface{}
+		wantStatus  string
+		wantChecked []string
+		// wantViolations maps each violating field to its expected range
+		wantViolations map[string]string
+	}{
+		{
+			name:        "all in range",
+			input:       map[string]interface{}{"temperature": 21.5, "discount": 0.1, "quantity": 3, "latency_ms": 90},
+			wantStatus:  "PASSED",
+			wantChecked: []string{"discount", "latency_ms", "quantity", "temperature"},
+		},
+		{
+			name:        "bounds are inclusive",
+			input:       map[string]interface{}{"temperature": -20, "discount": 0.5, "quantity": 1, "latency_ms": 250},
+			wantStatus:  "PASSED",
+			wantChecked: []string{"discount", "latency_ms", "quantity", "temperature"},
+		},
+		{
+			name:        "unbounded sides",
+			input:       map[string]interface{}{"quantity": 1e9, "latency_ms": -5},
+			wantStatus:  "PASSED",
+			wantChecked: []string{"latency_ms", "quantity"},
+		},
+		{
+			name:        "fields without a range are ignored",
+			input:       map[string]interface{}{"price": -100},
+			wantStatus:  "PASSED",
+			wantChecked: []string{},
+		},
+		{
+			name:           "below minimum",
+			input:          map[string]interface{}{"temperature": -21},
+			wantStatus:     "FAILED",
+			wantChecked:    []string{"temperature"},
+			wantViolations: map[string]string{"temperature": "[-20, 45]"},
+		},
+		{
+			name:        "above maximum",
+			input:       map[string]interface{}{"discount": json.Number("0.75"), "latency_ms": 251, "quantity": 0},
+			wantStatus:  "FAILED",
+			wantChecked: []string{"discount", "latency_ms", "quantity"},
+			wantViolations: map[string]string{
+				"discount":   "[0, 0.5]",
+				"latency_ms": "[-inf, 250]",
+				"quantity":   "[1, +inf]",
+			},
+		},
+		{
+			name:           "not a number",
+			input:          map[string]interface{}{"temperature": "hot"},
+			wantStatus:     "FAILED",
+			wantChecked:    []string{"temperature"},
+			wantViolations: map[string]string{"temperature": "[-20, 45]"},
+		},
+	}
+
+	p := fixture(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.Execute(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if result.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s (%s)", result.Status, tt.wantStatus, result.Message)
+			}
+			if !reflect.DeepEqual(result.Data["checked"], tt.wantChecked) {
+				t.Errorf("checked = %v, want %v", result.Data["checked"], tt.wantChecked)
+			}
+
+			violations := map[string]string{}
+			for _, v := range result.Data["violations"].([]map[string]interface{}) {
+				violations[v["field"].(string)] = v["expected"].(string)
+			}
+			if len(violations) != len(tt.wantViolations) || (len(violations) > 0 && !reflect.DeepEqual(violations, tt.wantViolations)) {
+				t.Errorf("violations = %v, want %v", violations, tt.wantViolations)
+			}
+		})
+	}
+}
+
+func TestExecuteNoRanges(t *testing.T) {
+	got, err := (&Policy{}).Execute(context.Background(), map[string]interface{}{"temperature": 1000})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result := got.(*policyresult.Result); result.Status != "PASSED" {
+		t.Errorf("status = %s, want PASSED", result.Status)
+	}
+
+	if _, err := fixture(t).Execute(context.Background(), []interface{}{}); err == nil {
+		t.Error("Execute accepted a non-map input")
+	}
+}
+
+func TestConfigureErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		wantErr string
+	}{
+		{"wrong column count", "a,1\n", "wrong number of fields"},
+		{"empty key", " ,1,2\n", "line 1: empty key"},
+		{"duplicate key", "a,1,2\na,3,4\n", `line 2: duplicate key "a"`},
+		{"invalid min", "a,low,2\n", "line 1: min"},
+		{"invalid max", "key,min,max\na,1,high\n", "line 2: max"},
+		{"min above max", "a,5,2\n", "line 1: min 5 is greater than max 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "ranges.csv")
+			if err := os.WriteFile(path, []byte(tt.csv), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			err := (&Policy{}).Configure(map[string]interface{}{"ranges_file": path})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Configure error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	err := (&Policy{}).Configure(map[string]interface{}{"ranges_file": filepath.Join(t.TempDir(), "missing.csv")})
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("missing file: Configure error = %v, want os.ErrNotExist", err)
+	}
+}
+
+func TestConfigureClearsRanges(t *testing.T) {
+	p := fixture(t)
+	if err := p.Configure(map[string]interface{}{"ranges_file": ""}); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+	got, err := p.Execute(context.Background(), map[string]interface{}{"temperature": 1000})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result := got.(*policyresult.Result); result.Status != "PASSED" {
+		t.Errorf("status = %s after clearing the ranges file, want PASSED", result.Status)
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Policy{}).Validate(); err != nil {
+		t.Errorf("zero value: %v", err)
+	}
+}
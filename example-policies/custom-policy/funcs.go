@@ -0,0 +1,79 @@
+package custompolicy
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ErrUnknownFunc is returned when a function name has not been registered
+var ErrUnknownFunc = errors.New("unknown function")
+
+// Func is a user-supplied function invoked by the custom policy
+type Func func(args ...interface{}) (interface{}, error)
+
+// FuncRegistry holds named functions. It is safe for concurrent use.
+type FuncRegistry struct {
+	mu    sync.RWMutex
+	funcs map[string]Func
+}
+
+// NewFuncRegistry creates an empty function registry
+func NewFuncRegistry() *FuncRegistry {
+	return &FuncRegistry{
+		funcs: make(map[string]Func),
+	}
+}
+
+// DefaultFuncs is the registry used by policies that do not set their own
+var DefaultFuncs = NewFuncRegistry()
+
+// RegisterFunc adds fn to DefaultFuncs
+func RegisterFunc(name string, fn Func) error {
+	return DefaultFuncs.Register(name, fn)
+}
+
+// Register adds a named function, replacing any existing one with that name
+func (r *FuncRegistry) Register(name string, fn Func) error {
+	if name == "" {
+		return fmt.Errorf("function name must not be empty")
+	}
+	if fn == nil {
+		return fmt.Errorf("function %s is nil", name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.funcs[name] = fn
+	return nil
+}
+
+// Get retrieves a function by name
+func (r *FuncRegistry) Get(name string) (Func, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	fn, ok := r.funcs[name]
+	return fn, ok
+}
+
+// List returns the registered function names in sorted order
+func (r *FuncRegistry) List() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.funcs))
+	for name := range r.funcs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Call invokes a named function with the given arguments
+func (r *FuncRegistry) Call(name string, args ...interface{}) (interface{}, error) {
+	fn, ok := r.Get(name)
+	if !ok {
+		return nil, ErrUnknownFunc
+	}
+	return fn(args...)
+}
@@ -0,0 +1,95 @@
+package custompolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Policy implements the policy engine interface
+// It invokes a registered function by name, passing the values of the
+// configured input fields as arguments in order, and returns the
+// function's result. This allows extending the engine without writing a
+// new policy package.
+type Policy struct {
+	// Function is the name of the registered function to call
+	Function string `json:"function"`
+
+	// Args lists the input fields passed as arguments; missing fields are
+	// passed as nil
+	Args []string `json:"args"`
+
+	// Funcs is the registry the function is looked up in; it defaults to
+	// DefaultFuncs
+	Funcs *FuncRegistry `json:"-"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "custom-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	result := policyresult.New(p.Name(), "custom function")
+
+	if p.Function == "" {
+		result.Status = "PASSED"
+		result.Message = "No function configured"
+		return result, nil
+	}
+
+	args := make([]interface{}, len(p.Args))
+	for i, field := range p.Args {
+		args[i] = inputMap[field]
+	}
+
+	value, err := p.funcs().Call(p.Function, args...)
+	if err != nil {
+		return nil, fmt.Errorf("function %s: %w", p.Function, err)
+	}
+
+	result.Status = "PASSED"
+	result.Data["function"] = p.Function
+	result.Data["result"] = value
+	result.Message = fmt.Sprintf("Function %s completed", p.Function)
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	return nil
+}
+
+func (p *Policy) funcs() *FuncRegistry {
+	if p.Funcs != nil {
+		return p.Funcs
+	}
+	return DefaultFuncs
+}
@@ -0,0 +1,152 @@
+package custompolicy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+// sum adds its arguments, which must all be numbers
+func sum(args ...interface{}) (interface{}, error) {
+	total := 0.0
+	for _, arg := range args {
+		n, ok := arg.(float64)
+		if !ok {
+			return nil, fmt.Errorf("%v is not a number", arg)
+		}
+		total += n
+	}
+	return total, nil
+}
+
+func newFuncs(t *testing.T) *FuncRegistry {
+	t.Helper()
+	funcs := NewFuncRegistry()
+	if err := funcs.Register("sum", sum); err != nil {
+		t.Fatal(err)
+	}
+	return funcs
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     Policy
+		input      map[string]interface{}
+		wantResult interface{}
+		wantStatus string
+	}{
+		{
+			name:       "calls the function with the fields in order",
+			policy:     Policy{Function: "sum", Args: []string{"a", "b"}},
+			input:      map[string]interface{}{"a": 2.0, "b": 3.5, "c": 100.0},
+			wantResult: 5.5,
+			wantStatus: "PASSED",
+		},
+		{
+			name:       "no arguments",
+			policy:     Policy{Function: "sum"},
+			input:      map[string]interface{}{"a": 2.0},
+			wantResult: 0.0,
+			wantStatus: "PASSED",
+		},
+		{
+			name:       "no function configured",
+			policy:     Policy{},
+			input:      map[string]interface{}{},
+			wantStatus: "PASSED",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.policy.Funcs = newFuncs(t)
+			got, err := tt.policy.Execute(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if result.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", result.Status, tt.wantStatus)
+			}
+			if result.Data["result"] != tt.wantResult {
+				t.Errorf("result = %v, want %v", result.Data["result"], tt.wantResult)
+			}
+		})
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	funcs := newFuncs(t)
+
+	p := &Policy{Function: "missing", Funcs: funcs}
+	if _, err := p.Execute(context.Background(), map[string]interface{}{}); !errors.Is(err, ErrUnknownFunc) {
+		t.Errorf("unknown function error = %v, want ErrUnknownFunc", err)
+	}
+
+	// A missing field is passed as nil, which sum rejects
+	p = &Policy{Function: "sum", Args: []string{"a"}, Funcs: funcs}
+	if _, err := p.Execute(context.Background(), map[string]interface{}{}); err == nil {
+		t.Error("function error was not returned")
+	}
+
+	if _, err := p.Execute(context.Background(), "not an object"); err == nil {
+		t.Error("non-object input succeeded, want error")
+	}
+}
+
+func TestDefaultFuncs(t *testing.T) {
+	if err := RegisterFunc("test-double", func(args ...interface{}) (interface{}, error) {
+		return args[0].(float64) * 2, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	p := &Policy{}
+	if err := p.Configure(map[string]interface{}{"function": "test-double", "args": []string{"n"}}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := p.Execute(context.Background(), map[string]interface{}{"n": 21.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value := got.(*policyresult.Result).Data["result"]; value != 42.0 {
+		t.Errorf("result = %v, want 42", value)
+	}
+}
+
+func TestFuncRegistry(t *testing.T) {
+	funcs := newFuncs(t)
+	if err := funcs.Register("", sum); err == nil {
+		t.Error("empty name accepted, want error")
+	}
+	if err := funcs.Register("nil", nil); err == nil {
+		t.Error("nil function accepted, want error")
+	}
+	if err := funcs.Register("count", sum); err != nil {
+		t.Fatal(err)
+	}
+	if names := funcs.List(); fmt.Sprint(names) != "[count sum]" {
+		t.Errorf("List = %v, want [count sum]", names)
+	}
+	if _, err := funcs.Call("nope"); !errors.Is(err, ErrUnknownFunc) {
+		t.Errorf("Call error = %v, want ErrUnknownFunc", err)
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Policy{}).Validate(); err != nil {
+		t.Errorf("zero value Validate() = %v, want nil", err)
+	}
+}
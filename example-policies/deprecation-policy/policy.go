@@ -0,0 +1,130 @@
+package deprecationpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Deprecation describes one deprecated field
+type Deprecation struct {
+	// Field is the deprecated field; dots address nested objects
+	Field string `json:"field"`
+
+	// Replacement optionally names the field clients should use instead
+	Replacement string `json:"replacement"`
+
+	// Note is optional extra guidance, e.g. the removal date
+	Note string `json:"note"`
+}
+
+// Policy implements the policy engine interface
+// It warns when deprecated fields appear in the input so clients can be
+// migrated gracefully. Deprecated fields never fail the policy; they
+// produce a WARNING status with a suggested replacement.
+type Policy struct {
+	// Deprecated lists the deprecated fields
+	Deprecated []Deprecation `json:"deprecated"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "deprecation-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	warnings := []map[string]interface{}{}
+	for _, d := range p.Deprecated {
+		if !hasPath(inputMap, strings.Split(d.Field, ".")) {
+			continue
+		}
+
+		warning := map[string]interface{}{
+			"field":   d.Field,
+			"message": d.message(),
+		}
+		if d.Replacement != "" {
+			warning["replacement"] = d.Replacement
+		}
+		warnings = append(warnings, warning)
+	}
+
+	result := policyresult.New(p.Name(), "deprecation check")
+	result.Data["warnings"] = warnings
+
+	if len(warnings) > 0 {
+		result.Status = "WARNING"
+		result.Message = fmt.Sprintf("Input uses %d deprecated field(s)", len(warnings))
+	} else {
+		result.Status = "PASSED"
+		result.Message = "No deprecated fields used"
+	}
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	seen := make(map[string]bool, len(p.Deprecated))
+	for i, d := range p.Deprecated {
+		if d.Field == "" {
+			return fmt.Errorf("deprecation %d: field is required", i)
+		}
+		if seen[d.Field] {
+			return fmt.Errorf("field %q is listed more than once", d.Field)
+		}
+		seen[d.Field] = true
+	}
+	return nil
+}
+
+func (d Deprecation) message() string {
+	message := fmt.Sprintf("field %q is deprecated", d.Field)
+	if d.Replacement != "" {
+		message += fmt.Sprintf("; use %q instead", d.Replacement)
+	}
+	if d.Note != "" {
+		message += " (" + d.Note + ")"
+	}
+	return message
+}
+
+// hasPath reports whether the nested field addressed by path is present
+func hasPath(obj map[string]interface{}, path []string) bool {
+	value, exists := obj[path[0]]
+	if !exists {
+		return false
+	}
+	if len(path) == 1 {
+		return true
+	}
+	nested, ok := value.(map[string]interface{})
+	return ok && hasPath(nested, path[1:])
+}
@@ -0,0 +1,144 @@
+package deprecationpolicy
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+func configured(t *testing.T) *Policy {
+	t.Helper()
+	p := &Policy{}
+	err := p.Configure(map[string]interface{}{
+		"deprecated": []interface{}{
+			map[string]interface{}{"field": "user_name", "replacement": "username"},
+			map[string]interface{}{"field": "address.zip", "replacement": "address.postal_code", "note": "removed in v3"},
+			map[string]interface{}{"field": "legacy_flag"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+	return p
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        map[string]interface{}
+		wantStatus   string
+		wantWarnings []map[string]interface{}
+	}{
+		{
+			name:         "no deprecated fields",
+			input:        map[string]interface{}{"username": "ada", "address": map[string]interface{}{"postal_code": "10100"}},
+			wantStatus:   "PASSED",
+			wantWarnings: []map[string]interface{}{},
+		},
+		{
+			name:       "deprecated field with replacement",
+			input:      map[string]interface{}{"user_name": "ada"},
+			wantStatus: "WARNING",
+			wantWarnings: []map[string]interface{}{
+				{"field": "user_name", "replacement": "username", "message": `field "user_name" is deprecated; use "username" instead`},
+			},
+		},
+		{
+			name:       "nested deprecated field with note",
+			input:      map[string]interface{}{"address": map[string]interface{}{"zip": "10100"}},
+			wantStatus: "WARNING",
+			wantWarnings: []map[string]interface{}{
+				{"field": "address.zip", "replacement": "address.postal_code", "message": `field "address.zip" is deprecated; use "address.postal_code" instead (removed in v3)`},
+			},
+		},
+		{
+			name:       "deprecated field without replacement, even when null",
+			input:      map[string]interface{}{"legacy_flag": nil},
+			wantStatus: "WARNING",
+			wantWarnings: []map[string]interface{}{
+				{"field": "legacy_flag", "message": `field "legacy_flag" is deprecated`},
+			},
+		},
+		{
+			name:         "nested path through a non-object",
+			input:        map[string]interface{}{"address": "1 Main St"},
+			wantStatus:   "PASSED",
+			wantWarnings: []map[string]interface{}{},
+		},
+		{
+			name: "several deprecated fields in configured order",
+			input: map[string]interface{}{
+				"legacy_flag": true,
+				"user_name":   "ada",
+			},
+			wantStatus: "WARNING",
+			wantWarnings: []map[string]interface{}{
+				{"field": "user_name", "replacement": "username", "message": `field "user_name" is deprecated; use "username" instead`},
+				{"field": "legacy_flag", "message": `field "legacy_flag" is deprecated`},
+			},
+		},
+	}
+
+	p := configured(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.Execute(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if result.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s (%s)", result.Status, tt.wantStatus, result.Message)
+			}
+			if !reflect.DeepEqual(result.Data["warnings"], tt.wantWarnings) {
+				t.Errorf("warnings = %v, want %v", result.Data["warnings"], tt.wantWarnings)
+			}
+		})
+	}
+
+	if _, err := p.Execute(context.Background(), "user_name"); err == nil {
+		t.Error("Execute accepted a non-map input")
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr bool
+	}{
+		{"empty", map[string]interface{}{}, false},
+		{"missing field", map[string]interface{}{"deprecated": []interface{}{map[string]interface{}{"replacement": "b"}}}, true},
+		{"duplicate field", map[string]interface{}{"deprecated": []interface{}{
+			map[string]interface{}{"field": "a"},
+			map[string]interface{}{"field": "a", "replacement": "b"},
+		}}, true},
+		{"wrong type", map[string]interface{}{"deprecated": "a"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&Policy{}).Configure(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Configure error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Policy{}).Validate(); err != nil {
+		t.Errorf("zero value: %v", err)
+	}
+}
@@ -0,0 +1,151 @@
+package depthlimitpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Policy implements the policy engine interface
+// It limits how deeply nested the input may be. In reject mode over-deep
+// inputs fail; in truncate mode subtrees below the limit are replaced with
+// a placeholder so downstream systems never see stack-heavy structures.
+type Policy struct {
+	// MaxDepth is the deepest nesting allowed; the top-level object is
+	// depth 1 (default 32)
+	MaxDepth int `json:"max_depth"`
+
+	// Mode is reject (default) or truncate
+	Mode string `json:"mode"`
+
+	// Placeholder replaces truncated subtrees (default "[truncated]")
+	Placeholder interface{} `json:"placeholder"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "depthlimit-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	result := policyresult.New(p.Name(), "depth limiting")
+	result.Data["max_depth"] = p.maxDepth()
+	result.Data["mode"] = p.mode()
+
+	truncated := []string{}
+	output := p.limit(inputMap, 1, "", &truncated).(map[string]interface{})
+	sort.Strings(truncated)
+
+	result.Data["truncated_at"] = truncated
+
+	switch {
+	case len(truncated) == 0:
+		result.Status = "PASSED"
+		result.Message = "Input within depth limit"
+		result.Output = inputMap
+	case p.mode() == "truncate":
+		result.Status = "TRUNCATED"
+		result.Message = fmt.Sprintf("Truncated %d subtree(s) deeper than %d", len(truncated), p.maxDepth())
+		result.Output = output
+	default:
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("Input exceeds maximum depth %d at %d location(s)", p.maxDepth(), len(truncated))
+	}
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	if p.MaxDepth < 0 {
+		return fmt.Errorf("max_depth must not be negative, got %d", p.MaxDepth)
+	}
+	switch p.mode() {
+	case "reject", "truncate":
+		return nil
+	default:
+		return fmt.Errorf("unsupported mode %q, expected reject or truncate", p.Mode)
+	}
+}
+
+func (p *Policy) maxDepth() int {
+	if p.MaxDepth == 0 {
+		return 32
+	}
+	return p.MaxDepth
+}
+
+func (p *Policy) mode() string {
+	if p.Mode == "" {
+		return "reject"
+	}
+	return strings.ToLower(p.Mode)
+}
+
+func (p *Policy) placeholder() interface{} {
+	if p.Placeholder == nil {
+		return "[truncated]"
+	}
+	return p.Placeholder
+}
+
+// limit returns a copy of value in which containers nested deeper than
+// the limit are replaced by the placeholder, recording where that happened
+func (p *Policy) limit(value interface{}, depth int, path string, truncated *[]string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if depth > p.maxDepth() {
+			*truncated = append(*truncated, path)
+			return p.placeholder()
+		}
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			out[key] = p.limit(item, depth+1, childPath, truncated)
+		}
+		return out
+	case []interface{}:
+		if depth > p.maxDepth() {
+			*truncated = append(*truncated, path)
+			return p.placeholder()
+		}
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = p.limit(item, depth+1, fmt.Sprintf("%s[%d]", path, i), truncated)
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,154 @@
+package depthlimitpolicy
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+// nested returns an object nested depth levels deep, counting the top level
+func nested(depth int) map[string]interface{} {
+	obj := map[string]interface{}{"leaf": true}
+	for i := 1; i < depth; i++ {
+		obj = map[string]interface{}{"child": obj}
+	}
+	return obj
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name          string
+		policy        *Policy
+		input         map[string]interface{}
+		wantStatus    string
+		wantTruncated []string
+		wantOutput    map[string]interface{}
+	}{
+		{
+			name:          "within depth",
+			policy:        &Policy{MaxDepth: 3},
+			input:         map[string]interface{}{"a": map[string]interface{}{"b": []interface{}{1, 2}}},
+			wantStatus:    "PASSED",
+			wantTruncated: []string{},
+			wantOutput:    map[string]interface{}{"a": map[string]interface{}{"b": []interface{}{1, 2}}},
+		},
+		{
+			name:          "exactly at the default limit",
+			policy:        &Policy{},
+			input:         nested(32),
+			wantStatus:    "PASSED",
+			wantTruncated: []string{},
+			wantOutput:    nested(32),
+		},
+		{
+			name:          "over-deep input rejected",
+			policy:        &Policy{MaxDepth: 2},
+			input:         map[string]interface{}{"a": map[string]interface{}{"b": map[string]interface{}{"c": 1}}},
+			wantStatus:    "FAILED",
+			wantTruncated: []string{"a.b"},
+		},
+		{
+			name:          "one past the default limit",
+			policy:        &Policy{},
+			input:         nested(33),
+			wantStatus:    "FAILED",
+			wantTruncated: []string{strings.TrimSuffix(strings.Repeat("child.", 32), ".")},
+		},
+		{
+			name:   "over-deep input truncated",
+			policy: &Policy{MaxDepth: 2, Mode: "Truncate"},
+			input: map[string]interface{}{
+				"a":     map[string]interface{}{"b": map[string]interface{}{"c": 1}, "keep": "x"},
+				"list":  []interface{}{[]interface{}{1}, "y"},
+				"flat":  1,
+				"empty": map[string]interface{}{},
+			},
+			wantStatus:    "TRUNCATED",
+			wantTruncated: []string{"a.b", "list[0]"},
+			wantOutput: map[string]interface{}{
+				"a":     map[string]interface{}{"b": "[truncated]", "keep": "x"},
+				"list":  []interface{}{"[truncated]", "y"},
+				"flat":  1,
+				"empty": map[string]interface{}{},
+			},
+		},
+		{
+			name:          "custom placeholder",
+			policy:        &Policy{MaxDepth: 1, Mode: "truncate", Placeholder: map[string]interface{}{"omitted": true}},
+			input:         map[string]interface{}{"a": []interface{}{1}, "b": 2},
+			wantStatus:    "TRUNCATED",
+			wantTruncated: []string{"a"},
+			wantOutput:    map[string]interface{}{"a": map[string]interface{}{"omitted": true}, "b": 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.policy.Execute(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if result.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s (%s)", result.Status, tt.wantStatus, result.Message)
+			}
+			if !reflect.DeepEqual(result.Data["truncated_at"], tt.wantTruncated) {
+				t.Errorf("truncated_at = %v, want %v", result.Data["truncated_at"], tt.wantTruncated)
+			}
+			if !reflect.DeepEqual(result.Output, tt.wantOutput) {
+				t.Errorf("output = %v, want %v", result.Output, tt.wantOutput)
+			}
+		})
+	}
+}
+
+func TestExecuteLeavesInputUntouched(t *testing.T) {
+	input := map[string]interface{}{"a": map[string]interface{}{"b": map[string]interface{}{}}}
+	p := &Policy{MaxDepth: 1, Mode: "truncate"}
+	if _, err := p.Execute(context.Background(), input); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if _, ok := input["a"].(map[string]interface{}); !ok {
+		t.Errorf("input modified: %v", input)
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr bool
+	}{
+		{"valid", map[string]interface{}{"max_depth": 4, "mode": "truncate", "placeholder": nil}, false},
+		{"negative depth", map[string]interface{}{"max_depth": -1}, true},
+		{"unknown mode", map[string]interface{}{"mode": "drop"}, true},
+		{"wrong type", map[string]interface{}{"max_depth": "4"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&Policy{}).Configure(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Configure error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Policy{}).Validate(); err != nil {
+		t.Errorf("zero value: %v", err)
+	}
+}
@@ -0,0 +1,262 @@
+package derivedcheckpolicy
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"math"
+	"strings"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Check describes one computed field and how to recompute it
+type Check struct {
+	// Field is the input field holding the computed value
+	Field string `json:"field"`
+
+	// Kind is "sum" or "hash"
+	Kind string `json:"kind"`
+
+	// ItemsField names the array of objects totalled by a sum check
+	ItemsField string `json:"items_field"`
+
+	// ValueField names the numeric item field added up by a sum check
+	ValueField string `json:"value_field"`
+
+	// MultiplyField optionally names an item field, such as a quantity,
+	// that each value is multiplied by
+	MultiplyField string `json:"multiply_field"`
+
+	// Tolerance is the allowed difference for a sum check (default 1e-9)
+	Tolerance float64 `json:"tolerance"`
+
+	// Fields lists the input fields concatenated by a hash check
+	Fields []string `json:"fields"`
+
+	// Separator joins the hashed fields (default "|")
+	Separator *string `json:"separator"`
+
+	// Algorithm is the hash used by a hash check: sha256 (default), sha1 or md5
+	Algorithm string `json:"algorithm"`
+}
+
+// Policy implements the policy engine interface
+// It recomputes derived fields, such as an order total from its line items
+// or a hash over several fields, and checks that the input carries the
+// same value, catching tampered or inconsistent computed fields
+type Policy struct {
+	// Checks lists the derived fields to verify
+	Checks []Check `json:"checks"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "derivedcheck-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	mismatches := []map[string]interface{}{}
+	checked := []string{}
+	for _, check := range p.Checks {
+		actual, exists := inputMap[check.Field]
+		if !exists {
+			continue
+		}
+		checked = append(checked, check.Field)
+
+		var (
+			expected interface{}
+			matches  bool
+			err      error
+		)
+		switch check.Kind {
+		case "sum":
+			expected, matches, err = check.verifySum(inputMap, actual)
+		case "hash":
+			expected, matches, err = check.verifyHash(inputMap, actual)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("check %s: %w", check.Field, err)
+		}
+
+		if !matches {
+			mismatches = append(mismatches, map[string]interface{}{
+				"field":    check.Field,
+				"kind":     check.Kind,
+				"expected": expected,
+				"actual":   actual,
+			})
+		}
+	}
+
+	result := policyresult.New(p.Name(), "derived field check")
+	result.Data["checked"] = checked
+	result.Data["mismatches"] = mismatches
+
+	if len(mismatches) > 0 {
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("%d derived field(s) do not match", len(mismatches))
+	} else {
+		result.Status = "PASSED"
+		result.Message = "All derived fields match"
+	}
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	for i, check := range p.Checks {
+		if check.Field == "" {
+			return fmt.Errorf("check %d: field is required", i)
+		}
+		switch check.Kind {
+		case "sum":
+			if check.ItemsField == "" || check.ValueField == "" {
+				return fmt.Errorf("check %s: sum requires items_field and value_field", check.Field)
+			}
+			if check.Tolerance < 0 {
+				return fmt.Errorf("check %s: tolerance must not be negative", check.Field)
+			}
+		case "hash":
+			if len(check.Fields) == 0 {
+				return fmt.Errorf("check %s: hash requires fields", check.Field)
+			}
+			if _, err := newHash(check.Algorithm); err != nil {
+				return fmt.Errorf("check %s: %w", check.Field, err)
+			}
+		default:
+			return fmt.Errorf("check %s: unsupported kind %q, expected sum or hash", check.Field, check.Kind)
+		}
+	}
+	return nil
+}
+
+// verifySum totals ValueField (times MultiplyField, if set) across items
+func (c Check) verifySum(inputMap map[string]interface{}, actual interface{}) (interface{}, bool, error) {
+	rawItems, ok := inputMap[c.ItemsField].([]interface{})
+	if !ok && inputMap[c.ItemsField] != nil {
+		return nil, false, fmt.Errorf("field %q: expected array, got %T", c.ItemsField, inputMap[c.ItemsField])
+	}
+
+	var total float64
+	for i, raw := range rawItems {
+		item, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, false, fmt.Errorf("item %d: expected object, got %T", i, raw)
+		}
+		value, ok := toFloat(item[c.ValueField])
+		if !ok {
+			return nil, false, fmt.Errorf("item %d field %q: expected a number, got %T", i, c.ValueField, item[c.ValueField])
+		}
+		if c.MultiplyField != "" {
+			factor, ok := toFloat(item[c.MultiplyField])
+			if !ok {
+				return nil, false, fmt.Errorf("item %d field %q: expected a number, got %T", i, c.MultiplyField, item[c.MultiplyField])
+			}
+			value *= factor
+		}
+		total += value
+	}
+
+	tolerance := c.Tolerance
+	if tolerance == 0 {
+		tolerance = 1e-9
+	}
+	number, ok := toFloat(actual)
+	return total, ok && math.Abs(number-total) <= tolerance, nil
+}
+
+// verifyHash hashes the configured fields joined by the separator and
+// compares the hex digest case-insensitively
+func (c Check) verifyHash(inputMap map[string]interface{}, actual interface{}) (interface{}, bool, error) {
+	separator := "|"
+	if c.Separator != nil {
+		separator = *c.Separator
+	}
+
+	parts := make([]string, len(c.Fields))
+	for i, field := range c.Fields {
+		value := inputMap[field]
+		switch v := value.(type) {
+		case nil:
+		case string:
+			parts[i] = v
+		case float64, int, int64, json.Number, bool:
+			parts[i] = fmt.Sprint(v)
+		default:
+			return nil, false, fmt.Errorf("field %q: cannot hash value of type %T", field, value)
+		}
+	}
+
+	h, err := newHash(c.Algorithm)
+	if err != nil {
+		return nil, false, err
+	}
+	h.Write([]byte(strings.Join(parts, separator)))
+	expected := hex.EncodeToString(h.Sum(nil))
+
+	s, ok := actual.(string)
+	return expected, ok && strings.EqualFold(s, expected), nil
+}
+
+func newHash(algorithm string) (hash.Hash, error) {
+	switch strings.ToLower(algorithm) {
+	case "", "sha256":
+		return sha256.New(), nil
+	case "sha1":
+		return sha1.New(), nil
+	case "md5":
+		return md5.New(), nil
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q, expected sha256, sha1 or md5", algorithm)
+	}
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
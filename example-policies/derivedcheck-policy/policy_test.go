@@ -0,0 +1,225 @@
+package derivedcheckpolicy
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func md5Hex(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// orderChecks verifies an order total from its lines and a signature over
+// the order's identifying fields
+func orderChecks() *Policy {
+	return &Policy{Checks: []Check{
+		{Field: "total", Kind: "sum", ItemsField: "lines", ValueField: "price", MultiplyField: "qty", Tolerance: 0.001},
+		{Field: "signature", Kind: "hash", Fields: []string{"id", "customer", "total"}},
+	}}
+}
+
+// order returns a consistent order; total 2*5.25 + 1*9.5 = 20
+func order() map[string]interface{} {
+	return map[string]interface{}{
+		"id":       "o-1",
+		"customer": "ada",
+		"total":    20.0,
+		"lines": []interface{}{
+			map[string]interface{}{"price": 5.25, "qty": 2},
+			map[string]interface{}{"price": 9.5, "qty": 1},
+		},
+		"signature": sha256Hex("o-1|ada|20"),
+	}
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name           string
+		tamper         func(map[string]interface{})
+		wantStatus     string
+		wantMismatches []map[string]interface{}
+	}{
+		{
+			name:           "consistent",
+			tamper:         func(map[string]interface{}) {},
+			wantStatus:     "PASSED",
+			wantMismatches: []map[string]interface{}{},
+		},
+		{
+			name:           "signature case ignored",
+			tamper:         func(in map[string]interface{}) { in["signature"] = strings.ToUpper(in["signature"].(string)) },
+			wantStatus:     "PASSED",
+			wantMismatches: []map[string]interface{}{},
+		},
+		{
+			name:           "total within tolerance",
+			tamper:         func(in map[string]interface{}) { in["total"] = 20.0004; in["signature"] = sha256Hex("o-1|ada|20.0004") },
+			wantStatus:     "PASSED",
+			wantMismatches: []map[string]interface{}{},
+		},
+		{
+			name: "tampered line item",
+			tamper: func(in map[string]interface{}) {
+				in["lines"].([]interface{})[1].(map[string]interface{})["qty"] = 2
+			},
+			wantStatus: "FAILED",
+			wantMismatches: []map[string]interface{}{
+				{"field": "total", "kind": "sum", "expected": 29.5, "actual": 20.0},
+			},
+		},
+		{
+			name:       "tampered hashed field",
+			tamper:     func(in map[string]interface{}) { in["customer"] = "mallory" },
+			wantStatus: "FAILED",
+			wantMismatches: []map[string]interface{}{
+				{"field": "signature", "kind": "hash", "expected": sha256Hex("o-1|mallory|20"), "actual": sha256Hex("o-1|ada|20")},
+			},
+		},
+		{
+			name: "total tampered along with the items",
+			tamper: func(in map[string]interface{}) {
+				in["total"] = 1.0
+				in["lines"] = []interface{}{map[string]interface{}{"price": 1, "qty": 1}}
+			},
+			wantStatus: "FAILED",
+			wantMismatches: []map[string]interface{}{
+				{"field": "signature", "kind": "hash", "expected": sha256Hex("o-1|ada|1"), "actual": sha256Hex("o-1|ada|20")},
+			},
+		},
+		{
+			name:       "derived value of the wrong type",
+			tamper:     func(in map[string]interface{}) { in["total"] = "20" },
+			wantStatus: "FAILED",
+			wantMismatches: []map[string]interface{}{
+				{"field": "total", "kind": "sum", "expected": 20.0, "actual": "20"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := order()
+			tt.tamper(input)
+			got, err := orderChecks().Execute(context.Background(), input)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if result.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s (%s)", result.Status, tt.wantStatus, result.Message)
+			}
+			if !reflect.DeepEqual(result.Data["mismatches"], tt.wantMismatches) {
+				t.Errorf("mismatches = %v, want %v", result.Data["mismatches"], tt.wantMismatches)
+			}
+		})
+	}
+}
+
+func TestExecuteHashOptions(t *testing.T) {
+	empty := ""
+	p := &Policy{Checks: []Check{
+		{Field: "etag", Kind: "hash", Fields: []string{"a", "missing", "b"}, Separator: &empty, Algorithm: "MD5"},
+	}}
+	got, err := p.Execute(context.Background(), map[string]interface{}{"a": "x", "b": true, "etag": md5Hex("xtrue")})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result := got.(*policyresult.Result); result.Status != "PASSED" {
+		t.Errorf("status = %s, want PASSED (%v)", result.Status, result.Data["mismatches"])
+	}
+}
+
+func TestExecuteSkipsAbsentFields(t *testing.T) {
+	input := order()
+	delete(input, "signature")
+	got, err := orderChecks().Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if checked := got.(*policyresult.Result).Data["checked"]; !reflect.DeepEqual(checked, []string{"total"}) {
+		t.Errorf("checked = %v, want [total]", checked)
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(map[string]interface{})
+	}{
+		{"lines not an array", func(in map[string]interface{}) { in["lines"] = "x" }},
+		{"line not an object", func(in map[string]interface{}) { in["lines"] = []interface{}{1} }},
+		{"non-numeric price", func(in map[string]interface{}) {
+			in["lines"] = []interface{}{map[string]interface{}{"price": "1", "qty": 1}}
+		}},
+		{"missing quantity", func(in map[string]interface{}) { in["lines"] = []interface{}{map[string]interface{}{"price": 1}} }},
+		{"unhashable field", func(in map[string]interface{}) { in["customer"] = map[string]interface{}{} }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := order()
+			tt.tamper(input)
+			if _, err := orderChecks().Execute(context.Background(), input); err == nil {
+				t.Error("Execute succeeded, want an error")
+			}
+		})
+	}
+
+	if _, err := orderChecks().Execute(context.Background(), "order"); err == nil {
+		t.Error("Execute accepted a non-map input")
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	tests := []struct {
+		name    string
+		check   map[string]interface{}
+		wantErr bool
+	}{
+		{"sum", map[string]interface{}{"field": "total", "kind": "sum", "items_field": "lines", "value_field": "price"}, false},
+		{"hash", map[string]interface{}{"field": "sig", "kind": "hash", "fields": []interface{}{"a"}, "algorithm": "sha1"}, false},
+		{"missing field", map[string]interface{}{"kind": "sum", "items_field": "lines", "value_field": "price"}, true},
+		{"sum without items", map[string]interface{}{"field": "total", "kind": "sum", "value_field": "price"}, true},
+		{"negative tolerance", map[string]interface{}{"field": "total", "kind": "sum", "items_field": "lines", "value_field": "price", "tolerance": -1}, true},
+		{"hash without fields", map[string]interface{}{"field": "sig", "kind": "hash"}, true},
+		{"unknown algorithm", map[string]interface{}{"field": "sig", "kind": "hash", "fields": []interface{}{"a"}, "algorithm": "crc32"}, true},
+		{"unknown kind", map[string]interface{}{"field": "x", "kind": "avg"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&Policy{}).Configure(map[string]interface{}{"checks": []interface{}{tt.check}})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Configure error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Policy{}).Validate(); err != nil {
+		t.Errorf("zero value: %v", err)
+	}
+}
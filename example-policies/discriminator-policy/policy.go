@@ -0,0 +1,111 @@
+package discriminatorpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Policy implements the policy engine interface
+// It applies a different set of required fields depending on the value of
+// a discriminator field, e.g. type=card requires card_number while
+// type=bank requires account
+type Policy struct {
+	// Discriminator names the input field that selects the variant (default "type")
+	Discriminator string `json:"discriminator"`
+
+	// Variants maps each discriminator value to its required fields
+	Variants map[string][]string `json:"variants"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "discriminator-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	result := policyresult.New(p.Name(), "discriminated field validation")
+	result.Data["discriminator"] = p.discriminator()
+
+	value, exists := inputMap[p.discriminator()]
+	if !exists {
+		result.Status = "PASSED"
+		result.Message = "No discriminator present"
+		return result, nil
+	}
+
+	variant := fmt.Sprint(value)
+	result.Data["variant"] = variant
+
+	required, known := p.Variants[variant]
+	if !known {
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("Unknown %s %q", p.discriminator(), variant)
+		return result, nil
+	}
+
+	missingFields := []string{}
+	for _, field := range required {
+		if _, exists := inputMap[field]; !exists {
+			missingFields = append(missingFields, field)
+		}
+	}
+
+	result.Data["required_fields"] = required
+	result.Data["missing_fields"] = missingFields
+
+	if len(missingFields) > 0 {
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("Missing required fields for %s %q: %v", p.discriminator(), variant, missingFields)
+	} else {
+		result.Status = "PASSED"
+		result.Message = fmt.Sprintf("All required fields present for %s %q", p.discriminator(), variant)
+	}
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	for variant, fields := range p.Variants {
+		for _, field := range fields {
+			if field == p.discriminator() {
+				return fmt.Errorf("variant %q lists the discriminator %q as a required field", variant, field)
+			}
+		}
+	}
+	return nil
+}
+
+func (p *Policy) discriminator() string {
+	if p.Discriminator == "" {
+		return "type"
+	}
+	return p.Discriminator
+}
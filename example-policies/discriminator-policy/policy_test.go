@@ -0,0 +1,137 @@
+package discriminatorpolicy
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+var paymentVariants = map[string][]string{
+	"card": {"card_number", "expiry"},
+	"bank": {"account"},
+	"cash": {},
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       map[string]interface{}
+		wantStatus  string
+		wantMissing []string
+	}{
+		{
+			name:        "card complete",
+			input:       map[string]interface{}{"type": "card", "card_number": "4111", "expiry": "12/30"},
+			wantStatus:  "PASSED",
+			wantMissing: []string{},
+		},
+		{
+			name:        "card missing fields",
+			input:       map[string]interface{}{"type": "card", "account": "123"},
+			wantStatus:  "FAILED",
+			wantMissing: []string{"card_number", "expiry"},
+		},
+		{
+			name:        "bank complete",
+			input:       map[string]interface{}{"type": "bank", "account": "123"},
+			wantStatus:  "PASSED",
+			wantMissing: []string{},
+		},
+		{
+			name:        "bank missing account",
+			input:       map[string]interface{}{"type": "bank", "card_number": "4111"},
+			wantStatus:  "FAILED",
+			wantMissing: []string{"account"},
+		},
+		{
+			name:        "variant without requirements",
+			input:       map[string]interface{}{"type": "cash"},
+			wantStatus:  "PASSED",
+			wantMissing: []string{},
+		},
+		{
+			name:        "null counts as present",
+			input:       map[string]interface{}{"type": "bank", "account": nil},
+			wantStatus:  "PASSED",
+			wantMissing: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Policy{Variants: paymentVariants}
+			got, err := p.Execute(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if result.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s (%s)", result.Status, tt.wantStatus, result.Message)
+			}
+			if !reflect.DeepEqual(result.Data["missing_fields"], tt.wantMissing) {
+				t.Errorf("missing_fields = %v, want %v", result.Data["missing_fields"], tt.wantMissing)
+			}
+		})
+	}
+}
+
+func TestExecuteUnknownDiscriminator(t *testing.T) {
+	p := &Policy{Variants: paymentVariants}
+	got, err := p.Execute(context.Background(), map[string]interface{}{"type": "crypto", "wallet": "x"})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	result := got.(*policyresult.Result)
+	if result.Status != "FAILED" {
+		t.Errorf("status = %s, want FAILED", result.Status)
+	}
+	if want := `Unknown type "crypto"`; result.Message != want {
+		t.Errorf("message = %q, want %q", result.Message, want)
+	}
+}
+
+func TestExecuteCustomDiscriminator(t *testing.T) {
+	// Non-string discriminator values are matched by their printed form
+	p := &Policy{Discriminator: "version", Variants: map[string][]string{"2": {"schema"}}}
+	got, err := p.Execute(context.Background(), map[string]interface{}{"version": 2.0})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	result := got.(*policyresult.Result)
+	if result.Data["variant"] != "2" || result.Status != "FAILED" {
+		t.Errorf("variant = %v, status = %s; want 2, FAILED", result.Data["variant"], result.Status)
+	}
+}
+
+func TestExecuteNoDiscriminator(t *testing.T) {
+	got, err := (&Policy{Variants: paymentVariants}).Execute(context.Background(), map[string]interface{}{"account": "1"})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result := got.(*policyresult.Result); result.Status != "PASSED" {
+		t.Errorf("status = %s, want PASSED", result.Status)
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Policy{}).Validate(); err != nil {
+		t.Errorf("zero value: %v", err)
+	}
+	if err := (&Policy{Variants: paymentVariants}).Validate(); err != nil {
+		t.Errorf("payment variants: %v", err)
+	}
+	if err := (&Policy{Variants: map[string][]string{"card": {"type"}}}).Validate(); err == nil {
+		t.Error("variant requiring the discriminator passed validation")
+	}
+}
@@ -0,0 +1,161 @@
+package envelopepolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Policy implements the policy engine interface
+// It standardizes API responses on the envelope
+//
+//	{"data": ..., "meta": {...}, "errors": [...]}
+//
+// In wrap mode the input becomes the envelope's data, with the configured
+// meta and an empty errors list. In validate mode the input must already
+// be an envelope: it must have exactly the data, meta and errors keys,
+// meta must be an object holding every required meta key, and errors must
+// be a list.
+type Policy struct {
+	// Mode is "wrap" or "validate" (default "wrap")
+	Mode string `json:"mode"`
+
+	// Meta is copied into the meta object of wrapped inputs
+	Meta map[string]interface{} `json:"meta"`
+
+	// RequiredMeta lists keys the meta object must contain in validate mode
+	RequiredMeta []string `json:"required_meta"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "envelope-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	if p.mode() == "validate" {
+		return p.validate(inputMap), nil
+	}
+	return p.wrap(inputMap), nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	switch p.mode() {
+	case "wrap", "validate":
+	default:
+		return fmt.Errorf("unsupported mode %q, expected wrap or validate", p.Mode)
+	}
+	for i, key := range p.RequiredMeta {
+		if key == "" {
+			return fmt.Errorf("required_meta %d: key must not be empty", i)
+		}
+	}
+	return nil
+}
+
+func (p *Policy) mode() string {
+	if p.Mode == "" {
+		return "wrap"
+	}
+	return p.Mode
+}
+
+// wrap places the input in a new envelope
+func (p *Policy) wrap(inputMap map[string]interface{}) *policyresult.Result {
+	meta := make(map[string]interface{}, len(p.Meta))
+	for key, value := range p.Meta {
+		meta[key] = value
+	}
+
+	return &policyresult.Result{
+		Policy:  p.Name(),
+		Action:  "envelope wrapping",
+		Status:  "PASSED",
+		Message: "Wrapped input in response envelope",
+		Output: map[string]interface{}{
+			"data":   inputMap,
+			"meta":   meta,
+			"errors": []interface{}{},
+		},
+	}
+}
+
+// validate checks that the input already has the envelope shape
+func (p *Policy) validate(inputMap map[string]interface{}) *policyresult.Result {
+	violations := []string{}
+
+	for _, key := range []string{"data", "meta", "errors"} {
+		if _, exists := inputMap[key]; !exists {
+			violations = append(violations, fmt.Sprintf("missing %q", key))
+		}
+	}
+
+	var unexpected []string
+	for key := range inputMap {
+		if key != "data" && key != "meta" && key != "errors" {
+			unexpected = append(unexpected, key)
+		}
+	}
+	sort.Strings(unexpected)
+	for _, key := range unexpected {
+		violations = append(violations, fmt.Sprintf("unexpected key %q", key))
+	}
+
+	if raw, exists := inputMap["meta"]; exists {
+		meta, ok := raw.(map[string]interface{})
+		if !ok {
+			violations = append(violations, fmt.Sprintf("meta: expected object, got %T", raw))
+		} else {
+			for _, key := range p.RequiredMeta {
+				if _, exists := meta[key]; !exists {
+					violations = append(violations, fmt.Sprintf("meta: missing %q", key))
+				}
+			}
+		}
+	}
+
+	if raw, exists := inputMap["errors"]; exists {
+		if _, ok := raw.([]interface{}); !ok {
+			violations = append(violations, fmt.Sprintf("errors: expected list, got %T", raw))
+		}
+	}
+
+	result := policyresult.New(p.Name(), "envelope validation")
+	result.Data["violations"] = violations
+	if len(violations) > 0 {
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("Input is not a valid envelope: %d violation(s)", len(violations))
+	} else {
+		result.Status = "PASSED"
+		result.Message = "Input is a valid envelope"
+	}
+	return result
+}
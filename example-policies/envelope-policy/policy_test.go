@@ -0,0 +1,144 @@
+package envelopepolicy
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+func TestExecuteWrap(t *testing.T) {
+	p := &Policy{Meta: map[string]interface{}{"version": "v1"}}
+	input := map[string]interface{}{"id": 7}
+
+	got, err := p.Execute(context.Background(), input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := got.(*policyresult.Result)
+	want := map[string]interface{}{
+		"data":   input,
+		"meta":   map[string]interface{}{"version": "v1"},
+		"errors": []interface{}{},
+	}
+	if result.Status != "PASSED" || !reflect.DeepEqual(result.Output, want) {
+		t.Errorf("status = %s, output = %v; want PASSED, %v", result.Status, result.Output, want)
+	}
+
+	// Each envelope gets its own meta, so later changes do not leak back
+	result.Output["meta"].(map[string]interface{})["version"] = "changed"
+	if p.Meta["version"] != "v1" {
+		t.Error("changing the wrapped meta changed the configured meta")
+	}
+}
+
+func TestExecuteValidate(t *testing.T) {
+	p := &Policy{Mode: "validate", RequiredMeta: []string{"request_id"}}
+
+	tests := []struct {
+		name           string
+		input          map[string]interface{}
+		wantStatus     string
+		wantViolations []string
+	}{
+		{
+			name:           "valid envelope",
+			input:          map[string]interface{}{"data": 1, "meta": map[string]interface{}{"request_id": "r1"}, "errors": []interface{}{}},
+			wantStatus:     "PASSED",
+			wantViolations: []string{},
+		},
+		{
+			name:           "missing keys",
+			input:          map[string]interface{}{"data": 1},
+			wantStatus:     "FAILED",
+			wantViolations: []string{`missing "meta"`, `missing "errors"`},
+		},
+		{
+			name:           "unexpected keys",
+			input:          map[string]interface{}{"data": 1, "meta": map[string]interface{}{"request_id": "r1"}, "errors": []interface{}{}, "status": 200, "extra": true},
+			wantStatus:     "FAILED",
+			wantViolations: []string{`unexpected key "extra"`, `unexpected key "status"`},
+		},
+		{
+			name:           "missing required meta",
+			input:          map[string]interface{}{"data": 1, "meta": map[string]interface{}{}, "errors": []interface{}{}},
+			wantStatus:     "FAILED",
+			wantViolations: []string{`meta: missing "request_id"`},
+		},
+		{
+			name:           "wrong types",
+			input:          map[string]interface{}{"data": 1, "meta": "m", "errors": "none"},
+			wantStatus:     "FAILED",
+			wantViolations: []string{"meta: expected object, got string", "errors: expected list, got string"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.Execute(context.Background(), tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result := got.(*policyresult.Result)
+			if result.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", result.Status, tt.wantStatus)
+			}
+			if violations := result.Data["violations"]; !reflect.DeepEqual(violations, tt.wantViolations) {
+				t.Errorf("violations = %q, want %q", violations, tt.wantViolations)
+			}
+		})
+	}
+}
+
+func TestWrappedEnvelopeValidates(t *testing.T) {
+	wrapped, err := (&Policy{Meta: map[string]interface{}{"request_id": "r1"}}).Execute(context.Background(), map[string]interface{}{"id": 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := (&Policy{Mode: "validate", RequiredMeta: []string{"request_id"}}).Execute(context.Background(), wrapped.(*policyresult.Result).Output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := got.(*policyresult.Result); result.Status != "PASSED" {
+		t.Errorf("validating a wrapped envelope: status = %s, violations = %v", result.Status, result.Data["violations"])
+	}
+}
+
+func TestExecuteNonObject(t *testing.T) {
+	if _, err := (&Policy{}).Execute(context.Background(), "text"); err == nil {
+		t.Error("non-object input succeeded, want error")
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr bool
+	}{
+		{"zero value", map[string]interface{}{}, false},
+		{"wrap", map[string]interface{}{"mode": "wrap", "meta": map[string]interface{}{"v": 1}}, false},
+		{"validate", map[string]interface{}{"mode": "validate", "required_meta": []interface{}{"request_id"}}, false},
+		{"unknown mode", map[string]interface{}{"mode": "strip"}, true},
+		{"empty required key", map[string]interface{}{"mode": "validate", "required_meta": []interface{}{""}}, true},
+		{"meta not an object", map[string]interface{}{"meta": "v1"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := (&Policy{}).Configure(tt.config); (err != nil) != tt.wantErr {
+				t.Errorf("Configure(%v) = %v, wantErr %v", tt.config, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package envresolvepolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/example/policies/policyresult"
+)
+
+// referencePattern matches ${VAR} references
+var referencePattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// Policy implements the policy engine interface
+// It replaces ${VAR} references in configured string fields with the
+// values of environment variables. References to unset variables are
+// reported and either left in place ("leave", the default) or fail the
+// policy ("fail").
+type Policy struct {
+	// Fields lists the input fields to resolve
+	Fields []string `json:"fields"`
+
+	// OnMissing is "leave" (default) or "fail"
+	OnMissing string `json:"on_missing"`
+
+	// LookupEnv looks up a variable; it defaults to os.LookupEnv
+	LookupEnv func(key string) (string, bool) `json:"-"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "envresolve-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	output := make(map[string]interface{}, len(inputMap))
+	for key, value := range inputMap {
+		output[key] = value
+	}
+
+	resolved := []string{}
+	unresolved := []map[string]interface{}{}
+	for _, field := range p.Fields {
+		value, ok := inputMap[field].(string)
+		if !ok {
+			continue
+		}
+
+		var missing []string
+		replaced := referencePattern.ReplaceAllStringFunc(value, func(ref string) string {
+			name := referencePattern.FindStringSubmatch(ref)[1]
+			if v, ok := p.lookupEnv(name); ok {
+				return v
+			}
+			missing = append(missing, name)
+			return ref
+		})
+
+		if len(missing) > 0 {
+			unresolved = append(unresolved, map[string]interface{}{
+				"field":     field,
+				"variables": missing,
+			})
+		}
+		if replaced != value {
+			output[field] = replaced
+			resolved = append(resolved, field)
+		}
+	}
+
+	result := policyresult.New(p.Name(), "environment resolution")
+	result.Data["resolved"] = resolved
+	result.Data["unresolved"] = unresolved
+	result.Output = output
+
+	if len(unresolved) > 0 && p.onMissing() == "fail" {
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("%d field(s) reference unset variables", len(unresolved))
+	} else {
+		result.Status = "PASSED"
+		result.Message = fmt.Sprintf("Resolved references in %d field(s)", len(resolved))
+	}
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	switch p.onMissing() {
+	case "leave", "fail":
+		return nil
+	default:
+		return fmt.Errorf("unsupported on_missing %q, expected leave or fail", p.OnMissing)
+	}
+}
+
+func (p *Policy) onMissing() string {
+	if p.OnMissing == "" {
+		return "leave"
+	}
+	return strings.ToLower(p.OnMissing)
+}
+
+func (p *Policy) lookupEnv(key string) (string, bool) {
+	if p.LookupEnv != nil {
+		return p.LookupEnv(key)
+	}
+	return os.LookupEnv(key)
+}
@@ -0,0 +1,149 @@
+package envresolvepolicy
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+// env returns a LookupEnv over a fixed set of variables
+func env(vars map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := vars[key]
+		return value, ok
+	}
+}
+
+func TestExecute(t *testing.T) {
+	lookup := env(map[string]string{"HOST": "db.local", "PORT": "5432", "EMPTY": ""})
+
+	tests := []struct {
+		name           string
+		onMissing      string
+		input          map[string]interface{}
+		wantStatus     string
+		wantOutput     map[string]interface{}
+		wantResolved   []string
+		wantUnresolved int
+	}{
+		{
+			name:         "all resolved",
+			input:        map[string]interface{}{"dsn": "postgres://${HOST}:${PORT}/app", "note": "${HOST}"},
+			wantStatus:   "PASSED",
+			wantOutput:   map[string]interface{}{"dsn": "postgres://db.local:5432/app", "note": "${HOST}"},
+			wantResolved: []string{"dsn"},
+		},
+		{
+			name:         "empty variable",
+			input:        map[string]interface{}{"dsn": "x${EMPTY}y"},
+			wantStatus:   "PASSED",
+			wantOutput:   map[string]interface{}{"dsn": "xy"},
+			wantResolved: []string{"dsn"},
+		},
+		{
+			name:           "unresolved left in place",
+			input:          map[string]interface{}{"dsn": "${HOST}:${MISSING}"},
+			wantStatus:     "PASSED",
+			wantOutput:     map[string]interface{}{"dsn": "db.local:${MISSING}"},
+			wantResolved:   []string{"dsn"},
+			wantUnresolved: 1,
+		},
+		{
+			name:           "unresolved fails",
+			onMissing:      "fail",
+			input:          map[string]interface{}{"dsn": "${MISSING}"},
+			wantStatus:     "FAILED",
+			wantOutput:     map[string]interface{}{"dsn": "${MISSING}"},
+			wantResolved:   []string{},
+			wantUnresolved: 1,
+		},
+		{
+			name:         "non-string field skipped",
+			onMissing:    "fail",
+			input:        map[string]interface{}{"dsn": 42.0},
+			wantStatus:   "PASSED",
+			wantOutput:   map[string]interface{}{"dsn": 42.0},
+			wantResolved: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Policy{Fields: []string{"dsn"}, OnMissing: tt.onMissing, LookupEnv: lookup}
+			got, err := p.Execute(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if result.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", result.Status, tt.wantStatus)
+			}
+			if !reflect.DeepEqual(result.Output, tt.wantOutput) {
+				t.Errorf("output = %v, want %v", result.Output, tt.wantOutput)
+			}
+			if !reflect.DeepEqual(result.Data["resolved"], tt.wantResolved) {
+				t.Errorf("resolved = %v, want %v", result.Data["resolved"], tt.wantResolved)
+			}
+			if unresolved := result.Data["unresolved"].([]map[string]interface{}); len(unresolved) != tt.wantUnresolved {
+				t.Errorf("unresolved = %v, want %d entries", unresolved, tt.wantUnresolved)
+			}
+		})
+	}
+}
+
+func TestExecuteReportsMissingVariables(t *testing.T) {
+	p := &Policy{Fields: []string{"url"}, LookupEnv: env(nil)}
+	got, err := p.Execute(context.Background(), map[string]interface{}{"url": "${SCHEME}://${HOST}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	unresolved := got.(*policyresult.Result).Data["unresolved"].([]map[string]interface{})
+	want := []map[string]interface{}{{"field": "url", "variables": []string{"SCHEME", "HOST"}}}
+	if !reflect.DeepEqual(unresolved, want) {
+		t.Errorf("unresolved = %v, want %v", unresolved, want)
+	}
+}
+
+func TestExecuteUsesEnvironment(t *testing.T) {
+	t.Setenv("ENVRESOLVE_TEST_REGION", "eu-west-1")
+	p := &Policy{Fields: []string{"region"}}
+	got, err := p.Execute(context.Background(), map[string]interface{}{"region": "${ENVRESOLVE_TEST_REGION}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if region := got.(*policyresult.Result).Output["region"]; region != "eu-west-1" {
+		t.Errorf("region = %v, want eu-west-1", region)
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr bool
+	}{
+		{"zero value", map[string]interface{}{}, false},
+		{"leave", map[string]interface{}{"on_missing": "leave"}, false},
+		{"fail uppercase", map[string]interface{}{"on_missing": "FAIL"}, false},
+		{"unknown on_missing", map[string]interface{}{"on_missing": "drop"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := (&Policy{}).Configure(tt.config); (err != nil) != tt.wantErr {
+				t.Errorf("Configure(%v) = %v, wantErr %v", tt.config, err, tt.wantErr)
+			}
+		})
+	}
+}
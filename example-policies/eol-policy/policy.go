@@ -0,0 +1,135 @@
+package eolpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/example/policies/policyresult"
+)
+
+// lineEndings maps the supported target styles to their byte sequences
+var lineEndings = map[string]string{
+	"lf":   "\n",
+	"crlf": "\r\n",
+	"cr":   "\r",
+}
+
+// Policy implements the policy engine interface
+// It normalizes line endings in configured string fields to a single
+// target style, which is useful for text coming from mixed-OS sources
+type Policy struct {
+	// Fields lists the input fields to normalize
+	Fields []string `json:"fields"`
+
+	// Target is the line ending style to convert to: lf (default), crlf or cr
+	Target string `json:"target"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "eol-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	result := policyresult.New(p.Name(), "line ending normalization")
+	result.Data["target"] = p.target()
+
+	output := make(map[string]interface{}, len(inputMap))
+	for key, value := range inputMap {
+		output[key] = value
+	}
+
+	eol := lineEndings[p.target()]
+	conversions := make(map[string]int)
+	total := 0
+	for _, field := range p.Fields {
+		s, ok := inputMap[field].(string)
+		if !ok {
+			continue
+		}
+
+		normalized, count := normalize(s, eol)
+		output[field] = normalized
+		conversions[field] = count
+		total += count
+	}
+
+	result.Data["input"] = inputMap
+	result.Output = output
+	result.Data["conversions"] = conversions
+	result.Data["total_conversions"] = total
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	if _, ok := lineEndings[p.target()]; !ok {
+		return fmt.Errorf("unsupported target %q, expected lf, crlf or cr", p.Target)
+	}
+	return nil
+}
+
+func (p *Policy) target() string {
+	if p.Target == "" {
+		return "lf"
+	}
+	return strings.ToLower(p.Target)
+}
+
+// normalize rewrites every line ending in s to eol and returns the number
+// of line endings that were changed
+func normalize(s, eol string) (string, int) {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	count := 0
+	for i := 0; i < len(s); i++ {
+		var found string
+		switch {
+		case s[i] == '\r' && i+1 < len(s) && s[i+1] == '\n':
+			found = "\r\n"
+			i++
+		case s[i] == '\r':
+			found = "\r"
+		case s[i] == '\n':
+			found = "\n"
+		default:
+			b.WriteByte(s[i])
+			continue
+		}
+
+		if found != eol {
+			count++
+		}
+		b.WriteString(eol)
+	}
+
+	return b.String(), count
+}
@@ -0,0 +1,100 @@
+package eolpolicy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+func TestExecuteMixedLineEndings(t *testing.T) {
+	// One of each line ending, so every target converts exactly two
+	const mixed = "a\nb\r\nc\rd"
+
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{"lf", "a\nb\nc\nd"},
+		{"crlf", "a\r\nb\r\nc\r\nd"},
+		{"cr", "a\rb\rc\rd"},
+		{"CRLF", "a\r\nb\r\nc\r\nd"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			p := &Policy{Fields: []string{"text"}, Target: tt.target}
+			got, err := p.Execute(context.Background(), map[string]interface{}{"text": mixed, "other": "x\r\ny"})
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if result.Output["text"] != tt.want {
+				t.Errorf("text = %q, want %q", result.Output["text"], tt.want)
+			}
+			if result.Output["other"] != "x\r\ny" {
+				t.Errorf("unconfigured field changed to %q", result.Output["other"])
+			}
+			if result.Data["total_conversions"] != 2 {
+				t.Errorf("total_conversions = %v, want 2", result.Data["total_conversions"])
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name      string
+		in, eol   string
+		want      string
+		wantCount int
+	}{
+		{"already normalized", "a\nb\n", "\n", "a\nb\n", 0},
+		{"crlf to lf", "a\r\nb\r\n", "\n", "a\nb\n", 2},
+		{"lone cr at end", "a\r", "\n", "a\n", 1},
+		{"blank lines", "\r\n\r\n", "\r", "\r\r", 2},
+		{"lf cr is two endings", "a\n\rb", "\r\n", "a\r\n\r\nb", 2},
+		{"no line endings", "abc", "\r\n", "abc", 0},
+		{"empty", "", "\n", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, count := normalize(tt.in, tt.eol)
+			if got != tt.want || count != tt.wantCount {
+				t.Errorf("normalize(%q) = %q, %d; want %q, %d", tt.in, got, count, tt.want, tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestExecuteIgnoresNonStrings(t *testing.T) {
+	p := &Policy{Fields: []string{"n", "missing"}}
+	got, err := p.Execute(context.Background(), map[string]interface{}{"n": 1})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result := got.(*policyresult.Result); result.Output["n"] != 1 {
+		t.Errorf("n = %v, want 1", result.Output["n"])
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, target := range []string{"", "lf", "CRLF", "cr"} {
+		if err := (&Policy{Target: target}).Validate(); err != nil {
+			t.Errorf("target %q: %v", target, err)
+		}
+	}
+	if err := (&Policy{Target: "nel"}).Validate(); err == nil {
+		t.Error("unsupported target passed validation")
+	}
+}
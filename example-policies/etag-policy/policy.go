@@ -0,0 +1,139 @@
+package etagpolicy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Policy implements the policy engine interface
+// It computes a strong ETag for the input, the SHA-256 of its JSON
+// serialization, and attaches it to the output. encoding/json sorts
+// object keys, so inputs with the same content get the same tag
+// regardless of key order. In conditional mode the tag is compared with
+// the input's If-None-Match value, and a match short-circuits with a
+// NOT_MODIFIED status and no output.
+type Policy struct {
+	// Field names the output field receiving the ETag (default "etag")
+	Field string `json:"field"`
+
+	// Conditional compares the ETag with the If-None-Match value
+	Conditional bool `json:"conditional"`
+
+	// IfNoneMatchField names the input field holding the If-None-Match
+	// value (default "if-none-match"). It is excluded from the hash and
+	// from the output.
+	IfNoneMatchField string `json:"if_none_match_field"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "etag-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	// Hash the content without the tag fields so a tagged or conditional
+	// request hashes the same as the bare content
+	content := make(map[string]interface{}, len(inputMap))
+	for key, value := range inputMap {
+		if key != p.field() && key != p.ifNoneMatchField() {
+			content[key] = value
+		}
+	}
+
+	data, err := json.Marshal(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize input: %w", err)
+	}
+	digest := sha256.Sum256(data)
+	etag := `"` + hex.EncodeToString(digest[:]) + `"`
+
+	result := policyresult.New(p.Name(), "etag computation")
+	result.Data["etag"] = etag
+
+	if p.Conditional {
+		if raw, exists := inputMap[p.ifNoneMatchField()]; exists {
+			header, ok := raw.(string)
+			if !ok {
+				return nil, fmt.Errorf("field %q: expected string, got %T", p.ifNoneMatchField(), raw)
+			}
+			if matches(header, etag) {
+				result.Status = "NOT_MODIFIED"
+				result.Message = "Content matches If-None-Match"
+				return result, nil
+			}
+		}
+	}
+
+	content[p.field()] = etag
+	result.Status = "PASSED"
+	result.Message = "Attached ETag"
+	result.Output = content
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	if p.field() == p.ifNoneMatchField() {
+		return fmt.Errorf("field and if_none_match_field must differ")
+	}
+	return nil
+}
+
+func (p *Policy) field() string {
+	if p.Field == "" {
+		return "etag"
+	}
+	return p.Field
+}
+
+func (p *Policy) ifNoneMatchField() string {
+	if p.IfNoneMatchField == "" {
+		return "if-none-match"
+	}
+	return p.IfNoneMatchField
+}
+
+// matches reports whether an If-None-Match header value matches etag. The
+// header may list several tags separated by commas or be "*". As HTTP
+// requires for If-None-Match, the comparison is weak, so a W/ prefix is
+// ignored.
+func matches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,159 @@
+package etagpolicy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+// etagOf returns the quoted SHA-256 of serialized
+func etagOf(serialized string) string {
+	digest := sha256.Sum256([]byte(serialized))
+	return `"` + hex.EncodeToString(digest[:]) + `"`
+}
+
+func execute(t *testing.T, p *Policy, input map[string]interface{}) *policyresult.Result {
+	t.Helper()
+	got, err := p.Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Execute(%v): %v", input, err)
+	}
+	return got.(*policyresult.Result)
+}
+
+func TestExecuteAttachesETag(t *testing.T) {
+	want := etagOf(`{"a":1,"b":"x"}`)
+	result := execute(t, &Policy{}, map[string]interface{}{"b": "x", "a": 1})
+	if result.Status != "PASSED" || result.Output["etag"] != want || result.Data["etag"] != want {
+		t.Errorf("status = %s, etag = %v; want PASSED, %s", result.Status, result.Output["etag"], want)
+	}
+	if result.Output["a"] != 1 || result.Output["b"] != "x" {
+		t.Errorf("output = %v, want the input fields kept", result.Output)
+	}
+
+	// A custom field receives the tag instead
+	result = execute(t, &Policy{Field: "version"}, map[string]interface{}{"b": "x", "a": 1})
+	if result.Output["version"] != want {
+		t.Errorf("version = %v, want %s", result.Output["version"], want)
+	}
+	if _, exists := result.Output["etag"]; exists {
+		t.Error("etag field set despite a custom field")
+	}
+}
+
+func TestExecuteETagStability(t *testing.T) {
+	p := &Policy{}
+	base := execute(t, p, map[string]interface{}{"id": 1, "tags": []interface{}{"a", "b"}}).Data["etag"]
+
+	tests := []struct {
+		name  string
+		input map[string]interface{}
+		same  bool
+	}{
+		{"same content", map[string]interface{}{"tags": []interface{}{"a", "b"}, "id": 1}, true},
+		{"existing tag ignored", map[string]interface{}{"id": 1, "tags": []interface{}{"a", "b"}, "etag": `"stale"`}, true},
+		{"if-none-match ignored", map[string]interface{}{"id": 1, "tags": []interface{}{"a", "b"}, "if-none-match": `"x"`}, true},
+		{"changed value", map[string]interface{}{"id": 2, "tags": []interface{}{"a", "b"}}, false},
+		{"reordered list", map[string]interface{}{"id": 1, "tags": []interface{}{"b", "a"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := execute(t, p, tt.input).Data["etag"]
+			if (got == base) != tt.same {
+				t.Errorf("etag %v vs %v: same = %v, want %v", got, base, got == base, tt.same)
+			}
+		})
+	}
+}
+
+func TestExecuteConditional(t *testing.T) {
+	etag := etagOf(`{"id":1}`)
+	p := &Policy{Conditional: true}
+
+	tests := []struct {
+		name        string
+		ifNoneMatch interface{}
+		wantStatus  string
+	}{
+		{"exact match", etag, "NOT_MODIFIED"},
+		{"weak match", "W/" + etag, "NOT_MODIFIED"},
+		{"in a list", `"other", ` + etag, "NOT_MODIFIED"},
+		{"wildcard", "*", "NOT_MODIFIED"},
+		{"no match", `"other"`, "PASSED"},
+		{"no header", nil, "PASSED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := map[string]interface{}{"id": 1}
+			if tt.ifNoneMatch != nil {
+				input["if-none-match"] = tt.ifNoneMatch
+			}
+			result := execute(t, p, input)
+			if result.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", result.Status, tt.wantStatus)
+			}
+			if tt.wantStatus == "NOT_MODIFIED" && result.Output != nil {
+				t.Errorf("not modified output = %v, want none", result.Output)
+			}
+			if tt.wantStatus == "PASSED" {
+				if _, exists := result.Output["if-none-match"]; exists {
+					t.Error("output keeps the if-none-match field")
+				}
+			}
+		})
+	}
+
+	// Without conditional mode a matching header is only stripped
+	if result := execute(t, &Policy{}, map[string]interface{}{"id": 1, "if-none-match": etag}); result.Status != "PASSED" {
+		t.Errorf("non-conditional status = %s, want PASSED", result.Status)
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	p := &Policy{Conditional: true}
+	if _, err := p.Execute(context.Background(), map[string]interface{}{"if-none-match": 7}); err == nil {
+		t.Error("non-string if-none-match succeeded, want error")
+	}
+	if _, err := p.Execute(context.Background(), "text"); err == nil {
+		t.Error("non-object input succeeded, want error")
+	}
+	if _, err := p.Execute(context.Background(), map[string]interface{}{"f": func() {}}); err == nil {
+		t.Error("unserializable input succeeded, want error")
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr bool
+	}{
+		{"zero value", map[string]interface{}{}, false},
+		{"custom fields", map[string]interface{}{"field": "tag", "if_none_match_field": "match", "conditional": true}, false},
+		{"same fields", map[string]interface{}{"field": "tag", "if_none_match_field": "tag"}, true},
+		{"field equals default header field", map[string]interface{}{"field": "if-none-match"}, true},
+		{"conditional not a bool", map[string]interface{}{"conditional": "yes"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := (&Policy{}).Configure(tt.config); (err != nil) != tt.wantErr {
+				t.Errorf("Configure(%v) = %v, wantErr %v", tt.config, err, tt.wantErr)
+			}
+		})
+	}
+}
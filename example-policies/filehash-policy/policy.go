@@ -0,0 +1,220 @@
+package filehashpolicy
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Policy implements the policy engine interface
+// It verifies that a file referenced by the input matches an expected
+// checksum. Only files inside the configured root directory can be read.
+type Policy struct {
+	// Root is the directory that referenced files must live under
+	Root string `json:"root"`
+
+	// PathField names the input field holding the file path, relative to Root
+	PathField string `json:"path_field"`
+
+	// HashField names the input field holding the expected hex digest
+	HashField string `json:"hash_field"`
+
+	// Algorithm is one of md5, sha1, sha256 (default) or sha512
+	Algorithm string `json:"algorithm"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "filehash-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	result := policyresult.New(p.Name(), "file checksum verification")
+	result.Data["algorithm"] = p.algorithm()
+
+	pathField, hashField := p.fields()
+	pathValue, exists := inputMap[pathField]
+	if !exists {
+		result.Status = "PASSED"
+		result.Message = "No file reference to verify"
+		return result, nil
+	}
+
+	path, ok := pathValue.(string)
+	if !ok {
+		return nil, fmt.Errorf("field %q: expected string, got %T", pathField, pathValue)
+	}
+	expected, ok := inputMap[hashField].(string)
+	if !ok {
+		return nil, fmt.Errorf("field %q: expected string, got %T", hashField, inputMap[hashField])
+	}
+
+	if p.Root == "" {
+		return nil, fmt.Errorf("no root directory configured")
+	}
+
+	result.Data["file"] = path
+	result.Data["expected"] = strings.ToLower(expected)
+
+	actual, err := p.checksum(path)
+	if err != nil {
+		// The cause stays private: it could reveal host paths, or whether
+		// a file outside the root exists
+		result.Status = "FAILED"
+		result.Message = "File not accessible"
+		return result, nil
+	}
+
+	result.Data["actual"] = actual
+
+	if actual != strings.ToLower(expected) {
+		result.Status = "FAILED"
+		result.Message = "Checksum mismatch"
+	} else {
+		result.Status = "PASSED"
+		result.Message = "Checksum matches"
+	}
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	if newHash(p.algorithm()) == nil {
+		return fmt.Errorf("unsupported algorithm %q", p.Algorithm)
+	}
+	return nil
+}
+
+// fields returns the configured field names, falling back to "file" and
+// "hash"
+func (p *Policy) fields() (string, string) {
+	pathField, hashField := p.PathField, p.HashField
+	if pathField == "" {
+		pathField = "file"
+	}
+	if hashField == "" {
+		hashField = "hash"
+	}
+	return pathField, hashField
+}
+
+func (p *Policy) algorithm() string {
+	if p.Algorithm == "" {
+		return "sha256"
+	}
+	return strings.ToLower(p.Algorithm)
+}
+
+// resolve maps a user-supplied path onto the filesystem, rejecting anything
+// that would escape the configured root, including via symlinks
+func (p *Policy) resolve(path string) (string, error) {
+	if filepath.IsAbs(path) {
+		return "", fmt.Errorf("absolute paths are not allowed: %s", path)
+	}
+
+	root, err := filepath.EvalSymlinks(p.Root)
+	if err != nil {
+		return "", err
+	}
+	root, err = filepath.Abs(root)
+	if err != nil {
+		return "", err
+	}
+
+	// Check the cleaned path before touching the filesystem, so paths
+	// outside the root are rejected whether or not they exist
+	full := filepath.Join(root, path)
+	if !within(root, full) {
+		return "", fmt.Errorf("path escapes root directory: %s", path)
+	}
+
+	// A symlink inside the root may still point outside it
+	full, err = filepath.EvalSymlinks(full)
+	if err != nil {
+		return "", err
+	}
+	if !within(root, full) {
+		return "", fmt.Errorf("path escapes root directory: %s", path)
+	}
+
+	return full, nil
+}
+
+// within reports whether path is root or lies below it
+func within(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// checksum returns the hex digest of the referenced file
+func (p *Policy) checksum(path string) (string, error) {
+	full, err := p.resolve(path)
+	if err != nil {
+		return "", err
+	}
+
+	f, err := os.Open(full)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := newHash(p.algorithm())
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func newHash(algorithm string) hash.Hash {
+	switch algorithm {
+	case "md5":
+		return md5.New()
+	case "sha1":
+		return sha1.New()
+	case "sha256":
+		return sha256.New()
+	case "sha512":
+		return sha512.New()
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,169 @@
+package filehashpolicy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+const fixtureContent = "policy engine fixture\n"
+
+// fixture creates a root directory holding fixture.txt, next to a secret
+// file outside the root, and returns the root
+func fixture(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	root := filepath.Join(dir, "root")
+	if err := os.MkdirAll(filepath.Join(root, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "sub", "fixture.txt"), []byte(fixtureContent), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "secret.txt"), []byte("secret"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func fixtureHash() string {
+	sum := sha256.Sum256([]byte(fixtureContent))
+	return hex.EncodeToString(sum[:])
+}
+
+func execute(t *testing.T, p *Policy, input map[string]interface{}) *policyresult.Result {
+	t.Helper()
+	got, err := p.Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	return got.(*policyresult.Result)
+}
+
+func TestExecuteChecksum(t *testing.T) {
+	p := &Policy{Root: fixture(t)}
+
+	tests := []struct {
+		name       string
+		hash       string
+		wantStatus string
+		wantMsg    string
+	}{
+		{"matching", fixtureHash(), "PASSED", "Checksum matches"},
+		{"matching uppercase", strings.ToUpper(fixtureHash()), "PASSED", "Checksum matches"},
+		{"mismatching", strings.Repeat("0", 64), "FAILED", "Checksum mismatch"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := execute(t, p, map[string]interface{}{"file": "sub/fixture.txt", "hash": tt.hash})
+			if result.Status != tt.wantStatus || result.Message != tt.wantMsg {
+				t.Errorf("result = %s %q, want %s %q", result.Status, result.Message, tt.wantStatus, tt.wantMsg)
+			}
+			if result.Data["actual"] != fixtureHash() {
+				t.Errorf("actual = %v, want %s", result.Data["actual"], fixtureHash())
+			}
+		})
+	}
+}
+
+func TestExecuteAlgorithms(t *testing.T) {
+	root := fixture(t)
+	for _, algorithm := range []string{"md5", "sha1", "sha512"} {
+		t.Run(algorithm, func(t *testing.T) {
+			p := &Policy{Root: root, Algorithm: algorithm}
+			h := newHash(algorithm)
+			h.Write([]byte(fixtureContent))
+			digest := hex.EncodeToString(h.Sum(nil))
+
+			result := execute(t, p, map[string]interface{}{"file": "sub/fixture.txt", "hash": digest})
+			if result.Status != "PASSED" {
+				t.Errorf("Status = %q (%s), want PASSED", result.Status, result.Message)
+			}
+		})
+	}
+}
+
+func TestExecuteInaccessibleFiles(t *testing.T) {
+	root := fixture(t)
+	if err := os.Symlink(filepath.Join(root, "..", "secret.txt"), filepath.Join(root, "link.txt")); err != nil {
+		t.Fatal(err)
+	}
+	p := &Policy{Root: root}
+
+	// Every failure reads the same, so callers learn neither host paths
+	// nor whether files outside the root exist
+	paths := []string{
+		"missing.txt",
+		"../secret.txt",
+		"../missing.txt",
+		"sub/../../secret.txt",
+		"../../../../etc/passwd",
+		filepath.Join(root, "sub", "fixture.txt"),
+		"link.txt",
+		"sub",
+	}
+	for _, path := range paths {
+		t.Run(path, func(t *testing.T) {
+			result := execute(t, p, map[string]interface{}{"file": path, "hash": fixtureHash()})
+			if result.Status != "FAILED" || result.Message != "File not accessible" {
+				t.Errorf("result = %s %q, want FAILED %q", result.Status, result.Message, "File not accessible")
+			}
+			if _, ok := result.Data["actual"]; ok {
+				t.Errorf("actual digest reported for an inaccessible file")
+			}
+		})
+	}
+}
+
+func TestExecuteWithoutReference(t *testing.T) {
+	result := execute(t, &Policy{}, map[string]interface{}{"message": "hi"})
+	if result.Status != "PASSED" {
+		t.Errorf("Status = %q, want PASSED", result.Status)
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy *Policy
+		input  map[string]interface{}
+	}{
+		{"no root", &Policy{}, map[string]interface{}{"file": "a", "hash": "b"}},
+		{"path not a string", &Policy{Root: "."}, map[string]interface{}{"file": 1, "hash": "b"}},
+		{"missing hash", &Policy{Root: "."}, map[string]interface{}{"file": "a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.policy.Execute(context.Background(), tt.input); err == nil {
+				t.Error("Execute succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, algorithm := range []string{"", "md5", "sha1", "SHA256", "sha512"} {
+		if err := (&Policy{Algorithm: algorithm}).Validate(); err != nil {
+			t.Errorf("algorithm %q: %v", algorithm, err)
+		}
+	}
+	if err := (&Policy{Algorithm: "crc32"}).Validate(); err == nil {
+		t.Error("unsupported algorithm passed validation")
+	}
+}
@@ -0,0 +1,154 @@
+package foreignkeypolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/example/policies/policyresult"
+)
+
+// LookupFunc reports whether an ID exists in the external store
+type LookupFunc func(id string) (bool, error)
+
+// Policy implements the policy engine interface
+// It checks that IDs referenced by a configured field exist in an external
+// store. Missing references and failed lookups are reported separately so
+// bad data can be told apart from an unavailable store.
+type Policy struct {
+	// Field names the input field holding an ID or an array of IDs
+	Field string `json:"field"`
+
+	// Lookup queries the external store; it must be set before references
+	// can be checked
+	Lookup LookupFunc `json:"-"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "foreignkey-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	result := policyresult.New(p.Name(), "reference validation")
+
+	value, exists := inputMap[p.Field]
+	if p.Field == "" || !exists {
+		result.Status = "PASSED"
+		result.Message = "No references to check"
+		return result, nil
+	}
+	if p.Lookup == nil {
+		return nil, fmt.Errorf("no lookup function configured")
+	}
+
+	ids, err := toIDs(value)
+	if err != nil {
+		return nil, fmt.Errorf("field %q: %w", p.Field, err)
+	}
+
+	found := []string{}
+	missing := []string{}
+	lookupErrors := []map[string]interface{}{}
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		ok, err := p.Lookup(id)
+		switch {
+		case err != nil:
+			lookupErrors = append(lookupErrors, map[string]interface{}{
+				"id":    id,
+				"error": err.Error(),
+			})
+		case ok:
+			found = append(found, id)
+		default:
+			missing = append(missing, id)
+		}
+	}
+
+	result.Data["found"] = found
+	result.Data["missing"] = missing
+	result.Data["lookup_errors"] = lookupErrors
+
+	switch {
+	case len(lookupErrors) > 0:
+		result.Status = "ERROR"
+		result.Message = fmt.Sprintf("%d lookup(s) failed", len(lookupErrors))
+	case len(missing) > 0:
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("Unknown references: %v", missing)
+	default:
+		result.Status = "PASSED"
+		result.Message = "All references exist"
+	}
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	return nil
+}
+
+// toIDs accepts a single ID or an array of IDs. Numeric IDs are formatted
+// without a fractional part when they are whole numbers.
+func toIDs(value interface{}) ([]string, error) {
+	switch v := value.(type) {
+	case []string:
+		return v, nil
+	case []interface{}:
+		ids := make([]string, 0, len(v))
+		for i, item := range v {
+			id, err := toID(item)
+			if err != nil {
+				return nil, fmt.Errorf("element %d: %w", i, err)
+			}
+			ids = append(ids, id)
+		}
+		return ids, nil
+	default:
+		id, err := toID(v)
+		if err != nil {
+			return nil, err
+		}
+		return []string{id}, nil
+	}
+}
+
+func toID(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case float64, int, int64, json.Number:
+		return fmt.Sprint(v), nil
+	default:
+		return "", fmt.Errorf("expected string or number ID, got %T", value)
+	}
+}
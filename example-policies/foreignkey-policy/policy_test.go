@@ -0,0 +1,195 @@
+package foreignkeypolicy
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+// store is a stub external store: known IDs exist, IDs in failing return
+// an error
+func store(known []string, failing ...string) LookupFunc {
+	return func(id string) (bool, error) {
+		for _, f := range failing {
+			if id == f {
+				return false, errors.New("connection refused")
+			}
+		}
+		for _, k := range known {
+			if id == k {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+}
+
+func TestExecute(t *testing.T) {
+	lookup := store([]string{"u1", "u2", "42"}, "u9")
+
+	tests := []struct {
+		name        string
+		value       interface{}
+		wantStatus  string
+		wantFound   []string
+		wantMissing []string
+		wantErrored []string
+	}{
+		{
+			name:       "existing id",
+			value:      "u1",
+			wantStatus: "PASSED",
+			wantFound:  []string{"u1"},
+		},
+		{
+			name:       "existing numeric id",
+			value:      float64(42),
+			wantStatus: "PASSED",
+			wantFound:  []string{"42"},
+		},
+		{
+			name:        "missing id",
+			value:       "u3",
+			wantStatus:  "FAILED",
+			wantMissing: []string{"u3"},
+		},
+		{
+			name:        "array of ids",
+			value:       []interface{}{"u1", "u3", "u2", 7},
+			wantStatus:  "FAILED",
+			wantFound:   []string{"u1", "u2"},
+			wantMissing: []string{"u3", "7"},
+		},
+		{
+			name:        "lookup failure reported apart from missing ids",
+			value:       []string{"u9", "u3", "u1"},
+			wantStatus:  "ERROR",
+			wantFound:   []string{"u1"},
+			wantMissing: []string{"u3"},
+			wantErrored: []string{"u9"},
+		},
+		{
+			name:       "empty array",
+			value:      []interface{}{},
+			wantStatus: "PASSED",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Policy{Field: "user_id", Lookup: lookup}
+			got, err := p.Execute(context.Background(), map[string]interface{}{"user_id": tt.value})
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if result.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s (%s)", result.Status, tt.wantStatus, result.Message)
+			}
+			if got := result.Data["found"].([]string); !equalIDs(got, tt.wantFound) {
+				t.Errorf("found = %v, want %v", got, tt.wantFound)
+			}
+			if got := result.Data["missing"].([]string); !equalIDs(got, tt.wantMissing) {
+				t.Errorf("missing = %v, want %v", got, tt.wantMissing)
+			}
+
+			var errored []string
+			for _, lookupErr := range result.Data["lookup_errors"].([]map[string]interface{}) {
+				errored = append(errored, lookupErr["id"].(string))
+				if lookupErr["error"] != "connection refused" {
+					t.Errorf("lookup error = %v, want connection refused", lookupErr["error"])
+				}
+			}
+			if !equalIDs(errored, tt.wantErrored) {
+				t.Errorf("lookup errors for %v, want %v", errored, tt.wantErrored)
+			}
+		})
+	}
+}
+
+// equalIDs compares ID lists, treating nil and empty as equal
+func equalIDs(got, want []string) bool {
+	if len(got) == 0 && len(want) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(got, want)
+}
+
+func TestExecuteNoReferences(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy *Policy
+	}{
+		{"no field configured", &Policy{}},
+		{"field absent", &Policy{Field: "user_id"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.policy.Execute(context.Background(), map[string]interface{}{"other": "u1"})
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			if result := got.(*policyresult.Result); result.Status != "PASSED" {
+				t.Errorf("status = %s, want PASSED", result.Status)
+			}
+		})
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy *Policy
+		input  interface{}
+	}{
+		{"no lookup", &Policy{Field: "id"}, map[string]interface{}{"id": "u1"}},
+		{"invalid id type", &Policy{Field: "id", Lookup: store(nil)}, map[string]interface{}{"id": true}},
+		{"invalid element type", &Policy{Field: "id", Lookup: store(nil)}, map[string]interface{}{"id": []interface{}{"u1", nil}}},
+		{"not a map", &Policy{Field: "id", Lookup: store(nil)}, "u1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.policy.Execute(context.Background(), tt.input); err == nil {
+				t.Error("Execute succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestExecuteCancelledDuringLookups(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	calls := 0
+	p := &Policy{Field: "ids", Lookup: func(string) (bool, error) {
+		calls++
+		cancel()
+		return true, nil
+	}}
+	_, err := p.Execute(ctx, map[string]interface{}{"ids": []string{"a", "b", "c"}})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+	if calls != 1 {
+		t.Errorf("lookups = %d after cancellation, want 1", calls)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Policy{}).Validate(); err != nil {
+		t.Errorf("zero value: %v", err)
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/example/policies/format-convert-policy
+
+go 1.21
+
+require (
+	github.com/example/policies/policyresult v0.0.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+replace github.com/example/policies/policyresult => ../policyresult
@@ -0,0 +1,171 @@
+package formatconvertpolicy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Policy implements the policy engine interface
+// It converts a document between JSON and YAML. The input holds the
+// document as a string under "payload" and the target format, "json" or
+// "yaml", under "format". The source format is detected: a payload that
+// parses as JSON is JSON, anything else must be YAML. Converting a
+// document to its own format reformats it.
+type Policy struct {
+	// Indent is the number of spaces used to indent the converted
+	// document (default 2)
+	Indent int `json:"indent"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "format-convert-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	target, ok := inputMap["format"].(string)
+	if !ok || (target != "json" && target != "yaml") {
+		return nil, fmt.Errorf(`field "format": expected "json" or "yaml", got %v`, inputMap["format"])
+	}
+	payload, ok := inputMap["payload"].(string)
+	if !ok {
+		return nil, fmt.Errorf(`field "payload": expected string, got %T`, inputMap["payload"])
+	}
+
+	source, document, err := parse(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var converted string
+	if target == "json" {
+		converted, err = p.toJSON(document)
+	} else {
+		converted, err = p.toYAML(document)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &policyresult.Result{
+		Policy:  p.Name(),
+		Action:  "format conversion",
+		Status:  "PASSED",
+		Message: fmt.Sprintf("Converted %s to %s", source, target),
+		Data: map[string]interface{}{
+			"source_format": source,
+			"target_format": target,
+			"converted":     converted,
+		},
+	}, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	if p.Indent < 0 || p.Indent > 8 {
+		return fmt.Errorf("indent must be between 0 and 8, got %d", p.Indent)
+	}
+	return nil
+}
+
+func (p *Policy) indent() int {
+	if p.Indent == 0 {
+		return 2
+	}
+	return p.Indent
+}
+
+// parse detects the payload's format and decodes it
+func parse(payload string) (string, interface{}, error) {
+	if json.Valid([]byte(payload)) {
+		var document interface{}
+		if err := json.Unmarshal([]byte(payload), &document); err != nil {
+			return "", nil, fmt.Errorf("payload is not valid JSON: %w", err)
+		}
+		return "json", document, nil
+	}
+
+	var document interface{}
+	if err := yaml.Unmarshal([]byte(payload), &document); err != nil {
+		return "", nil, fmt.Errorf("payload is neither valid JSON nor valid YAML: %w", err)
+	}
+	return "yaml", document, nil
+}
+
+func (p *Policy) toJSON(document interface{}) (string, error) {
+	data, err := json.MarshalIndent(jsonCompatible(document), "", fmt.Sprintf("%*s", p.indent(), ""))
+	if err != nil {
+		return "", fmt.Errorf("cannot convert to JSON: %w", err)
+	}
+	return string(data), nil
+}
+
+func (p *Policy) toYAML(document interface{}) (string, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(p.indent())
+	if err := encoder.Encode(document); err != nil {
+		return "", fmt.Errorf("cannot convert to YAML: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return "", fmt.Errorf("cannot convert to YAML: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// jsonCompatible rewrites YAML mappings with non-string keys, which
+// encoding/json cannot serialize, into objects with string keys
+func jsonCompatible(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			out[key] = jsonCompatible(item)
+		}
+		return out
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			out[fmt.Sprint(key)] = jsonCompatible(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = jsonCompatible(item)
+		}
+		return out
+	default:
+		return value
+	}
+}
@@ -0,0 +1,147 @@
+package formatconvertpolicy
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+func convert(t *testing.T, p *Policy, format, payload string) *policyresult.Result {
+	t.Helper()
+	got, err := p.Execute(context.Background(), map[string]interface{}{"format": format, "payload": payload})
+	if err != nil {
+		t.Fatalf("Execute(%s): %v", format, err)
+	}
+	return got.(*policyresult.Result)
+}
+
+func TestExecuteConversions(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     *Policy
+		format     string
+		payload    string
+		wantSource string
+		want       string
+	}{
+		{
+			name:       "json to yaml",
+			policy:     &Policy{},
+			format:     "yaml",
+			payload:    `{"name": "ada", "tags": ["a", "b"], "age": 36}`,
+			wantSource: "json",
+			want:       "age: 36\nname: ada\ntags:\n  - a\n  - b\n",
+		},
+		{
+			name:       "yaml to json",
+			policy:     &Policy{},
+			format:     "json",
+			payload:    "name: ada\ntags:\n  - a\n  - b\nage: 36\n",
+			wantSource: "yaml",
+			want:       "{\n  \"age\": 36,\n  \"name\": \"ada\",\n  \"tags\": [\n    \"a\",\n    \"b\"\n  ]\n}",
+		},
+		{
+			name:       "yaml with non-string keys",
+			policy:     &Policy{},
+			format:     "json",
+			payload:    "codes:\n  200: ok\n  true: yes\n",
+			wantSource: "yaml",
+			want:       "{\n  \"codes\": {\n    \"200\": \"ok\",\n    \"true\": \"yes\"\n  }\n}",
+		},
+		{
+			name:       "json reformatted",
+			policy:     &Policy{Indent: 4},
+			format:     "json",
+			payload:    `{"a":{"b":1}}`,
+			wantSource: "json",
+			want:       "{\n    \"a\": {\n        \"b\": 1\n    }\n}",
+		},
+		{
+			name:       "yaml indent",
+			policy:     &Policy{Indent: 4},
+			format:     "yaml",
+			payload:    `{"a":{"b":1}}`,
+			wantSource: "json",
+			want:       "a:\n    b: 1\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := convert(t, tt.policy, tt.format, tt.payload)
+			if result.Status != "PASSED" || result.Data["source_format"] != tt.wantSource || result.Data["target_format"] != tt.format {
+				t.Errorf("status = %s, source = %v, target = %v; want PASSED, %s, %s", result.Status, result.Data["source_format"], result.Data["target_format"], tt.wantSource, tt.format)
+			}
+			if result.Data["converted"] != tt.want {
+				t.Errorf("converted =\n%s\nwant\n%s", result.Data["converted"], tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteRoundTrip(t *testing.T) {
+	p := &Policy{}
+	original := "{\n  \"list\": [\n    1,\n    {\n      \"x\": null\n    }\n  ],\n  \"ok\": true\n}"
+	yamlDoc := convert(t, p, "yaml", original).Data["converted"].(string)
+	if back := convert(t, p, "json", yamlDoc).Data["converted"]; back != original {
+		t.Errorf("round trip =\n%s\nwant\n%s", back, original)
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   interface{}
+		wantErr string
+	}{
+		{"malformed yaml", map[string]interface{}{"format": "json", "payload": "a: [1, 2"}, "payload is neither valid JSON nor valid YAML"},
+		{"tab indented yaml", map[string]interface{}{"format": "json", "payload": "a:\n\tb: 1"}, "payload is neither valid JSON nor valid YAML"},
+		{"unknown format", map[string]interface{}{"format": "toml", "payload": "{}"}, `field "format": expected "json" or "yaml", got toml`},
+		{"missing format", map[string]interface{}{"payload": "{}"}, `field "format"`},
+		{"payload not a string", map[string]interface{}{"format": "json", "payload": 1}, `field "payload": expected string, got int`},
+		{"non-object input", "text", "expected map[string]interface{}, got string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := (&Policy{}).Execute(context.Background(), tt.input)
+			if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr bool
+	}{
+		{"zero value", map[string]interface{}{}, false},
+		{"indent", map[string]interface{}{"indent": 4}, false},
+		{"maximum indent", map[string]interface{}{"indent": 8}, false},
+		{"negative indent", map[string]interface{}{"indent": -1}, true},
+		{"indent too large", map[string]interface{}{"indent": 9}, true},
+		{"indent not a number", map[string]interface{}{"indent": "two"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := (&Policy{}).Configure(tt.config); (err != nil) != tt.wantErr {
+				t.Errorf("Configure(%v) = %v, wantErr %v", tt.config, err, tt.wantErr)
+			}
+		})
+	}
+}
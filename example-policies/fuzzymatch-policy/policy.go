@@ -0,0 +1,159 @@
+package fuzzymatchpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Policy implements the policy engine interface
+// It finds the closest entry in a reference set for a configured field by
+// Levenshtein distance, which helps correct typos in categorical inputs
+type Policy struct {
+	// Field names the input field to match
+	Field string `json:"field"`
+
+	// References is the set of known-good values
+	References []string `json:"references"`
+
+	// Threshold is the minimum similarity (0-1) for a match (default 0.8)
+	Threshold float64 `json:"threshold"`
+
+	// CaseSensitive disables case folding before comparison
+	CaseSensitive bool `json:"case_sensitive"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "fuzzymatch-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	result := policyresult.New(p.Name(), "fuzzy matching")
+	result.Data["threshold"] = p.threshold()
+
+	value, exists := inputMap[p.Field]
+	if p.Field == "" || !exists {
+		result.Status = "PASSED"
+		result.Message = "No value to match"
+		return result, nil
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("field %q: expected string, got %T", p.Field, value)
+	}
+
+	best, similarity := "", 0.0
+	for _, ref := range p.References {
+		if sim := p.similarity(s, ref); sim > similarity {
+			best, similarity = ref, sim
+		}
+	}
+
+	result.Data["value"] = s
+	result.Data["similarity"] = similarity
+
+	if best == "" || similarity < p.threshold() {
+		result.Status = "FAILED"
+		result.Data["match"] = nil
+		result.Message = fmt.Sprintf("No reference value within threshold for %q", s)
+		return result, nil
+	}
+
+	result.Status = "PASSED"
+	result.Data["match"] = best
+	result.Data["exact"] = similarity == 1
+	result.Message = fmt.Sprintf("Matched %q to %q", s, best)
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	if t := p.threshold(); t < 0 || t > 1 {
+		return fmt.Errorf("threshold must be between 0 and 1, got %v", t)
+	}
+	if p.Field != "" && len(p.References) == 0 {
+		return fmt.Errorf("references must not be empty when field is configured")
+	}
+	return nil
+}
+
+func (p *Policy) threshold() float64 {
+	if p.Threshold == 0 {
+		return 0.8
+	}
+	return p.Threshold
+}
+
+// similarity returns 1 minus the edit distance normalized by the longer
+// string's length, so identical strings score 1
+func (p *Policy) similarity(a, b string) float64 {
+	if !p.CaseSensitive {
+		a, b = strings.ToLower(a), strings.ToLower(b)
+	}
+
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	if longest == 0 {
+		return 1
+	}
+
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// levenshtein computes the edit distance between two rune slices using a
+// single rolling row
+func levenshtein(a, b []rune) int {
+	row := make([]int, len(b)+1)
+	for j := range row {
+		row[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		prev := row[0]
+		row[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current := row[j]
+			row[j] = min(row[j]+1, row[j-1]+1, prev+cost)
+			prev = current
+		}
+	}
+
+	return row[len(b)]
+}
@@ -0,0 +1,172 @@
+package fuzzymatchpolicy
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+var categories = []string{"electronics", "clothing", "groceries", "furniture"}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name           string
+		policy         Policy
+		value          string
+		wantMatch      interface{}
+		wantExact      bool
+		wantSimilarity float64
+	}{
+		{
+			name:           "exact",
+			value:          "clothing",
+			wantMatch:      "clothing",
+			wantExact:      true,
+			wantSimilarity: 1,
+		},
+		{
+			name:           "case folded",
+			value:          "Electronics",
+			wantMatch:      "electronics",
+			wantExact:      true,
+			wantSimilarity: 1,
+		},
+		{
+			name:           "transposed letters",
+			value:          "electornics",
+			wantMatch:      "electronics",
+			wantSimilarity: 1 - 2.0/11,
+		},
+		{
+			name:           "missing letter",
+			value:          "furnture",
+			wantMatch:      "furniture",
+			wantSimilarity: 1 - 1.0/9,
+		},
+		{
+			name:           "below default threshold",
+			value:          "grocery",
+			wantMatch:      nil,
+			wantSimilarity: 1 - 3.0/9,
+		},
+		{
+			name:           "lower threshold",
+			policy:         Policy{Threshold: 0.6},
+			value:          "grocery",
+			wantMatch:      "groceries",
+			wantSimilarity: 1 - 3.0/9,
+		},
+		{
+			name:      "case sensitive",
+			policy:    Policy{CaseSensitive: true},
+			value:     "CLOTHING",
+			wantMatch: nil,
+		},
+		{
+			name:      "clear non-match",
+			value:     "automotive",
+			wantMatch: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.policy
+			p.Field = "category"
+			p.References = categories
+			got, err := p.Execute(context.Background(), map[string]interface{}{"category": tt.value})
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+
+			if result.Data["match"] != tt.wantMatch {
+				t.Errorf("match = %v, want %v", result.Data["match"], tt.wantMatch)
+			}
+			if tt.wantMatch == nil {
+				if result.Status != "FAILED" {
+					t.Errorf("status = %s, want FAILED", result.Status)
+				}
+			} else {
+				if result.Status != "PASSED" {
+					t.Errorf("status = %s, want PASSED", result.Status)
+				}
+				if result.Data["exact"] != tt.wantExact {
+					t.Errorf("exact = %v, want %v", result.Data["exact"], tt.wantExact)
+				}
+			}
+			if similarity := result.Data["similarity"].(float64); tt.wantSimilarity != 0 && math.Abs(similarity-tt.wantSimilarity) > 1e-9 {
+				t.Errorf("similarity = %v, want %v", result.Data["similarity"], tt.wantSimilarity)
+			}
+		})
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"flaw", "lawn", 2},
+		{"café", "cafe", 1},
+		{"same", "same", 0},
+	}
+
+	for _, tt := range tests {
+		if got := levenshtein([]rune(tt.a), []rune(tt.b)); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestExecuteMissingAndInvalid(t *testing.T) {
+	p := &Policy{Field: "category", References: categories}
+	got, err := p.Execute(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result := got.(*policyresult.Result); result.Status != "PASSED" {
+		t.Errorf("missing field status = %s, want PASSED", result.Status)
+	}
+
+	if _, err := p.Execute(context.Background(), map[string]interface{}{"category": 1}); err == nil {
+		t.Error("non-string value succeeded, want error")
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  Policy
+		wantErr bool
+	}{
+		{"zero value", Policy{}, false},
+		{"configured", Policy{Field: "f", References: categories, Threshold: 1}, false},
+		{"threshold above one", Policy{Threshold: 1.5}, true},
+		{"negative threshold", Policy{Threshold: -0.1}, true},
+		{"field without references", Policy{Field: "f"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policy.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package genidpolicy
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Policy implements the policy engine interface
+// It fills configured ID fields with freshly generated UUIDs when they are
+// absent or empty, leaving existing IDs untouched
+type Policy struct {
+	// Fields lists the ID fields to fill
+	Fields []string `json:"fields"`
+
+	// NewID generates an ID; it defaults to random (version 4) UUIDs
+	NewID func() (string, error) `json:"-"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "genid-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	result := policyresult.New(p.Name(), "id generation")
+
+	output := make(map[string]interface{}, len(inputMap))
+	for key, value := range inputMap {
+		output[key] = value
+	}
+
+	newID := p.NewID
+	if newID == nil {
+		newID = newUUID
+	}
+
+	generated := make(map[string]string)
+	for _, field := range p.Fields {
+		if value, exists := output[field]; exists && value != nil && value != "" {
+			continue
+		}
+
+		id, err := newID()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate id for %q: %w", field, err)
+		}
+		output[field] = id
+		generated[field] = id
+	}
+
+	result.Data["input"] = inputMap
+	result.Output = output
+	result.Data["generated"] = generated
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	return nil
+}
+
+// newUUID returns a random RFC 4122 version 4 UUID
+func newUUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
@@ -0,0 +1,161 @@
+package genidpolicy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+// sequence returns an ID source yielding id-1, id-2, ...
+func sequence() func() (string, error) {
+	n := 0
+	return func() (string, error) {
+		n++
+		return fmt.Sprintf("id-%d", n), nil
+	}
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name          string
+		fields        []string
+		input         map[string]interface{}
+		wantOutput    map[string]interface{}
+		wantGenerated map[string]string
+	}{
+		{
+			name:          "present id kept",
+			fields:        []string{"id"},
+			input:         map[string]interface{}{"id": "existing", "name": "a"},
+			wantOutput:    map[string]interface{}{"id": "existing", "name": "a"},
+			wantGenerated: map[string]string{},
+		},
+		{
+			name:          "absent id generated",
+			fields:        []string{"id"},
+			input:         map[string]interface{}{"name": "a"},
+			wantOutput:    map[string]interface{}{"id": "id-1", "name": "a"},
+			wantGenerated: map[string]string{"id": "id-1"},
+		},
+		{
+			name:          "empty id generated",
+			fields:        []string{"id"},
+			input:         map[string]interface{}{"id": ""},
+			wantOutput:    map[string]interface{}{"id": "id-1"},
+			wantGenerated: map[string]string{"id": "id-1"},
+		},
+		{
+			name:          "null id generated",
+			fields:        []string{"id"},
+			input:         map[string]interface{}{"id": nil},
+			wantOutput:    map[string]interface{}{"id": "id-1"},
+			wantGenerated: map[string]string{"id": "id-1"},
+		},
+		{
+			name:   "several fields",
+			fields: []string{"id", "trace_id", "request_id"},
+			input:  map[string]interface{}{"id": 7, "request_id": ""},
+			wantOutput: map[string]interface{}{
+				"id": 7, "trace_id": "id-1", "request_id": "id-2",
+			},
+			wantGenerated: map[string]string{"trace_id": "id-1", "request_id": "id-2"},
+		},
+		{
+			name:          "no fields configured",
+			input:         map[string]interface{}{"name": "a"},
+			wantOutput:    map[string]interface{}{"name": "a"},
+			wantGenerated: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Policy{Fields: tt.fields, NewID: sequence()}
+			got, err := p.Execute(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if !reflect.DeepEqual(result.Output, tt.wantOutput) {
+				t.Errorf("output = %v, want %v", result.Output, tt.wantOutput)
+			}
+			if !reflect.DeepEqual(result.Data["generated"], tt.wantGenerated) {
+				t.Errorf("generated = %v, want %v", result.Data["generated"], tt.wantGenerated)
+			}
+		})
+	}
+}
+
+func TestExecuteLeavesInputUntouched(t *testing.T) {
+	input := map[string]interface{}{"name": "a"}
+	p := &Policy{Fields: []string{"id"}, NewID: sequence()}
+	if _, err := p.Execute(context.Background(), input); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if _, ok := input["id"]; ok {
+		t.Error("Execute added the id to the caller's input")
+	}
+}
+
+func TestDefaultUUID(t *testing.T) {
+	uuidV4 := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	p := &Policy{Fields: []string{"a", "b"}}
+	got, err := p.Execute(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	generated := got.(*policyresult.Result).Data["generated"].(map[string]string)
+	for field, id := range generated {
+		if !uuidV4.MatchString(id) {
+			t.Errorf("%s = %q, not a version 4 UUID", field, id)
+		}
+	}
+	if generated["a"] == generated["b"] {
+		t.Errorf("duplicate UUIDs: %q", generated["a"])
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	sourceErr := errors.New("entropy exhausted")
+	p := &Policy{Fields: []string{"id"}, NewID: func() (string, error) { return "", sourceErr }}
+	if _, err := p.Execute(context.Background(), map[string]interface{}{}); !errors.Is(err, sourceErr) {
+		t.Errorf("Execute error = %v, want %v", err, sourceErr)
+	}
+
+	if _, err := (&Policy{}).Execute(context.Background(), "not a map"); err == nil {
+		t.Error("Execute accepted a non-map input")
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	p := &Policy{}
+	if err := p.Configure(map[string]interface{}{"fields": []interface{}{"id", "trace_id"}}); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+	if want := []string{"id", "trace_id"}; !reflect.DeepEqual(p.Fields, want) {
+		t.Errorf("fields = %v, want %v", p.Fields, want)
+	}
+	if err := p.Configure(map[string]interface{}{"fields": "id"}); err == nil {
+		t.Error("Configure accepted a non-list fields value")
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Policy{}).Validate(); err != nil {
+		t.Errorf("zero value: %v", err)
+	}
+}
@@ -0,0 +1,161 @@
+package grammarpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Token is one element of the grammar
+type Token struct {
+	// Name labels the token in the parse and in syntax errors
+	Name string `json:"name"`
+
+	// Pattern is the regular expression the token must match
+	Pattern string `json:"pattern"`
+
+	// Optional lets the token be absent
+	Optional bool `json:"optional"`
+
+	re *regexp.Regexp
+}
+
+// Policy implements the policy engine interface
+// It parses a string field against a grammar made of a sequence of named
+// regular-expression tokens, as used for structured identifiers such as
+// SKUs. Each token must match at the position where the previous one ended
+// and the whole string must be consumed. The result holds the tokenized
+// parse or a syntax error giving the position and the expected token.
+type Policy struct {
+	// Field names the input field to parse (default "value")
+	Field string `json:"field"`
+
+	// Grammar lists the tokens in order
+	Grammar []Token `json:"grammar"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "grammar-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	result := policyresult.New(p.Name(), "grammar validation")
+
+	raw, exists := inputMap[p.field()]
+	if len(p.Grammar) == 0 || !exists {
+		result.Status = "PASSED"
+		result.Message = "Nothing to parse"
+		return result, nil
+	}
+	value, ok := raw.(string)
+	if !ok {
+		return nil, fmt.Errorf("field %q: expected string, got %T", p.field(), raw)
+	}
+
+	tokens, syntaxErr := p.parse(value)
+	result.Data["tokens"] = tokens
+
+	if syntaxErr != nil {
+		result.Status = "FAILED"
+		result.Data["error"] = syntaxErr
+		result.Message = fmt.Sprintf("Syntax error at position %d: %s", syntaxErr["position"], syntaxErr["reason"])
+	} else {
+		result.Status = "PASSED"
+		result.Message = fmt.Sprintf("Parsed %d token(s)", len(tokens))
+	}
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid and compiles the
+// token patterns
+func (p *Policy) Validate() error {
+	for i := range p.Grammar {
+		token := &p.Grammar[i]
+		if token.Name == "" {
+			return fmt.Errorf("grammar token %d: name is required", i)
+		}
+		if token.Pattern == "" {
+			return fmt.Errorf("grammar token %s: pattern is required", token.Name)
+		}
+		// Anchor at the start so the token only matches at the current position
+		re, err := regexp.Compile(`^(?:` + token.Pattern + `)`)
+		if err != nil {
+			return fmt.Errorf("grammar token %s: invalid pattern: %w", token.Name, err)
+		}
+		token.re = re
+	}
+	return nil
+}
+
+func (p *Policy) field() string {
+	if p.Field == "" {
+		return "value"
+	}
+	return p.Field
+}
+
+// parse matches the grammar tokens in order, returning the tokens matched
+// so far and, on failure, a syntax error description. Positions are byte
+// offsets into value.
+func (p *Policy) parse(value string) ([]map[string]interface{}, map[string]interface{}) {
+	tokens := []map[string]interface{}{}
+	position := 0
+
+	for _, token := range p.Grammar {
+		match := token.re.FindString(value[position:])
+		if match == "" {
+			if token.Optional {
+				continue
+			}
+			return tokens, map[string]interface{}{
+				"position": position,
+				"expected": token.Name,
+				"reason":   fmt.Sprintf("expected %s", token.Name),
+			}
+		}
+
+		tokens = append(tokens, map[string]interface{}{
+			"name":     token.Name,
+			"value":    match,
+			"position": position,
+		})
+		position += len(match)
+	}
+
+	if position < len(value) {
+		return tokens, map[string]interface{}{
+			"position": position,
+			"reason":   fmt.Sprintf("unexpected trailing input %q", value[position:]),
+		}
+	}
+	return tokens, nil
+}
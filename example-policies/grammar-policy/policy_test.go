@@ -0,0 +1,166 @@
+package grammarpolicy
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+// newSKUPolicy parses SKUs such as "ELEC-00042-XL": a category, a number
+// and an optional size
+func newSKUPolicy(t *testing.T) *Policy {
+	t.Helper()
+	p := &Policy{}
+	err := p.Configure(map[string]interface{}{
+		"field": "sku",
+		"grammar": []interface{}{
+			map[string]interface{}{"name": "category", "pattern": "[A-Z]{4}"},
+			map[string]interface{}{"name": "separator", "pattern": "-"},
+			map[string]interface{}{"name": "number", "pattern": `\d{5}`},
+			map[string]interface{}{"name": "size", "pattern": "-(S|M|L|XL)", "optional": true},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name       string
+		sku        string
+		wantTokens []string
+		wantError  map[string]interface{}
+	}{
+		{
+			name:       "all tokens",
+			sku:        "ELEC-00042-XL",
+			wantTokens: []string{"ELEC", "-", "00042", "-XL"},
+		},
+		{
+			name:       "optional token absent",
+			sku:        "ELEC-00042",
+			wantTokens: []string{"ELEC", "-", "00042"},
+		},
+		{
+			name:       "bad category",
+			sku:        "el-00042",
+			wantTokens: []string{},
+			wantError:  map[string]interface{}{"position": 0, "expected": "category", "reason": "expected category"},
+		},
+		{
+			name:       "short number",
+			sku:        "ELEC-042",
+			wantTokens: []string{"ELEC", "-"},
+			wantError:  map[string]interface{}{"position": 5, "expected": "number", "reason": "expected number"},
+		},
+		{
+			name:       "trailing input",
+			sku:        "ELEC-00042-XXL",
+			wantTokens: []string{"ELEC", "-", "00042"},
+			wantError:  map[string]interface{}{"position": 10, "reason": `unexpected trailing input "-XXL"`},
+		},
+		{
+			name:       "empty",
+			sku:        "",
+			wantTokens: []string{},
+			wantError:  map[string]interface{}{"position": 0, "expected": "category", "reason": "expected category"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newSKUPolicy(t).Execute(context.Background(), map[string]interface{}{"sku": tt.sku})
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+
+			tokens := []string{}
+			for _, token := range result.Data["tokens"].([]map[string]interface{}) {
+				tokens = append(tokens, token["value"].(string))
+			}
+			if !reflect.DeepEqual(tokens, tt.wantTokens) {
+				t.Errorf("tokens = %q, want %q", tokens, tt.wantTokens)
+			}
+
+			if tt.wantError == nil {
+				if result.Status != "PASSED" || result.Data["error"] != nil {
+					t.Errorf("status = %s, error = %v; want PASSED", result.Status, result.Data["error"])
+				}
+				return
+			}
+			if result.Status != "FAILED" {
+				t.Errorf("status = %s, want FAILED", result.Status)
+			}
+			if !reflect.DeepEqual(result.Data["error"], tt.wantError) {
+				t.Errorf("error = %v, want %v", result.Data["error"], tt.wantError)
+			}
+		})
+	}
+}
+
+func TestExecuteTokenPositions(t *testing.T) {
+	got, err := newSKUPolicy(t).Execute(context.Background(), map[string]interface{}{"sku": "ELEC-00042-S"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var positions []int
+	for _, token := range got.(*policyresult.Result).Data["tokens"].([]map[string]interface{}) {
+		positions = append(positions, token["position"].(int))
+	}
+	if want := []int{0, 4, 5, 10}; !reflect.DeepEqual(positions, want) {
+		t.Errorf("positions = %v, want %v", positions, want)
+	}
+}
+
+func TestExecuteSkipsAndErrors(t *testing.T) {
+	p := newSKUPolicy(t)
+	got, err := p.Execute(context.Background(), map[string]interface{}{"other": "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := got.(*policyresult.Result).Status; status != "PASSED" {
+		t.Errorf("missing field status = %s, want PASSED", status)
+	}
+	if _, err := p.Execute(context.Background(), map[string]interface{}{"sku": 42}); err == nil {
+		t.Error("non-string field succeeded, want error")
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	token := func(name, pattern string) map[string]interface{} {
+		return map[string]interface{}{"name": name, "pattern": pattern}
+	}
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr bool
+	}{
+		{"zero value", map[string]interface{}{}, false},
+		{"grammar", map[string]interface{}{"grammar": []interface{}{token("id", `\d+`)}}, false},
+		{"unnamed token", map[string]interface{}{"grammar": []interface{}{token("", `\d+`)}}, true},
+		{"empty pattern", map[string]interface{}{"grammar": []interface{}{token("id", "")}}, true},
+		{"invalid pattern", map[string]interface{}{"grammar": []interface{}{token("id", "(")}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := (&Policy{}).Configure(tt.config); (err != nil) != tt.wantErr {
+				t.Errorf("Configure(%v) = %v, wantErr %v", tt.config, err, tt.wantErr)
+			}
+		})
+	}
+}
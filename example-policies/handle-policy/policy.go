@@ -0,0 +1,191 @@
+package handlepolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/example/policies/policyresult"
+)
+
+// PlatformRule describes what a valid username looks like on one platform
+type PlatformRule struct {
+	// MinLength and MaxLength bound the handle length in characters,
+	// excluding any leading '@'; zero means no bound
+	MinLength int `json:"min_length"`
+	MaxLength int `json:"max_length"`
+
+	// Pattern is a regular expression every character must match, e.g.
+	// "[A-Za-z0-9_]" (default allows letters, digits, '_' and '.')
+	Pattern string `json:"pattern"`
+
+	// PreserveCase keeps the handle's casing instead of lowercasing it
+	PreserveCase bool `json:"preserve_case"`
+
+	chars *regexp.Regexp
+}
+
+// Policy implements the policy engine interface
+// It validates social media handles against per-platform rules and
+// normalizes valid ones by stripping a leading '@' and lowercasing them
+type Policy struct {
+	// Platforms maps platform names to their rules
+	Platforms map[string]*PlatformRule `json:"platforms"`
+
+	// Fields maps input fields to the platform whose rules apply
+	Fields map[string]string `json:"fields"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "handle-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	output := make(map[string]interface{}, len(inputMap))
+	for key, value := range inputMap {
+		output[key] = value
+	}
+
+	fields := make([]string, 0, len(p.Fields))
+	for field := range p.Fields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	invalid := []map[string]interface{}{}
+	for _, field := range fields {
+		value, exists := inputMap[field]
+		if !exists {
+			continue
+		}
+		platform := p.Fields[field]
+
+		handle, ok := value.(string)
+		if !ok {
+			invalid = append(invalid, map[string]interface{}{
+				"field":    field,
+				"platform": platform,
+				"reason":   fmt.Sprintf("expected string, got %T", value),
+			})
+			continue
+		}
+
+		normalized, err := p.Platforms[platform].normalize(handle)
+		if err != nil {
+			invalid = append(invalid, map[string]interface{}{
+				"field":    field,
+				"platform": platform,
+				"value":    handle,
+				"reason":   err.Error(),
+			})
+			continue
+		}
+		output[field] = normalized
+	}
+
+	result := policyresult.New(p.Name(), "handle validation")
+	result.Data["invalid"] = invalid
+	result.Output = output
+
+	if len(invalid) > 0 {
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("%d invalid handle(s)", len(invalid))
+	} else {
+		result.Status = "PASSED"
+		result.Message = "All handles valid"
+	}
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid and compiles the
+// platform patterns
+func (p *Policy) Validate() error {
+	for name, rule := range p.Platforms {
+		if rule == nil {
+			return fmt.Errorf("platform %s has no rules", name)
+		}
+		if rule.MinLength < 0 || rule.MaxLength < 0 {
+			return fmt.Errorf("platform %s: lengths must not be negative", name)
+		}
+		if rule.MaxLength > 0 && rule.MinLength > rule.MaxLength {
+			return fmt.Errorf("platform %s: min_length %d exceeds max_length %d", name, rule.MinLength, rule.MaxLength)
+		}
+
+		pattern := rule.Pattern
+		if pattern == "" {
+			pattern = `[\p{L}\p{N}_.]`
+		}
+		chars, err := regexp.Compile(`^(?:` + pattern + `)$`)
+		if err != nil {
+			return fmt.Errorf("platform %s: invalid pattern: %w", name, err)
+		}
+		rule.chars = chars
+	}
+
+	for field, platform := range p.Fields {
+		if _, ok := p.Platforms[platform]; !ok {
+			return fmt.Errorf("field %s uses unknown platform %q", field, platform)
+		}
+	}
+	return nil
+}
+
+// normalize strips a leading '@', checks the handle against the rule and
+// applies the rule's casing
+func (r *PlatformRule) normalize(handle string) (string, error) {
+	handle = strings.TrimPrefix(strings.TrimSpace(handle), "@")
+
+	length := utf8.RuneCountInString(handle)
+	if length == 0 {
+		return "", fmt.Errorf("handle is empty")
+	}
+	if r.MinLength > 0 && length < r.MinLength {
+		return "", fmt.Errorf("handle has %d characters, minimum is %d", length, r.MinLength)
+	}
+	if r.MaxLength > 0 && length > r.MaxLength {
+		return "", fmt.Errorf("handle has %d characters, maximum is %d", length, r.MaxLength)
+	}
+
+	position := 0
+	for _, c := range handle {
+		position++
+		if !r.chars.MatchString(string(c)) {
+			return "", fmt.Errorf("character %q at position %d is not allowed", c, position)
+		}
+	}
+
+	if r.PreserveCase {
+		return handle, nil
+	}
+	return strings.ToLower(handle), nil
+}
@@ -0,0 +1,127 @@
+package handlepolicy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+// newPolicy configures a policy with twitter and github rules
+func newPolicy(t *testing.T) *Policy {
+	t.Helper()
+	p := &Policy{}
+	err := p.Configure(map[string]interface{}{
+		"platforms": map[string]interface{}{
+			"twitter": map[string]interface{}{"min_length": 4, "max_length": 15, "pattern": "[A-Za-z0-9_]"},
+			"github":  map[string]interface{}{"max_length": 39, "pattern": "[A-Za-z0-9-]", "preserve_case": true},
+		},
+		"fields": map[string]interface{}{"twitter": "twitter", "github": "github"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name       string
+		field      string
+		value      interface{}
+		wantOutput interface{}
+		wantReason string
+	}{
+		{name: "valid with @", field: "twitter", value: "@Go_Lang", wantOutput: "go_lang"},
+		{name: "valid without @", field: "twitter", value: "golang", wantOutput: "golang"},
+		{name: "surrounding space", field: "twitter", value: "  @golang ", wantOutput: "golang"},
+		{name: "case preserved", field: "github", value: "@Renuka-Fernando", wantOutput: "Renuka-Fernando"},
+		{name: "over length", field: "twitter", value: "@abcdefghijklmnop", wantReason: "handle has 16 characters, maximum is 15"},
+		{name: "under length", field: "twitter", value: "@abc", wantReason: "handle has 3 characters, minimum is 4"},
+		{name: "invalid character", field: "twitter", value: "@go-lang", wantReason: `character '-' at position 3 is not allowed`},
+		{name: "empty", field: "github", value: "@", wantReason: "handle is empty"},
+		{name: "not a string", field: "github", value: 42.0, wantReason: "expected string, got float64"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newPolicy(t).Execute(context.Background(), map[string]interface{}{tt.field: tt.value})
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			invalid := result.Data["invalid"].([]map[string]interface{})
+
+			if tt.wantReason == "" {
+				if result.Status != "PASSED" || len(invalid) != 0 {
+					t.Fatalf("status = %s, invalid = %v; want PASSED", result.Status, invalid)
+				}
+				if result.Output[tt.field] != tt.wantOutput {
+					t.Errorf("output = %v, want %v", result.Output[tt.field], tt.wantOutput)
+				}
+				return
+			}
+			if result.Status != "FAILED" || len(invalid) != 1 {
+				t.Fatalf("status = %s, invalid = %v; want one invalid handle", result.Status, invalid)
+			}
+			if invalid[0]["reason"] != tt.wantReason || invalid[0]["platform"] != tt.field {
+				t.Errorf("invalid = %v, want reason %q on %s", invalid[0], tt.wantReason, tt.field)
+			}
+			if result.Output[tt.field] != tt.value {
+				t.Errorf("invalid handle rewritten to %v", result.Output[tt.field])
+			}
+		})
+	}
+}
+
+func TestExecuteDefaultPattern(t *testing.T) {
+	p := &Policy{}
+	err := p.Configure(map[string]interface{}{
+		"platforms": map[string]interface{}{"any": map[string]interface{}{}},
+		"fields":    map[string]interface{}{"handle": "any"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := p.Execute(context.Background(), map[string]interface{}{"handle": "@Ünïcode.name_1", "other": "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := got.(*policyresult.Result)
+	if result.Output["handle"] != "ünïcode.name_1" || result.Output["other"] != "x" {
+		t.Errorf("output = %v, want the lowercased handle and untouched fields", result.Output)
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr bool
+	}{
+		{"zero value", map[string]interface{}{}, false},
+		{"platform", map[string]interface{}{"platforms": map[string]interface{}{"x": map[string]interface{}{"max_length": 5}}}, false},
+		{"missing rules", map[string]interface{}{"platforms": map[string]interface{}{"x": nil}}, true},
+		{"negative length", map[string]interface{}{"platforms": map[string]interface{}{"x": map[string]interface{}{"min_length": -1}}}, true},
+		{"min above max", map[string]interface{}{"platforms": map[string]interface{}{"x": map[string]interface{}{"min_length": 6, "max_length": 5}}}, true},
+		{"invalid pattern", map[string]interface{}{"platforms": map[string]interface{}{"x": map[string]interface{}{"pattern": "["}}}, true},
+		{"unknown platform", map[string]interface{}{"fields": map[string]interface{}{"handle": "x"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := (&Policy{}).Configure(tt.config); (err != nil) != tt.wantErr {
+				t.Errorf("Configure(%v) = %v, wantErr %v", tt.config, err, tt.wantErr)
+			}
+		})
+	}
+}
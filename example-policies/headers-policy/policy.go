@@ -0,0 +1,179 @@
+package headerspolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Policy implements the policy engine interface
+// It validates a map of HTTP-style headers, as captured from request
+// metadata, checking that required headers are present and that their
+// values match configured patterns. Header names are compared
+// case-insensitively. A header value may be a string or a list of
+// strings, in which case every entry must match.
+type Policy struct {
+	// Field names the input field holding the headers map (default "headers")
+	Field string `json:"field"`
+
+	// Required maps header names to the regular expression their values
+	// must fully match; an empty pattern only requires the header to be
+	// present
+	Required map[string]string `json:"required"`
+
+	patterns map[string]*regexp.Regexp
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "headers-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	result := policyresult.New(p.Name(), "header validation")
+
+	headers := map[string]interface{}{}
+	if raw, exists := inputMap[p.field()]; exists {
+		headers, ok = raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("field %q: expected object, got %T", p.field(), raw)
+		}
+	}
+
+	// Index the headers by canonical name so lookups ignore case
+	canonical := make(map[string]interface{}, len(headers))
+	for name, value := range headers {
+		canonical[http.CanonicalHeaderKey(name)] = value
+	}
+
+	names := make([]string, 0, len(p.Required))
+	for name := range p.Required {
+		names = append(names, http.CanonicalHeaderKey(name))
+	}
+	sort.Strings(names)
+
+	missing := []string{}
+	malformed := []map[string]interface{}{}
+	for _, name := range names {
+		value, exists := canonical[name]
+		if !exists {
+			missing = append(missing, name)
+			continue
+		}
+		if reason := p.check(name, value); reason != "" {
+			malformed = append(malformed, map[string]interface{}{
+				"header": name,
+				"value":  value,
+				"reason": reason,
+			})
+		}
+	}
+
+	result.Data["missing"] = missing
+	result.Data["malformed"] = malformed
+
+	if len(missing) > 0 || len(malformed) > 0 {
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("%d missing and %d malformed header(s)", len(missing), len(malformed))
+	} else {
+		result.Status = "PASSED"
+		result.Message = "All required headers present and valid"
+	}
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid and compiles the
+// header patterns
+func (p *Policy) Validate() error {
+	p.patterns = make(map[string]*regexp.Regexp, len(p.Required))
+	for name, pattern := range p.Required {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("required header name must not be empty")
+		}
+		key := http.CanonicalHeaderKey(name)
+		if _, duplicate := p.patterns[key]; duplicate {
+			return fmt.Errorf("header %s is listed more than once", key)
+		}
+		if pattern == "" {
+			p.patterns[key] = nil
+			continue
+		}
+		re, err := regexp.Compile(`^(?:` + pattern + `)$`)
+		if err != nil {
+			return fmt.Errorf("header %s: invalid pattern: %w", key, err)
+		}
+		p.patterns[key] = re
+	}
+	return nil
+}
+
+func (p *Policy) field() string {
+	if p.Field == "" {
+		return "headers"
+	}
+	return p.Field
+}
+
+// check returns why a header value is malformed, or "" if it is valid
+func (p *Policy) check(name string, value interface{}) string {
+	var values []string
+	switch v := value.(type) {
+	case string:
+		values = []string{v}
+	case []interface{}:
+		if len(v) == 0 {
+			return "header has no values"
+		}
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return fmt.Sprintf("expected string values, got %T", item)
+			}
+			values = append(values, s)
+		}
+	default:
+		return fmt.Sprintf("expected string or list of strings, got %T", value)
+	}
+
+	re := p.patterns[name]
+	if re == nil {
+		return ""
+	}
+	for _, s := range values {
+		if !re.MatchString(s) {
+			return fmt.Sprintf("value %q does not match %s", s, re.String())
+		}
+	}
+	return ""
+}
@@ -0,0 +1,187 @@
+package headerspolicy
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+// newPolicy requires a JSON content type, a bearer token and a request ID
+// of any format
+func newPolicy(t *testing.T) *Policy {
+	t.Helper()
+	p := &Policy{}
+	err := p.Configure(map[string]interface{}{
+		"required": map[string]interface{}{
+			"content-type":  `application/json(; charset=utf-8)?`,
+			"Authorization": `Bearer [A-Za-z0-9._-]+`,
+			"X-REQUEST-ID":  "",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name          string
+		headers       map[string]interface{}
+		wantStatus    string
+		wantMissing   []string
+		wantMalformed []string
+	}{
+		{
+			name: "complete",
+			headers: map[string]interface{}{
+				"Content-Type":  "application/json",
+				"authorization": "Bearer abc.def",
+				"x-request-id":  "42",
+				"Accept":        "*/*",
+			},
+			wantStatus:    "PASSED",
+			wantMissing:   []string{},
+			wantMalformed: []string{},
+		},
+		{
+			name: "list values",
+			headers: map[string]interface{}{
+				"CONTENT-TYPE":  []interface{}{"application/json; charset=utf-8"},
+				"Authorization": []interface{}{"Bearer a", "Bearer b"},
+				"X-Request-Id":  []interface{}{"1"},
+			},
+			wantStatus:    "PASSED",
+			wantMissing:   []string{},
+			wantMalformed: []string{},
+		},
+		{
+			name:          "missing",
+			headers:       map[string]interface{}{"Content-Type": "application/json"},
+			wantStatus:    "FAILED",
+			wantMissing:   []string{"Authorization", "X-Request-Id"},
+			wantMalformed: []string{},
+		},
+		{
+			name: "malformed",
+			headers: map[string]interface{}{
+				"Content-Type":  "text/html",
+				"Authorization": []interface{}{"Bearer ok", "Basic xyz"},
+				"X-Request-Id":  7.0,
+			},
+			wantStatus:    "FAILED",
+			wantMissing:   []string{},
+			wantMalformed: []string{"Authorization", "Content-Type", "X-Request-Id"},
+		},
+		{
+			name:          "no headers",
+			wantStatus:    "FAILED",
+			wantMissing:   []string{"Authorization", "Content-Type", "X-Request-Id"},
+			wantMalformed: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := map[string]interface{}{}
+			if tt.headers != nil {
+				input["headers"] = tt.headers
+			}
+			got, err := newPolicy(t).Execute(context.Background(), input)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if result.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", result.Status, tt.wantStatus)
+			}
+			if !reflect.DeepEqual(result.Data["missing"], tt.wantMissing) {
+				t.Errorf("missing = %v, want %v", result.Data["missing"], tt.wantMissing)
+			}
+			malformed := []string{}
+			for _, entry := range result.Data["malformed"].([]map[string]interface{}) {
+				malformed = append(malformed, entry["header"].(string))
+			}
+			if !reflect.DeepEqual(malformed, tt.wantMalformed) {
+				t.Errorf("malformed = %v, want %v", malformed, tt.wantMalformed)
+			}
+		})
+	}
+}
+
+func TestCheckReasons(t *testing.T) {
+	p := newPolicy(t)
+	tests := []struct {
+		name   string
+		header string
+		value  interface{}
+		want   string
+	}{
+		{"no match", "Content-Type", "text/html", `value "text/html" does not match ^(?:application/json(; charset=utf-8)?)$`},
+		{"partial match", "Authorization", "Bearer abc!", `value "Bearer abc!" does not match ^(?:Bearer [A-Za-z0-9._-]+)$`},
+		{"empty list", "X-Request-Id", []interface{}{}, "header has no values"},
+		{"non-string entry", "X-Request-Id", []interface{}{1.0}, "expected string values, got float64"},
+		{"wrong type", "X-Request-Id", true, "expected string or list of strings, got bool"},
+		{"presence only", "X-Request-Id", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.check(tt.header, tt.value); got != tt.want {
+				t.Errorf("check = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	p := newPolicy(t)
+	if _, err := p.Execute(context.Background(), map[string]interface{}{"headers": "Accept: */*"}); err == nil {
+		t.Error("non-object headers succeeded, want error")
+	}
+
+	p = &Policy{}
+	if err := p.Configure(map[string]interface{}{"field": "meta", "required": map[string]interface{}{"accept": ""}}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := p.Execute(context.Background(), map[string]interface{}{"meta": map[string]interface{}{"ACCEPT": "*/*"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := got.(*policyresult.Result).Status; status != "PASSED" {
+		t.Errorf("custom field status = %s, want PASSED", status)
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr bool
+	}{
+		{"zero value", map[string]interface{}{}, false},
+		{"patterns", map[string]interface{}{"required": map[string]interface{}{"accept": ".*"}}, false},
+		{"empty name", map[string]interface{}{"required": map[string]interface{}{" ": ""}}, true},
+		{"same header twice", map[string]interface{}{"required": map[string]interface{}{"accept": "", "ACCEPT": ""}}, true},
+		{"invalid pattern", map[string]interface{}{"required": map[string]interface{}{"accept": "("}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := (&Policy{}).Configure(tt.config); (err != nil) != tt.wantErr {
+				t.Errorf("Configure(%v) = %v, wantErr %v", tt.config, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package httpstatuspolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Policy implements the policy engine interface
+// It checks that configured fields hold valid HTTP status codes (integers
+// from 100 to 599) within an allowed set. Allowed entries are exact codes
+// such as "404" or classes such as "2xx"; when none are configured every
+// valid code is allowed.
+type Policy struct {
+	// Fields lists the input fields holding status codes
+	Fields []string `json:"fields"`
+
+	// Allowed lists the allowed codes and classes
+	Allowed []string `json:"allowed"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "httpstatus-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	invalid := []map[string]interface{}{}
+	disallowed := []map[string]interface{}{}
+	for _, field := range p.Fields {
+		value, exists := inputMap[field]
+		if !exists {
+			continue
+		}
+
+		code, ok := toStatusCode(value)
+		if !ok {
+			invalid = append(invalid, map[string]interface{}{
+				"field": field,
+				"value": value,
+			})
+			continue
+		}
+
+		if !p.allowed(code) {
+			disallowed = append(disallowed, map[string]interface{}{
+				"field": field,
+				"code":  code,
+				"class": class(code),
+				"text":  http.StatusText(code),
+			})
+		}
+	}
+
+	result := policyresult.New(p.Name(), "HTTP status validation")
+	result.Data["invalid"] = invalid
+	result.Data["disallowed"] = disallowed
+
+	if len(invalid)+len(disallowed) > 0 {
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("%d invalid and %d disallowed status code(s)", len(invalid), len(disallowed))
+	} else {
+		result.Status = "PASSED"
+		result.Message = "All status codes allowed"
+	}
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	for _, entry := range p.Allowed {
+		if _, _, err := parseAllowed(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Policy) allowed(code int) bool {
+	if len(p.Allowed) == 0 {
+		return true
+	}
+	for _, entry := range p.Allowed {
+		low, high, err := parseAllowed(entry)
+		if err == nil && code >= low && code <= high {
+			return true
+		}
+	}
+	return false
+}
+
+// parseAllowed turns "404" or "2xx" into an inclusive code range
+func parseAllowed(entry string) (low, high int, err error) {
+	s := strings.ToLower(strings.TrimSpace(entry))
+	if len(s) == 3 && strings.HasSuffix(s, "xx") && s[0] >= '1' && s[0] <= '5' {
+		low = int(s[0]-'0') * 100
+		return low, low + 99, nil
+	}
+	code, convErr := strconv.Atoi(s)
+	if convErr != nil || code < 100 || code > 599 {
+		return 0, 0, fmt.Errorf("invalid allowed entry %q, expected a status code or class such as 2xx", entry)
+	}
+	return code, code, nil
+}
+
+// toStatusCode accepts whole numbers in the 100-599 range
+func toStatusCode(value interface{}) (int, bool) {
+	var f float64
+	switch v := value.(type) {
+	case float64:
+		f = v
+	case int:
+		f = float64(v)
+	case int64:
+		f = float64(v)
+	case json.Number:
+		n, err := v.Float64()
+		if err != nil {
+			return 0, false
+		}
+		f = n
+	default:
+		return 0, false
+	}
+	if f != math.Trunc(f) || f < 100 || f > 599 {
+		return 0, false
+	}
+	return int(f), true
+}
+
+func class(code int) string {
+	return fmt.Sprintf("%dxx", code/100)
+}
@@ -0,0 +1,146 @@
+package httpstatuspolicy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name           string
+		allowed        []string
+		value          interface{}
+		wantStatus     string
+		wantDisallowed []map[string]interface{}
+		wantInvalid    bool
+	}{
+		{name: "allowed class", allowed: []string{"2xx"}, value: 204.0, wantStatus: "PASSED"},
+		{name: "allowed code", allowed: []string{"2xx", "304"}, value: 304, wantStatus: "PASSED"},
+		{name: "class bounds", allowed: []string{"2XX"}, value: 299, wantStatus: "PASSED"},
+		{name: "anything valid when unrestricted", value: json.Number("503"), wantStatus: "PASSED"},
+		{
+			name:       "disallowed code",
+			allowed:    []string{"2xx"},
+			value:      404.0,
+			wantStatus: "FAILED",
+			wantDisallowed: []map[string]interface{}{
+				{"field": "code", "code": 404, "class": "4xx", "text": "Not Found"},
+			},
+		},
+		{
+			name:       "neighbour of an allowed code",
+			allowed:    []string{" 301 ", "5xx"},
+			value:      302,
+			wantStatus: "FAILED",
+			wantDisallowed: []map[string]interface{}{
+				{"field": "code", "code": 302, "class": "3xx", "text": "Found"},
+			},
+		},
+		{
+			name:       "unassigned code in range",
+			allowed:    []string{"2xx"},
+			value:      599,
+			wantStatus: "FAILED",
+			wantDisallowed: []map[string]interface{}{
+				{"field": "code", "code": 599, "class": "5xx", "text": ""},
+			},
+		},
+		{name: "below range", value: 99, wantStatus: "FAILED", wantInvalid: true},
+		{name: "above range", value: 600.0, wantStatus: "FAILED", wantInvalid: true},
+		{name: "fractional", value: 200.5, wantStatus: "FAILED", wantInvalid: true},
+		{name: "numeric string", value: "200", wantStatus: "FAILED", wantInvalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Policy{Fields: []string{"code", "absent"}, Allowed: tt.allowed}
+			got, err := p.Execute(context.Background(), map[string]interface{}{"code": tt.value})
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if result.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s (%s)", result.Status, tt.wantStatus, result.Message)
+			}
+
+			disallowed := result.Data["disallowed"].([]map[string]interface{})
+			if len(disallowed)+len(tt.wantDisallowed) > 0 && !reflect.DeepEqual(disallowed, tt.wantDisallowed) {
+				t.Errorf("disallowed = %v, want %v", disallowed, tt.wantDisallowed)
+			}
+
+			invalid := result.Data["invalid"].([]map[string]interface{})
+			wantInvalid := []map[string]interface{}{}
+			if tt.wantInvalid {
+				wantInvalid = append(wantInvalid, map[string]interface{}{"field": "code", "value": tt.value})
+			}
+			if !reflect.DeepEqual(invalid, wantInvalid) {
+				t.Errorf("invalid = %v, want %v", invalid, wantInvalid)
+			}
+		})
+	}
+}
+
+func TestExecuteSeveralFields(t *testing.T) {
+	p := &Policy{}
+	err := p.Configure(map[string]interface{}{
+		"fields":  []interface{}{"success_code", "retry_code", "error_code"},
+		"allowed": []interface{}{"2xx", "429", "5xx"},
+	})
+	if err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+
+	got, err := p.Execute(context.Background(), map[string]interface{}{"success_code": 201, "retry_code": 429, "error_code": 503})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result := got.(*policyresult.Result); result.Status != "PASSED" {
+		t.Errorf("status = %s, want PASSED (%s)", result.Status, result.Message)
+	}
+
+	if _, err := p.Execute(context.Background(), 200); err == nil {
+		t.Error("Execute accepted a non-map input")
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []interface{}
+		wantErr bool
+	}{
+		{"codes and classes", []interface{}{"200", "1xx", "5XX"}, false},
+		{"unknown class", []interface{}{"6xx"}, true},
+		{"code out of range", []interface{}{"700"}, true},
+		{"not a code", []interface{}{"ok"}, true},
+		{"two digit class", []interface{}{"2x"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&Policy{}).Configure(map[string]interface{}{"allowed": tt.allowed})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Configure error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Policy{}).Validate(); err != nil {
+		t.Errorf("zero value: %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package integercheckpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Policy implements the policy engine interface
+// It checks that configured numeric fields hold whole integers within an
+// optional range, catching floats where integers are expected. json.Number
+// values are parsed exactly; float64 values must be whole and lie within
+// the range where float64 represents integers exactly (±2^53).
+type Policy struct {
+	// Fields lists the input fields that must be integers
+	Fields []string `json:"fields"`
+
+	// Min and Max optionally bound the values (inclusive)
+	Min *int64 `json:"min"`
+	Max *int64 `json:"max"`
+}
+
+// maxSafeInteger is the largest magnitude float64 holds without gaps
+const maxSafeInteger = 1 << 53
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "integercheck-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	violations := []map[string]interface{}{}
+	for _, field := range p.Fields {
+		value, exists := inputMap[field]
+		if !exists {
+			continue
+		}
+
+		n, reason := toInteger(value)
+		if reason == "" {
+			reason = p.checkRange(n)
+		}
+		if reason != "" {
+			violations = append(violations, map[string]interface{}{
+				"field":  field,
+				"value":  value,
+				"reason": reason,
+			})
+		}
+	}
+
+	result := policyresult.New(p.Name(), "integer validation")
+	result.Data["violations"] = violations
+
+	if len(violations) > 0 {
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("%d field(s) are not valid integers", len(violations))
+	} else {
+		result.Status = "PASSED"
+		result.Message = "All fields are valid integers"
+	}
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	if p.Min != nil && p.Max != nil && *p.Min > *p.Max {
+		return fmt.Errorf("min %d is greater than max %d", *p.Min, *p.Max)
+	}
+	return nil
+}
+
+// checkRange returns a violation reason, or "" when n is within range
+func (p *Policy) checkRange(n int64) string {
+	if p.Min != nil && n < *p.Min {
+		return fmt.Sprintf("below minimum %d", *p.Min)
+	}
+	if p.Max != nil && n > *p.Max {
+		return fmt.Sprintf("above maximum %d", *p.Max)
+	}
+	return ""
+}
+
+// toInteger converts value to an int64, returning a violation reason when
+// it is not an exact integer
+func toInteger(value interface{}) (int64, string) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), ""
+	case int32:
+		return int64(v), ""
+	case int64:
+		return v, ""
+	case json.Number:
+		if n, err := strconv.ParseInt(string(v), 10, 64); err == nil {
+			return n, ""
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return 0, "not a number"
+		}
+		return fromFloat(f)
+	case float32:
+		return fromFloat(float64(v))
+	case float64:
+		return fromFloat(v)
+	default:
+		return 0, fmt.Sprintf("expected a number, got %T", value)
+	}
+}
+
+func fromFloat(f float64) (int64, string) {
+	if math.IsNaN(f) || math.IsInf(f, 0) || f != math.Trunc(f) {
+		return 0, "not an integer"
+	}
+	if math.Abs(f) > maxSafeInteger {
+		return 0, "too large to be represented exactly"
+	}
+	return int64(f), ""
+}
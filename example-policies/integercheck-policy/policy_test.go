@@ -0,0 +1,106 @@
+package integercheckpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+func int64p(n int64) *int64 {
+	return &n
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name       string
+		min, max   *int64
+		value      interface{}
+		wantReason string
+	}{
+		{name: "float64 integer", value: 42.0},
+		{name: "int", value: 7},
+		{name: "negative", value: -3.0},
+		{name: "json.Number integer", value: json.Number("9007199254740993")},
+		{name: "json.Number whole decimal", value: json.Number("12.0")},
+		{name: "fractional float", value: 1.5, wantReason: "not an integer"},
+		{name: "fractional json.Number", value: json.Number("0.25"), wantReason: "not an integer"},
+		{name: "NaN", value: math.NaN(), wantReason: "not an integer"},
+		{name: "infinity", value: math.Inf(1), wantReason: "not an integer"},
+		{name: "beyond 2^53", value: 1e17, wantReason: "too large to be represented exactly"},
+		{name: "string", value: "42", wantReason: "expected a number, got string"},
+		{name: "within range", min: int64p(1), max: int64p(10), value: 10.0},
+		{name: "below minimum", min: int64p(1), value: 0.0, wantReason: "below minimum 1"},
+		{name: "above maximum", max: int64p(10), value: 11.0, wantReason: "above maximum 10"},
+		{name: "range checked on json.Number", max: int64p(10), value: json.Number("11"), wantReason: "above maximum 10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Policy{Fields: []string{"count"}, Min: tt.min, Max: tt.max}
+			got, err := p.Execute(context.Background(), map[string]interface{}{"count": tt.value})
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			violations := result.Data["violations"].([]map[string]interface{})
+
+			if tt.wantReason == "" {
+				if result.Status != "PASSED" || len(violations) != 0 {
+					t.Errorf("status = %s, violations = %v; want PASSED", result.Status, violations)
+				}
+				return
+			}
+			if result.Status != "FAILED" || len(violations) != 1 {
+				t.Fatalf("status = %s, violations = %v; want one violation", result.Status, violations)
+			}
+			if reason := violations[0]["reason"]; reason != tt.wantReason {
+				t.Errorf("reason = %v, want %q", reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestExecuteMissingFieldIgnored(t *testing.T) {
+	p := &Policy{Fields: []string{"count", "total"}}
+	got, err := p.Execute(context.Background(), map[string]interface{}{"total": 3.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := got.(*policyresult.Result).Status; status != "PASSED" {
+		t.Errorf("status = %s, want PASSED", status)
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr bool
+	}{
+		{"zero value", map[string]interface{}{}, false},
+		{"range", map[string]interface{}{"fields": []string{"n"}, "min": 0, "max": 10}, false},
+		{"equal bounds", map[string]interface{}{"min": 5, "max": 5}, false},
+		{"min above max", map[string]interface{}{"min": 10, "max": 0}, true},
+		{"fractional bound", map[string]interface{}{"min": 1.5}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := (&Policy{}).Configure(tt.config); (err != nil) != tt.wantErr {
+				t.Errorf("Configure(%v) = %v, wantErr %v", tt.config, err, tt.wantErr)
+			}
+		})
+	}
+}
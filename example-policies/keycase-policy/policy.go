@@ -0,0 +1,195 @@
+package keycasepolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Policy implements the policy engine interface
+// It rewrites every map key, at any depth, to a single case style so that
+// producers with inconsistent key casing can be consumed uniformly
+type Policy struct {
+	// Style is one of lower, upper, snake (default) or camel
+	Style string `json:"style"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "keycase-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	result := policyresult.New(p.Name(), "key case normalization")
+	result.Data["style"] = p.style()
+
+	collisions := []map[string]interface{}{}
+	output := p.rewrite(inputMap, "", &collisions).(map[string]interface{})
+
+	result.Data["input"] = inputMap
+	result.Output = output
+	result.Data["collisions"] = collisions
+
+	if len(collisions) > 0 {
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("%d key collision(s) after rewriting", len(collisions))
+	} else {
+		result.Status = "PASSED"
+		result.Message = "All keys rewritten"
+	}
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	switch p.style() {
+	case "lower", "upper", "snake", "camel":
+		return nil
+	default:
+		return fmt.Errorf("unsupported style %q, expected lower, upper, snake or camel", p.Style)
+	}
+}
+
+func (p *Policy) style() string {
+	if p.Style == "" {
+		return "snake"
+	}
+	return strings.ToLower(p.Style)
+}
+
+// rewrite returns a copy of value with every map key converted. When two
+// keys at the same level convert to the same key, the first in sorted
+// order is kept and the collision is recorded.
+func (p *Policy) rewrite(value interface{}, path string, collisions *[]map[string]interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		out := make(map[string]interface{}, len(v))
+		origin := make(map[string]string, len(v))
+		for _, key := range keys {
+			converted := convert(key, p.style())
+			childPath := converted
+			if path != "" {
+				childPath = path + "." + converted
+			}
+
+			if first, taken := origin[converted]; taken {
+				*collisions = append(*collisions, map[string]interface{}{
+					"key":     childPath,
+					"sources": []string{first, key},
+				})
+				continue
+			}
+
+			origin[converted] = key
+			out[converted] = p.rewrite(v[key], childPath, collisions)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = p.rewrite(item, fmt.Sprintf("%s[%d]", path, i), collisions)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// convert renders key in the given style
+func convert(key, style string) string {
+	switch style {
+	case "lower":
+		return strings.ToLower(key)
+	case "upper":
+		return strings.ToUpper(key)
+	}
+
+	words := splitWords(key)
+	if len(words) == 0 {
+		return key
+	}
+
+	if style == "snake" {
+		return strings.Join(words, "_")
+	}
+
+	var b strings.Builder
+	b.WriteString(words[0])
+	for _, word := range words[1:] {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	return b.String()
+}
+
+// splitWords breaks a key into lower-case words at separators and case
+// boundaries, so "userID", "user_id", "User-Id" all yield [user id]
+func splitWords(key string) []string {
+	var words []string
+	var current []rune
+
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, strings.ToLower(string(current)))
+			current = current[:0]
+		}
+	}
+
+	runes := []rune(key)
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-' || r == ' ' || r == '.':
+			flush()
+			continue
+		case unicode.IsUpper(r) && i > 0:
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			// Split on lower->Upper ("userId") and at the end of an
+			// acronym ("HTTPServer" -> HTTP, Server)
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush()
+			}
+		}
+		current = append(current, r)
+	}
+	flush()
+
+	return words
+}
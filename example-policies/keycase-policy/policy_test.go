@@ -0,0 +1,138 @@
+package keycasepolicy
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		key                        string
+		lower, upper, snake, camel string
+	}{
+		{"userID", "userid", "USERID", "user_id", "userId"},
+		{"user_id", "user_id", "USER_ID", "user_id", "userId"},
+		{"User-Id", "user-id", "USER-ID", "user_id", "userId"},
+		{"HTTPServer", "httpserver", "HTTPSERVER", "http_server", "httpServer"},
+		{"first name", "first name", "FIRST NAME", "first_name", "firstName"},
+		{"address2Line", "address2line", "ADDRESS2LINE", "address2_line", "address2Line"},
+		{"__", "__", "__", "__", "__"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			for style, want := range map[string]string{"lower": tt.lower, "upper": tt.upper, "snake": tt.snake, "camel": tt.camel} {
+				if got := convert(tt.key, style); got != want {
+					t.Errorf("convert(%q, %s) = %q, want %q", tt.key, style, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestExecuteNested(t *testing.T) {
+	input := map[string]interface{}{
+		"userName": "ada",
+		"HomeAddress": map[string]interface{}{
+			"streetName": "Main St",
+			"ZIPCode":    "12345",
+		},
+		"phoneNumbers": []interface{}{
+			map[string]interface{}{"countryCode": "+44"},
+			"not a map",
+		},
+	}
+
+	got, err := (&Policy{}).Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	result := got.(*policyresult.Result)
+
+	want := map[string]interface{}{
+		"user_name": "ada",
+		"home_address": map[string]interface{}{
+			"street_name": "Main St",
+			"zip_code":    "12345",
+		},
+		"phone_numbers": []interface{}{
+			map[string]interface{}{"country_code": "+44"},
+			"not a map",
+		},
+	}
+	if !reflect.DeepEqual(result.Output, want) {
+		t.Errorf("output = %v, want %v", result.Output, want)
+	}
+	if result.Status != "PASSED" {
+		t.Errorf("status = %s, want PASSED", result.Status)
+	}
+	if _, ok := input["HomeAddress"]; !ok {
+		t.Error("input modified")
+	}
+}
+
+func TestExecuteCollisions(t *testing.T) {
+	input := map[string]interface{}{
+		"user_id": 1,
+		"userId":  2,
+		"nested": map[string]interface{}{
+			"Name": "a",
+			"name": "b",
+			"NAME": "c",
+		},
+	}
+
+	got, err := (&Policy{Style: "lower"}).Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	result := got.(*policyresult.Result)
+
+	// With lower case the user keys stay distinct; the nested keys collide
+	// and the first in sorted order wins
+	wantCollisions := []map[string]interface{}{
+		{"key": "nested.name", "sources": []string{"NAME", "Name"}},
+		{"key": "nested.name", "sources": []string{"NAME", "name"}},
+	}
+	if !reflect.DeepEqual(result.Data["collisions"], wantCollisions) {
+		t.Errorf("collisions = %v, want %v", result.Data["collisions"], wantCollisions)
+	}
+	if nested := result.Output["nested"].(map[string]interface{}); !reflect.DeepEqual(nested, map[string]interface{}{"name": "c"}) {
+		t.Errorf("nested = %v, want the NAME value kept", nested)
+	}
+	if result.Status != "FAILED" {
+		t.Errorf("status = %s, want FAILED", result.Status)
+	}
+
+	got, err = (&Policy{Style: "snake"}).Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	collisions := got.(*policyresult.Result).Data["collisions"].([]map[string]interface{})
+	if len(collisions) != 3 || collisions[0]["key"] != "nested.name" || collisions[2]["key"] != "user_id" {
+		t.Errorf("snake collisions = %v, want two nested and one user_id", collisions)
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, style := range []string{"", "lower", "UPPER", "snake", "Camel"} {
+		if err := (&Policy{Style: style}).Validate(); err != nil {
+			t.Errorf("style %q: %v", style, err)
+		}
+	}
+	if err := (&Policy{Style: "kebab"}).Validate(); err == nil {
+		t.Error("unsupported style passed validation")
+	}
+}
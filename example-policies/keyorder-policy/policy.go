@@ -0,0 +1,172 @@
+package keyorderpolicy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Policy implements the policy engine interface
+// It checks that the top-level keys of a JSON document appear in a
+// configured order, for systems that require canonical serialization.
+// Decoded maps lose key order, so the document is read as raw JSON: either
+// the input itself ([]byte, json.RawMessage or string) or a string field of
+// a map input.
+type Policy struct {
+	// Order lists the keys in the order they must appear
+	Order []string `json:"order"`
+
+	// Field names the map input field holding the raw JSON (default "payload")
+	Field string `json:"field"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "keyorder-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result := policyresult.New(p.Name(), "key order validation")
+	result.Data["required_order"] = p.Order
+
+	var raw []byte
+	switch v := input.(type) {
+	case []byte:
+		raw = v
+	case json.RawMessage:
+		raw = v
+	case string:
+		raw = []byte(v)
+	case map[string]interface{}:
+		payload, exists := v[p.field()]
+		if !exists {
+			result.Status = "PASSED"
+			result.Message = "No JSON document to validate"
+			return result, nil
+		}
+		s, ok := payload.(string)
+		if !ok {
+			return nil, fmt.Errorf("field %q: expected JSON string, got %T", p.field(), payload)
+		}
+		raw = []byte(s)
+	default:
+		return nil, fmt.Errorf("expected raw JSON or map[string]interface{}, got %T", input)
+	}
+
+	keys, err := topLevelKeys(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON document: %w", err)
+	}
+
+	position := make(map[string]int, len(p.Order))
+	for i, key := range p.Order {
+		position[key] = i
+	}
+
+	// A configured key is out of order when a key that must come after it
+	// has already been seen
+	outOfOrder := []string{}
+	highest := -1
+	for _, key := range keys {
+		pos, tracked := position[key]
+		if !tracked {
+			continue
+		}
+		if pos < highest {
+			outOfOrder = append(outOfOrder, key)
+			continue
+		}
+		highest = pos
+	}
+
+	result.Data["keys"] = keys
+	result.Data["out_of_order"] = outOfOrder
+
+	if len(outOfOrder) > 0 {
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("Keys out of order: %v", outOfOrder)
+	} else {
+		result.Status = "PASSED"
+		result.Message = "Keys appear in required order"
+	}
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	seen := make(map[string]bool, len(p.Order))
+	for _, key := range p.Order {
+		if seen[key] {
+			return fmt.Errorf("key %q appears more than once in order", key)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+func (p *Policy) field() string {
+	if p.Field == "" {
+		return "payload"
+	}
+	return p.Field
+}
+
+// topLevelKeys returns the keys of a JSON object in document order
+func topLevelKeys(raw []byte) ([]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return nil, fmt.Errorf("expected JSON object")
+	}
+
+	var keys []string
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, tok.(string))
+
+		// Skip over the value, however deeply nested
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after JSON object")
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,129 @@
+package keyorderpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          interface{}
+		wantStatus     string
+		wantOutOfOrder []string
+	}{
+		{
+			name:           "required order",
+			input:          `{"id": 1, "type": "a", "body": {}}`,
+			wantStatus:     "PASSED",
+			wantOutOfOrder: []string{},
+		},
+		{
+			name:           "untracked keys anywhere",
+			input:          `{"extra": 0, "id": 1, "more": [1, {"body": 2}], "body": {}}`,
+			wantStatus:     "PASSED",
+			wantOutOfOrder: []string{},
+		},
+		{
+			name:           "missing keys are not out of order",
+			input:          `{"id": 1, "body": {}}`,
+			wantStatus:     "PASSED",
+			wantOutOfOrder: []string{},
+		},
+		{
+			name:           "swapped pair",
+			input:          `{"type": "a", "id": 1, "body": {}}`,
+			wantStatus:     "FAILED",
+			wantOutOfOrder: []string{"id"},
+		},
+		{
+			name:           "reversed",
+			input:          []byte(`{"body": {}, "type": "a", "id": 1}`),
+			wantStatus:     "FAILED",
+			wantOutOfOrder: []string{"type", "id"},
+		},
+		{
+			name:           "raw message",
+			input:          json.RawMessage(`{"body": {"id": 1, "type": "a"}, "id": 1}`),
+			wantStatus:     "FAILED",
+			wantOutOfOrder: []string{"id"},
+		},
+		{
+			name:           "map payload field",
+			input:          map[string]interface{}{"payload": `{"type": "a", "id": 1}`},
+			wantStatus:     "FAILED",
+			wantOutOfOrder: []string{"id"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Policy{Order: []string{"id", "type", "body"}}
+			got, err := p.Execute(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if result.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s (%s)", result.Status, tt.wantStatus, result.Message)
+			}
+			if !reflect.DeepEqual(result.Data["out_of_order"], tt.wantOutOfOrder) {
+				t.Errorf("out_of_order = %v, want %v", result.Data["out_of_order"], tt.wantOutOfOrder)
+			}
+		})
+	}
+}
+
+func TestExecuteMissingPayload(t *testing.T) {
+	p := &Policy{Order: []string{"id"}, Field: "doc"}
+	got, err := p.Execute(context.Background(), map[string]interface{}{"payload": `{}`})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result := got.(*policyresult.Result); result.Status != "PASSED" {
+		t.Errorf("status = %s, want PASSED", result.Status)
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input interface{}
+	}{
+		{"array document", `[1, 2]`},
+		{"truncated document", `{"id": 1,`},
+		{"trailing garbage", `{"id": 1}}`},
+		{"non-string payload", map[string]interface{}{"payload": 1}},
+		{"unsupported input", 42},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := (&Policy{}).Execute(context.Background(), tt.input); err == nil {
+				t.Error("Execute succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, `{}`); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Policy{}).Validate(); err != nil {
+		t.Errorf("zero value: %v", err)
+	}
+	if err := (&Policy{Order: []string{"id", "type", "id"}}).Validate(); err == nil {
+		t.Error("duplicate key passed validation")
+	}
+}
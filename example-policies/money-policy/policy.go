@@ -0,0 +1,226 @@
+package moneypolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/example/policies/policyresult"
+)
+
+// defaultScales holds the ISO 4217 minor units for common currencies
+var defaultScales = map[string]int{
+	"USD": 2, "EUR": 2, "GBP": 2, "AUD": 2, "CAD": 2, "CHF": 2, "CNY": 2,
+	"INR": 2, "LKR": 2, "SGD": 2, "JPY": 0, "KRW": 0, "VND": 0, "CLP": 0,
+	"ISK": 0, "BHD": 3, "KWD": 3, "OMR": 3, "JOD": 3, "TND": 3,
+}
+
+// Policy implements the policy engine interface
+// It validates monetary fields: amounts must fall within a range (by
+// default non-negative) and use no more decimal places than the
+// currency's minor unit allows, e.g. 2 for USD and 0 for JPY
+type Policy struct {
+	// Fields lists the input fields holding amounts
+	Fields []string `json:"fields"`
+
+	// CurrencyField names the input field holding the ISO currency code
+	// (default "currency")
+	CurrencyField string `json:"currency_field"`
+
+	// DefaultCurrency is used when the input has no currency field
+	DefaultCurrency string `json:"default_currency"`
+
+	// Scales overrides or extends the built-in minor units per currency
+	Scales map[string]int `json:"scales"`
+
+	// Min and Max bound the allowed amounts; Min defaults to 0
+	Min *float64 `json:"min"`
+	Max *float64 `json:"max"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "money-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	result := policyresult.New(p.Name(), "monetary amount validation")
+
+	if len(p.Fields) == 0 {
+		result.Status = "PASSED"
+		result.Message = "No monetary fields configured"
+		return result, nil
+	}
+
+	currency := p.DefaultCurrency
+	if value, ok := inputMap[p.currencyField()].(string); ok {
+		currency = value
+	}
+	currency = strings.ToUpper(currency)
+
+	scale, known := p.scale(currency)
+	if !known {
+		return nil, fmt.Errorf("unknown currency %q", currency)
+	}
+
+	result.Data["currency"] = currency
+	result.Data["scale"] = scale
+
+	violations := []map[string]interface{}{}
+	for _, field := range p.Fields {
+		value, exists := inputMap[field]
+		if !exists {
+			continue
+		}
+
+		amount, decimals, err := parseAmount(value)
+		if err != nil {
+			violations = append(violations, map[string]interface{}{
+				"field": field,
+				"error": err.Error(),
+			})
+			continue
+		}
+
+		if decimals > scale {
+			violations = append(violations, map[string]interface{}{
+				"field": field,
+				"value": amount,
+				"error": fmt.Sprintf("has %d decimal places, %s allows %d", decimals, currency, scale),
+			})
+		}
+		if amount < p.min() {
+			violations = append(violations, map[string]interface{}{
+				"field": field,
+				"value": amount,
+				"error": fmt.Sprintf("below minimum %v", p.min()),
+			})
+		}
+		if p.Max != nil && amount > *p.Max {
+			violations = append(violations, map[string]interface{}{
+				"field": field,
+				"value": amount,
+				"error": fmt.Sprintf("above maximum %v", *p.Max),
+			})
+		}
+	}
+
+	result.Data["violations"] = violations
+
+	if len(violations) > 0 {
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("%d monetary violation(s)", len(violations))
+	} else {
+		result.Status = "PASSED"
+		result.Message = "All amounts valid"
+	}
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	if p.Max != nil && *p.Max < p.min() {
+		return fmt.Errorf("max (%v) must not be less than min (%v)", *p.Max, p.min())
+	}
+	for currency, scale := range p.Scales {
+		if scale < 0 {
+			return fmt.Errorf("scale for %s must not be negative, got %d", currency, scale)
+		}
+	}
+	if p.DefaultCurrency != "" {
+		if _, ok := p.scale(strings.ToUpper(p.DefaultCurrency)); !ok {
+			return fmt.Errorf("unknown default currency %q", p.DefaultCurrency)
+		}
+	}
+	return nil
+}
+
+func (p *Policy) currencyField() string {
+	if p.CurrencyField == "" {
+		return "currency"
+	}
+	return p.CurrencyField
+}
+
+func (p *Policy) min() float64 {
+	if p.Min == nil {
+		return 0
+	}
+	return *p.Min
+}
+
+func (p *Policy) scale(currency string) (int, bool) {
+	if scale, ok := p.Scales[currency]; ok {
+		return scale, true
+	}
+	scale, ok := defaultScales[currency]
+	return scale, ok
+}
+
+// parseAmount returns the amount and its number of decimal places. The
+// decimal places are counted from the shortest text representation, so
+// float inputs like 10.1 count as one place rather than their binary
+// expansion. Strings are accepted so exact decimal text can be checked.
+func parseAmount(value interface{}) (float64, int, error) {
+	var text string
+	switch v := value.(type) {
+	case float64:
+		text = strconv.FormatFloat(v, 'f', -1, 64)
+	case int:
+		text = strconv.Itoa(v)
+	case int64:
+		text = strconv.FormatInt(v, 10)
+	case json.Number:
+		text = v.String()
+	case string:
+		text = strings.TrimSpace(v)
+	default:
+		return 0, 0, fmt.Errorf("expected number, got %T", value)
+	}
+
+	amount, err := strconv.ParseFloat(text, 64)
+	if err != nil || math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return 0, 0, fmt.Errorf("invalid amount %q", text)
+	}
+
+	// Checked after parsing so words containing an e are reported as
+	// invalid rather than as exponents
+	if strings.ContainsAny(text, "eE") {
+		return 0, 0, fmt.Errorf("amount %q must not use exponent notation", text)
+	}
+
+	decimals := 0
+	if dot := strings.IndexByte(text, '.'); dot >= 0 {
+		decimals = len(strings.TrimRight(text[dot+1:], "0"))
+	}
+
+	return amount, decimals, nil
+}
@@ -0,0 +1,240 @@
+package moneypolicy
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+func float(v float64) *float64 {
+	return &v
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     *Policy
+		input      map[string]interface{}
+		wantStatus string
+		wantScale  int
+		// wantErrors holds a substring of each expected violation, in order
+		wantErrors []string
+	}{
+		{
+			name:       "USD two places",
+			policy:     &Policy{Fields: []string{"amount"}},
+			input:      map[string]interface{}{"amount": 10.25, "currency": "USD"},
+			wantStatus: "PASSED",
+			wantScale:  2,
+		},
+		{
+			name:       "USD three places",
+			policy:     &Policy{Fields: []string{"amount"}},
+			input:      map[string]interface{}{"amount": 10.255, "currency": "usd"},
+			wantStatus: "FAILED",
+			wantScale:  2,
+			wantErrors: []string{"has 3 decimal places, USD allows 2"},
+		},
+		{
+			name:       "JPY whole amount",
+			policy:     &Policy{Fields: []string{"amount"}},
+			input:      map[string]interface{}{"amount": 1500, "currency": "JPY"},
+			wantStatus: "PASSED",
+		},
+		{
+			name:       "JPY trailing zeros allowed",
+			policy:     &Policy{Fields: []string{"amount"}},
+			input:      map[string]interface{}{"amount": "1500.00", "currency": "JPY"},
+			wantStatus: "PASSED",
+		},
+		{
+			name:       "JPY fraction",
+			policy:     &Policy{Fields: []string{"amount"}},
+			input:      map[string]interface{}{"amount": 1500.5, "currency": "JPY"},
+			wantStatus: "FAILED",
+			wantErrors: []string{"has 1 decimal places, JPY allows 0"},
+		},
+		{
+			name:       "BHD three places",
+			policy:     &Policy{Fields: []string{"amount"}},
+			input:      map[string]interface{}{"amount": "1.125", "currency": "BHD"},
+			wantStatus: "PASSED",
+			wantScale:  3,
+		},
+		{
+			name:       "BHD four places",
+			policy:     &Policy{Fields: []string{"amount"}},
+			input:      map[string]interface{}{"amount": "1.1255", "currency": "BHD"},
+			wantStatus: "FAILED",
+			wantScale:  3,
+			wantErrors: []string{"has 4 decimal places, BHD allows 3"},
+		},
+		{
+			name:       "scale override",
+			policy:     &Policy{Fields: []string{"amount"}, Scales: map[string]int{"USD": 4, "BTC": 8}},
+			input:      map[string]interface{}{"amount": 0.00012345, "currency": "BTC"},
+			wantStatus: "PASSED",
+			wantScale:  8,
+		},
+		{
+			name:       "default currency",
+			policy:     &Policy{Fields: []string{"amount"}, DefaultCurrency: "JPY"},
+			input:      map[string]interface{}{"amount": 1.5},
+			wantStatus: "FAILED",
+			wantErrors: []string{"JPY allows 0"},
+		},
+		{
+			name:       "custom currency field",
+			policy:     &Policy{Fields: []string{"amount"}, CurrencyField: "ccy"},
+			input:      map[string]interface{}{"amount": 1.5, "ccy": "KWD"},
+			wantStatus: "PASSED",
+			wantScale:  3,
+		},
+		{
+			name:       "negative by default",
+			policy:     &Policy{Fields: []string{"amount"}},
+			input:      map[string]interface{}{"amount": -0.01, "currency": "EUR"},
+			wantStatus: "FAILED",
+			wantScale:  2,
+			wantErrors: []string{"below minimum 0"},
+		},
+		{
+			name:       "negative within configured range",
+			policy:     &Policy{Fields: []string{"amount"}, Min: float(-100), Max: float(100)},
+			input:      map[string]interface{}{"amount": -50, "currency": "EUR"},
+			wantStatus: "PASSED",
+			wantScale:  2,
+		},
+		{
+			name:       "above maximum",
+			policy:     &Policy{Fields: []string{"amount"}, Max: float(100)},
+			input:      map[string]interface{}{"amount": 100.001, "currency": "EUR"},
+			wantStatus: "FAILED",
+			wantScale:  2,
+			wantErrors: []string{"has 3 decimal places", "above maximum 100"},
+		},
+		{
+			name:       "each field reported",
+			policy:     &Policy{Fields: []string{"price", "tax", "missing"}},
+			input:      map[string]interface{}{"price": 9.999, "tax": true, "currency": "GBP"},
+			wantStatus: "FAILED",
+			wantScale:  2,
+			wantErrors: []string{"has 3 decimal places", "expected number, got bool"},
+		},
+		{
+			name:       "exponent notation",
+			policy:     &Policy{Fields: []string{"amount"}},
+			input:      map[string]interface{}{"amount": "1e3", "currency": "USD"},
+			wantStatus: "FAILED",
+			wantScale:  2,
+			wantErrors: []string{"must not use exponent notation"},
+		},
+		{
+			name:       "invalid text",
+			policy:     &Policy{Fields: []string{"amount"}},
+			input:      map[string]interface{}{"amount": "ten", "currency": "USD"},
+			wantStatus: "FAILED",
+			wantScale:  2,
+			wantErrors: []string{`invalid amount "ten"`},
+		},
+		{
+			name:       "not a number",
+			policy:     &Policy{Fields: []string{"amount"}},
+			input:      map[string]interface{}{"amount": "NaN", "currency": "USD"},
+			wantStatus: "FAILED",
+			wantScale:  2,
+			wantErrors: []string{`invalid amount "NaN"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.policy.Execute(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if result.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s (%v)", result.Status, tt.wantStatus, result.Data["violations"])
+			}
+			if result.Data["scale"] != tt.wantScale {
+				t.Errorf("scale = %v, want %d", result.Data["scale"], tt.wantScale)
+			}
+
+			violations := result.Data["violations"].([]map[string]interface{})
+			if len(violations) != len(tt.wantErrors) {
+				t.Fatalf("violations = %v, want %d", violations, len(tt.wantErrors))
+			}
+			for i, want := range tt.wantErrors {
+				if msg, _ := violations[i]["error"].(string); !strings.Contains(msg, want) {
+					t.Errorf("violation %d = %q, want it to contain %q", i, msg, want)
+				}
+			}
+		})
+	}
+}
+
+func TestExecuteNoFields(t *testing.T) {
+	got, err := (&Policy{}).Execute(context.Background(), map[string]interface{}{"currency": "XXX"})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result := got.(*policyresult.Result); result.Status != "PASSED" {
+		t.Errorf("status = %s, want PASSED", result.Status)
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	p := &Policy{Fields: []string{"amount"}}
+	if _, err := p.Execute(context.Background(), map[string]interface{}{"amount": 1, "currency": "XXX"}); err == nil {
+		t.Error("Execute accepted an unknown currency")
+	}
+	if _, err := p.Execute(context.Background(), map[string]interface{}{"amount": 1}); err == nil {
+		t.Error("Execute accepted a missing currency with no default")
+	}
+	if _, err := p.Execute(context.Background(), []interface{}{1}); err == nil {
+		t.Error("Execute accepted a non-map input")
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr bool
+	}{
+		{"valid", map[string]interface{}{"fields": []interface{}{"amount"}, "default_currency": "usd", "max": 500}, false},
+		{"max below default min", map[string]interface{}{"max": -1}, true},
+		{"max below min", map[string]interface{}{"min": 10, "max": 5}, true},
+		{"negative scale", map[string]interface{}{"scales": map[string]interface{}{"XTS": -1}}, true},
+		{"unknown default currency", map[string]interface{}{"default_currency": "XXX"}, true},
+		{"custom default currency", map[string]interface{}{"default_currency": "XTS", "scales": map[string]interface{}{"XTS": 1}}, false},
+		{"wrong type", map[string]interface{}{"fields": "amount"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&Policy{}).Configure(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Configure error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Policy{}).Validate(); err != nil {
+		t.Errorf("zero value: %v", err)
+	}
+}
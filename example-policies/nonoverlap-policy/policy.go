@@ -0,0 +1,182 @@
+package nonoverlappolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/example/policies/policyresult"
+)
+
+// layouts are tried in order when parsing range boundaries
+var layouts = []string{time.RFC3339Nano, time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"}
+
+// Policy implements the policy engine interface
+// It checks that the date ranges in a configured array of objects do not
+// overlap. Ranges are half-open by default, so a range ending exactly
+// when the next begins is not an overlap.
+type Policy struct {
+	// Field names the input field holding the array of ranges
+	Field string `json:"field"`
+
+	// StartField and EndField name the boundary fields of each range
+	// (default "start" and "end")
+	StartField string `json:"start_field"`
+	EndField   string `json:"end_field"`
+
+	// Inclusive treats ranges that merely touch as overlapping
+	Inclusive bool `json:"inclusive"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "nonoverlap-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	result := policyresult.New(p.Name(), "range overlap validation")
+
+	value, exists := inputMap[p.Field]
+	if p.Field == "" || !exists {
+		result.Status = "PASSED"
+		result.Message = "No ranges to validate"
+		return result, nil
+	}
+
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("field %q: expected array, got %T", p.Field, value)
+	}
+
+	startField, endField := p.fields()
+	type span struct {
+		index      int
+		start, end time.Time
+	}
+
+	spans := make([]span, 0, len(items))
+	invalid := []map[string]interface{}{}
+	for i, item := range items {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			invalid = append(invalid, map[string]interface{}{"index": i, "error": fmt.Sprintf("expected object, got %T", item)})
+			continue
+		}
+
+		start, err := parseTime(obj[startField])
+		if err != nil {
+			invalid = append(invalid, map[string]interface{}{"index": i, "error": fmt.Sprintf("%s: %v", startField, err)})
+			continue
+		}
+		end, err := parseTime(obj[endField])
+		if err != nil {
+			invalid = append(invalid, map[string]interface{}{"index": i, "error": fmt.Sprintf("%s: %v", endField, err)})
+			continue
+		}
+		if end.Before(start) {
+			invalid = append(invalid, map[string]interface{}{"index": i, "error": "end is before start"})
+			continue
+		}
+
+		spans = append(spans, span{index: i, start: start, end: end})
+	}
+
+	// Sort by start so each range only needs comparing with the ranges
+	// that begin before it ends
+	sort.SliceStable(spans, func(i, j int) bool {
+		return spans[i].start.Before(spans[j].start)
+	})
+
+	overlaps := [][]int{}
+	for i := range spans {
+		for j := i + 1; j < len(spans); j++ {
+			if spans[j].start.After(spans[i].end) || (!p.Inclusive && spans[j].start.Equal(spans[i].end)) {
+				break
+			}
+			a, b := spans[i].index, spans[j].index
+			if a > b {
+				a, b = b, a
+			}
+			overlaps = append(overlaps, []int{a, b})
+		}
+	}
+	sort.Slice(overlaps, func(i, j int) bool {
+		if overlaps[i][0] != overlaps[j][0] {
+			return overlaps[i][0] < overlaps[j][0]
+		}
+		return overlaps[i][1] < overlaps[j][1]
+	})
+
+	result.Data["overlaps"] = overlaps
+	result.Data["invalid_ranges"] = invalid
+
+	if len(overlaps) > 0 || len(invalid) > 0 {
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("%d overlapping pair(s), %d invalid range(s)", len(overlaps), len(invalid))
+	} else {
+		result.Status = "PASSED"
+		result.Message = "No overlapping ranges"
+	}
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	startField, endField := p.fields()
+	if startField == endField {
+		return fmt.Errorf("start_field and end_field must differ, both are %q", startField)
+	}
+	return nil
+}
+
+func (p *Policy) fields() (string, string) {
+	startField, endField := p.StartField, p.EndField
+	if startField == "" {
+		startField = "start"
+	}
+	if endField == "" {
+		endField = "end"
+	}
+	return startField, endField
+}
+
+// parseTime parses a boundary value using the supported layouts
+func parseTime(value interface{}) (time.Time, error) {
+	s, ok := value.(string)
+	if !ok {
+		return time.Time{}, fmt.Errorf("expected timestamp string, got %T", value)
+	}
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized timestamp %q", s)
+}
@@ -0,0 +1,160 @@
+package nonoverlappolicy
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+// rng builds a range object
+func rng(start, end interface{}) map[string]interface{} {
+	return map[string]interface{}{"start": start, "end": end}
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name         string
+		inclusive    bool
+		ranges       []interface{}
+		wantOverlaps [][]int
+	}{
+		{
+			name: "non-overlapping",
+			ranges: []interface{}{
+				rng("2024-03-01", "2024-03-05"),
+				rng("2024-01-01", "2024-01-31"),
+				rng("2024-02-01", "2024-02-10"),
+			},
+			wantOverlaps: [][]int{},
+		},
+		{
+			name: "touching",
+			ranges: []interface{}{
+				rng("2024-01-01T09:00:00Z", "2024-01-01T10:00:00Z"),
+				rng("2024-01-01T10:00:00Z", "2024-01-01T11:00:00Z"),
+			},
+			wantOverlaps: [][]int{},
+		},
+		{
+			name:      "touching when inclusive",
+			inclusive: true,
+			ranges: []interface{}{
+				rng("2024-01-01T09:00:00Z", "2024-01-01T10:00:00Z"),
+				rng("2024-01-01T10:00:00Z", "2024-01-01T11:00:00Z"),
+			},
+			wantOverlaps: [][]int{{0, 1}},
+		},
+		{
+			name: "overlapping",
+			ranges: []interface{}{
+				rng("2024-01-10", "2024-01-20"),
+				rng("2024-01-01", "2024-01-15"),
+				rng("2024-02-01", "2024-02-02"),
+			},
+			wantOverlaps: [][]int{{0, 1}},
+		},
+		{
+			name: "containing range overlaps every range inside it",
+			ranges: []interface{}{
+				rng("2024-01-02", "2024-01-03"),
+				rng("2024-01-05", "2024-01-06"),
+				rng("2024-01-01", "2024-01-31"),
+				rng("2024-01-10", "2024-01-12"),
+			},
+			wantOverlaps: [][]int{{0, 2}, {1, 2}, {2, 3}},
+		},
+		{
+			name: "mixed layouts and time zones",
+			ranges: []interface{}{
+				rng("2024-01-01T10:00:00+02:00", "2024-01-01T12:00:00+02:00"),
+				rng("2024-01-01T10:00:00Z", "2024-01-01T10:30:00.5Z"),
+			},
+			wantOverlaps: [][]int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Policy{Field: "bookings", Inclusive: tt.inclusive}
+			got, err := p.Execute(context.Background(), map[string]interface{}{"bookings": tt.ranges})
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if !reflect.DeepEqual(result.Data["overlaps"], tt.wantOverlaps) {
+				t.Errorf("overlaps = %v, want %v", result.Data["overlaps"], tt.wantOverlaps)
+			}
+			wantStatus := "PASSED"
+			if len(tt.wantOverlaps) > 0 {
+				wantStatus = "FAILED"
+			}
+			if result.Status != wantStatus {
+				t.Errorf("status = %s, want %s (%s)", result.Status, wantStatus, result.Message)
+			}
+		})
+	}
+}
+
+func TestExecuteInvalidRanges(t *testing.T) {
+	p := &Policy{Field: "bookings", StartField: "from", EndField: "to"}
+	ranges := []interface{}{
+		map[string]interface{}{"from": "2024-01-01", "to": "2024-01-02"},
+		"not an object",
+		map[string]interface{}{"from": "yesterday", "to": "2024-01-02"},
+		map[string]interface{}{"from": "2024-01-01", "to": 5},
+		map[string]interface{}{"from": "2024-01-05", "to": "2024-01-01"},
+	}
+
+	got, err := p.Execute(context.Background(), map[string]interface{}{"bookings": ranges})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	result := got.(*policyresult.Result)
+
+	invalid := result.Data["invalid_ranges"].([]map[string]interface{})
+	var indices []int
+	for _, entry := range invalid {
+		indices = append(indices, entry["index"].(int))
+	}
+	if want := []int{1, 2, 3, 4}; !reflect.DeepEqual(indices, want) {
+		t.Errorf("invalid indices = %v, want %v (%v)", indices, want, invalid)
+	}
+	if result.Status != "FAILED" {
+		t.Errorf("status = %s, want FAILED", result.Status)
+	}
+}
+
+func TestExecuteNotAnArray(t *testing.T) {
+	p := &Policy{Field: "bookings"}
+	if _, err := p.Execute(context.Background(), map[string]interface{}{"bookings": "x"}); err == nil {
+		t.Error("Execute succeeded, want error")
+	}
+
+	got, err := p.Execute(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result := got.(*policyresult.Result); result.Status != "PASSED" {
+		t.Errorf("missing field status = %s, want PASSED", result.Status)
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Policy{}).Validate(); err != nil {
+		t.Errorf("zero value: %v", err)
+	}
+	if err := (&Policy{StartField: "end"}).Validate(); err == nil {
+		t.Error("same start and end fields passed validation")
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/example/policies/oneof-policy
+
+go 1.21
+
+require (
+	github.com/example/policies/policyresult v0.0.0
+	github.com/santhosh-tekuri/jsonschema/v5 v5.3.1
+)
+
+replace github.com/example/policies/policyresult => ../policyresult
@@ -0,0 +1,156 @@
+package oneofpolicy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Policy implements the policy engine interface
+// It applies JSON Schema "oneOf" semantics to polymorphic payloads: the
+// input must match exactly one of the configured sub-schemas
+type Policy struct {
+	// Schemas lists the candidate sub-schemas. A schema's "title", when
+	// present, is used to name it in the result.
+	Schemas []map[string]interface{} `json:"schemas"`
+
+	compiled []*jsonschema.Schema
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "oneof-policy"
+}
+
+// Configure applies the given configuration to the policy and compiles the
+// sub-schemas
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	compiled, err := p.compile()
+	if err != nil {
+		return err
+	}
+	p.compiled = compiled
+	return nil
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result := policyresult.New(p.Name(), "oneOf schema validation")
+
+	if len(p.Schemas) == 0 {
+		result.Status = "PASSED"
+		result.Message = "No schemas configured"
+		return result, nil
+	}
+
+	compiled := p.compiled
+	if compiled == nil {
+		var err error
+		if compiled, err = p.compile(); err != nil {
+			return nil, err
+		}
+	}
+
+	// The validator only understands the types produced by encoding/json,
+	// so normalize inputs such as []string or int first
+	doc, err := normalize(input)
+	if err != nil {
+		return nil, fmt.Errorf("input is not JSON-compatible: %w", err)
+	}
+
+	matched := []string{}
+	errors := make(map[string]string)
+	for i, schema := range compiled {
+		label := p.label(i)
+		if err := schema.Validate(doc); err != nil {
+			errors[label] = err.Error()
+			continue
+		}
+		matched = append(matched, label)
+	}
+
+	result.Data["matched"] = matched
+
+	switch len(matched) {
+	case 1:
+		result.Status = "PASSED"
+		result.Message = fmt.Sprintf("Input matches %s", matched[0])
+	case 0:
+		result.Status = "FAILED"
+		result.Data["errors"] = errors
+		result.Message = "Input matches none of the schemas"
+	default:
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("Input matches %d schemas, expected exactly one: %v", len(matched), matched)
+	}
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	_, err := p.compile()
+	return err
+}
+
+// compile compiles each configured sub-schema
+func (p *Policy) compile() ([]*jsonschema.Schema, error) {
+	compiled := make([]*jsonschema.Schema, 0, len(p.Schemas))
+	for i, raw := range p.Schemas {
+		data, err := json.Marshal(raw)
+		if err != nil {
+			return nil, fmt.Errorf("schema %s: %w", p.label(i), err)
+		}
+
+		url := fmt.Sprintf("mem://oneof/%d.json", i)
+		compiler := jsonschema.NewCompiler()
+		if err := compiler.AddResource(url, bytes.NewReader(data)); err != nil {
+			return nil, fmt.Errorf("schema %s: %w", p.label(i), err)
+		}
+		schema, err := compiler.Compile(url)
+		if err != nil {
+			return nil, fmt.Errorf("schema %s: %w", p.label(i), err)
+		}
+		compiled = append(compiled, schema)
+	}
+	return compiled, nil
+}
+
+// label names a sub-schema by its title, or by index when it has none
+func (p *Policy) label(i int) string {
+	if title, ok := p.Schemas[i]["title"].(string); ok && title != "" {
+		return title
+	}
+	return fmt.Sprintf("schema[%d]", i)
+}
+
+// normalize round-trips a value through encoding/json
+func normalize(input interface{}) (interface{}, error) {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
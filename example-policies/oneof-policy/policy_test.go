@@ -0,0 +1,142 @@
+package oneofpolicy
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+// paymentSchemas accepts a card payment or a bank payment. A payload with
+// both a card number and an account matches both.
+func paymentSchemas() map[string]interface{} {
+	return map[string]interface{}{
+		"schemas": []interface{}{
+			map[string]interface{}{
+				"title":    "card",
+				"type":     "object",
+				"required": []interface{}{"card_number"},
+				"properties": map[string]interface{}{
+					"card_number": map[string]interface{}{"type": "string", "pattern": "^[0-9]{12,19}$"},
+				},
+			},
+			map[string]interface{}{
+				"type":     "object",
+				"required": []interface{}{"account"},
+			},
+		},
+	}
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       interface{}
+		wantStatus  string
+		wantMatched []string
+	}{
+		{
+			name:        "matches exactly one",
+			input:       map[string]interface{}{"card_number": "4111111111111111"},
+			wantStatus:  "PASSED",
+			wantMatched: []string{"card"},
+		},
+		{
+			name:        "matches the untitled schema",
+			input:       map[string]interface{}{"account": "GB00", "amount": 5},
+			wantStatus:  "PASSED",
+			wantMatched: []string{"schema[1]"},
+		},
+		{
+			name:        "matches none",
+			input:       map[string]interface{}{"card_number": "not-digits"},
+			wantStatus:  "FAILED",
+			wantMatched: []string{},
+		},
+		{
+			name:        "not an object",
+			input:       []string{"card_number"},
+			wantStatus:  "FAILED",
+			wantMatched: []string{},
+		},
+		{
+			name:        "matches multiple",
+			input:       map[string]interface{}{"card_number": "4111111111111111", "account": "GB00"},
+			wantStatus:  "FAILED",
+			wantMatched: []string{"card", "schema[1]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Policy{}
+			if err := p.Configure(paymentSchemas()); err != nil {
+				t.Fatalf("Configure: %v", err)
+			}
+			got, err := p.Execute(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if result.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s (%s)", result.Status, tt.wantStatus, result.Message)
+			}
+			if !reflect.DeepEqual(result.Data["matched"], tt.wantMatched) {
+				t.Errorf("matched = %v, want %v", result.Data["matched"], tt.wantMatched)
+			}
+
+			// Errors are only reported when nothing matched
+			errs, hasErrors := result.Data["errors"].(map[string]string)
+			if len(tt.wantMatched) == 0 {
+				if !hasErrors || errs["card"] == "" || errs["schema[1]"] == "" {
+					t.Errorf("errors = %v, want one per schema", result.Data["errors"])
+				}
+			} else if hasErrors {
+				t.Errorf("errors reported despite a match: %v", errs)
+			}
+		})
+	}
+}
+
+func TestExecuteWithoutConfigure(t *testing.T) {
+	// Schemas set directly are compiled on first use
+	p := &Policy{Schemas: []map[string]interface{}{{"type": "string"}}}
+	got, err := p.Execute(context.Background(), "hello")
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result := got.(*policyresult.Result); result.Status != "PASSED" {
+		t.Errorf("status = %s, want PASSED", result.Status)
+	}
+
+	got, err = (&Policy{}).Execute(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result := got.(*policyresult.Result); result.Status != "PASSED" {
+		t.Errorf("no schemas status = %s, want PASSED", result.Status)
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Policy{}).Validate(); err != nil {
+		t.Errorf("zero value: %v", err)
+	}
+	bad := &Policy{Schemas: []map[string]interface{}{{"type": 5}}}
+	if err := bad.Validate(); err == nil {
+		t.Error("invalid sub-schema passed validation")
+	}
+	if err := (&Policy{}).Configure(map[string]interface{}{"schemas": []interface{}{map[string]interface{}{"minimum": "x"}}}); err == nil {
+		t.Error("Configure accepted an invalid sub-schema")
+	}
+}
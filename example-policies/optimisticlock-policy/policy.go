@@ -0,0 +1,152 @@
+package optimisticlockpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Policy implements the policy engine interface
+// It implements optimistic locking: an update whose version is older than
+// the last accepted version for the same ID is rejected as stale, and
+// accepted updates advance the stored version
+type Policy struct {
+	// IDField names the input field identifying the entity (default "id")
+	IDField string `json:"id_field"`
+
+	// VersionField names the input field holding the version (default "version")
+	VersionField string `json:"version_field"`
+
+	// Store holds accepted versions; it defaults to a MemoryStore
+	Store Store `json:"-"`
+
+	once         sync.Once
+	defaultStore Store
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "optimisticlock-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	result := policyresult.New(p.Name(), "optimistic lock check")
+
+	idField, versionField := p.fields()
+	idValue, hasID := inputMap[idField]
+	if !hasID {
+		result.Status = "PASSED"
+		result.Message = "No entity ID to check"
+		return result, nil
+	}
+
+	id := fmt.Sprint(idValue)
+	version, err := toVersion(inputMap[versionField])
+	if err != nil {
+		return nil, fmt.Errorf("field %q: %w", versionField, err)
+	}
+
+	stored, found, accepted, err := p.store().Advance(id, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check version for %s: %w", id, err)
+	}
+
+	result.Data["id"] = id
+	result.Data["version"] = version
+	if found {
+		result.Data["stored_version"] = stored
+	}
+
+	switch {
+	case !accepted:
+		result.Status = "REJECTED"
+		result.Message = fmt.Sprintf("Stale update for %s: version %d is older than stored version %d", id, version, stored)
+	case !found:
+		result.Status = "PASSED"
+		result.Message = fmt.Sprintf("First version %d recorded for %s", version, id)
+	default:
+		result.Status = "PASSED"
+		result.Message = fmt.Sprintf("Version for %s advanced from %d to %d", id, stored, version)
+	}
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	idField, versionField := p.fields()
+	if idField == versionField {
+		return fmt.Errorf("id_field and version_field must differ, both are %q", idField)
+	}
+	return nil
+}
+
+func (p *Policy) fields() (string, string) {
+	idField, versionField := p.IDField, p.VersionField
+	if idField == "" {
+		idField = "id"
+	}
+	if versionField == "" {
+		versionField = "version"
+	}
+	return idField, versionField
+}
+
+func (p *Policy) store() Store {
+	if p.Store != nil {
+		return p.Store
+	}
+	p.once.Do(func() {
+		p.defaultStore = NewMemoryStore()
+	})
+	return p.defaultStore
+}
+
+// toVersion converts a decoded version value to an integer
+func toVersion(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case float64:
+		if v != math.Trunc(v) {
+			return 0, fmt.Errorf("version must be a whole number, got %v", v)
+		}
+		return int64(v), nil
+	case json.Number:
+		return v.Int64()
+	case nil:
+		return 0, fmt.Errorf("version is missing")
+	default:
+		return 0, fmt.Errorf("expected number, got %T", value)
+	}
+}
@@ -0,0 +1,144 @@
+package optimisticlockpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+func TestExecuteSequence(t *testing.T) {
+	p := &Policy{}
+
+	steps := []struct {
+		name       string
+		id         interface{}
+		version    interface{}
+		wantStatus string
+		wantStored interface{}
+	}{
+		{"first-time key", "doc-1", 3, "PASSED", nil},
+		{"fresh update", "doc-1", 4.0, "PASSED", int64(3)},
+		{"same version", "doc-1", json.Number("4"), "PASSED", int64(4)},
+		{"stale update", "doc-1", int64(2), "REJECTED", int64(4)},
+		{"stale update leaves store unchanged", "doc-1", 3, "REJECTED", int64(4)},
+		{"other key is independent", "doc-2", 1, "PASSED", nil},
+		{"numeric ids are stringified", 7, 1, "PASSED", nil},
+		{"stringified id shares the key", "7", 0, "REJECTED", int64(1)},
+	}
+
+	for _, step := range steps {
+		got, err := p.Execute(context.Background(), map[string]interface{}{"id": step.id, "version": step.version})
+		if err != nil {
+			t.Fatalf("%s: Execute: %v", step.name, err)
+		}
+		result := got.(*policyresult.Result)
+		if result.Status != step.wantStatus {
+			t.Errorf("%s: status = %s, want %s (%s)", step.name, result.Status, step.wantStatus, result.Message)
+		}
+		if result.Data["stored_version"] != step.wantStored {
+			t.Errorf("%s: stored_version = %v, want %v", step.name, result.Data["stored_version"], step.wantStored)
+		}
+	}
+}
+
+func TestExecuteCustomFields(t *testing.T) {
+	p := &Policy{IDField: "key", VersionField: "rev"}
+	input := map[string]interface{}{"key": "a", "rev": 2, "id": "ignored"}
+	for _, want := range []string{"PASSED", "PASSED"} {
+		got, err := p.Execute(context.Background(), input)
+		if err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+		if status := got.(*policyresult.Result).Status; status != want {
+			t.Errorf("status = %s, want %s", status, want)
+		}
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		version interface{}
+	}{
+		{"missing version", nil},
+		{"fractional version", 1.5},
+		{"string version", "3"},
+		{"malformed number", json.Number("3.5")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := map[string]interface{}{"id": "doc"}
+			if tt.version != nil {
+				input["version"] = tt.version
+			}
+			if _, err := (&Policy{}).Execute(context.Background(), input); err == nil {
+				t.Error("Execute succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestExecuteWithoutID(t *testing.T) {
+	got, err := (&Policy{}).Execute(context.Background(), map[string]interface{}{"version": 1})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result := got.(*policyresult.Result); result.Status != "PASSED" {
+		t.Errorf("status = %s, want PASSED", result.Status)
+	}
+}
+
+// failingStore is a Store that always fails
+type failingStore struct{}
+
+func (failingStore) Advance(string, int64) (int64, bool, bool, error) {
+	return 0, false, false, errors.New("store unavailable")
+}
+
+func TestExecuteStoreError(t *testing.T) {
+	p := &Policy{Store: failingStore{}}
+	if _, err := p.Execute(context.Background(), map[string]interface{}{"id": "doc", "version": 1}); err == nil {
+		t.Error("Execute succeeded with a failing store")
+	}
+}
+
+func TestMemoryStoreConcurrentAdvance(t *testing.T) {
+	// Concurrent writers must leave the highest version stored
+	store := NewMemoryStore()
+	var wg sync.WaitGroup
+	for v := int64(1); v <= 100; v++ {
+		wg.Add(1)
+		go func(v int64) {
+			defer wg.Done()
+			store.Advance("doc", v)
+		}(v)
+	}
+	wg.Wait()
+
+	stored, found, accepted, _ := store.Advance("doc", 99)
+	if !found || stored != 100 || accepted {
+		t.Errorf("Advance after writers = %d, %v, %v; want 100, true, false", stored, found, accepted)
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Policy{}).Validate(); err != nil {
+		t.Errorf("zero value: %v", err)
+	}
+	if err := (&Policy{IDField: "version"}).Validate(); err == nil {
+		t.Error("id_field equal to the default version_field passed validation")
+	}
+}
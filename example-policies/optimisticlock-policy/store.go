@@ -0,0 +1,38 @@
+package optimisticlockpolicy
+
+import "sync"
+
+// Store holds the latest accepted version for each entity ID
+type Store interface {
+	// Advance records version for id unless the stored version is newer.
+	// It returns the version stored before the call, whether one existed,
+	// and whether the new version was accepted. Implementations must make
+	// the check and the update atomic.
+	Advance(id string, version int64) (stored int64, found bool, accepted bool, err error)
+}
+
+// MemoryStore is an in-process Store. It is safe for concurrent use.
+type MemoryStore struct {
+	mu       sync.Mutex
+	versions map[string]int64
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		versions: make(map[string]int64),
+	}
+}
+
+// Advance implements Store
+func (s *MemoryStore) Advance(id string, version int64) (int64, bool, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, found := s.versions[id]
+	if found && version < stored {
+		return stored, true, false, nil
+	}
+	s.versions[id] = version
+	return stored, found, true, nil
+}
@@ -0,0 +1,128 @@
+package parallelarrayspolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Policy implements the policy engine interface
+// It checks that configured array fields, such as names[], emails[] and
+// phones[] in a columnar input, all have the same length. A missing field
+// counts as a mismatch unless AllowMissing is set.
+type Policy struct {
+	// Fields lists the array fields that must align
+	Fields []string `json:"fields"`
+
+	// AllowMissing ignores absent fields instead of reporting them
+	AllowMissing bool `json:"allow_missing"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "parallelarrays-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	lengths := make(map[string]int, len(p.Fields))
+	missing := []string{}
+	notArrays := []string{}
+	for _, field := range p.Fields {
+		value, exists := inputMap[field]
+		if !exists {
+			if !p.AllowMissing {
+				missing = append(missing, field)
+			}
+			continue
+		}
+
+		rv := reflect.ValueOf(value)
+		if value == nil || (rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array) {
+			notArrays = append(notArrays, field)
+			continue
+		}
+		lengths[field] = rv.Len()
+	}
+
+	distinct := make(map[int]bool)
+	for _, length := range lengths {
+		distinct[length] = true
+	}
+
+	result := policyresult.New(p.Name(), "parallel array alignment")
+	result.Data["lengths"] = lengths
+	result.Data["missing"] = missing
+	result.Data["not_arrays"] = notArrays
+
+	switch {
+	case len(missing) > 0 || len(notArrays) > 0:
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("Missing fields %v, non-array fields %v", missing, notArrays)
+	case len(distinct) > 1:
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("Array lengths differ: %s", describe(lengths))
+	default:
+		result.Status = "PASSED"
+		result.Message = "All arrays have the same length"
+	}
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	seen := make(map[string]bool, len(p.Fields))
+	for _, field := range p.Fields {
+		if seen[field] {
+			return fmt.Errorf("field %q is listed more than once", field)
+		}
+		seen[field] = true
+	}
+	return nil
+}
+
+// describe formats lengths as "a=3, b=2" in field order
+func describe(lengths map[string]int) string {
+	fields := make([]string, 0, len(lengths))
+	for field := range lengths {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	s := ""
+	for i, field := range fields {
+		if i > 0 {
+			s += ", "
+		}
+		s += fmt.Sprintf("%s=%d", field, lengths[field])
+	}
+	return s
+}
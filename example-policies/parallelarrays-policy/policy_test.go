@@ -0,0 +1,138 @@
+package parallelarrayspolicy
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+func TestExecute(t *testing.T) {
+	fields := []string{"names", "emails", "phones"}
+
+	tests := []struct {
+		name          string
+		allowMissing  bool
+		input         map[string]interface{}
+		wantStatus    string
+		wantMessage   string
+		wantLengths   map[string]int
+		wantMissing   []string
+		wantNotArrays []string
+	}{
+		{
+			name: "aligned",
+			input: map[string]interface{}{
+				"names":  []interface{}{"a", "b"},
+				"emails": []interface{}{"a@x", "b@x"},
+				"phones": []string{"1", "2"},
+			},
+			wantStatus:    "PASSED",
+			wantMessage:   "All arrays have the same length",
+			wantLengths:   map[string]int{"names": 2, "emails": 2, "phones": 2},
+			wantMissing:   []string{},
+			wantNotArrays: []string{},
+		},
+		{
+			name: "misaligned",
+			input: map[string]interface{}{
+				"names":  []interface{}{"a", "b", "c"},
+				"emails": []interface{}{"a@x", "b@x"},
+				"phones": []interface{}{"1", "2", "3"},
+			},
+			wantStatus:    "FAILED",
+			wantMessage:   "Array lengths differ: emails=2, names=3, phones=3",
+			wantLengths:   map[string]int{"names": 3, "emails": 2, "phones": 3},
+			wantMissing:   []string{},
+			wantNotArrays: []string{},
+		},
+		{
+			name: "missing and non-array fields",
+			input: map[string]interface{}{
+				"names":  []interface{}{"a"},
+				"emails": "a@x",
+			},
+			wantStatus:    "FAILED",
+			wantMessage:   "Missing fields [phones], non-array fields [emails]",
+			wantLengths:   map[string]int{"names": 1},
+			wantMissing:   []string{"phones"},
+			wantNotArrays: []string{"emails"},
+		},
+		{
+			name:         "missing allowed",
+			allowMissing: true,
+			input: map[string]interface{}{
+				"names":  []interface{}{},
+				"emails": []interface{}{},
+			},
+			wantStatus:    "PASSED",
+			wantMessage:   "All arrays have the same length",
+			wantLengths:   map[string]int{"names": 0, "emails": 0},
+			wantMissing:   []string{},
+			wantNotArrays: []string{},
+		},
+		{
+			name:          "null is not an array",
+			allowMissing:  true,
+			input:         map[string]interface{}{"names": nil},
+			wantStatus:    "FAILED",
+			wantMessage:   "Missing fields [], non-array fields [names]",
+			wantLengths:   map[string]int{},
+			wantMissing:   []string{},
+			wantNotArrays: []string{"names"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Policy{Fields: fields, AllowMissing: tt.allowMissing}
+			got, err := p.Execute(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if result.Status != tt.wantStatus || result.Message != tt.wantMessage {
+				t.Errorf("status = %s, message = %q; want %s, %q", result.Status, result.Message, tt.wantStatus, tt.wantMessage)
+			}
+			if !reflect.DeepEqual(result.Data["lengths"], tt.wantLengths) {
+				t.Errorf("lengths = %v, want %v", result.Data["lengths"], tt.wantLengths)
+			}
+			if !reflect.DeepEqual(result.Data["missing"], tt.wantMissing) {
+				t.Errorf("missing = %v, want %v", result.Data["missing"], tt.wantMissing)
+			}
+			if !reflect.DeepEqual(result.Data["not_arrays"], tt.wantNotArrays) {
+				t.Errorf("not_arrays = %v, want %v", result.Data["not_arrays"], tt.wantNotArrays)
+			}
+		})
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr bool
+	}{
+		{"zero value", map[string]interface{}{}, false},
+		{"fields", map[string]interface{}{"fields": []string{"a", "b"}, "allow_missing": true}, false},
+		{"duplicate field", map[string]interface{}{"fields": []string{"a", "a"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := (&Policy{}).Configure(tt.config); (err != nil) != tt.wantErr {
+				t.Errorf("Configure(%v) = %v, wantErr %v", tt.config, err, tt.wantErr)
+			}
+		})
+	}
+}
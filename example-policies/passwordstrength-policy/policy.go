@@ -0,0 +1,196 @@
+package passwordstrengthpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/example/policies/policyresult"
+)
+
+// commonPasswords is a small built-in blocklist, extended by configuration
+var commonPasswords = []string{
+	"123456", "12345678", "123456789", "password", "password1", "qwerty",
+	"qwerty123", "abc123", "111111", "letmein", "welcome", "iloveyou",
+	"admin", "monkey", "dragon", "football", "baseball", "sunshine",
+}
+
+// Policy implements the policy engine interface
+// It scores a password field against configurable strength rules. The
+// password itself is never included in the result.
+type Policy struct {
+	// Field names the input field holding the password (default "password")
+	Field string `json:"field"`
+
+	// MinLength is the minimum number of characters (default 8)
+	MinLength int `json:"min_length"`
+
+	RequireUpper  bool `json:"require_upper"`
+	RequireLower  bool `json:"require_lower"`
+	RequireDigit  bool `json:"require_digit"`
+	RequireSymbol bool `json:"require_symbol"`
+
+	// Blocklist adds passwords to the built-in common password list
+	Blocklist []string `json:"blocklist"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "passwordstrength-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	result := policyresult.New(p.Name(), "password strength evaluation")
+
+	value, exists := inputMap[p.field()]
+	if !exists {
+		result.Status = "PASSED"
+		result.Message = "No password to evaluate"
+		return result, nil
+	}
+
+	password, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("field %q: expected string, got %T", p.field(), value)
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+
+	length := utf8.RuneCountInString(password)
+	failed := []string{}
+	if length < p.minLength() {
+		failed = append(failed, "min_length")
+	}
+	if p.RequireUpper && !hasUpper {
+		failed = append(failed, "uppercase")
+	}
+	if p.RequireLower && !hasLower {
+		failed = append(failed, "lowercase")
+	}
+	if p.RequireDigit && !hasDigit {
+		failed = append(failed, "digit")
+	}
+	if p.RequireSymbol && !hasSymbol {
+		failed = append(failed, "symbol")
+	}
+
+	blocked := p.isBlocked(password)
+	if blocked {
+		failed = append(failed, "common_password")
+	}
+
+	// Length earns up to 40 points at twice the minimum length, and each
+	// character class present earns 15. Blocklisted passwords score zero.
+	score := 0
+	if !blocked {
+		score = length * 40 / (2 * p.minLength())
+		if score > 40 {
+			score = 40
+		}
+		for _, present := range []bool{hasUpper, hasLower, hasDigit, hasSymbol} {
+			if present {
+				score += 15
+			}
+		}
+	}
+
+	result.Data["score"] = score
+	result.Data["strength"] = strength(score)
+	result.Data["failed_rules"] = failed
+
+	if len(failed) > 0 {
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("Password failed rules: %v", failed)
+	} else {
+		result.Status = "PASSED"
+		result.Message = "Password meets all rules"
+	}
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	if p.MinLength < 0 {
+		return fmt.Errorf("min_length must not be negative, got %d", p.MinLength)
+	}
+	return nil
+}
+
+func (p *Policy) field() string {
+	if p.Field == "" {
+		return "password"
+	}
+	return p.Field
+}
+
+func (p *Policy) minLength() int {
+	if p.MinLength == 0 {
+		return 8
+	}
+	return p.MinLength
+}
+
+// isBlocked reports whether the password is on the built-in or configured
+// blocklist, ignoring case
+func (p *Policy) isBlocked(password string) bool {
+	for _, list := range [][]string{commonPasswords, p.Blocklist} {
+		for _, common := range list {
+			if strings.EqualFold(password, common) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// strength maps a 0-100 score onto a coarse label
+func strength(score int) string {
+	switch {
+	case score >= 70:
+		return "strong"
+	case score >= 40:
+		return "medium"
+	default:
+		return "weak"
+	}
+}
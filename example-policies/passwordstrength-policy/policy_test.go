@@ -0,0 +1,143 @@
+package passwordstrengthpolicy
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name         string
+		password     string
+		wantScore    int
+		wantStrength string
+		wantFailed   []string
+	}{
+		{
+			name:         "weak",
+			password:     "abc",
+			wantScore:    7 + 15,
+			wantStrength: "weak",
+			wantFailed:   []string{"min_length", "uppercase", "digit", "symbol"},
+		},
+		{
+			name:         "medium",
+			password:     "applepie12",
+			wantScore:    25 + 30,
+			wantStrength: "medium",
+			wantFailed:   []string{"uppercase", "symbol"},
+		},
+		{
+			name:         "strong",
+			password:     "Tr0ub4dor&3x",
+			wantScore:    30 + 60,
+			wantStrength: "strong",
+			wantFailed:   []string{},
+		},
+		{
+			name:         "long passphrase caps length points",
+			password:     "Correct horse battery staple 9",
+			wantScore:    40 + 60,
+			wantStrength: "strong",
+			wantFailed:   []string{},
+		},
+		{
+			name:         "blocklisted common password ignores case",
+			password:     "PassWord1",
+			wantScore:    0,
+			wantStrength: "weak",
+			wantFailed:   []string{"symbol", "common_password"},
+		},
+		{
+			name:         "configured blocklist",
+			password:     "Acme-Corp-2024",
+			wantScore:    0,
+			wantStrength: "weak",
+			wantFailed:   []string{"common_password"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Policy{
+				RequireUpper:  true,
+				RequireLower:  true,
+				RequireDigit:  true,
+				RequireSymbol: true,
+				Blocklist:     []string{"acme-corp-2024"},
+			}
+			got, err := p.Execute(context.Background(), map[string]interface{}{"password": tt.password})
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if result.Data["score"] != tt.wantScore {
+				t.Errorf("score = %v, want %d", result.Data["score"], tt.wantScore)
+			}
+			if result.Data["strength"] != tt.wantStrength {
+				t.Errorf("strength = %v, want %s", result.Data["strength"], tt.wantStrength)
+			}
+			if !reflect.DeepEqual(result.Data["failed_rules"], tt.wantFailed) {
+				t.Errorf("failed_rules = %v, want %v", result.Data["failed_rules"], tt.wantFailed)
+			}
+			wantStatus := "PASSED"
+			if len(tt.wantFailed) > 0 {
+				wantStatus = "FAILED"
+			}
+			if result.Status != wantStatus {
+				t.Errorf("status = %s, want %s", result.Status, wantStatus)
+			}
+			if strings.Contains(result.Message, tt.password) {
+				t.Errorf("message %q leaks the password", result.Message)
+			}
+		})
+	}
+}
+
+func TestExecuteCountsRunes(t *testing.T) {
+	// Eight characters but sixteen bytes
+	p := &Policy{Field: "secret"}
+	got, err := p.Execute(context.Background(), map[string]interface{}{"secret": "пароль12"})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if failed := got.(*policyresult.Result).Data["failed_rules"]; !reflect.DeepEqual(failed, []string{}) {
+		t.Errorf("failed_rules = %v, want none", failed)
+	}
+}
+
+func TestExecuteMissingAndInvalid(t *testing.T) {
+	got, err := (&Policy{}).Execute(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result := got.(*policyresult.Result); result.Status != "PASSED" {
+		t.Errorf("missing password status = %s, want PASSED", result.Status)
+	}
+
+	if _, err := (&Policy{}).Execute(context.Background(), map[string]interface{}{"password": 12345678}); err == nil {
+		t.Error("non-string password succeeded, want error")
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Policy{}).Validate(); err != nil {
+		t.Errorf("zero value: %v", err)
+	}
+	if err := (&Policy{MinLength: -1}).Validate(); err == nil {
+		t.Error("negative min_length passed validation")
+	}
+}
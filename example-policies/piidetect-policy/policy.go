@@ -0,0 +1,179 @@
+package piidetectpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/example/policies/policyresult"
+)
+
+// builtinDetectors are the PII patterns available by name
+var builtinDetectors = map[string]*regexp.Regexp{
+	"email": regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+	"phone": regexp.MustCompile(`(?:\+?\d{1,3}[\s.-]?)?(?:\(\d{3}\)|\d{3})[\s.-]?\d{3}[\s.-]?\d{4}\b`),
+	"ssn":   regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`),
+}
+
+// Policy implements the policy engine interface
+// It scans every string in the input for values that look like PII and
+// reports where they were found. Nothing is masked; this is for
+// compliance scanning.
+type Policy struct {
+	// Detectors lists the built-in detectors to run (default: all)
+	Detectors []string `json:"detectors"`
+
+	// CustomDetectors adds named regular expressions to the detector set
+	CustomDetectors map[string]string `json:"custom_detectors"`
+
+	detectors map[string]*regexp.Regexp
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "piidetect-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	detectors, err := p.compile()
+	if err != nil {
+		return err
+	}
+	p.detectors = detectors
+	return nil
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	result := policyresult.New(p.Name(), "pii detection")
+
+	detectors := p.detectors
+	if detectors == nil {
+		detectors = builtinDetectors
+	}
+
+	names := make([]string, 0, len(detectors))
+	for name := range detectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	findings := []map[string]interface{}{}
+	scan(inputMap, "", func(path, s string) {
+		for _, name := range names {
+			if count := len(detectors[name].FindAllStringIndex(s, -1)); count > 0 {
+				findings = append(findings, map[string]interface{}{
+					"field": path,
+					"type":  name,
+					"count": count,
+				})
+			}
+		}
+	})
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i]["field"].(string) < findings[j]["field"].(string)
+	})
+
+	result.Data["detectors"] = names
+	result.Data["findings"] = findings
+
+	if len(findings) > 0 {
+		result.Status = "DETECTED"
+		result.Message = fmt.Sprintf("Found possible PII in %d location(s)", len(findings))
+	} else {
+		result.Status = "CLEAN"
+		result.Message = "No PII detected"
+	}
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	for _, name := range p.Detectors {
+		if _, ok := builtinDetectors[name]; !ok {
+			if _, custom := p.CustomDetectors[name]; !custom {
+				return fmt.Errorf("unknown detector %q", name)
+			}
+		}
+	}
+	for name, pattern := range p.CustomDetectors {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("custom detector %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// compile builds the active detector set from the configuration
+func (p *Policy) compile() (map[string]*regexp.Regexp, error) {
+	detectors := make(map[string]*regexp.Regexp)
+	if len(p.Detectors) == 0 {
+		for name, re := range builtinDetectors {
+			detectors[name] = re
+		}
+	}
+	for _, name := range p.Detectors {
+		if re, ok := builtinDetectors[name]; ok {
+			detectors[name] = re
+		}
+	}
+	for name, pattern := range p.CustomDetectors {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("custom detector %q: %w", name, err)
+		}
+		detectors[name] = re
+	}
+	return detectors, nil
+}
+
+// scan calls fn for every string in value, with its dotted path
+func scan(value interface{}, path string, fn func(path, s string)) {
+	switch v := value.(type) {
+	case string:
+		fn(path, v)
+	case []string:
+		for i, s := range v {
+			fn(fmt.Sprintf("%s[%d]", path, i), s)
+		}
+	case []interface{}:
+		for i, item := range v {
+			scan(item, fmt.Sprintf("%s[%d]", path, i), fn)
+		}
+	case map[string]interface{}:
+		for key, item := range v {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			scan(item, childPath, fn)
+		}
+	}
+}
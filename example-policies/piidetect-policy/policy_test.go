@@ -0,0 +1,135 @@
+package piidetectpolicy
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+// finding builds an expected finding
+func finding(field, kind string, count int) map[string]interface{} {
+	return map[string]interface{}{"field": field, "type": kind, "count": count}
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        map[string]interface{}
+		wantFindings []map[string]interface{}
+	}{
+		{
+			name: "no pii",
+			input: map[string]interface{}{
+				"title":   "Quarterly report",
+				"version": "1.2.3",
+				"count":   5551234567,
+				"tags":    []string{"finance", "q3"},
+			},
+			wantFindings: []map[string]interface{}{},
+		},
+		{
+			name: "mixed content",
+			input: map[string]interface{}{
+				"note":    "Contact jane.doe@example.com or (555) 123-4567 for details",
+				"ssn":     "SSN on file: 123-45-6789",
+				"comment": "nothing to see here",
+			},
+			wantFindings: []map[string]interface{}{
+				finding("note", "email", 1),
+				finding("note", "phone", 1),
+				finding("ssn", "ssn", 1),
+			},
+		},
+		{
+			name: "nested values and slices",
+			input: map[string]interface{}{
+				"user": map[string]interface{}{
+					"emails": []interface{}{"a@example.org", "not an email", "b@example.co.uk c@example.net"},
+				},
+				"phones": []string{"+1 555.123.4567", "ext 12"},
+			},
+			wantFindings: []map[string]interface{}{
+				finding("phones[0]", "phone", 1),
+				finding("user.emails[0]", "email", 1),
+				finding("user.emails[2]", "email", 2),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := (&Policy{}).Execute(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if !reflect.DeepEqual(result.Data["findings"], tt.wantFindings) {
+				t.Errorf("findings = %v, want %v", result.Data["findings"], tt.wantFindings)
+			}
+			wantStatus := "CLEAN"
+			if len(tt.wantFindings) > 0 {
+				wantStatus = "DETECTED"
+			}
+			if result.Status != wantStatus {
+				t.Errorf("status = %s, want %s", result.Status, wantStatus)
+			}
+		})
+	}
+}
+
+func TestConfigureDetectors(t *testing.T) {
+	p := &Policy{}
+	err := p.Configure(map[string]interface{}{
+		"detectors":        []string{"email", "employee_id"},
+		"custom_detectors": map[string]string{"employee_id": `\bEMP-\d{5}\b`},
+	})
+	if err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+
+	input := map[string]interface{}{"text": "EMP-00042 at x@example.com, SSN 123-45-6789"}
+	got, err := p.Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	result := got.(*policyresult.Result)
+	if want := []string{"email", "employee_id"}; !reflect.DeepEqual(result.Data["detectors"], want) {
+		t.Errorf("detectors = %v, want %v", result.Data["detectors"], want)
+	}
+	want := []map[string]interface{}{finding("text", "email", 1), finding("text", "employee_id", 1)}
+	if !reflect.DeepEqual(result.Data["findings"], want) {
+		t.Errorf("findings = %v, want %v", result.Data["findings"], want)
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  Policy
+		wantErr bool
+	}{
+		{"zero value", Policy{}, false},
+		{"builtin subset", Policy{Detectors: []string{"ssn"}}, false},
+		{"unknown detector", Policy{Detectors: []string{"passport"}}, true},
+		{"invalid custom pattern", Policy{CustomDetectors: map[string]string{"bad": "("}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policy.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
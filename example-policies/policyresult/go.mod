@@ -0,0 +1,3 @@
+module github.com/example/policies/policyresult
+
+go 1.21
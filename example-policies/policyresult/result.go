@@ -0,0 +1,37 @@
+// Package policyresult provides the result type shared by the example
+// policies. It has the same fields and JSON tags as the engine's
+// PolicyResult, which policies cannot import because the engine is a
+// main package.
+package policyresult
+
+// Result is the recommended shape for policy results
+type Result struct {
+	// Policy is the name of the policy that produced the result
+	Policy string `json:"policy"`
+
+	// Action briefly describes what the policy did
+	Action string `json:"action"`
+
+	// Status is the outcome, such as "PASSED" or "FAILED"
+	Status string `json:"status,omitempty"`
+
+	// Message explains the outcome for humans
+	Message string `json:"message,omitempty"`
+
+	// Output is the rewritten input of a transforming policy; pipelines
+	// pass it on to the next stage
+	Output map[string]interface{} `json:"output,omitempty"`
+
+	// Data holds any further policy-specific details
+	Data map[string]interface{} `json:"data,omitempty"`
+}
+
+// New returns a result for the named policy and action with an empty Data
+// map, ready for details to be added
+func New(policy, action string) *Result {
+	return &Result{
+		Policy: policy,
+		Action: action,
+		Data:   make(map[string]interface{}),
+	}
+}
@@ -0,0 +1,55 @@
+package policyresult
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	result := New("p", "check")
+	if result.Policy != "p" || result.Action != "check" {
+		t.Errorf("New = %+v, want policy p and action check", result)
+	}
+	if result.Data == nil {
+		t.Fatal("Data is nil, want an empty map")
+	}
+	result.Data["k"] = "v"
+}
+
+func TestResultJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		result *Result
+		want   string
+	}{
+		{
+			name:   "new result omits empty fields",
+			result: New("p", "check"),
+			want:   `{"policy":"p","action":"check"}`,
+		},
+		{
+			name: "all fields use the engine's keys",
+			result: &Result{
+				Policy:  "p",
+				Action:  "check",
+				Status:  "FAILED",
+				Message: "bad",
+				Output:  map[string]interface{}{"a": 1},
+				Data:    map[string]interface{}{"b": 2},
+			},
+			want: `{"policy":"p","action":"check","status":"FAILED","message":"bad","output":{"a":1},"data":{"b":2}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.result)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("Marshal = %s, want %s", data, tt.want)
+			}
+		})
+	}
+}
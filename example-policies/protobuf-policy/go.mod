@@ -0,0 +1,10 @@
+module github.com/example/policies/protobuf-policy
+
+go 1.21
+
+require (
+	github.com/example/policies/policyresult v0.0.0
+	google.golang.org/protobuf v1.36.5
+)
+
+replace github.com/example/policies/policyresult => ../policyresult
@@ -0,0 +1,198 @@
+package protobufpolicy
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	// Register the well-known types so they can be decoded by name
+	_ "google.golang.org/protobuf/types/known/anypb"
+	_ "google.golang.org/protobuf/types/known/durationpb"
+	_ "google.golang.org/protobuf/types/known/structpb"
+	_ "google.golang.org/protobuf/types/known/timestamppb"
+	_ "google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Policy implements the policy engine interface
+// It decodes base64-encoded protobuf bytes from a configured field into a
+// JSON-style map, bridging protobuf producers into the engine
+type Policy struct {
+	// Field names the input field holding base64-encoded protobuf bytes
+	// (default "payload")
+	Field string `json:"field"`
+
+	// MessageType is the fully-qualified message name, e.g. "google.protobuf.Struct"
+	MessageType string `json:"message_type"`
+
+	// DescriptorFile optionally points at a serialized FileDescriptorSet
+	// (as produced by protoc --descriptor_set_out) that defines MessageType.
+	// When empty, MessageType must be registered in the binary.
+	DescriptorFile string `json:"descriptor_file"`
+
+	messageType protoreflect.MessageType
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "protobuf-policy"
+}
+
+// Configure applies the given configuration to the policy and resolves
+// the configured message type
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	p.messageType = nil
+	if p.MessageType == "" {
+		return nil
+	}
+
+	messageType, err := resolveMessageType(p.MessageType, p.DescriptorFile)
+	if err != nil {
+		return fmt.Errorf("failed to resolve message type %q: %w", p.MessageType, err)
+	}
+	p.messageType = messageType
+	return nil
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	result := policyresult.New(p.Name(), "protobuf decoding")
+	result.Data["message_type"] = p.MessageType
+
+	value, exists := inputMap[p.field()]
+	if !exists {
+		result.Status = "PASSED"
+		result.Message = "No protobuf payload to decode"
+		return result, nil
+	}
+	if p.messageType == nil {
+		return nil, fmt.Errorf("no message type configured")
+	}
+
+	encoded, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("field %q: expected base64 string, got %T", p.field(), value)
+	}
+
+	decoded, err := p.decode(encoded)
+	if err != nil {
+		result.Status = "FAILED"
+		result.Message = err.Error()
+		return result, nil
+	}
+
+	result.Status = "PASSED"
+	result.Message = "Payload decoded"
+	result.Data["decoded"] = decoded
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	if p.DescriptorFile != "" && p.MessageType == "" {
+		return fmt.Errorf("message_type is required when descriptor_file is set")
+	}
+	return nil
+}
+
+func (p *Policy) field() string {
+	if p.Field == "" {
+		return "payload"
+	}
+	return p.Field
+}
+
+// decode unmarshals base64-encoded protobuf bytes into a JSON-style map
+func (p *Policy) decode(encoded string) (map[string]interface{}, error) {
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64: %w", err)
+	}
+
+	msg := p.messageType.New().Interface()
+	if err := proto.Unmarshal(raw, msg); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", p.MessageType, err)
+	}
+
+	jsonData, err := protojson.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %s to JSON: %w", p.MessageType, err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(jsonData, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to convert %s to JSON: %w", p.MessageType, err)
+	}
+
+	return decoded, nil
+}
+
+// resolveMessageType finds a message type either in a descriptor set file
+// or in the global registry of types linked into the binary
+func resolveMessageType(name, descriptorFile string) (protoreflect.MessageType, error) {
+	fullName := protoreflect.FullName(name)
+	if descriptorFile == "" {
+		return protoregistry.GlobalTypes.FindMessageByName(fullName)
+	}
+
+	data, err := os.ReadFile(descriptorFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("invalid descriptor set: %w", err)
+	}
+
+	files, err := protodesc.NewFiles(&set)
+	if err != nil {
+		return nil, fmt.Errorf("invalid descriptor set: %w", err)
+	}
+
+	desc, err := files.FindDescriptorByName(fullName)
+	if err != nil {
+		return nil, err
+	}
+
+	msgDesc, ok := desc.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a message", name)
+	}
+
+	return dynamicpb.NewMessageType(msgDesc), nil
+}
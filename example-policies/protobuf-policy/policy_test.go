@@ -0,0 +1,213 @@
+package protobufpolicy
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/example/policies/policyresult"
+)
+
+// userFile describes a test.User message with a name and an age
+var userFile = &descriptorpb.FileDescriptorProto{
+	Name:    proto.String("user.proto"),
+	Package: proto.String("test"),
+	Syntax:  proto.String("proto3"),
+	MessageType: []*descriptorpb.DescriptorProto{{
+		Name: proto.String("User"),
+		Field: []*descriptorpb.FieldDescriptorProto{
+			{
+				Name:     proto.String("name"),
+				JsonName: proto.String("name"),
+				Number:   proto.Int32(1),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+			},
+			{
+				Name:     proto.String("age"),
+				JsonName: proto.String("age"),
+				Number:   proto.Int32(2),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type:     descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum(),
+			},
+		},
+	}},
+}
+
+// writeDescriptorSet writes a FileDescriptorSet holding userFile and
+// returns its path
+func writeDescriptorSet(t *testing.T) string {
+	t.Helper()
+	data, err := proto.Marshal(&descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{userFile}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "user.pb")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// encodeUser returns a base64-encoded test.User
+func encodeUser(t *testing.T, name string, age int32) string {
+	t.Helper()
+	file, err := protodesc.NewFile(userFile, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	desc := file.Messages().ByName("User")
+	msg := dynamicpb.NewMessage(desc)
+	msg.Set(desc.Fields().ByName("name"), protoreflect.ValueOfString(name))
+	msg.Set(desc.Fields().ByName("age"), protoreflect.ValueOfInt32(age))
+	return encode(t, msg)
+}
+
+func encode(t *testing.T, msg proto.Message) string {
+	t.Helper()
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+func TestExecute(t *testing.T) {
+	descriptorFile := writeDescriptorSet(t)
+	greeting, err := structpb.NewStruct(map[string]interface{}{"greeting": "hello", "count": 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		config      map[string]interface{}
+		input       map[string]interface{}
+		wantStatus  string
+		wantDecoded map[string]interface{}
+		wantMessage string
+	}{
+		{
+			name:        "linked-in message type",
+			config:      map[string]interface{}{"message_type": "google.protobuf.Struct"},
+			input:       map[string]interface{}{"payload": encode(t, greeting)},
+			wantStatus:  "PASSED",
+			wantDecoded: map[string]interface{}{"greeting": "hello", "count": 2.0},
+		},
+		{
+			name: "descriptor file message type",
+			config: map[string]interface{}{
+				"field":           "user",
+				"message_type":    "test.User",
+				"descriptor_file": descriptorFile,
+			},
+			input:       map[string]interface{}{"user": encodeUser(t, "ada", 36)},
+			wantStatus:  "PASSED",
+			wantDecoded: map[string]interface{}{"name": "ada", "age": 36.0},
+		},
+		{
+			name:       "missing field",
+			config:     map[string]interface{}{"message_type": "google.protobuf.Struct"},
+			input:      map[string]interface{}{"other": "x"},
+			wantStatus: "PASSED",
+		},
+		{
+			name:        "invalid base64",
+			config:      map[string]interface{}{"message_type": "google.protobuf.Struct"},
+			input:       map[string]interface{}{"payload": "not base64!"},
+			wantStatus:  "FAILED",
+			wantMessage: "invalid base64",
+		},
+		{
+			name:        "malformed protobuf bytes",
+			config:      map[string]interface{}{"message_type": "google.protobuf.Struct"},
+			input:       map[string]interface{}{"payload": base64.StdEncoding.EncodeToString([]byte{0x0a, 0xff, 0xff})},
+			wantStatus:  "FAILED",
+			wantMessage: "failed to decode google.protobuf.Struct",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Policy{}
+			if err := p.Configure(tt.config); err != nil {
+				t.Fatalf("Configure: %v", err)
+			}
+			got, err := p.Execute(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if result.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s (%s)", result.Status, tt.wantStatus, result.Message)
+			}
+			if tt.wantDecoded != nil && !reflect.DeepEqual(result.Data["decoded"], tt.wantDecoded) {
+				t.Errorf("decoded = %v, want %v", result.Data["decoded"], tt.wantDecoded)
+			}
+			if !strings.HasPrefix(result.Message, tt.wantMessage) {
+				t.Errorf("message = %q, want prefix %q", result.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestConfigureErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config map[string]interface{}
+	}{
+		{"unknown linked-in type", map[string]interface{}{"message_type": "test.Missing"}},
+		{"unknown descriptor type", map[string]interface{}{"message_type": "test.Missing", "descriptor_file": writeDescriptorSet(t)}},
+		{"missing descriptor file", map[string]interface{}{"message_type": "test.User", "descriptor_file": filepath.Join(t.TempDir(), "missing.pb")}},
+		{"descriptor file without type", map[string]interface{}{"descriptor_file": "user.pb"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := (&Policy{}).Configure(tt.config); err == nil {
+				t.Error("Configure succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	unconfigured := &Policy{}
+	if _, err := unconfigured.Execute(context.Background(), map[string]interface{}{"payload": ""}); err == nil {
+		t.Error("Execute without a message type succeeded, want error")
+	}
+
+	p := &Policy{}
+	if err := p.Configure(map[string]interface{}{"message_type": "google.protobuf.Struct"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Execute(context.Background(), map[string]interface{}{"payload": 42}); err == nil {
+		t.Error("Execute with a non-string payload succeeded, want error")
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Policy{}).Validate(); err != nil {
+		t.Errorf("zero value: %v", err)
+	}
+}
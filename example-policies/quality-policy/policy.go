@@ -0,0 +1,266 @@
+package qualitypolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Points awarded to a field for being present and non-empty; the rest of a
+// field's 100 points are split evenly between its validity checks
+const completenessPoints = 40
+
+// FieldRule describes the checks applied to one field
+type FieldRule struct {
+	// Field is the input field name
+	Field string `json:"field"`
+
+	// Type is the expected JSON type: string, number, bool, array or object
+	Type string `json:"type"`
+
+	// Pattern is a regular expression string values must match
+	Pattern string `json:"pattern"`
+
+	// Min and Max bound numeric values, or the length of strings and arrays
+	Min *float64 `json:"min"`
+	Max *float64 `json:"max"`
+
+	// Weight is the field's share of the overall score (default 1)
+	Weight float64 `json:"weight"`
+
+	pattern *regexp.Regexp
+}
+
+// Policy implements the policy engine interface
+// It scores configured fields from 0 to 100 for completeness and validity
+// and combines them into a weighted overall score, listing the factors that
+// cost each field points. It is meant for monitoring data pipelines, so it
+// fails only when MinScore is set and the overall score falls below it.
+type Policy struct {
+	// Rules lists the scored fields
+	Rules []FieldRule `json:"rules"`
+
+	// MinScore is the lowest passing overall score; 0 never fails
+	MinScore float64 `json:"min_score"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "quality-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	fields := make(map[string]interface{}, len(p.Rules))
+	var weighted, totalWeight float64
+	for i := range p.Rules {
+		rule := &p.Rules[i]
+		score, factors := rule.score(inputMap)
+
+		fields[rule.Field] = map[string]interface{}{
+			"score":   score,
+			"factors": factors,
+		}
+		weighted += score * rule.weight()
+		totalWeight += rule.weight()
+	}
+
+	overall := 100.0
+	if totalWeight > 0 {
+		overall = math.Round(weighted/totalWeight*10) / 10
+	}
+
+	result := policyresult.New(p.Name(), "data quality scoring")
+	result.Data["score"] = overall
+	result.Data["fields"] = fields
+
+	if p.MinScore > 0 && overall < p.MinScore {
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("Quality score %.1f is below %.1f", overall, p.MinScore)
+	} else {
+		result.Status = "PASSED"
+		result.Message = fmt.Sprintf("Quality score %.1f", overall)
+	}
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid and compiles the
+// rule patterns
+func (p *Policy) Validate() error {
+	if p.MinScore < 0 || p.MinScore > 100 {
+		return fmt.Errorf("min_score must be between 0 and 100, got %v", p.MinScore)
+	}
+	for i := range p.Rules {
+		rule := &p.Rules[i]
+		if rule.Field == "" {
+			return fmt.Errorf("rule %d: field is required", i)
+		}
+		switch rule.Type {
+		case "", "string", "number", "bool", "array", "object":
+		default:
+			return fmt.Errorf("rule %s: unsupported type %q", rule.Field, rule.Type)
+		}
+		if rule.Weight < 0 {
+			return fmt.Errorf("rule %s: weight must not be negative", rule.Field)
+		}
+		rule.pattern = nil
+		if rule.Pattern != "" {
+			re, err := regexp.Compile(rule.Pattern)
+			if err != nil {
+				return fmt.Errorf("rule %s: invalid pattern: %w", rule.Field, err)
+			}
+			rule.pattern = re
+		}
+	}
+	return nil
+}
+
+func (r *FieldRule) weight() float64 {
+	if r.Weight == 0 {
+		return 1
+	}
+	return r.Weight
+}
+
+// score returns the field's 0-100 score and the reasons it lost points
+func (r *FieldRule) score(inputMap map[string]interface{}) (float64, []string) {
+	factors := []string{}
+
+	value, exists := inputMap[r.Field]
+	if !exists || value == nil {
+		return 0, append(factors, "missing")
+	}
+	if isEmpty(value) {
+		return 0, append(factors, "empty")
+	}
+
+	checks, passed := 0, 0
+	check := func(ok bool, factor string) {
+		checks++
+		if ok {
+			passed++
+		} else {
+			factors = append(factors, factor)
+		}
+	}
+
+	if r.Type != "" {
+		actual := typeOf(value)
+		check(actual == r.Type, fmt.Sprintf("expected %s, got %s", r.Type, actual))
+	}
+	if r.pattern != nil {
+		s, ok := value.(string)
+		check(ok && r.pattern.MatchString(s), "does not match pattern")
+	}
+	if r.Min != nil || r.Max != nil {
+		size, ok := measure(value)
+		switch {
+		case !ok:
+			check(false, "cannot be range checked")
+		case r.Min != nil && size < *r.Min:
+			check(false, fmt.Sprintf("below minimum %v", *r.Min))
+		case r.Max != nil && size > *r.Max:
+			check(false, fmt.Sprintf("above maximum %v", *r.Max))
+		default:
+			check(true, "")
+		}
+	}
+
+	if checks == 0 {
+		return 100, factors
+	}
+	validity := float64(100-completenessPoints) * float64(passed) / float64(checks)
+	return math.Round((completenessPoints+validity)*10) / 10, factors
+}
+
+func isEmpty(value interface{}) bool {
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v) == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
+
+func typeOf(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case float64, float32, int, int32, int64, uint, uint32, uint64, json.Number:
+		return "number"
+	case bool:
+		return "bool"
+	case []interface{}, []string:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// measure returns a number's value or a string's or array's length
+func measure(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case string:
+		return float64(len([]rune(v))), true
+	case []interface{}:
+		return float64(len(v)), true
+	case []string:
+		return float64(len(v)), true
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
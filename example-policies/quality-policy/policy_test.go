@@ -0,0 +1,186 @@
+package qualitypolicy
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+// customerRules scores a customer record; email counts twice
+func customerRules() map[string]interface{} {
+	return map[string]interface{}{
+		"min_score": 50,
+		"rules": []interface{}{
+			map[string]interface{}{"field": "email", "type": "string", "pattern": "^[^@]+@[^@]+$", "weight": 2},
+			map[string]interface{}{"field": "age", "type": "number", "min": 0, "max": 150},
+			map[string]interface{}{"field": "tags", "type": "array", "min": 1},
+			map[string]interface{}{"field": "name"},
+		},
+	}
+}
+
+// fieldScore is the expected score and factors of one field
+type fieldScore struct {
+	score   float64
+	factors []string
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      map[string]interface{}
+		wantStatus string
+		wantScore  float64
+		wantFields map[string]fieldScore
+	}{
+		{
+			name: "complete and valid",
+			input: map[string]interface{}{
+				"email": "ada@example.com",
+				"age":   36,
+				"tags":  []interface{}{"vip"},
+				"name":  "Ada",
+			},
+			wantStatus: "PASSED",
+			wantScore:  100,
+			wantFields: map[string]fieldScore{
+				"email": {100, []string{}},
+				"age":   {100, []string{}},
+				"tags":  {100, []string{}},
+				"name":  {100, []string{}},
+			},
+		},
+		{
+			name: "present but partly invalid",
+			input: map[string]interface{}{
+				"email": "not-an-email",
+				"age":   200.0,
+				"tags":  []interface{}{"vip"},
+				"name":  "Ada",
+			},
+			wantStatus: "PASSED",
+			// (70*2 + 70 + 100 + 100) / 5
+			wantScore: 82,
+			wantFields: map[string]fieldScore{
+				"email": {70, []string{"does not match pattern"}},
+				"age":   {70, []string{"above maximum 150"}},
+				"tags":  {100, []string{}},
+				"name":  {100, []string{}},
+			},
+		},
+		{
+			name: "sparse and low quality",
+			input: map[string]interface{}{
+				"age":  true,
+				"tags": []interface{}{},
+				"name": "  ",
+			},
+			wantStatus: "FAILED",
+			// (0*2 + 40 + 0 + 0) / 5
+			wantScore: 8,
+			wantFields: map[string]fieldScore{
+				"email": {0, []string{"missing"}},
+				"age":   {40, []string{"expected number, got bool", "cannot be range checked"}},
+				"tags":  {0, []string{"empty"}},
+				"name":  {0, []string{"empty"}},
+			},
+		},
+		{
+			name:       "null counts as missing",
+			input:      map[string]interface{}{"email": nil, "age": -1, "tags": []interface{}{1, 2}, "name": "Ada"},
+			wantStatus: "PASSED",
+			// (0*2 + 70 + 100 + 100) / 5
+			wantScore: 54,
+			wantFields: map[string]fieldScore{
+				"email": {0, []string{"missing"}},
+				"age":   {70, []string{"below minimum 0"}},
+				"tags":  {100, []string{}},
+				"name":  {100, []string{}},
+			},
+		},
+	}
+
+	p := &Policy{}
+	if err := p.Configure(customerRules()); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.Execute(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if result.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s (%s)", result.Status, tt.wantStatus, result.Message)
+			}
+			if result.Data["score"] != tt.wantScore {
+				t.Errorf("score = %v, want %v", result.Data["score"], tt.wantScore)
+			}
+
+			fields := result.Data["fields"].(map[string]interface{})
+			for name, want := range tt.wantFields {
+				field := fields[name].(map[string]interface{})
+				if field["score"] != want.score || !reflect.DeepEqual(field["factors"], want.factors) {
+					t.Errorf("%s = %v %v, want %v %v", name, field["score"], field["factors"], want.score, want.factors)
+				}
+			}
+		})
+	}
+}
+
+func TestExecuteNoRules(t *testing.T) {
+	got, err := (&Policy{}).Execute(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result := got.(*policyresult.Result); result.Status != "PASSED" || result.Data["score"] != 100.0 {
+		t.Errorf("result = %s %v, want PASSED 100", result.Status, result.Data["score"])
+	}
+
+	if _, err := (&Policy{}).Execute(context.Background(), []interface{}{}); err == nil {
+		t.Error("Execute accepted a non-map input")
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr bool
+	}{
+		{"valid", customerRules(), false},
+		{"min score above 100", map[string]interface{}{"min_score": 101}, true},
+		{"negative min score", map[string]interface{}{"min_score": -1}, true},
+		{"rule without field", map[string]interface{}{"rules": []interface{}{map[string]interface{}{"type": "string"}}}, true},
+		{"unknown type", map[string]interface{}{"rules": []interface{}{map[string]interface{}{"field": "a", "type": "date"}}}, true},
+		{"negative weight", map[string]interface{}{"rules": []interface{}{map[string]interface{}{"field": "a", "weight": -1}}}, true},
+		{"invalid pattern", map[string]interface{}{"rules": []interface{}{map[string]interface{}{"field": "a", "pattern": "("}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&Policy{}).Configure(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Configure error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Policy{}).Validate(); err != nil {
+		t.Errorf("zero value: %v", err)
+	}
+}
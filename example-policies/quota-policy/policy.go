@@ -0,0 +1,188 @@
+package quotapolicy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/example/policies/policyresult"
+)
+
+// ErrQuotaExceeded is returned when a tenant has used up its quota for the
+// current window
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+type tenantKey struct{}
+
+// WithTenant returns a context carrying the tenant that executions are
+// charged to. The key is private to this package, so only Go programs that
+// import it and call WithTenant can set it; the engine binary cannot, and
+// executions through its CLI, HTTP and gRPC front ends always fall back to
+// the input field.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant set by WithTenant, if any
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenant, ok := ctx.Value(tenantKey{}).(string)
+	return tenant, ok && tenant != ""
+}
+
+// Policy implements the policy engine interface
+// It limits how many executions each tenant may perform per fixed time
+// window. The tenant comes from the context (see WithTenant) or, failing
+// that, from a configured input field.
+//
+// The input field is chosen by the caller, so the fallback is not a
+// security boundary: a caller can spread executions across made-up tenants
+// or charge them to someone else's. It keeps well-behaved callers within
+// their quota; enforcing a quota against untrusted callers needs a trusted
+// layer in front of the engine that sets or overwrites the tenant field
+// from the authenticated identity.
+type Policy struct {
+	// Limit is the number of executions allowed per tenant per window;
+	// zero disables the quota
+	Limit int `json:"limit"`
+
+	// Window is the quota window as a Go duration string (default "1m")
+	Window string `json:"window"`
+
+	// TenantField names the input field used when the context carries no
+	// tenant (default "tenant"). Its value is caller-supplied and trusted
+	// as is.
+	TenantField string `json:"tenant_field"`
+
+	// Store holds the counters; it defaults to a MemoryStore
+	Store Store `json:"-"`
+
+	// Now returns the current time; it defaults to time.Now
+	Now func() time.Time `json:"-"`
+
+	once         sync.Once
+	defaultStore Store
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "quota-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	result := policyresult.New(p.Name(), "tenant quota enforcement")
+
+	if p.Limit == 0 {
+		result.Status = "PASSED"
+		result.Message = "No quota configured"
+		return result, nil
+	}
+
+	tenant, ok := TenantFromContext(ctx)
+	if !ok {
+		tenant, ok = inputMap[p.tenantField()].(string)
+		if !ok || tenant == "" {
+			return nil, fmt.Errorf("no tenant in context or field %q", p.tenantField())
+		}
+	}
+
+	window, err := p.window()
+	if err != nil {
+		return nil, err
+	}
+	windowStart := p.now().Truncate(window)
+
+	count, err := p.store().Increment(tenant, windowStart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record execution for tenant %s: %w", tenant, err)
+	}
+	if count > p.Limit {
+		return nil, fmt.Errorf("tenant %s used %d of %d executions in window starting %s: %w",
+			tenant, count, p.Limit, windowStart.Format(time.RFC3339), ErrQuotaExceeded)
+	}
+
+	result.Status = "PASSED"
+	result.Data["tenant"] = tenant
+	result.Data["used"] = count
+	result.Data["limit"] = p.Limit
+	result.Data["remaining"] = p.Limit - count
+	result.Data["window_start"] = windowStart.Format(time.RFC3339)
+	result.Message = fmt.Sprintf("Tenant %s has %d execution(s) remaining", tenant, p.Limit-count)
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	if p.Limit < 0 {
+		return fmt.Errorf("limit must not be negative, got %d", p.Limit)
+	}
+	if _, err := p.window(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (p *Policy) window() (time.Duration, error) {
+	if p.Window == "" {
+		return time.Minute, nil
+	}
+	d, err := time.ParseDuration(p.Window)
+	if err != nil {
+		return 0, fmt.Errorf("invalid window %q: %w", p.Window, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("window must be positive, got %s", p.Window)
+	}
+	return d, nil
+}
+
+func (p *Policy) tenantField() string {
+	if p.TenantField == "" {
+		return "tenant"
+	}
+	return p.TenantField
+}
+
+func (p *Policy) store() Store {
+	if p.Store != nil {
+		return p.Store
+	}
+	p.once.Do(func() {
+		p.defaultStore = NewMemoryStore()
+	})
+	return p.defaultStore
+}
+
+func (p *Policy) now() time.Time {
+	if p.Now != nil {
+		return p.Now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,198 @@
+package quotapolicy
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/example/policies/policyresult"
+)
+
+// clock is a settable time source for tests
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+// newTestPolicy returns a policy allowing limit executions per minute,
+// with its clock at the start of a window
+func newTestPolicy(limit int) (*Policy, *clock) {
+	c := &clock{now: time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)}
+	return &Policy{Limit: limit, Window: "1m", Now: c.Now}, c
+}
+
+func TestExecuteQuota(t *testing.T) {
+	p, _ := newTestPolicy(3)
+	input := map[string]interface{}{"tenant": "acme"}
+
+	// Under quota, then exactly at quota
+	for used := 1; used <= 3; used++ {
+		got, err := p.Execute(context.Background(), input)
+		if err != nil {
+			t.Fatalf("execution %d: %v", used, err)
+		}
+		result := got.(*policyresult.Result)
+		if result.Data["used"] != used || result.Data["remaining"] != 3-used {
+			t.Errorf("execution %d: used = %v, remaining = %v", used, result.Data["used"], result.Data["remaining"])
+		}
+	}
+
+	// Over quota
+	if _, err := p.Execute(context.Background(), input); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("execution 4: error = %v, want ErrQuotaExceeded", err)
+	}
+
+	// Other tenants have their own quota
+	if _, err := p.Execute(context.Background(), map[string]interface{}{"tenant": "globex"}); err != nil {
+		t.Errorf("other tenant: %v", err)
+	}
+}
+
+func TestExecuteWindowReset(t *testing.T) {
+	p, c := newTestPolicy(1)
+	input := map[string]interface{}{"tenant": "acme"}
+
+	if _, err := p.Execute(context.Background(), input); err != nil {
+		t.Fatal(err)
+	}
+
+	// Still inside the same window
+	c.now = c.now.Add(59 * time.Second)
+	if _, err := p.Execute(context.Background(), input); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("same window: error = %v, want ErrQuotaExceeded", err)
+	}
+
+	// The next window starts from zero
+	c.now = c.now.Add(time.Second)
+	got, err := p.Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("next window: %v", err)
+	}
+	result := got.(*policyresult.Result)
+	if result.Data["used"] != 1 {
+		t.Errorf("used = %v, want 1", result.Data["used"])
+	}
+	if want := "2024-01-15T10:31:00Z"; result.Data["window_start"] != want {
+		t.Errorf("window_start = %v, want %s", result.Data["window_start"], want)
+	}
+}
+
+func TestExecuteContextTenant(t *testing.T) {
+	p, _ := newTestPolicy(1)
+	ctx := WithTenant(context.Background(), "acme")
+
+	// The context tenant wins over the input field
+	got, err := p.Execute(ctx, map[string]interface{}{"tenant": "globex"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tenant := got.(*policyresult.Result).Data["tenant"]; tenant != "acme" {
+		t.Errorf("tenant = %v, want acme", tenant)
+	}
+	if _, err := p.Execute(ctx, map[string]interface{}{}); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("error = %v, want ErrQuotaExceeded", err)
+	}
+}
+
+func TestExecuteNoTenant(t *testing.T) {
+	p, _ := newTestPolicy(1)
+	p.TenantField = "org"
+	for _, input := range []map[string]interface{}{{}, {"org": ""}, {"org": 7}, {"tenant": "acme"}} {
+		if _, err := p.Execute(context.Background(), input); err == nil {
+			t.Errorf("input %v: succeeded without a tenant", input)
+		}
+	}
+}
+
+func TestExecuteNoLimit(t *testing.T) {
+	got, err := (&Policy{}).Execute(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result := got.(*policyresult.Result); result.Status != "PASSED" {
+		t.Errorf("status = %s, want PASSED", result.Status)
+	}
+}
+
+func TestMemoryStorePrunesExpiredWindows(t *testing.T) {
+	s := NewMemoryStore()
+	first := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+	second := first.Add(time.Minute)
+
+	for _, tenant := range []string{"acme", "globex", "initech"} {
+		if _, err := s.Increment(tenant, first); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(s.windows) != 3 {
+		t.Fatalf("windows = %d, want 3", len(s.windows))
+	}
+
+	// The first call in a later window drops every tenant idle since the first
+	if _, err := s.Increment("acme", second); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.windows) != 1 {
+		t.Errorf("windows after new window = %d, want 1", len(s.windows))
+	}
+
+	// A late call for an earlier window is kept until the next window begins
+	if _, err := s.Increment("globex", first); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Increment("acme", second); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.windows) != 2 {
+		t.Errorf("windows = %d, want 2", len(s.windows))
+	}
+}
+
+// failingStore is a Store that always fails
+type failingStore struct{}
+
+func (failingStore) Increment(string, time.Time) (int, error) {
+	return 0, errors.New("store unavailable")
+}
+
+func TestExecuteStoreError(t *testing.T) {
+	p, _ := newTestPolicy(1)
+	p.Store = failingStore{}
+	if _, err := p.Execute(context.Background(), map[string]interface{}{"tenant": "acme"}); err == nil {
+		t.Error("Execute succeeded with a failing store")
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  *Policy
+		wantErr bool
+	}{
+		{"zero value", &Policy{}, false},
+		{"configured", &Policy{Limit: 10, Window: "1h"}, false},
+		{"negative limit", &Policy{Limit: -1}, true},
+		{"malformed window", &Policy{Window: "soon"}, true},
+		{"zero window", &Policy{Window: "0s"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policy.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
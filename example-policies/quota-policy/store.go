@@ -0,0 +1,65 @@
+package quotapolicy
+
+import (
+	"sync"
+	"time"
+)
+
+// Store records per-tenant execution counts for fixed time windows
+type Store interface {
+	// Increment adds one execution for tenant in the window starting at
+	// windowStart and returns the updated count for that window
+	Increment(tenant string, windowStart time.Time) (int, error)
+}
+
+// MemoryStore is an in-process Store that keeps only the current window
+// for each tenant. Windows that have ended are dropped the first time a later
+// window is seen, so idle tenants do not accumulate. It is safe for concurrent
+// use.
+type MemoryStore struct {
+	mu      sync.Mutex
+	windows map[string]window
+	current time.Time // latest window start seen
+}
+
+type window struct {
+	start time.Time
+	count int
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		windows: make(map[string]window),
+	}
+}
+
+// Increment implements Store
+func (s *MemoryStore) Increment(tenant string, windowStart time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prune(windowStart)
+	w := s.windows[tenant]
+	if !w.start.Equal(windowStart) {
+		w = window{start: windowStart}
+	}
+	w.count++
+	s.windows[tenant] = w
+
+	return w.count, nil
+}
+
+// prune drops every window that started before windowStart. It only scans
+// the map when a new window begins, so the cost is paid once per window.
+func (s *MemoryStore) prune(windowStart time.Time) {
+	if !windowStart.After(s.current) {
+		return
+	}
+	s.current = windowStart
+	for tenant, w := range s.windows {
+		if w.start.Before(windowStart) {
+			delete(s.windows, tenant)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package ratechangepolicy
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/example/policies/policyresult"
+)
+
+// defaultMaxKeys bounds the number of keys tracked at once when MaxKeys is
+// not set
+const defaultMaxKeys = 10000
+
+// Policy implements the policy engine interface
+// It tracks a numeric field across successive inputs for each key and flags
+// anomalous jumps: a change from the previous value larger than MaxChange,
+// or larger than MaxChangePercent of the previous value. The first value
+// seen for a key only establishes the baseline. Flagged values still become
+// the new baseline, so a sustained shift is reported once. Keys come from
+// the input, so the number of keys tracked is capped and, optionally, idle
+// keys are dropped; a dropped key starts over with a new baseline.
+type Policy struct {
+	// Field names the numeric input field to track
+	Field string `json:"field"`
+
+	// KeyField names the input field identifying the series (default "id");
+	// inputs without it share the "default" series
+	KeyField string `json:"key_field"`
+
+	// MaxChange is the largest allowed absolute change
+	MaxChange *float64 `json:"max_change"`
+
+	// MaxChangePercent is the largest allowed change relative to the
+	// previous value, in percent
+	MaxChangePercent *float64 `json:"max_change_percent"`
+
+	// MaxKeys caps the number of keys tracked (default 10000). Once
+	// reached, the least recently updated key is dropped.
+	MaxKeys int `json:"max_keys"`
+
+	// IdleTimeout drops keys not updated within this Go duration when set
+	IdleTimeout string `json:"idle_timeout"`
+
+	// Now returns the current time; it defaults to time.Now
+	Now func() time.Time `json:"-"`
+
+	mu     sync.Mutex
+	series map[string]*list.Element
+	// order holds *series values, most recently updated first
+	order *list.List
+}
+
+// series is the tracked state for one key
+type series struct {
+	key      string
+	last     float64
+	lastSeen time.Time
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "ratechange-policy"
+}
+
+// Configure applies the given configuration to the policy and resets all
+// tracked values
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	p.mu.Lock()
+	p.series = nil
+	p.order = nil
+	p.mu.Unlock()
+
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	result := policyresult.New(p.Name(), "rate of change check")
+
+	raw, exists := inputMap[p.Field]
+	if p.Field == "" || !exists {
+		result.Status = "PASSED"
+		result.Message = "No value to track"
+		return result, nil
+	}
+	value, ok := toFloat(raw)
+	if !ok {
+		return nil, fmt.Errorf("field %q: expected a number, got %T", p.Field, raw)
+	}
+	key := p.key(inputMap)
+	idleTimeout, err := p.idleTimeout()
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	s, seen := p.lookup(key, p.now(), idleTimeout)
+	previous := s.last
+	s.last = value
+	p.mu.Unlock()
+
+	result.Data["key"] = key
+	result.Data["value"] = value
+
+	if !seen {
+		result.Status = "PASSED"
+		result.Message = fmt.Sprintf("Baseline recorded for %s", key)
+		return result, nil
+	}
+
+	change := value - previous
+	result.Data["previous"] = previous
+	result.Data["change"] = change
+
+	var violations []string
+	if p.MaxChange != nil && math.Abs(change) > *p.MaxChange {
+		violations = append(violations, fmt.Sprintf("change %g exceeds %g", change, *p.MaxChange))
+	}
+	if p.MaxChangePercent != nil {
+		if previous == 0 {
+			if change != 0 {
+				violations = append(violations, "change from zero has no finite percentage")
+			}
+		} else {
+			percent := math.Abs(change/previous) * 100
+			result.Data["change_percent"] = percent
+			if percent > *p.MaxChangePercent {
+				violations = append(violations, fmt.Sprintf("change %.2f%% exceeds %g%%", percent, *p.MaxChangePercent))
+			}
+		}
+	}
+
+	if len(violations) > 0 {
+		result.Status = "ANOMALY"
+		result.Data["violations"] = violations
+		result.Message = fmt.Sprintf("Anomalous change for %s: %v", key, violations)
+	} else {
+		result.Status = "PASSED"
+		result.Message = fmt.Sprintf("Change for %s within limits", key)
+	}
+
+	return result, nil
+}
+
+// lookup returns the state for key, creating it if needed, and marks it as
+// updated at now; seen is false for a new key. Keys idle for longer than
+// idleTimeout are dropped first, then the least recently updated ones
+// beyond MaxKeys. The caller must hold p.mu.
+func (p *Policy) lookup(key string, now time.Time, idleTimeout time.Duration) (s *series, seen bool) {
+	if p.series == nil {
+		p.series = make(map[string]*list.Element)
+		p.order = list.New()
+	}
+
+	if idleTimeout > 0 {
+		cutoff := now.Add(-idleTimeout)
+		for oldest := p.order.Back(); oldest != nil && oldest.Value.(*series).lastSeen.Before(cutoff); oldest = p.order.Back() {
+			p.order.Remove(oldest)
+			delete(p.series, oldest.Value.(*series).key)
+		}
+	}
+
+	elem, seen := p.series[key]
+	if seen {
+		p.order.MoveToFront(elem)
+	} else {
+		elem = p.order.PushFront(&series{key: key})
+		p.series[key] = elem
+		for p.order.Len() > p.maxKeys() {
+			oldest := p.order.Back()
+			p.order.Remove(oldest)
+			delete(p.series, oldest.Value.(*series).key)
+		}
+	}
+
+	s = elem.Value.(*series)
+	s.lastSeen = now
+	return s, seen
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	if p.MaxChange != nil && *p.MaxChange < 0 {
+		return fmt.Errorf("max_change must not be negative, got %v", *p.MaxChange)
+	}
+	if p.MaxChangePercent != nil && *p.MaxChangePercent < 0 {
+		return fmt.Errorf("max_change_percent must not be negative, got %v", *p.MaxChangePercent)
+	}
+	if p.MaxKeys < 0 {
+		return fmt.Errorf("max_keys must not be negative, got %d", p.MaxKeys)
+	}
+	if _, err := p.idleTimeout(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (p *Policy) key(inputMap map[string]interface{}) string {
+	field := p.KeyField
+	if field == "" {
+		field = "id"
+	}
+	value, exists := inputMap[field]
+	if !exists || value == nil {
+		return "default"
+	}
+	return fmt.Sprint(value)
+}
+
+func (p *Policy) maxKeys() int {
+	if p.MaxKeys == 0 {
+		return defaultMaxKeys
+	}
+	return p.MaxKeys
+}
+
+func (p *Policy) idleTimeout() (time.Duration, error) {
+	if p.IdleTimeout == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(p.IdleTimeout)
+	if err != nil {
+		return 0, fmt.Errorf("invalid idle_timeout %q: %w", p.IdleTimeout, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("idle_timeout must be positive, got %s", p.IdleTimeout)
+	}
+	return d, nil
+}
+
+func (p *Policy) now() time.Time {
+	if p.Now != nil {
+		return p.Now()
+	}
+	return time.Now()
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,204 @@
+package ratechangepolicy
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/example/policies/policyresult"
+)
+
+// clock is a settable time source for tests
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func float64p(f float64) *float64 {
+	return &f
+}
+
+// feed executes p with value for key and returns the result
+func feed(t *testing.T, p *Policy, key string, value float64) *policyresult.Result {
+	t.Helper()
+	got, err := p.Execute(context.Background(), map[string]interface{}{"id": key, "reading": value})
+	if err != nil {
+		t.Fatalf("Execute(%v): %v", value, err)
+	}
+	return got.(*policyresult.Result)
+}
+
+func TestExecuteSequence(t *testing.T) {
+	p := &Policy{Field: "reading", MaxChange: float64p(10), MaxChangePercent: float64p(50)}
+
+	steps := []struct {
+		value      float64
+		wantStatus string
+		wantChange interface{}
+	}{
+		{100, "PASSED", nil}, // baseline
+		{105, "PASSED", 5.0},
+		{98, "PASSED", -7.0},
+		{140, "ANOMALY", 42.0}, // spike
+		{145, "PASSED", 5.0},   // the spike is the new baseline
+		{40, "ANOMALY", -105.0},
+	}
+
+	for i, step := range steps {
+		result := feed(t, p, "sensor-1", step.value)
+		if result.Status != step.wantStatus {
+			t.Errorf("step %d (%v): status = %s, want %s", i, step.value, result.Status, step.wantStatus)
+		}
+		if result.Data["change"] != step.wantChange {
+			t.Errorf("step %d (%v): change = %v, want %v", i, step.value, result.Data["change"], step.wantChange)
+		}
+	}
+}
+
+func TestExecutePercentLimit(t *testing.T) {
+	tests := []struct {
+		name           string
+		previous       float64
+		value          float64
+		wantStatus     string
+		wantViolations int
+	}{
+		{"within percent", 200, 250, "PASSED", 0},
+		{"over percent", 200, 350, "ANOMALY", 1},
+		{"from zero", 0, 1, "ANOMALY", 1},
+		{"zero to zero", 0, 0, "PASSED", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Policy{Field: "reading", MaxChangePercent: float64p(50)}
+			feed(t, p, "k", tt.previous)
+			result := feed(t, p, "k", tt.value)
+			if result.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", result.Status, tt.wantStatus)
+			}
+			violations, _ := result.Data["violations"].([]string)
+			if len(violations) != tt.wantViolations {
+				t.Errorf("violations = %v, want %d", violations, tt.wantViolations)
+			}
+		})
+	}
+}
+
+func TestExecuteKeysAreIndependent(t *testing.T) {
+	p := &Policy{Field: "reading", MaxChange: float64p(10)}
+	feed(t, p, "a", 100)
+	feed(t, p, "b", 500)
+	if result := feed(t, p, "a", 105); result.Status != "PASSED" || result.Data["previous"] != 100.0 {
+		t.Errorf("key a: status = %s, previous = %v; want PASSED, 100", result.Status, result.Data["previous"])
+	}
+
+	// Inputs without a key share the default series
+	got, err := p.Execute(context.Background(), map[string]interface{}{"reading": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key := got.(*policyresult.Result).Data["key"]; key != "default" {
+		t.Errorf("key = %v, want default", key)
+	}
+}
+
+func TestExecuteMaxKeys(t *testing.T) {
+	p := &Policy{Field: "reading", MaxChange: float64p(10), MaxKeys: 2}
+	feed(t, p, "a", 1)
+	feed(t, p, "b", 2)
+	feed(t, p, "a", 1) // a is now the most recently updated
+	feed(t, p, "c", 3) // evicts b
+
+	if len(p.series) != 2 {
+		t.Errorf("tracking %d keys, want 2", len(p.series))
+	}
+	if result := feed(t, p, "a", 1); result.Data["previous"] != 1.0 {
+		t.Errorf("key a previous = %v, want 1 (kept)", result.Data["previous"])
+	}
+	if result := feed(t, p, "b", 100); result.Status != "PASSED" || result.Data["previous"] != nil {
+		t.Errorf("key b: status = %s, previous = %v; want a new baseline", result.Status, result.Data["previous"])
+	}
+}
+
+func TestExecuteIdleTimeout(t *testing.T) {
+	c := &clock{now: time.Date(2024, time.January, 15, 10, 0, 0, 0, time.UTC)}
+	p := &Policy{Field: "reading", MaxChange: float64p(10), IdleTimeout: "10m", Now: c.Now}
+	feed(t, p, "idle", 1)
+	c.now = c.now.Add(5 * time.Minute)
+	feed(t, p, "busy", 1)
+
+	c.now = c.now.Add(6 * time.Minute)
+	feed(t, p, "busy", 2)
+	if _, ok := p.series["idle"]; ok {
+		t.Error("key idle for 11m still tracked with a 10m idle timeout")
+	}
+	if result := feed(t, p, "busy", 3); result.Data["previous"] != 2.0 {
+		t.Errorf("busy previous = %v, want 2", result.Data["previous"])
+	}
+}
+
+func TestConfigureResetsKeys(t *testing.T) {
+	p := &Policy{}
+	config := map[string]interface{}{"field": "reading", "max_change": 10}
+	if err := p.Configure(config); err != nil {
+		t.Fatal(err)
+	}
+	feed(t, p, "a", 1)
+	if err := p.Configure(config); err != nil {
+		t.Fatal(err)
+	}
+	if result := feed(t, p, "a", 100); result.Status != "PASSED" || result.Data["previous"] != nil {
+		t.Errorf("after reconfiguring: status = %s, previous = %v; want a new baseline", result.Status, result.Data["previous"])
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	p := &Policy{Field: "reading"}
+	if _, err := p.Execute(context.Background(), map[string]interface{}{"reading": "high"}); err == nil {
+		t.Error("non-numeric value succeeded, want error")
+	}
+	got, err := p.Execute(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := got.(*policyresult.Result); result.Status != "PASSED" {
+		t.Errorf("missing value status = %s, want PASSED", result.Status)
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr bool
+	}{
+		{"zero value", map[string]interface{}{}, false},
+		{"limits", map[string]interface{}{"field": "x", "max_change": 5, "max_change_percent": 20}, false},
+		{"negative max_change", map[string]interface{}{"max_change": -1}, true},
+		{"negative max_change_percent", map[string]interface{}{"max_change_percent": -1}, true},
+		{"negative max_keys", map[string]interface{}{"max_keys": -1}, true},
+		{"zero idle_timeout", map[string]interface{}{"idle_timeout": "0s"}, true},
+		{"malformed idle_timeout", map[string]interface{}{"idle_timeout": "soon"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := (&Policy{}).Configure(tt.config); (err != nil) != tt.wantErr {
+				t.Errorf("Configure(%v) = %v, wantErr %v", tt.config, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,264 @@
+package recursionguardpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Policy implements the policy engine interface
+// It detects cycles in self-referential data where objects point at each
+// other by ID, so traversal-based policies can reject such input instead of
+// looping forever. The input holds an array of nodes; each node has an ID
+// and one or more reference fields naming other node IDs. Traversal stops
+// at MaxDepth and the deepest chains are reported.
+type Policy struct {
+	// NodesField names the input array of nodes (default "nodes")
+	NodesField string `json:"nodes_field"`
+
+	// IDField names the node ID field (default "id")
+	IDField string `json:"id_field"`
+
+	// RefFields lists the node fields holding a referenced ID or an array
+	// of IDs (default ["parent"])
+	RefFields []string `json:"ref_fields"`
+
+	// MaxDepth is the longest reference chain followed (default 100)
+	MaxDepth int `json:"max_depth"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "recursionguard-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	result := policyresult.New(p.Name(), "recursion guard")
+
+	rawNodes, exists := inputMap[p.nodesField()]
+	if !exists {
+		result.Status = "PASSED"
+		result.Message = "No nodes to check"
+		return result, nil
+	}
+	nodes, ok := rawNodes.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("field %q: expected array of objects, got %T", p.nodesField(), rawNodes)
+	}
+
+	graph, unresolved, err := p.buildGraph(nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &guard{
+		graph:    graph,
+		maxDepth: p.maxDepth(),
+		state:    make(map[string]int),
+		cycles:   [][]string{},
+		tooDeep:  [][]string{},
+	}
+	ids := make([]string, 0, len(graph))
+	for id := range graph {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if g.state[id] == unvisited {
+			g.visit(id, nil)
+		}
+	}
+
+	result.Data["cycles"] = g.cycles
+	result.Data["depth_exceeded"] = g.tooDeep
+	result.Data["unresolved"] = unresolved
+
+	switch {
+	case len(g.cycles) > 0:
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("Detected %d reference cycle(s)", len(g.cycles))
+	case len(g.tooDeep) > 0:
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("Reference chains exceed max depth %d", p.maxDepth())
+	default:
+		result.Status = "PASSED"
+		result.Message = "No reference cycles"
+	}
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	if p.MaxDepth < 0 {
+		return fmt.Errorf("max_depth must not be negative, got %d", p.MaxDepth)
+	}
+	return nil
+}
+
+func (p *Policy) nodesField() string {
+	if p.NodesField == "" {
+		return "nodes"
+	}
+	return p.NodesField
+}
+
+func (p *Policy) idField() string {
+	if p.IDField == "" {
+		return "id"
+	}
+	return p.IDField
+}
+
+func (p *Policy) refFields() []string {
+	if len(p.RefFields) == 0 {
+		return []string{"parent"}
+	}
+	return p.RefFields
+}
+
+func (p *Policy) maxDepth() int {
+	if p.MaxDepth == 0 {
+		return 100
+	}
+	return p.MaxDepth
+}
+
+// buildGraph maps each node ID to the IDs it references. References to IDs
+// not present in the input are returned separately as "from->to" pairs.
+func (p *Policy) buildGraph(nodes []interface{}) (map[string][]string, []string, error) {
+	graph := make(map[string][]string, len(nodes))
+	for i, raw := range nodes {
+		node, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, nil, fmt.Errorf("node %d: expected object, got %T", i, raw)
+		}
+		id, ok := toID(node[p.idField()])
+		if !ok {
+			return nil, nil, fmt.Errorf("node %d: missing or invalid %q", i, p.idField())
+		}
+		if _, dup := graph[id]; dup {
+			return nil, nil, fmt.Errorf("node %d: duplicate id %q", i, id)
+		}
+
+		refs := []string{}
+		for _, field := range p.refFields() {
+			switch value := node[field].(type) {
+			case nil:
+			case []interface{}:
+				for _, item := range value {
+					if ref, ok := toID(item); ok {
+						refs = append(refs, ref)
+					}
+				}
+			default:
+				if ref, ok := toID(value); ok {
+					refs = append(refs, ref)
+				}
+			}
+		}
+		graph[id] = refs
+	}
+
+	unresolved := []string{}
+	for id, refs := range graph {
+		for _, ref := range refs {
+			if _, ok := graph[ref]; !ok {
+				unresolved = append(unresolved, id+"->"+ref)
+			}
+		}
+	}
+	sort.Strings(unresolved)
+	return graph, unresolved, nil
+}
+
+// Visit states for the depth-first search
+const (
+	unvisited = iota
+	inProgress
+	done
+)
+
+// guard walks the reference graph depth first. A reference back to a node
+// still on the current path closes a cycle.
+type guard struct {
+	graph    map[string][]string
+	maxDepth int
+	state    map[string]int
+	cycles   [][]string
+	tooDeep  [][]string
+}
+
+func (g *guard) visit(id string, path []string) {
+	path = append(path, id)
+	if len(path) > g.maxDepth {
+		g.tooDeep = append(g.tooDeep, append([]string(nil), path...))
+		return
+	}
+
+	g.state[id] = inProgress
+	for _, ref := range g.graph[id] {
+		if _, ok := g.graph[ref]; !ok {
+			continue
+		}
+		switch g.state[ref] {
+		case inProgress:
+			g.cycles = append(g.cycles, cycleFrom(path, ref))
+		case unvisited:
+			g.visit(ref, path)
+		}
+	}
+	g.state[id] = done
+}
+
+// cycleFrom returns the part of path starting at ref, closed by ref again
+func cycleFrom(path []string, ref string) []string {
+	for i, id := range path {
+		if id == ref {
+			cycle := append([]string(nil), path[i:]...)
+			return append(cycle, ref)
+		}
+	}
+	return []string{ref, ref}
+}
+
+func toID(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, v != ""
+	case float64, int, int64, json.Number:
+		return fmt.Sprint(v), true
+	default:
+		return "", false
+	}
+}
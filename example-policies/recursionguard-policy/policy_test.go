@@ -0,0 +1,176 @@
+package recursionguardpolicy
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+// node builds a node with the default id and parent fields
+func node(id string, parent interface{}) map[string]interface{} {
+	n := map[string]interface{}{"id": id}
+	if parent != nil {
+		n["parent"] = parent
+	}
+	return n
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name           string
+		policy         *Policy
+		nodes          []interface{}
+		wantStatus     string
+		wantCycles     [][]string
+		wantTooDeep    [][]string
+		wantUnresolved []string
+	}{
+		{
+			name:       "acyclic chain",
+			policy:     &Policy{},
+			nodes:      []interface{}{node("root", nil), node("child", "root"), node("grandchild", "child")},
+			wantStatus: "PASSED",
+		},
+		{
+			name:   "acyclic diamond",
+			policy: &Policy{RefFields: []string{"deps"}},
+			nodes: []interface{}{
+				map[string]interface{}{"id": "a", "deps": []interface{}{"b", "c"}},
+				map[string]interface{}{"id": "b", "deps": []interface{}{"d"}},
+				map[string]interface{}{"id": "c", "deps": []interface{}{"d"}},
+				map[string]interface{}{"id": "d"},
+			},
+			wantStatus: "PASSED",
+		},
+		{
+			name:       "self reference",
+			policy:     &Policy{},
+			nodes:      []interface{}{node("a", "a")},
+			wantStatus: "FAILED",
+			wantCycles: [][]string{{"a", "a"}},
+		},
+		{
+			name:       "three node cycle",
+			policy:     &Policy{},
+			nodes:      []interface{}{node("a", "b"), node("b", "c"), node("c", "a"), node("d", "a")},
+			wantStatus: "FAILED",
+			wantCycles: [][]string{{"a", "b", "c", "a"}},
+		},
+		{
+			name:   "cycles through several reference fields",
+			policy: &Policy{IDField: "key", NodesField: "items", RefFields: []string{"next", "links"}},
+			nodes: []interface{}{
+				map[string]interface{}{"key": 1, "next": 2},
+				map[string]interface{}{"key": 2, "links": []interface{}{1, 3}},
+				map[string]interface{}{"key": 3, "next": 3},
+			},
+			wantStatus: "FAILED",
+			wantCycles: [][]string{{"1", "2", "1"}, {"3", "3"}},
+		},
+		{
+			name:           "unresolved references are not cycles",
+			policy:         &Policy{},
+			nodes:          []interface{}{node("a", "missing"), node("b", "a")},
+			wantStatus:     "PASSED",
+			wantUnresolved: []string{"a->missing"},
+		},
+		{
+			name:        "chain deeper than max depth",
+			policy:      &Policy{MaxDepth: 2},
+			nodes:       []interface{}{node("a", "b"), node("b", "c"), node("c", "d"), node("d", nil)},
+			wantStatus:  "FAILED",
+			wantTooDeep: [][]string{{"a", "b", "c"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.policy.Execute(context.Background(), map[string]interface{}{tt.policy.nodesField(): tt.nodes})
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if result.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s (%s)", result.Status, tt.wantStatus, result.Message)
+			}
+			if got := result.Data["cycles"].([][]string); len(got)+len(tt.wantCycles) > 0 && !reflect.DeepEqual(got, tt.wantCycles) {
+				t.Errorf("cycles = %v, want %v", got, tt.wantCycles)
+			}
+			if got := result.Data["depth_exceeded"].([][]string); len(got)+len(tt.wantTooDeep) > 0 && !reflect.DeepEqual(got, tt.wantTooDeep) {
+				t.Errorf("depth_exceeded = %v, want %v", got, tt.wantTooDeep)
+			}
+			if got := result.Data["unresolved"].([]string); len(got)+len(tt.wantUnresolved) > 0 && !reflect.DeepEqual(got, tt.wantUnresolved) {
+				t.Errorf("unresolved = %v, want %v", got, tt.wantUnresolved)
+			}
+		})
+	}
+}
+
+func TestExecuteLongCycleTerminates(t *testing.T) {
+	// A ring longer than the default max depth is cut off rather than
+	// followed forever
+	const size = 500
+	nodes := make([]interface{}, size)
+	for i := range nodes {
+		nodes[i] = map[string]interface{}{"id": i, "parent": (i + 1) % size}
+	}
+
+	got, err := (&Policy{}).Execute(context.Background(), map[string]interface{}{"nodes": nodes})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result := got.(*policyresult.Result); result.Status != "FAILED" {
+		t.Errorf("status = %s, want FAILED (%s)", result.Status, result.Message)
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input interface{}
+	}{
+		{"not a map", []interface{}{}},
+		{"nodes not an array", map[string]interface{}{"nodes": "a"}},
+		{"node not an object", map[string]interface{}{"nodes": []interface{}{"a"}}},
+		{"missing id", map[string]interface{}{"nodes": []interface{}{map[string]interface{}{"parent": "a"}}}},
+		{"duplicate id", map[string]interface{}{"nodes": []interface{}{node("a", nil), node("a", nil)}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := (&Policy{}).Execute(context.Background(), tt.input); err == nil {
+				t.Error("Execute succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestExecuteNoNodes(t *testing.T) {
+	got, err := (&Policy{}).Execute(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result := got.(*policyresult.Result); result.Status != "PASSED" {
+		t.Errorf("status = %s, want PASSED", result.Status)
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Policy{}).Validate(); err != nil {
+		t.Errorf("zero value: %v", err)
+	}
+	if err := (&Policy{}).Configure(map[string]interface{}{"max_depth": -1}); err == nil {
+		t.Error("Configure accepted a negative max_depth")
+	}
+}
@@ -0,0 +1,37 @@
+package reorderpolicy
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// OrderedMap is a JSON object that serializes its keys in Keys order,
+// unlike map[string]interface{} which encoding/json always sorts
+type OrderedMap struct {
+	Keys   []string
+	Values map[string]interface{}
+}
+
+// MarshalJSON implements json.Marshaler
+func (m *OrderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.Keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		keyJSON, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		valueJSON, err := json.Marshal(m.Values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(keyJSON)
+		buf.WriteByte(':')
+		buf.Write(valueJSON)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,103 @@
+package reorderpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Policy implements the policy engine interface
+// It orders the top-level keys of the input for serialization: configured
+// keys first, in the configured order, followed by the remaining keys in
+// sorted order. The ordered result is an OrderedMap, which keeps that
+// order when encoded as JSON, and the serialized form is returned
+// alongside it.
+type Policy struct {
+	// Order lists the keys that lead the output
+	Order []string `json:"order"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "reorder-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	keys := make([]string, 0, len(inputMap))
+	placed := make(map[string]bool, len(p.Order))
+	for _, key := range p.Order {
+		if _, exists := inputMap[key]; exists {
+			keys = append(keys, key)
+			placed[key] = true
+		}
+	}
+
+	rest := make([]string, 0, len(inputMap)-len(keys))
+	for key := range inputMap {
+		if !placed[key] {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+	keys = append(keys, rest...)
+
+	output := &OrderedMap{Keys: keys, Values: inputMap}
+	serialized, err := json.Marshal(output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize output: %w", err)
+	}
+
+	// Output carries the fields on to later pipeline stages, which see
+	// plain objects; the ordered form keeps the key order when encoded
+	return &policyresult.Result{
+		Policy:  p.Name(),
+		Action:  "key reordering",
+		Status:  "PASSED",
+		Message: fmt.Sprintf("Ordered %d key(s)", len(keys)),
+		Output:  inputMap,
+		Data: map[string]interface{}{
+			"order":      keys,
+			"ordered":    output,
+			"serialized": string(serialized),
+		},
+	}, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	seen := make(map[string]bool, len(p.Order))
+	for _, key := range p.Order {
+		if seen[key] {
+			return fmt.Errorf("key %q appears more than once in order", key)
+		}
+		seen[key] = true
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package reorderpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+func TestExecute(t *testing.T) {
+	input := map[string]interface{}{
+		"zeta":  1.0,
+		"alpha": "a",
+		"id":    "42",
+		"name":  "widget",
+		"meta":  map[string]interface{}{"b": 2.0, "a": 1.0},
+	}
+
+	tests := []struct {
+		name           string
+		order          []string
+		wantOrder      []string
+		wantSerialized string
+	}{
+		{
+			name:           "configured keys first",
+			order:          []string{"id", "name"},
+			wantOrder:      []string{"id", "name", "alpha", "meta", "zeta"},
+			wantSerialized: `{"id":"42","name":"widget","alpha":"a","meta":{"a":1,"b":2},"zeta":1}`,
+		},
+		{
+			name:           "absent keys skipped",
+			order:          []string{"missing", "zeta", "id"},
+			wantOrder:      []string{"zeta", "id", "alpha", "meta", "name"},
+			wantSerialized: `{"zeta":1,"id":"42","alpha":"a","meta":{"a":1,"b":2},"name":"widget"}`,
+		},
+		{
+			name:           "no order sorts all keys",
+			wantOrder:      []string{"alpha", "id", "meta", "name", "zeta"},
+			wantSerialized: `{"alpha":"a","id":"42","meta":{"a":1,"b":2},"name":"widget","zeta":1}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Policy{Order: tt.order}
+			got, err := p.Execute(context.Background(), input)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if !reflect.DeepEqual(result.Data["order"], tt.wantOrder) {
+				t.Errorf("order = %v, want %v", result.Data["order"], tt.wantOrder)
+			}
+			if result.Data["serialized"] != tt.wantSerialized {
+				t.Errorf("serialized = %s, want %s", result.Data["serialized"], tt.wantSerialized)
+			}
+
+			// The ordered map keeps the order wherever it is encoded
+			encoded, err := json.Marshal(result.Data["ordered"])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(encoded) != tt.wantSerialized {
+				t.Errorf("encoded ordered map = %s, want %s", encoded, tt.wantSerialized)
+			}
+			if !reflect.DeepEqual(result.Output, input) {
+				t.Errorf("output = %v, want the input fields", result.Output)
+			}
+		})
+	}
+}
+
+func TestOrderedMapEscapesKeys(t *testing.T) {
+	m := &OrderedMap{Keys: []string{`a"b`, "<"}, Values: map[string]interface{}{`a"b`: 1, "<": nil}}
+	encoded, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"a\"b":1,"\u003c":null}`; string(encoded) != want {
+		t.Errorf("encoded = %s, want %s", encoded, want)
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr bool
+	}{
+		{"zero value", map[string]interface{}{}, false},
+		{"order", map[string]interface{}{"order": []string{"id", "name"}}, false},
+		{"duplicate key", map[string]interface{}{"order": []string{"id", "id"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := (&Policy{}).Configure(tt.config); (err != nil) != tt.wantErr {
+				t.Errorf("Configure(%v) = %v, wantErr %v", tt.config, err, tt.wantErr)
+			}
+		})
+	}
+}
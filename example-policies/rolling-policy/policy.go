@@ -0,0 +1,345 @@
+package rollingpolicy
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/example/policies/policyresult"
+)
+
+// defaultMaxStreams bounds the number of streams tracked at once when
+// MaxStreams is not set
+const defaultMaxStreams = 10000
+
+// Policy implements the policy engine interface
+// It keeps a rolling window of a numeric field across successive inputs
+// and emits the running sum and average, flagging when the configured
+// aggregate crosses a threshold. Each stream (identified by a configured
+// input field) has its own window. Stream IDs come from the input, so the
+// number of streams tracked is capped and, optionally, idle streams are
+// dropped; a dropped stream starts over with an empty window.
+type Policy struct {
+	// Field names the numeric input field to aggregate
+	Field string `json:"field"`
+
+	// StreamField names the input field identifying the stream (default "stream")
+	StreamField string `json:"stream_field"`
+
+	// Size keeps the last Size values when set
+	Size int `json:"size"`
+
+	// Duration keeps values seen within this Go duration when set
+	Duration string `json:"duration"`
+
+	// Threshold is compared against the aggregate named by ThresholdOn
+	Threshold *float64 `json:"threshold"`
+
+	// ThresholdOn is "average" (default) or "sum"
+	ThresholdOn string `json:"threshold_on"`
+
+	// MaxStreams caps the number of streams tracked (default 10000). Once
+	// reached, the least recently updated stream is dropped.
+	MaxStreams int `json:"max_streams"`
+
+	// IdleTimeout drops streams not updated within this Go duration when set
+	IdleTimeout string `json:"idle_timeout"`
+
+	// Now returns the current time; it defaults to time.Now
+	Now func() time.Time `json:"-"`
+
+	mu      sync.Mutex
+	streams map[string]*list.Element
+	// order holds *stream values, most recently updated first
+	order *list.List
+}
+
+// stream is the rolling state for one stream
+type stream struct {
+	id       string
+	samples  []sample
+	above    bool
+	lastSeen time.Time
+}
+
+type sample struct {
+	at    time.Time
+	value float64
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "rolling-policy"
+}
+
+// Configure applies the given configuration to the policy and resets all
+// stream state
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	p.mu.Lock()
+	p.streams = nil
+	p.order = nil
+	p.mu.Unlock()
+
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	result := policyresult.New(p.Name(), "rolling aggregate")
+
+	raw, exists := inputMap[p.Field]
+	if p.Field == "" || !exists {
+		result.Status = "PASSED"
+		result.Message = "No value to aggregate"
+		return result, nil
+	}
+
+	value, ok := toFloat(raw)
+	if !ok {
+		return nil, fmt.Errorf("field %q: expected number, got %T", p.Field, raw)
+	}
+
+	streamID := p.streamID(inputMap)
+	duration, err := p.duration()
+	if err != nil {
+		return nil, err
+	}
+	idleTimeout, err := p.idleTimeout()
+	if err != nil {
+		return nil, err
+	}
+	now := p.now()
+
+	p.mu.Lock()
+	s := p.stream(streamID, now, idleTimeout)
+	s.samples = append(s.samples, sample{at: now, value: value})
+	if p.Size > 0 && len(s.samples) > p.Size {
+		s.samples = s.samples[len(s.samples)-p.Size:]
+	}
+	if duration > 0 {
+		cutoff := now.Add(-duration)
+		i := 0
+		for i < len(s.samples) && !s.samples[i].at.After(cutoff) {
+			i++
+		}
+		s.samples = s.samples[i:]
+	}
+
+	sum := 0.0
+	for _, smp := range s.samples {
+		sum += smp.value
+	}
+	count := len(s.samples)
+	average := sum / float64(count)
+
+	wasAbove := s.above
+	if p.Threshold != nil {
+		aggregate := average
+		if p.thresholdOn() == "sum" {
+			aggregate = sum
+		}
+		s.above = aggregate > *p.Threshold
+	}
+	isAbove := s.above
+	p.mu.Unlock()
+
+	result.Data["stream"] = streamID
+	result.Data["count"] = count
+	result.Data["sum"] = sum
+	result.Data["average"] = average
+
+	if p.Threshold == nil {
+		result.Status = "PASSED"
+		result.Message = "Rolling aggregate updated"
+		return result, nil
+	}
+
+	result.Data["threshold"] = *p.Threshold
+	result.Data["above_threshold"] = isAbove
+	result.Data["crossed"] = isAbove != wasAbove
+
+	switch {
+	case isAbove && !wasAbove:
+		result.Status = "ALERT"
+		result.Message = fmt.Sprintf("Rolling %s crossed above %v", p.thresholdOn(), *p.Threshold)
+	case !isAbove && wasAbove:
+		result.Status = "RECOVERED"
+		result.Message = fmt.Sprintf("Rolling %s dropped back to or below %v", p.thresholdOn(), *p.Threshold)
+	case isAbove:
+		result.Status = "ALERT"
+		result.Message = fmt.Sprintf("Rolling %s remains above %v", p.thresholdOn(), *p.Threshold)
+	default:
+		result.Status = "PASSED"
+		result.Message = fmt.Sprintf("Rolling %s within threshold", p.thresholdOn())
+	}
+
+	return result, nil
+}
+
+// stream returns the state for streamID, creating it if needed, and marks
+// it as updated at now. Streams idle for longer than idleTimeout are
+// dropped first, then the least recently updated ones beyond MaxStreams.
+// The caller must hold p.mu.
+func (p *Policy) stream(streamID string, now time.Time, idleTimeout time.Duration) *stream {
+	if p.streams == nil {
+		p.streams = make(map[string]*list.Element)
+		p.order = list.New()
+	}
+
+	if idleTimeout > 0 {
+		cutoff := now.Add(-idleTimeout)
+		for oldest := p.order.Back(); oldest != nil && oldest.Value.(*stream).lastSeen.Before(cutoff); oldest = p.order.Back() {
+			p.order.Remove(oldest)
+			delete(p.streams, oldest.Value.(*stream).id)
+		}
+	}
+
+	elem, ok := p.streams[streamID]
+	if ok {
+		p.order.MoveToFront(elem)
+	} else {
+		elem = p.order.PushFront(&stream{id: streamID})
+		p.streams[streamID] = elem
+		for p.order.Len() > p.maxStreams() {
+			oldest := p.order.Back()
+			p.order.Remove(oldest)
+			delete(p.streams, oldest.Value.(*stream).id)
+		}
+	}
+
+	s := elem.Value.(*stream)
+	s.lastSeen = now
+	return s
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	if p.Size < 0 {
+		return fmt.Errorf("size must not be negative, got %d", p.Size)
+	}
+	if _, err := p.duration(); err != nil {
+		return err
+	}
+	if p.MaxStreams < 0 {
+		return fmt.Errorf("max_streams must not be negative, got %d", p.MaxStreams)
+	}
+	if _, err := p.idleTimeout(); err != nil {
+		return err
+	}
+	if p.Field != "" && p.Size == 0 && p.Duration == "" {
+		return fmt.Errorf("one of size or duration must be set")
+	}
+	switch p.thresholdOn() {
+	case "average", "sum":
+	default:
+		return fmt.Errorf("unsupported threshold_on %q, expected average or sum", p.ThresholdOn)
+	}
+	return nil
+}
+
+func (p *Policy) streamField() string {
+	if p.StreamField == "" {
+		return "stream"
+	}
+	return p.StreamField
+}
+
+// streamID returns the stream an input belongs to, or "default" when the
+// input does not name one
+func (p *Policy) streamID(inputMap map[string]interface{}) string {
+	value, exists := inputMap[p.streamField()]
+	if !exists || value == nil {
+		return "default"
+	}
+	return fmt.Sprint(value)
+}
+
+func (p *Policy) thresholdOn() string {
+	if p.ThresholdOn == "" {
+		return "average"
+	}
+	return p.ThresholdOn
+}
+
+func (p *Policy) duration() (time.Duration, error) {
+	if p.Duration == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(p.Duration)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q: %w", p.Duration, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("duration must be positive, got %s", p.Duration)
+	}
+	return d, nil
+}
+
+func (p *Policy) maxStreams() int {
+	if p.MaxStreams == 0 {
+		return defaultMaxStreams
+	}
+	return p.MaxStreams
+}
+
+func (p *Policy) idleTimeout() (time.Duration, error) {
+	if p.IdleTimeout == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(p.IdleTimeout)
+	if err != nil {
+		return 0, fmt.Errorf("invalid idle_timeout %q: %w", p.IdleTimeout, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("idle_timeout must be positive, got %s", p.IdleTimeout)
+	}
+	return d, nil
+}
+
+func (p *Policy) now() time.Time {
+	if p.Now != nil {
+		return p.Now()
+	}
+	return time.Now()
+}
+
+// toFloat converts any of the numeric types a decoded input may carry
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,208 @@
+package rollingpolicy
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/example/policies/policyresult"
+)
+
+// clock is a settable time source for tests
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func newClock() *clock {
+	return &clock{now: time.Date(2024, time.January, 15, 10, 0, 0, 0, time.UTC)}
+}
+
+// feed executes p with value on stream and returns the result
+func feed(t *testing.T, p *Policy, stream string, value float64) *policyresult.Result {
+	t.Helper()
+	got, err := p.Execute(context.Background(), map[string]interface{}{"latency": value, "stream": stream})
+	if err != nil {
+		t.Fatalf("Execute(%v): %v", value, err)
+	}
+	return got.(*policyresult.Result)
+}
+
+func TestExecuteCountWindow(t *testing.T) {
+	threshold := 50.0
+	p := &Policy{Field: "latency", Size: 3, Threshold: &threshold}
+
+	steps := []struct {
+		value       float64
+		wantSum     float64
+		wantAverage float64
+		wantStatus  string
+		wantCrossed bool
+	}{
+		{30, 30, 30, "PASSED", false},
+		{60, 90, 45, "PASSED", false},
+		{90, 180, 60, "ALERT", true},
+		{90, 240, 80, "ALERT", false},
+		{0, 180, 60, "ALERT", false},
+		{0, 90, 30, "RECOVERED", true},
+		{30, 30, 10, "PASSED", false},
+	}
+
+	for i, step := range steps {
+		result := feed(t, p, "api", step.value)
+		if result.Data["sum"] != step.wantSum || result.Data["average"] != step.wantAverage {
+			t.Errorf("step %d: sum = %v, average = %v; want %v, %v", i, result.Data["sum"], result.Data["average"], step.wantSum, step.wantAverage)
+		}
+		if result.Status != step.wantStatus || result.Data["crossed"] != step.wantCrossed {
+			t.Errorf("step %d: status = %s, crossed = %v; want %s, %v", i, result.Status, result.Data["crossed"], step.wantStatus, step.wantCrossed)
+		}
+	}
+}
+
+func TestExecuteTimeWindow(t *testing.T) {
+	c := newClock()
+	p := &Policy{Field: "latency", Duration: "1m", Now: c.Now}
+
+	feed(t, p, "api", 10)
+	c.now = c.now.Add(30 * time.Second)
+	if result := feed(t, p, "api", 20); result.Data["count"] != 2 || result.Data["sum"] != 30.0 {
+		t.Errorf("within window: count = %v, sum = %v; want 2, 30", result.Data["count"], result.Data["sum"])
+	}
+
+	// The first sample is exactly one window old and drops out
+	c.now = c.now.Add(30 * time.Second)
+	if result := feed(t, p, "api", 40); result.Data["count"] != 2 || result.Data["sum"] != 60.0 {
+		t.Errorf("after expiry: count = %v, sum = %v; want 2, 60", result.Data["count"], result.Data["sum"])
+	}
+}
+
+func TestExecuteSumThreshold(t *testing.T) {
+	threshold := 100.0
+	p := &Policy{Field: "latency", Size: 10, Threshold: &threshold, ThresholdOn: "sum"}
+	feed(t, p, "api", 60)
+	if result := feed(t, p, "api", 60); result.Status != "ALERT" {
+		t.Errorf("status = %s, want ALERT on a sum of 120", result.Status)
+	}
+}
+
+func TestExecuteStreamsAreIndependent(t *testing.T) {
+	p := &Policy{Field: "latency", Size: 5}
+	feed(t, p, "a", 10)
+	feed(t, p, "b", 100)
+	if result := feed(t, p, "a", 20); result.Data["sum"] != 30.0 || result.Data["stream"] != "a" {
+		t.Errorf("stream a: sum = %v, want 30", result.Data["sum"])
+	}
+
+	// Inputs without a stream share the default stream
+	got, err := p.Execute(context.Background(), map[string]interface{}{"latency": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stream := got.(*policyresult.Result).Data["stream"]; stream != "default" {
+		t.Errorf("stream = %v, want default", stream)
+	}
+}
+
+func TestExecuteMaxStreams(t *testing.T) {
+	p := &Policy{Field: "latency", Size: 5, MaxStreams: 2}
+	feed(t, p, "a", 1)
+	feed(t, p, "b", 2)
+	feed(t, p, "a", 1) // a is now the most recently updated
+	feed(t, p, "c", 3) // evicts b
+
+	if len(p.streams) != 2 {
+		t.Errorf("tracking %d streams, want 2", len(p.streams))
+	}
+	if result := feed(t, p, "a", 1); result.Data["count"] != 3 {
+		t.Errorf("stream a count = %v, want 3 (kept)", result.Data["count"])
+	}
+	if result := feed(t, p, "b", 2); result.Data["count"] != 1 {
+		t.Errorf("stream b count = %v, want 1 (started over)", result.Data["count"])
+	}
+}
+
+func TestExecuteIdleTimeout(t *testing.T) {
+	c := newClock()
+	p := &Policy{Field: "latency", Size: 5, IdleTimeout: "10m", Now: c.Now}
+	feed(t, p, "idle", 1)
+	c.now = c.now.Add(5 * time.Minute)
+	feed(t, p, "busy", 1)
+
+	c.now = c.now.Add(6 * time.Minute)
+	feed(t, p, "busy", 1)
+	if _, ok := p.streams["idle"]; ok {
+		t.Error("stream idle for 11m still tracked with a 10m idle timeout")
+	}
+	if result := feed(t, p, "busy", 1); result.Data["count"] != 3 {
+		t.Errorf("busy count = %v, want 3", result.Data["count"])
+	}
+}
+
+func TestConfigureResetsStreams(t *testing.T) {
+	p := &Policy{}
+	if err := p.Configure(map[string]interface{}{"field": "latency", "size": 2}); err != nil {
+		t.Fatal(err)
+	}
+	feed(t, p, "a", 1)
+	if err := p.Configure(map[string]interface{}{"field": "latency", "size": 2}); err != nil {
+		t.Fatal(err)
+	}
+	if result := feed(t, p, "a", 1); result.Data["count"] != 1 {
+		t.Errorf("count after reconfiguring = %v, want 1", result.Data["count"])
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	p := &Policy{Field: "latency", Size: 2}
+	if _, err := p.Execute(context.Background(), map[string]interface{}{"latency": "fast"}); err == nil {
+		t.Error("non-numeric value succeeded, want error")
+	}
+	got, err := p.Execute(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := got.(*policyresult.Result); result.Status != "PASSED" {
+		t.Errorf("missing value status = %s, want PASSED", result.Status)
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr bool
+	}{
+		{"zero value", map[string]interface{}{}, false},
+		{"count window", map[string]interface{}{"field": "x", "size": 10}, false},
+		{"time window", map[string]interface{}{"field": "x", "duration": "5m"}, false},
+		{"no window", map[string]interface{}{"field": "x"}, true},
+		{"zero duration and size", map[string]interface{}{"field": "x", "duration": "0s", "size": 0}, true},
+		{"zero duration without field", map[string]interface{}{"duration": "0s"}, true},
+		{"negative duration", map[string]interface{}{"field": "x", "duration": "-1m"}, true},
+		{"negative size", map[string]interface{}{"field": "x", "size": -1}, true},
+		{"unknown threshold_on", map[string]interface{}{"field": "x", "size": 1, "threshold_on": "max"}, true},
+		{"negative max_streams", map[string]interface{}{"field": "x", "size": 1, "max_streams": -1}, true},
+		{"zero idle_timeout", map[string]interface{}{"field": "x", "size": 1, "idle_timeout": "0s"}, true},
+		{"malformed idle_timeout", map[string]interface{}{"field": "x", "size": 1, "idle_timeout": "soon"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := (&Policy{}).Configure(tt.config); (err != nil) != tt.wantErr {
+				t.Errorf("Configure(%v) = %v, wantErr %v", tt.config, err, tt.wantErr)
+			}
+		})
+	}
+}
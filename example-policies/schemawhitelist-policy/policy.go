@@ -0,0 +1,141 @@
+package schemawhitelistpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Policy implements the policy engine interface
+// It is a forgiving alternative to strict JSON Schema validation: wherever
+// the schema declares "additionalProperties": false, input fields missing
+// from "properties" are stripped instead of failing validation
+type Policy struct {
+	// Schema is an inline JSON Schema object
+	Schema map[string]interface{} `json:"schema"`
+
+	// SchemaFile is a path to a JSON Schema file, loaded by Configure when
+	// no inline schema is given
+	SchemaFile string `json:"schema_file"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "schemawhitelist-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	if p.Schema == nil && p.SchemaFile != "" {
+		raw, err := os.ReadFile(p.SchemaFile)
+		if err != nil {
+			return fmt.Errorf("failed to read schema: %w", err)
+		}
+		if err := json.Unmarshal(raw, &p.Schema); err != nil {
+			return fmt.Errorf("invalid schema %s: %w", p.SchemaFile, err)
+		}
+	}
+
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	result := policyresult.New(p.Name(), "schema field whitelisting")
+
+	stripped := []string{}
+	output := whitelist(inputMap, p.Schema, "", &stripped)
+	sort.Strings(stripped)
+
+	result.Data["input"] = inputMap
+	result.Output = output
+	result.Data["stripped_fields"] = stripped
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	return validateSchema(p.Schema, "")
+}
+
+// whitelist returns a copy of obj with undeclared fields removed wherever
+// the schema forbids additional properties, recording stripped paths
+func whitelist(obj map[string]interface{}, schema map[string]interface{}, prefix string, stripped *[]string) map[string]interface{} {
+	properties, _ := schema["properties"].(map[string]interface{})
+	strict := schema["additionalProperties"] == false
+
+	out := make(map[string]interface{}, len(obj))
+	for key, value := range obj {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		propSchema, declared := properties[key].(map[string]interface{})
+		if !declared {
+			if strict {
+				*stripped = append(*stripped, path)
+				continue
+			}
+			out[key] = value
+			continue
+		}
+
+		if nested, ok := value.(map[string]interface{}); ok {
+			out[key] = whitelist(nested, propSchema, path, stripped)
+		} else {
+			out[key] = value
+		}
+	}
+
+	return out
+}
+
+// validateSchema checks that every "properties" entry is itself a schema
+// object, so whitelist can walk the tree without surprises
+func validateSchema(schema map[string]interface{}, path string) error {
+	raw, exists := schema["properties"]
+	if !exists {
+		return nil
+	}
+
+	properties, ok := raw.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("schema%s: properties must be an object", path)
+	}
+
+	for key, value := range properties {
+		propSchema, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("schema%s.properties.%s: expected schema object", path, key)
+		}
+		if err := validateSchema(propSchema, path+".properties."+key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
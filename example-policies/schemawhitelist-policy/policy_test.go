@@ -0,0 +1,161 @@
+package schemawhitelistpolicy
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+// testSchema forbids unknown top-level fields and unknown address fields,
+// but allows anything inside metadata
+var testSchema = map[string]interface{}{
+	"type":                 "object",
+	"additionalProperties": false,
+	"properties": map[string]interface{}{
+		"name": map[string]interface{}{"type": "string"},
+		"address": map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties": map[string]interface{}{
+				"city": map[string]interface{}{"type": "string"},
+			},
+		},
+		"metadata": map[string]interface{}{"type": "object"},
+	},
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        map[string]interface{}
+		wantOutput   map[string]interface{}
+		wantStripped []string
+	}{
+		{
+			name: "conforming input",
+			input: map[string]interface{}{
+				"name":    "ada",
+				"address": map[string]interface{}{"city": "London"},
+			},
+			wantOutput: map[string]interface{}{
+				"name":    "ada",
+				"address": map[string]interface{}{"city": "London"},
+			},
+			wantStripped: []string{},
+		},
+		{
+			name: "extra top-level and nested fields",
+			input: map[string]interface{}{
+				"name":    "ada",
+				"admin":   true,
+				"address": map[string]interface{}{"city": "London", "zip": "N1"},
+			},
+			wantOutput: map[string]interface{}{
+				"name":    "ada",
+				"address": map[string]interface{}{"city": "London"},
+			},
+			wantStripped: []string{"address.zip", "admin"},
+		},
+		{
+			name: "open object keeps extra fields",
+			input: map[string]interface{}{
+				"metadata": map[string]interface{}{"anything": 1},
+			},
+			wantOutput: map[string]interface{}{
+				"metadata": map[string]interface{}{"anything": 1},
+			},
+			wantStripped: []string{},
+		},
+		{
+			name:         "non-object value for object property",
+			input:        map[string]interface{}{"address": "London"},
+			wantOutput:   map[string]interface{}{"address": "London"},
+			wantStripped: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Policy{Schema: testSchema}
+			got, err := p.Execute(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if !reflect.DeepEqual(result.Output, tt.wantOutput) {
+				t.Errorf("output = %v, want %v", result.Output, tt.wantOutput)
+			}
+			if !reflect.DeepEqual(result.Data["stripped_fields"], tt.wantStripped) {
+				t.Errorf("stripped_fields = %v, want %v", result.Data["stripped_fields"], tt.wantStripped)
+			}
+		})
+	}
+}
+
+func TestExecuteDoesNotModifyInput(t *testing.T) {
+	address := map[string]interface{}{"city": "London", "zip": "N1"}
+	input := map[string]interface{}{"admin": true, "address": address}
+	if _, err := (&Policy{Schema: testSchema}).Execute(context.Background(), input); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if len(input) != 2 || len(address) != 2 {
+		t.Errorf("input modified: %v", input)
+	}
+}
+
+func TestConfigureSchemaFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schema.json")
+	schema := `{"additionalProperties": false, "properties": {"id": {}}}`
+	if err := os.WriteFile(path, []byte(schema), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := &Policy{}
+	if err := p.Configure(map[string]interface{}{"schema_file": path}); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+	got, err := p.Execute(context.Background(), map[string]interface{}{"id": 1, "extra": 2})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if want := []string{"extra"}; !reflect.DeepEqual(got.(*policyresult.Result).Data["stripped_fields"], want) {
+		t.Errorf("stripped_fields = %v, want %v", got.(*policyresult.Result).Data["stripped_fields"], want)
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  map[string]interface{}
+		wantErr bool
+	}{
+		{"zero value", nil, false},
+		{"nested schema", testSchema, false},
+		{"properties not an object", map[string]interface{}{"properties": []interface{}{}}, true},
+		{"property not a schema", map[string]interface{}{"properties": map[string]interface{}{"a": true}}, true},
+		{"nested property not a schema", map[string]interface{}{"properties": map[string]interface{}{
+			"a": map[string]interface{}{"properties": map[string]interface{}{"b": "string"}},
+		}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := (&Policy{Schema: tt.schema}).Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package scorepolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Policy implements the policy engine interface
+// It computes a weighted sum of configured numeric fields, optionally
+// rescaled onto a 0-100 range
+type Policy struct {
+	// Weights maps each input field to the weight it contributes
+	Weights map[string]float64 `json:"weights"`
+
+	// Normalize rescales the score from [MinScore, MaxScore] onto [0, 100]
+	Normalize bool    `json:"normalize"`
+	MinScore  float64 `json:"min_score"`
+	MaxScore  float64 `json:"max_score"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "score-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	result := policyresult.New(p.Name(), "weighted scoring")
+
+	// Iterate in a stable order so the skipped list is deterministic
+	fields := make([]string, 0, len(p.Weights))
+	for field := range p.Weights {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	score := 0.0
+	contributions := make(map[string]float64)
+	skipped := []map[string]interface{}{}
+	for _, field := range fields {
+		value, exists := inputMap[field]
+		if !exists {
+			skipped = append(skipped, map[string]interface{}{"field": field, "reason": "missing"})
+			continue
+		}
+
+		n, ok := toFloat(value)
+		if !ok {
+			skipped = append(skipped, map[string]interface{}{
+				"field":  field,
+				"reason": fmt.Sprintf("not numeric (%T)", value),
+			})
+			continue
+		}
+
+		contributions[field] = n * p.Weights[field]
+		score += contributions[field]
+	}
+
+	result.Data["score"] = score
+	result.Data["contributions"] = contributions
+	result.Data["skipped"] = skipped
+
+	if p.Normalize {
+		normalized := (score - p.MinScore) / (p.MaxScore - p.MinScore) * 100
+		if normalized < 0 {
+			normalized = 0
+		} else if normalized > 100 {
+			normalized = 100
+		}
+		result.Data["normalized_score"] = normalized
+	}
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	if p.Normalize && p.MaxScore <= p.MinScore {
+		return fmt.Errorf("max_score (%v) must be greater than min_score (%v)", p.MaxScore, p.MinScore)
+	}
+	return nil
+}
+
+// toFloat converts any of the numeric types a decoded input may carry
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,123 @@
+package scorepolicy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+func TestExecute(t *testing.T) {
+	weights := map[string]float64{"age": 0.5, "income": 0.001, "debt": -2}
+
+	tests := []struct {
+		name        string
+		input       map[string]interface{}
+		wantScore   float64
+		wantSkipped []map[string]interface{}
+	}{
+		{
+			name:        "all fields",
+			input:       map[string]interface{}{"age": 40, "income": json.Number("50000"), "debt": float32(3)},
+			wantScore:   20 + 50 - 6,
+			wantSkipped: []map[string]interface{}{},
+		},
+		{
+			name:      "missing field",
+			input:     map[string]interface{}{"age": 40.0, "income": int64(50000)},
+			wantScore: 70,
+			wantSkipped: []map[string]interface{}{
+				{"field": "debt", "reason": "missing"},
+			},
+		},
+		{
+			name:      "non-numeric fields",
+			input:     map[string]interface{}{"age": "40", "income": uint(1000), "debt": json.Number("n/a")},
+			wantScore: 1,
+			wantSkipped: []map[string]interface{}{
+				{"field": "age", "reason": "not numeric (string)"},
+				{"field": "debt", "reason": "not numeric (json.Number)"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Policy{Weights: weights}
+			got, err := p.Execute(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if result.Data["score"] != tt.wantScore {
+				t.Errorf("score = %v, want %v", result.Data["score"], tt.wantScore)
+			}
+			if !reflect.DeepEqual(result.Data["skipped"], tt.wantSkipped) {
+				t.Errorf("skipped = %v, want %v", result.Data["skipped"], tt.wantSkipped)
+			}
+			if _, ok := result.Data["normalized_score"]; ok {
+				t.Error("normalized_score set without normalize")
+			}
+		})
+	}
+}
+
+func TestExecuteNormalized(t *testing.T) {
+	tests := []struct {
+		name  string
+		value float64
+		want  float64
+	}{
+		{"minimum", 10, 0},
+		{"midpoint", 30, 50},
+		{"maximum", 50, 100},
+		{"clamped below", -5, 0},
+		{"clamped above", 80, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Policy{Weights: map[string]float64{"x": 2}, Normalize: true, MinScore: 20, MaxScore: 100}
+			got, err := p.Execute(context.Background(), map[string]interface{}{"x": tt.value})
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			if normalized := got.(*policyresult.Result).Data["normalized_score"]; normalized != tt.want {
+				t.Errorf("normalized_score = %v, want %v", normalized, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  Policy
+		wantErr bool
+	}{
+		{"zero value", Policy{}, false},
+		{"normalized range", Policy{Normalize: true, MinScore: -1, MaxScore: 1}, false},
+		{"empty range", Policy{Normalize: true, MinScore: 5, MaxScore: 5}, true},
+		{"inverted range", Policy{Normalize: true, MinScore: 5, MaxScore: 1}, true},
+		{"range ignored without normalize", Policy{MinScore: 5, MaxScore: 1}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policy.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package streamsizepolicy
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/example/policies/policyresult"
+)
+
+// ErrStreamTooLarge is returned once a stream's cumulative size exceeds the
+// configured ceiling
+var ErrStreamTooLarge = errors.New("stream exceeds total size limit")
+
+// defaultMaxStreams bounds the number of streams tracked at once when
+// MaxStreams is not set
+const defaultMaxStreams = 10000
+
+// Policy implements the policy engine interface
+// It guards against runaway ingestion by summing the serialized JSON size of
+// every item seen on a stream (identified by a configured input field) and
+// aborting the stream once the total exceeds MaxTotalBytes. An aborted
+// stream rejects every later item until it is Reset, and the error reports
+// how many items were accepted before the abort. Stream IDs come from the
+// input, so the number of streams tracked is capped and, optionally, idle
+// streams are dropped; a dropped stream starts over from zero bytes.
+type Policy struct {
+	// MaxTotalBytes is the cumulative size ceiling per stream; zero
+	// disables the guard
+	MaxTotalBytes int64 `json:"max_total_bytes"`
+
+	// StreamField names the input field identifying the stream (default "stream")
+	StreamField string `json:"stream_field"`
+
+	// MaxStreams caps the number of streams tracked (default 10000). Once
+	// reached, the least recently updated stream is dropped.
+	MaxStreams int `json:"max_streams"`
+
+	// IdleTimeout drops streams not updated within this Go duration when set
+	IdleTimeout string `json:"idle_timeout"`
+
+	// Now returns the current time; it defaults to time.Now
+	Now func() time.Time `json:"-"`
+
+	mu      sync.Mutex
+	streams map[string]*list.Element
+	// order holds *stream values, most recently updated first
+	order *list.List
+}
+
+// stream is the running total for one stream
+type stream struct {
+	id       string
+	items    int
+	bytes    int64
+	aborted  bool
+	lastSeen time.Time
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "streamsize-policy"
+}
+
+// Configure applies the given configuration to the policy and resets all
+// stream state
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	p.mu.Lock()
+	p.streams = nil
+	p.order = nil
+	p.mu.Unlock()
+
+	return p.Validate()
+}
+
+// Reset forgets the running total for a stream, e.g. when it ends, so the
+// stream ID can be reused
+func (p *Policy) Reset(streamID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if elem, ok := p.streams[streamID]; ok {
+		p.order.Remove(elem)
+		delete(p.streams, streamID)
+	}
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	result := policyresult.New(p.Name(), "stream size guard")
+
+	if p.MaxTotalBytes == 0 {
+		result.Status = "PASSED"
+		result.Message = "No size limit configured"
+		return result, nil
+	}
+
+	data, err := json.Marshal(inputMap)
+	if err != nil {
+		return nil, fmt.Errorf("failed to measure input: %w", err)
+	}
+	size := int64(len(data))
+	streamID := p.streamID(inputMap)
+
+	idleTimeout, err := p.idleTimeout()
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	s := p.stream(streamID, p.now(), idleTimeout)
+	if !s.aborted && s.bytes+size <= p.MaxTotalBytes {
+		s.items++
+		s.bytes += size
+	} else {
+		s.aborted = true
+	}
+	items, total, aborted := s.items, s.bytes, s.aborted
+	p.mu.Unlock()
+
+	if aborted {
+		return nil, fmt.Errorf("stream %s aborted after %d item(s) totalling %d bytes (limit %d): %w",
+			streamID, items, total, p.MaxTotalBytes, ErrStreamTooLarge)
+	}
+
+	result.Status = "PASSED"
+	result.Data["stream"] = streamID
+	result.Data["items"] = items
+	result.Data["total_bytes"] = total
+	result.Data["remaining_bytes"] = p.MaxTotalBytes - total
+	result.Message = fmt.Sprintf("Stream %s has used %d of %d bytes", streamID, total, p.MaxTotalBytes)
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	if p.MaxTotalBytes < 0 {
+		return fmt.Errorf("max_total_bytes must not be negative, got %d", p.MaxTotalBytes)
+	}
+	if p.MaxStreams < 0 {
+		return fmt.Errorf("max_streams must not be negative, got %d", p.MaxStreams)
+	}
+	if _, err := p.idleTimeout(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// stream returns the state for streamID, creating it if needed, and marks
+// it as updated at now. Streams idle for longer than idleTimeout are
+// dropped first, then the least recently updated ones beyond MaxStreams.
+// The caller must hold p.mu.
+func (p *Policy) stream(streamID string, now time.Time, idleTimeout time.Duration) *stream {
+	if p.streams == nil {
+		p.streams = make(map[string]*list.Element)
+		p.order = list.New()
+	}
+
+	if idleTimeout > 0 {
+		cutoff := now.Add(-idleTimeout)
+		for oldest := p.order.Back(); oldest != nil && oldest.Value.(*stream).lastSeen.Before(cutoff); oldest = p.order.Back() {
+			p.order.Remove(oldest)
+			delete(p.streams, oldest.Value.(*stream).id)
+		}
+	}
+
+	elem, ok := p.streams[streamID]
+	if ok {
+		p.order.MoveToFront(elem)
+	} else {
+		elem = p.order.PushFront(&stream{id: streamID})
+		p.streams[streamID] = elem
+		for p.order.Len() > p.maxStreams() {
+			oldest := p.order.Back()
+			p.order.Remove(oldest)
+			delete(p.streams, oldest.Value.(*stream).id)
+		}
+	}
+
+	s := elem.Value.(*stream)
+	s.lastSeen = now
+	return s
+}
+
+func (p *Policy) streamField() string {
+	if p.StreamField == "" {
+		return "stream"
+	}
+	return p.StreamField
+}
+
+func (p *Policy) maxStreams() int {
+	if p.MaxStreams == 0 {
+		return defaultMaxStreams
+	}
+	return p.MaxStreams
+}
+
+func (p *Policy) idleTimeout() (time.Duration, error) {
+	if p.IdleTimeout == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(p.IdleTimeout)
+	if err != nil {
+		return 0, fmt.Errorf("invalid idle_timeout %q: %w", p.IdleTimeout, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("idle_timeout must be positive, got %s", p.IdleTimeout)
+	}
+	return d, nil
+}
+
+func (p *Policy) now() time.Time {
+	if p.Now != nil {
+		return p.Now()
+	}
+	return time.Now()
+}
+
+func (p *Policy) streamID(inputMap map[string]interface{}) string {
+	value, exists := inputMap[p.streamField()]
+	if !exists || value == nil {
+		return "default"
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,179 @@
+package streamsizepolicy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/example/policies/policyresult"
+)
+
+// clock is a settable time source for tests
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+// item returns a stream item carrying n bytes of payload
+func item(stream string, n int) map[string]interface{} {
+	return map[string]interface{}{"stream": stream, "data": strings.Repeat("x", n)}
+}
+
+// size is the serialized size the policy charges for an input
+func size(t *testing.T, input map[string]interface{}) int64 {
+	t.Helper()
+	data, err := json.Marshal(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return int64(len(data))
+}
+
+// feed executes p with input and fails the test on error
+func feed(t *testing.T, p *Policy, input map[string]interface{}) *policyresult.Result {
+	t.Helper()
+	got, err := p.Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	return got.(*policyresult.Result)
+}
+
+func TestExecuteAbortsOversizedStream(t *testing.T) {
+	chunk, small := item("s", 40), item("s", 0)
+	p := &Policy{MaxTotalBytes: 2*size(t, chunk) + size(t, small)}
+
+	for i := 1; i <= 2; i++ {
+		result := feed(t, p, chunk)
+		if result.Data["items"] != i || result.Data["total_bytes"] != int64(i)*size(t, chunk) {
+			t.Errorf("item %d: items %v total %v", i, result.Data["items"], result.Data["total_bytes"])
+		}
+	}
+	if result := feed(t, p, small); result.Data["remaining_bytes"] != int64(0) {
+		t.Errorf("remaining = %v, want 0 at the ceiling", result.Data["remaining_bytes"])
+	}
+
+	// Any further item overflows the ceiling and aborts the stream
+	_, err := p.Execute(context.Background(), small)
+	if !errors.Is(err, ErrStreamTooLarge) || !strings.Contains(err.Error(), "aborted after 3 item(s)") {
+		t.Fatalf("Execute error = %v, want ErrStreamTooLarge after 3 items", err)
+	}
+
+	// Even an empty item is rejected once aborted
+	if _, err := p.Execute(context.Background(), map[string]interface{}{"stream": "s"}); !errors.Is(err, ErrStreamTooLarge) {
+		t.Errorf("Execute after abort error = %v, want ErrStreamTooLarge", err)
+	}
+
+	// Other streams keep their own totals
+	if result := feed(t, p, item("other", 40)); result.Data["items"] != 1 {
+		t.Errorf("other stream items = %v, want 1", result.Data["items"])
+	}
+
+	// Reset lets the stream ID be reused
+	p.Reset("s")
+	if result := feed(t, p, chunk); result.Data["items"] != 1 {
+		t.Errorf("items after Reset = %v, want 1", result.Data["items"])
+	}
+}
+
+func TestExecuteSingleItemOverLimit(t *testing.T) {
+	p := &Policy{MaxTotalBytes: 10, StreamField: "session"}
+	_, err := p.Execute(context.Background(), map[string]interface{}{"session": 7, "data": "more than ten bytes"})
+	if !errors.Is(err, ErrStreamTooLarge) || !strings.Contains(err.Error(), "stream 7 aborted after 0 item(s)") {
+		t.Errorf("Execute error = %v, want stream 7 aborted after 0 items", err)
+	}
+}
+
+func TestExecuteMaxStreams(t *testing.T) {
+	p := &Policy{MaxTotalBytes: 1 << 20, MaxStreams: 2}
+	feed(t, p, item("a", 1))
+	feed(t, p, item("b", 1))
+	feed(t, p, item("a", 1)) // a is now the most recently updated
+	feed(t, p, item("c", 1)) // evicts b
+
+	if len(p.streams) != 2 {
+		t.Errorf("tracking %d streams, want 2", len(p.streams))
+	}
+	if result := feed(t, p, item("a", 1)); result.Data["items"] != 3 {
+		t.Errorf("stream a items = %v, want 3 (kept)", result.Data["items"])
+	}
+	if result := feed(t, p, item("b", 1)); result.Data["items"] != 1 {
+		t.Errorf("stream b items = %v, want 1 (started over)", result.Data["items"])
+	}
+}
+
+func TestExecuteIdleTimeout(t *testing.T) {
+	c := &clock{now: time.Date(2024, time.January, 15, 10, 0, 0, 0, time.UTC)}
+	p := &Policy{MaxTotalBytes: 1 << 20, IdleTimeout: "10m", Now: c.Now}
+	feed(t, p, item("idle", 1))
+	c.now = c.now.Add(5 * time.Minute)
+	feed(t, p, item("busy", 1))
+
+	c.now = c.now.Add(6 * time.Minute)
+	feed(t, p, item("busy", 1))
+	if _, ok := p.streams["idle"]; ok {
+		t.Error("stream idle for 11m still tracked with a 10m idle timeout")
+	}
+	if result := feed(t, p, item("busy", 1)); result.Data["items"] != 3 {
+		t.Errorf("busy items = %v, want 3", result.Data["items"])
+	}
+}
+
+func TestConfigureResetsStreams(t *testing.T) {
+	p := &Policy{}
+	if err := p.Configure(map[string]interface{}{"max_total_bytes": 50}); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+	if _, err := p.Execute(context.Background(), item("s", 100)); !errors.Is(err, ErrStreamTooLarge) {
+		t.Fatalf("Execute error = %v, want ErrStreamTooLarge", err)
+	}
+	if err := p.Configure(map[string]interface{}{"max_total_bytes": 500}); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+	if result := feed(t, p, item("s", 100)); result.Data["items"] != 1 {
+		t.Errorf("items after reconfiguring = %v, want 1", result.Data["items"])
+	}
+}
+
+func TestExecuteNoLimit(t *testing.T) {
+	result := feed(t, &Policy{}, item("s", 1<<16))
+	if result.Status != "PASSED" || result.Message != "No size limit configured" {
+		t.Errorf("result = %s %q", result.Status, result.Message)
+	}
+
+	if _, err := (&Policy{}).Execute(context.Background(), "data"); err == nil {
+		t.Error("Execute accepted a non-map input")
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Policy{}).Validate(); err != nil {
+		t.Errorf("zero value: %v", err)
+	}
+
+	invalid := []*Policy{
+		{MaxTotalBytes: -1},
+		{MaxStreams: -1},
+		{IdleTimeout: "soon"},
+		{IdleTimeout: "0s"},
+	}
+	for _, p := range invalid {
+		if err := p.Validate(); err == nil {
+			t.Errorf("Validate(%+v) succeeded, want an error", p)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package timezonepolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	// Embed the timezone database; the Alpine runtime image does not ship one
+	_ "time/tzdata"
+
+	"github.com/example/policies/policyresult"
+)
+
+// naiveLayouts are accepted timestamp layouts that carry no zone information
+var naiveLayouts = []string{
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+}
+
+// Policy implements the policy engine interface
+// It checks that timestamp fields carry the UTC offset of a configured
+// timezone and optionally converts them to a target timezone. Timestamps
+// without zone information are reported as naive unless AssumeZone is set,
+// in which case they are interpreted in the configured timezone.
+type Policy struct {
+	// Fields lists the input fields holding timestamps
+	Fields []string `json:"fields"`
+
+	// Timezone is the IANA name of the expected timezone (default "UTC")
+	Timezone string `json:"timezone"`
+
+	// AssumeZone interprets naive timestamps in Timezone instead of
+	// reporting them
+	AssumeZone bool `json:"assume_zone"`
+
+	// TargetTimezone, when set, converts valid timestamps to this IANA
+	// timezone in the output
+	TargetTimezone string `json:"target_timezone"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "timezone-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	location, err := time.LoadLocation(p.timezone())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load timezone %q: %w", p.timezone(), err)
+	}
+	var target *time.Location
+	if p.TargetTimezone != "" {
+		target, err = time.LoadLocation(p.TargetTimezone)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load target timezone %q: %w", p.TargetTimezone, err)
+		}
+	}
+
+	output := make(map[string]interface{}, len(inputMap))
+	for key, value := range inputMap {
+		output[key] = value
+	}
+
+	naive := []string{}
+	wrongZone := []map[string]interface{}{}
+	invalid := []string{}
+	converted := []string{}
+	for _, field := range p.Fields {
+		value, ok := inputMap[field].(string)
+		if !ok {
+			continue
+		}
+
+		t, zoned, err := parseTimestamp(value, location)
+		if err != nil {
+			invalid = append(invalid, field)
+			continue
+		}
+
+		if !zoned && !p.AssumeZone {
+			naive = append(naive, field)
+			continue
+		}
+
+		// A zoned timestamp matches when its offset is the one the
+		// configured timezone uses at that instant
+		if zoned {
+			_, offset := t.Zone()
+			_, expected := t.In(location).Zone()
+			if offset != expected {
+				wrongZone = append(wrongZone, map[string]interface{}{
+					"field":           field,
+					"offset":          t.Format("-07:00"),
+					"expected_offset": t.In(location).Format("-07:00"),
+				})
+				continue
+			}
+		}
+
+		if target != nil {
+			output[field] = t.In(target).Format(time.RFC3339Nano)
+			converted = append(converted, field)
+		}
+	}
+
+	result := policyresult.New(p.Name(), "timezone validation")
+	result.Data["timezone"] = p.timezone()
+	result.Data["naive"] = naive
+	result.Data["wrong_zone"] = wrongZone
+	result.Data["invalid"] = invalid
+	if target != nil {
+		result.Data["converted"] = converted
+		result.Output = output
+	}
+
+	if len(naive)+len(wrongZone)+len(invalid) > 0 {
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("%d naive, %d in the wrong zone, %d invalid", len(naive), len(wrongZone), len(invalid))
+	} else {
+		result.Status = "PASSED"
+		result.Message = fmt.Sprintf("All timestamps are in %s", p.timezone())
+	}
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	if _, err := time.LoadLocation(p.timezone()); err != nil {
+		return fmt.Errorf("invalid timezone %q: %w", p.timezone(), err)
+	}
+	if p.TargetTimezone != "" {
+		if _, err := time.LoadLocation(p.TargetTimezone); err != nil {
+			return fmt.Errorf("invalid target_timezone %q: %w", p.TargetTimezone, err)
+		}
+	}
+	return nil
+}
+
+func (p *Policy) timezone() string {
+	if p.Timezone == "" {
+		return "UTC"
+	}
+	return p.Timezone
+}
+
+// parseTimestamp parses an RFC 3339 timestamp, falling back to naive layouts
+// interpreted in location. zoned reports whether the value carried an offset.
+func parseTimestamp(value string, location *time.Location) (t time.Time, zoned bool, err error) {
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, true, nil
+	}
+	for _, layout := range naiveLayouts {
+		if t, err := time.ParseInLocation(layout, value, location); err == nil {
+			return t, false, nil
+		}
+	}
+	return time.Time{}, false, fmt.Errorf("unrecognized timestamp %q", value)
+}
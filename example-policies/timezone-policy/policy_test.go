@@ -0,0 +1,186 @@
+package timezonepolicy
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name          string
+		policy        *Policy
+		value         string
+		wantStatus    string
+		wantNaive     []string
+		wantWrongZone []map[string]interface{}
+		wantInvalid   []string
+		// wantOutput is the converted value, or empty when nothing is
+		// converted
+		wantOutput string
+	}{
+		{
+			name:       "zoned in the configured timezone",
+			policy:     &Policy{Timezone: "Asia/Colombo"},
+			value:      "2024-01-15T10:00:00+05:30",
+			wantStatus: "PASSED",
+		},
+		{
+			name:       "zoned UTC by default",
+			policy:     &Policy{},
+			value:      "2024-01-15T10:00:00.123Z",
+			wantStatus: "PASSED",
+		},
+		{
+			name:       "zoned with daylight saving offset",
+			policy:     &Policy{Timezone: "America/New_York"},
+			value:      "2024-07-01T10:00:00-04:00",
+			wantStatus: "PASSED",
+		},
+		{
+			name:       "zoned in the wrong timezone",
+			policy:     &Policy{Timezone: "Asia/Colombo"},
+			value:      "2024-01-15T10:00:00Z",
+			wantStatus: "FAILED",
+			wantWrongZone: []map[string]interface{}{
+				{"field": "ts", "offset": "+00:00", "expected_offset": "+05:30"},
+			},
+		},
+		{
+			name:       "standard offset during daylight saving",
+			policy:     &Policy{Timezone: "America/New_York"},
+			value:      "2024-07-01T10:00:00-05:00",
+			wantStatus: "FAILED",
+			wantWrongZone: []map[string]interface{}{
+				{"field": "ts", "offset": "-05:00", "expected_offset": "-04:00"},
+			},
+		},
+		{
+			name:       "naive reported",
+			policy:     &Policy{Timezone: "Asia/Colombo"},
+			value:      "2024-01-15T10:00:00",
+			wantStatus: "FAILED",
+			wantNaive:  []string{"ts"},
+		},
+		{
+			name:       "naive with a space",
+			policy:     &Policy{},
+			value:      "2024-01-15 10:00:00.5",
+			wantStatus: "FAILED",
+			wantNaive:  []string{"ts"},
+		},
+		{
+			name:       "naive assumed in the configured timezone and converted",
+			policy:     &Policy{Timezone: "Asia/Colombo", AssumeZone: true, TargetTimezone: "UTC"},
+			value:      "2024-01-15T10:00:00",
+			wantStatus: "PASSED",
+			wantOutput: "2024-01-15T04:30:00Z",
+		},
+		{
+			name:       "zoned converted",
+			policy:     &Policy{Timezone: "America/New_York", TargetTimezone: "Asia/Tokyo"},
+			value:      "2024-07-01T10:00:00-04:00",
+			wantStatus: "PASSED",
+			wantOutput: "2024-07-01T23:00:00+09:00",
+		},
+		{
+			name:          "wrong zone not converted",
+			policy:        &Policy{Timezone: "UTC", TargetTimezone: "Asia/Tokyo"},
+			value:         "2024-07-01T10:00:00+01:00",
+			wantStatus:    "FAILED",
+			wantWrongZone: []map[string]interface{}{{"field": "ts", "offset": "+01:00", "expected_offset": "+00:00"}},
+			wantOutput:    "2024-07-01T10:00:00+01:00",
+		},
+		{
+			name:        "invalid",
+			policy:      &Policy{},
+			value:       "yesterday",
+			wantStatus:  "FAILED",
+			wantInvalid: []string{"ts"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.policy.Fields = []string{"ts", "absent"}
+			got, err := tt.policy.Execute(context.Background(), map[string]interface{}{"ts": tt.value, "other": 1})
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if result.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s (%s)", result.Status, tt.wantStatus, result.Message)
+			}
+			if got := result.Data["naive"].([]string); len(got)+len(tt.wantNaive) > 0 && !reflect.DeepEqual(got, tt.wantNaive) {
+				t.Errorf("naive = %v, want %v", got, tt.wantNaive)
+			}
+			if got := result.Data["wrong_zone"].([]map[string]interface{}); len(got)+len(tt.wantWrongZone) > 0 && !reflect.DeepEqual(got, tt.wantWrongZone) {
+				t.Errorf("wrong_zone = %v, want %v", got, tt.wantWrongZone)
+			}
+			if got := result.Data["invalid"].([]string); len(got)+len(tt.wantInvalid) > 0 && !reflect.DeepEqual(got, tt.wantInvalid) {
+				t.Errorf("invalid = %v, want %v", got, tt.wantInvalid)
+			}
+
+			if tt.policy.TargetTimezone == "" {
+				if result.Output != nil {
+					t.Errorf("output = %v without a target timezone", result.Output)
+				}
+				return
+			}
+			if result.Output["ts"] != tt.wantOutput || result.Output["other"] != 1 {
+				t.Errorf("output = %v, want ts %s", result.Output, tt.wantOutput)
+			}
+		})
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	if _, err := (&Policy{Timezone: "Mars/Olympus"}).Execute(context.Background(), map[string]interface{}{}); err == nil {
+		t.Error("Execute accepted an unknown timezone")
+	}
+	if _, err := (&Policy{TargetTimezone: "Mars/Olympus"}).Execute(context.Background(), map[string]interface{}{}); err == nil {
+		t.Error("Execute accepted an unknown target timezone")
+	}
+	if _, err := (&Policy{}).Execute(context.Background(), "2024-01-15T10:00:00Z"); err == nil {
+		t.Error("Execute accepted a non-map input")
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr bool
+	}{
+		{"valid", map[string]interface{}{"fields": []interface{}{"ts"}, "timezone": "Europe/London", "target_timezone": "UTC", "assume_zone": true}, false},
+		{"unknown timezone", map[string]interface{}{"timezone": "Mars/Olympus"}, true},
+		{"unknown target timezone", map[string]interface{}{"target_timezone": "Mars/Olympus"}, true},
+		{"wrong type", map[string]interface{}{"assume_zone": "yes"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&Policy{}).Configure(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Configure error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Policy{}).Validate(); err != nil {
+		t.Errorf("zero value: %v", err)
+	}
+}
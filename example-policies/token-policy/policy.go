@@ -0,0 +1,146 @@
+package tokenpolicy
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Policy implements the policy engine interface
+// It decodes an opaque URL-safe base64 token (padded or unpadded), checks
+// its decoded length and structure, and returns the decoded payload
+type Policy struct {
+	// Field names the input field holding the token (default "token")
+	Field string `json:"field"`
+
+	// Length is the exact decoded length in bytes; zero allows any length
+	Length int `json:"length"`
+
+	// Format describes the decoded payload: binary (default, returned as
+	// hex), text (must be UTF-8) or json (must be a JSON object)
+	Format string `json:"format"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "token-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	result := policyresult.New(p.Name(), "token decoding")
+	result.Data["format"] = p.format()
+
+	value, exists := inputMap[p.field()]
+	if !exists {
+		result.Status = "PASSED"
+		result.Message = "No token to decode"
+		return result, nil
+	}
+
+	token, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("field %q: expected string, got %T", p.field(), value)
+	}
+
+	payload, err := p.decode(token)
+	if err != nil {
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("Malformed token: %v", err)
+		return result, nil
+	}
+
+	result.Status = "PASSED"
+	result.Message = "Token decoded"
+	result.Data["payload"] = payload
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	if p.Length < 0 {
+		return fmt.Errorf("length must not be negative, got %d", p.Length)
+	}
+	switch p.format() {
+	case "binary", "text", "json":
+		return nil
+	default:
+		return fmt.Errorf("unsupported format %q, expected binary, text or json", p.Format)
+	}
+}
+
+func (p *Policy) field() string {
+	if p.Field == "" {
+		return "token"
+	}
+	return p.Field
+}
+
+func (p *Policy) format() string {
+	if p.Format == "" {
+		return "binary"
+	}
+	return strings.ToLower(p.Format)
+}
+
+// decode decodes and checks a token, returning its payload in the
+// configured format
+func (p *Policy) decode(token string) (interface{}, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(token, "="))
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL-safe base64: %w", err)
+	}
+
+	if p.Length > 0 && len(raw) != p.Length {
+		return nil, fmt.Errorf("decoded length is %d bytes, expected %d", len(raw), p.Length)
+	}
+
+	switch p.format() {
+	case "text":
+		if !utf8.Valid(raw) {
+			return nil, fmt.Errorf("payload is not valid UTF-8 text")
+		}
+		return string(raw), nil
+	case "json":
+		var obj map[string]interface{}
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			return nil, fmt.Errorf("payload is not a JSON object: %w", err)
+		}
+		if obj == nil {
+			return nil, fmt.Errorf("payload is not a JSON object")
+		}
+		return obj, nil
+	default:
+		return hex.EncodeToString(raw), nil
+	}
+}
@@ -0,0 +1,189 @@
+package tokenpolicy
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+// encode returns the unpadded URL-safe base64 encoding of s
+func encode(s string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(s))
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      *Policy
+		token       string
+		wantStatus  string
+		wantPayload interface{}
+		// wantMessage is a substring of the expected message
+		wantMessage string
+	}{
+		{
+			name:        "valid binary token",
+			policy:      &Policy{Length: 4},
+			token:       base64.RawURLEncoding.EncodeToString([]byte{0xde, 0xad, 0xbe, 0xef}),
+			wantStatus:  "PASSED",
+			wantPayload: "deadbeef",
+		},
+		{
+			name:        "padded token",
+			policy:      &Policy{Format: "text"},
+			token:       base64.URLEncoding.EncodeToString([]byte("ab")),
+			wantStatus:  "PASSED",
+			wantPayload: "ab",
+		},
+		{
+			name:        "URL-safe alphabet",
+			policy:      &Policy{},
+			token:       "-_8",
+			wantStatus:  "PASSED",
+			wantPayload: "fbff",
+		},
+		{
+			name:        "valid JSON token",
+			policy:      &Policy{Format: "JSON"},
+			token:       encode(`{"sub":"ada","exp":1700000000}`),
+			wantStatus:  "PASSED",
+			wantPayload: map[string]interface{}{"sub": "ada", "exp": float64(1700000000)},
+		},
+		{
+			name:        "too short",
+			policy:      &Policy{Length: 16},
+			token:       encode("0123456789"),
+			wantStatus:  "FAILED",
+			wantMessage: "decoded length is 10 bytes, expected 16",
+		},
+		{
+			name:        "too long",
+			policy:      &Policy{Length: 4},
+			token:       encode("01234"),
+			wantStatus:  "FAILED",
+			wantMessage: "decoded length is 5 bytes, expected 4",
+		},
+		{
+			name:        "standard alphabet",
+			policy:      &Policy{},
+			token:       "+/8",
+			wantStatus:  "FAILED",
+			wantMessage: "invalid URL-safe base64",
+		},
+		{
+			name:        "truncated",
+			policy:      &Policy{},
+			token:       "abcde",
+			wantStatus:  "FAILED",
+			wantMessage: "invalid URL-safe base64",
+		},
+		{
+			name:        "text not UTF-8",
+			policy:      &Policy{Format: "text"},
+			token:       base64.RawURLEncoding.EncodeToString([]byte{0xff, 0xfe}),
+			wantStatus:  "FAILED",
+			wantMessage: "not valid UTF-8",
+		},
+		{
+			name:        "JSON array",
+			policy:      &Policy{Format: "json"},
+			token:       encode(`[1,2]`),
+			wantStatus:  "FAILED",
+			wantMessage: "not a JSON object",
+		},
+		{
+			name:        "JSON null",
+			policy:      &Policy{Format: "json"},
+			token:       encode(`null`),
+			wantStatus:  "FAILED",
+			wantMessage: "not a JSON object",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.policy.Execute(context.Background(), map[string]interface{}{"token": tt.token})
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if result.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s (%s)", result.Status, tt.wantStatus, result.Message)
+			}
+			if !reflect.DeepEqual(result.Data["payload"], tt.wantPayload) {
+				t.Errorf("payload = %#v, want %#v", result.Data["payload"], tt.wantPayload)
+			}
+			if !strings.Contains(result.Message, tt.wantMessage) {
+				t.Errorf("message = %q, want it to contain %q", result.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestExecuteField(t *testing.T) {
+	p := &Policy{Field: "session", Format: "text"}
+
+	got, err := p.Execute(context.Background(), map[string]interface{}{"session": encode("hi")})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if payload := got.(*policyresult.Result).Data["payload"]; payload != "hi" {
+		t.Errorf("payload = %v, want hi", payload)
+	}
+
+	got, err = p.Execute(context.Background(), map[string]interface{}{"token": encode("hi")})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result := got.(*policyresult.Result); result.Status != "PASSED" || result.Message != "No token to decode" {
+		t.Errorf("missing field: %s %q", result.Status, result.Message)
+	}
+
+	if _, err := p.Execute(context.Background(), map[string]interface{}{"session": 42}); err == nil {
+		t.Error("Execute accepted a non-string token")
+	}
+	if _, err := p.Execute(context.Background(), "token"); err == nil {
+		t.Error("Execute accepted a non-map input")
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr bool
+	}{
+		{"valid", map[string]interface{}{"field": "t", "length": 32, "format": "text"}, false},
+		{"negative length", map[string]interface{}{"length": -1}, true},
+		{"unknown format", map[string]interface{}{"format": "yaml"}, true},
+		{"wrong type", map[string]interface{}{"length": "32"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&Policy{}).Configure(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Configure error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Policy{}).Validate(); err != nil {
+		t.Errorf("zero value: %v", err)
+	}
+}
@@ -0,0 +1,144 @@
+package transitionpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Policy implements the policy engine interface
+// It enforces state-machine rules by checking that the status change
+// described by the input is one of the configured allowed transitions
+type Policy struct {
+	// FromField names the input field holding the current state
+	FromField string `json:"from_field"`
+
+	// ToField names the input field holding the requested state
+	ToField string `json:"to_field"`
+
+	// Transitions maps each state to the states it may move to
+	Transitions map[string][]string `json:"transitions"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "transition-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	result := policyresult.New(p.Name(), "state transition validation")
+
+	fromField, toField := p.fields()
+	fromValue, hasFrom := inputMap[fromField]
+	toValue, hasTo := inputMap[toField]
+	if !hasFrom && !hasTo {
+		result.Status = "PASSED"
+		result.Message = "No transition to validate"
+		return result, nil
+	}
+
+	from, ok := fromValue.(string)
+	if !ok {
+		return nil, fmt.Errorf("field %q: expected string, got %T", fromField, fromValue)
+	}
+	to, ok := toValue.(string)
+	if !ok {
+		return nil, fmt.Errorf("field %q: expected string, got %T", toField, toValue)
+	}
+
+	result.Data["from"] = from
+	result.Data["to"] = to
+
+	states := p.states()
+	var unknown []string
+	for _, state := range []string{from, to} {
+		if !states[state] {
+			unknown = append(unknown, state)
+		}
+	}
+
+	switch {
+	case len(unknown) > 0:
+		result.Status = "FAILED"
+		result.Data["unknown_states"] = unknown
+		result.Message = fmt.Sprintf("Unknown state(s): %v", unknown)
+	case !p.allowed(from, to):
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("Illegal transition %s -> %s", from, to)
+	default:
+		result.Status = "PASSED"
+		result.Message = fmt.Sprintf("Transition %s -> %s is allowed", from, to)
+	}
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	fromField, toField := p.fields()
+	if fromField == toField {
+		return fmt.Errorf("from_field and to_field must differ, both are %q", fromField)
+	}
+	return nil
+}
+
+// fields returns the configured state field names, falling back to
+// "from" and "to"
+func (p *Policy) fields() (string, string) {
+	fromField, toField := p.FromField, p.ToField
+	if fromField == "" {
+		fromField = "from"
+	}
+	if toField == "" {
+		toField = "to"
+	}
+	return fromField, toField
+}
+
+// states returns every state named in the transition map
+func (p *Policy) states() map[string]bool {
+	states := make(map[string]bool)
+	for from, targets := range p.Transitions {
+		states[from] = true
+		for _, to := range targets {
+			states[to] = true
+		}
+	}
+	return states
+}
+
+// allowed reports whether moving from one state to another is permitted
+func (p *Policy) allowed(from, to string) bool {
+	for _, target := range p.Transitions[from] {
+		if target == to {
+			return true
+		}
+	}
+	return false
+}
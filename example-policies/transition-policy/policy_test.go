@@ -0,0 +1,114 @@
+package transitionpolicy
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+func orderPolicy() *Policy {
+	return &Policy{
+		FromField: "old_status",
+		ToField:   "new_status",
+		Transitions: map[string][]string{
+			"draft":     {"submitted"},
+			"submitted": {"approved", "rejected"},
+			"rejected":  {"draft"},
+		},
+	}
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name        string
+		from, to    string
+		wantStatus  string
+		wantUnknown []string
+	}{
+		{"allowed", "draft", "submitted", "PASSED", nil},
+		{"second allowed target", "submitted", "rejected", "PASSED", nil},
+		{"disallowed", "draft", "approved", "FAILED", nil},
+		{"terminal state has no transitions", "approved", "draft", "FAILED", nil},
+		{"same state without self transition", "draft", "draft", "FAILED", nil},
+		{"unknown source", "archived", "draft", "FAILED", []string{"archived"}},
+		{"unknown target", "draft", "deleted", "FAILED", []string{"deleted"}},
+		{"both unknown", "x", "y", "FAILED", []string{"x", "y"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := orderPolicy().Execute(context.Background(), map[string]interface{}{
+				"old_status": tt.from,
+				"new_status": tt.to,
+			})
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if result.Status != tt.wantStatus {
+				t.Errorf("Status = %q (%s), want %q", result.Status, result.Message, tt.wantStatus)
+			}
+			unknown, _ := result.Data["unknown_states"].([]string)
+			if !reflect.DeepEqual(unknown, tt.wantUnknown) {
+				t.Errorf("unknown_states = %v, want %v", unknown, tt.wantUnknown)
+			}
+		})
+	}
+}
+
+func TestExecuteWithoutTransition(t *testing.T) {
+	got, err := orderPolicy().Execute(context.Background(), map[string]interface{}{"id": 1})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if status := got.(*policyresult.Result).Status; status != "PASSED" {
+		t.Errorf("Status = %q, want PASSED", status)
+	}
+}
+
+func TestExecuteRejectsMalformedStates(t *testing.T) {
+	inputs := []map[string]interface{}{
+		{"old_status": "draft"},
+		{"old_status": 1, "new_status": "draft"},
+		{"old_status": "draft", "new_status": true},
+	}
+	for _, input := range inputs {
+		if _, err := orderPolicy().Execute(context.Background(), input); err == nil {
+			t.Errorf("Execute(%v) succeeded, want an error", input)
+		}
+	}
+}
+
+func TestExecuteDefaultFields(t *testing.T) {
+	p := &Policy{Transitions: map[string][]string{"open": {"closed"}}}
+	got, err := p.Execute(context.Background(), map[string]interface{}{"from": "open", "to": "closed"})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if status := got.(*policyresult.Result).Status; status != "PASSED" {
+		t.Errorf("Status = %q, want PASSED", status)
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := orderPolicy().Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Policy{}).Validate(); err != nil {
+		t.Errorf("zero value: %v", err)
+	}
+	if err := (&Policy{FromField: "state", ToField: "state"}).Validate(); err == nil {
+		t.Error("identical from and to fields passed validation")
+	}
+	if err := (&Policy{ToField: "from"}).Validate(); err == nil {
+		t.Error("to_field equal to the default from field passed validation")
+	}
+}
@@ -0,0 +1,127 @@
+package translatepolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Policy implements the policy engine interface
+// It maps field values through per-field lookup tables, such as internal
+// codes to external labels at an integration boundary. Numeric and boolean
+// values are looked up by their string form. Unmapped values pass through
+// unchanged unless Strict is set, in which case they fail the policy.
+type Policy struct {
+	// Tables maps each translated field to its value lookup table
+	Tables map[string]map[string]interface{} `json:"tables"`
+
+	// Strict fails the policy when a value has no mapping
+	Strict bool `json:"strict"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "translate-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	output := make(map[string]interface{}, len(inputMap))
+	for key, value := range inputMap {
+		output[key] = value
+	}
+
+	fields := make([]string, 0, len(p.Tables))
+	for field := range p.Tables {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	translated := []string{}
+	unmapped := []map[string]interface{}{}
+	for _, field := range fields {
+		value, exists := inputMap[field]
+		if !exists {
+			continue
+		}
+
+		key, ok := lookupKey(value)
+		if ok {
+			if mapped, found := p.Tables[field][key]; found {
+				output[field] = mapped
+				translated = append(translated, field)
+				continue
+			}
+		}
+		unmapped = append(unmapped, map[string]interface{}{
+			"field": field,
+			"value": value,
+		})
+	}
+
+	result := policyresult.New(p.Name(), "value translation")
+	result.Data["translated"] = translated
+	result.Data["unmapped"] = unmapped
+	result.Output = output
+
+	if p.Strict && len(unmapped) > 0 {
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("%d value(s) have no mapping", len(unmapped))
+	} else {
+		result.Status = "PASSED"
+		result.Message = fmt.Sprintf("Translated %d field(s)", len(translated))
+	}
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	for field, table := range p.Tables {
+		if field == "" {
+			return fmt.Errorf("table field name must not be empty")
+		}
+		if table == nil {
+			return fmt.Errorf("table for field %q is empty", field)
+		}
+	}
+	return nil
+}
+
+// lookupKey returns the table key for a scalar value
+func lookupKey(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case float64, int, int64, json.Number, bool:
+		return fmt.Sprint(v), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,158 @@
+package translatepolicy
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+// tables maps internal status codes and priorities to external labels
+func tables() map[string]map[string]interface{} {
+	return map[string]map[string]interface{}{
+		"status":   {"A": "active", "S": "suspended", "true": "yes"},
+		"priority": {"1": "high", "2": "low", "3": map[string]interface{}{"label": "none"}},
+	}
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name           string
+		strict         bool
+		input          map[string]interface{}
+		wantStatus     string
+		wantOutput     map[string]interface{}
+		wantTranslated []string
+		wantUnmapped   []map[string]interface{}
+	}{
+		{
+			name:           "mapped",
+			input:          map[string]interface{}{"status": "A", "priority": 1.0, "name": "x"},
+			wantStatus:     "PASSED",
+			wantOutput:     map[string]interface{}{"status": "active", "priority": "high", "name": "x"},
+			wantTranslated: []string{"priority", "status"},
+			wantUnmapped:   []map[string]interface{}{},
+		},
+		{
+			name:           "non-string keys and values",
+			input:          map[string]interface{}{"status": true, "priority": 3},
+			wantStatus:     "PASSED",
+			wantOutput:     map[string]interface{}{"status": "yes", "priority": map[string]interface{}{"label": "none"}},
+			wantTranslated: []string{"priority", "status"},
+			wantUnmapped:   []map[string]interface{}{},
+		},
+		{
+			name:           "unmapped passed through",
+			input:          map[string]interface{}{"status": "X", "priority": 2},
+			wantStatus:     "PASSED",
+			wantOutput:     map[string]interface{}{"status": "X", "priority": "low"},
+			wantTranslated: []string{"priority"},
+			wantUnmapped:   []map[string]interface{}{{"field": "status", "value": "X"}},
+		},
+		{
+			name:           "unmapped flagged when strict",
+			strict:         true,
+			input:          map[string]interface{}{"status": "X", "priority": []interface{}{1}},
+			wantStatus:     "FAILED",
+			wantOutput:     map[string]interface{}{"status": "X", "priority": []interface{}{1}},
+			wantTranslated: []string{},
+			wantUnmapped: []map[string]interface{}{
+				{"field": "priority", "value": []interface{}{1}},
+				{"field": "status", "value": "X"},
+			},
+		},
+		{
+			name:           "strict with everything mapped",
+			strict:         true,
+			input:          map[string]interface{}{"status": "S"},
+			wantStatus:     "PASSED",
+			wantOutput:     map[string]interface{}{"status": "suspended"},
+			wantTranslated: []string{"status"},
+			wantUnmapped:   []map[string]interface{}{},
+		},
+		{
+			name:           "keys are case sensitive",
+			strict:         true,
+			input:          map[string]interface{}{"status": "a"},
+			wantStatus:     "FAILED",
+			wantOutput:     map[string]interface{}{"status": "a"},
+			wantTranslated: []string{},
+			wantUnmapped:   []map[string]interface{}{{"field": "status", "value": "a"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Policy{Tables: tables(), Strict: tt.strict}
+			got, err := p.Execute(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if result.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s (%s)", result.Status, tt.wantStatus, result.Message)
+			}
+			if !reflect.DeepEqual(result.Output, tt.wantOutput) {
+				t.Errorf("output = %v, want %v", result.Output, tt.wantOutput)
+			}
+			if !reflect.DeepEqual(result.Data["translated"], tt.wantTranslated) {
+				t.Errorf("translated = %v, want %v", result.Data["translated"], tt.wantTranslated)
+			}
+			if !reflect.DeepEqual(result.Data["unmapped"], tt.wantUnmapped) {
+				t.Errorf("unmapped = %v, want %v", result.Data["unmapped"], tt.wantUnmapped)
+			}
+		})
+	}
+}
+
+func TestExecuteLeavesInputUntouched(t *testing.T) {
+	input := map[string]interface{}{"status": "A"}
+	if _, err := (&Policy{Tables: tables()}).Execute(context.Background(), input); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if input["status"] != "A" {
+		t.Errorf("input modified: %v", input)
+	}
+
+	if _, err := (&Policy{}).Execute(context.Background(), "A"); err == nil {
+		t.Error("Execute accepted a non-map input")
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr bool
+	}{
+		{"valid", map[string]interface{}{"strict": true, "tables": map[string]interface{}{"status": map[string]interface{}{"A": "active"}}}, false},
+		{"empty field name", map[string]interface{}{"tables": map[string]interface{}{"": map[string]interface{}{"A": "active"}}}, true},
+		{"null table", map[string]interface{}{"tables": map[string]interface{}{"status": nil}}, true},
+		{"wrong type", map[string]interface{}{"tables": []interface{}{"status"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&Policy{}).Configure(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Configure error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Policy{}).Validate(); err != nil {
+		t.Errorf("zero value: %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+package uniquebypolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Policy implements the policy engine interface
+// It checks that the objects in a configured array have unique values for
+// a configured key, e.g. a list of users with unique emails
+type Policy struct {
+	// Field names the input field holding the array of objects
+	Field string `json:"field"`
+
+	// Key names the object field that must be unique across the array
+	Key string `json:"key"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "uniqueby-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	result := policyresult.New(p.Name(), "uniqueness validation")
+	result.Data["field"] = p.Field
+	result.Data["key"] = p.Key
+
+	value, exists := inputMap[p.Field]
+	if p.Field == "" || !exists {
+		result.Status = "PASSED"
+		result.Message = "No array to validate"
+		return result, nil
+	}
+
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("field %q: expected array, got %T", p.Field, value)
+	}
+
+	// Values are compared by their JSON encoding so 1 and "1" stay distinct
+	indices := make(map[string][]int)
+	values := make(map[string]interface{})
+	missing := []int{}
+	for i, item := range items {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("field %q[%d]: expected object, got %T", p.Field, i, item)
+		}
+
+		keyValue, exists := obj[p.Key]
+		if !exists {
+			missing = append(missing, i)
+			continue
+		}
+
+		encoded, err := json.Marshal(keyValue)
+		if err != nil {
+			return nil, fmt.Errorf("field %q[%d].%s: %w", p.Field, i, p.Key, err)
+		}
+		indices[string(encoded)] = append(indices[string(encoded)], i)
+		values[string(encoded)] = keyValue
+	}
+
+	duplicates := []map[string]interface{}{}
+	for encoded, idx := range indices {
+		if len(idx) > 1 {
+			duplicates = append(duplicates, map[string]interface{}{
+				"value":   values[encoded],
+				"indices": idx,
+			})
+		}
+	}
+	sort.Slice(duplicates, func(i, j int) bool {
+		return duplicates[i]["indices"].([]int)[0] < duplicates[j]["indices"].([]int)[0]
+	})
+
+	result.Data["duplicates"] = duplicates
+	result.Data["missing_key"] = missing
+
+	if len(duplicates) > 0 {
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("%d duplicate %s value(s)", len(duplicates), p.Key)
+	} else {
+		result.Status = "PASSED"
+		result.Message = fmt.Sprintf("All %s values are unique", p.Key)
+	}
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	if p.Field != "" && p.Key == "" {
+		return fmt.Errorf("key is required when field is configured")
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package uniquebypolicy
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+// user builds an array element with the given email
+func user(email interface{}) map[string]interface{} {
+	return map[string]interface{}{"email": email}
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name           string
+		users          []interface{}
+		wantDuplicates []map[string]interface{}
+		wantMissing    []int
+	}{
+		{
+			name:           "unique",
+			users:          []interface{}{user("a@x.io"), user("b@x.io"), user("c@x.io")},
+			wantDuplicates: []map[string]interface{}{},
+			wantMissing:    []int{},
+		},
+		{
+			name:           "empty array",
+			users:          []interface{}{},
+			wantDuplicates: []map[string]interface{}{},
+			wantMissing:    []int{},
+		},
+		{
+			name:  "duplicates ordered by first index",
+			users: []interface{}{user("b@x.io"), user("a@x.io"), user("b@x.io"), user("a@x.io"), user("b@x.io")},
+			wantDuplicates: []map[string]interface{}{
+				{"value": "b@x.io", "indices": []int{0, 2, 4}},
+				{"value": "a@x.io", "indices": []int{1, 3}},
+			},
+			wantMissing: []int{},
+		},
+		{
+			name:           "values compared by type",
+			users:          []interface{}{user(1.0), user("1"), user(nil)},
+			wantDuplicates: []map[string]interface{}{},
+			wantMissing:    []int{},
+		},
+		{
+			name:  "structured values",
+			users: []interface{}{user([]interface{}{"a"}), user([]interface{}{"a"})},
+			wantDuplicates: []map[string]interface{}{
+				{"value": []interface{}{"a"}, "indices": []int{0, 1}},
+			},
+			wantMissing: []int{},
+		},
+		{
+			name:           "missing keys are not duplicates",
+			users:          []interface{}{map[string]interface{}{}, user("a@x.io"), map[string]interface{}{"name": "x"}},
+			wantDuplicates: []map[string]interface{}{},
+			wantMissing:    []int{0, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Policy{Field: "users", Key: "email"}
+			got, err := p.Execute(context.Background(), map[string]interface{}{"users": tt.users})
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if !reflect.DeepEqual(result.Data["duplicates"], tt.wantDuplicates) {
+				t.Errorf("duplicates = %v, want %v", result.Data["duplicates"], tt.wantDuplicates)
+			}
+			if !reflect.DeepEqual(result.Data["missing_key"], tt.wantMissing) {
+				t.Errorf("missing_key = %v, want %v", result.Data["missing_key"], tt.wantMissing)
+			}
+			wantStatus := "PASSED"
+			if len(tt.wantDuplicates) > 0 {
+				wantStatus = "FAILED"
+			}
+			if result.Status != wantStatus {
+				t.Errorf("status = %s, want %s", result.Status, wantStatus)
+			}
+		})
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		users interface{}
+	}{
+		{"not an array", "a@x.io"},
+		{"element not an object", []interface{}{user("a@x.io"), "b@x.io"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Policy{Field: "users", Key: "email"}
+			if _, err := p.Execute(context.Background(), map[string]interface{}{"users": tt.users}); err == nil {
+				t.Error("Execute succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestExecuteMissingField(t *testing.T) {
+	p := &Policy{Field: "users", Key: "email"}
+	got, err := p.Execute(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result := got.(*policyresult.Result); result.Status != "PASSED" {
+		t.Errorf("status = %s, want PASSED", result.Status)
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Policy{}).Validate(); err != nil {
+		t.Errorf("zero value: %v", err)
+	}
+	if err := (&Policy{Field: "users"}).Validate(); err == nil {
+		t.Error("field without key passed validation")
+	}
+}
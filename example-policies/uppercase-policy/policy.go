@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"strings"
+
+	"github.com/example/policies/policyresult"
 )
 
 // Policy implements the policy engine interface
@@ -15,39 +17,52 @@ func (p *Policy) Name() string {
 	return "uppercase-policy"
 }
 
+// Description returns a human-readable summary of the policy
+func (p *Policy) Description() string {
+	return "Converts all string values in the input to uppercase"
+}
+
+// Version returns the policy version
+func (p *Policy) Version() string {
+	return "1.0.0"
+}
+
+// Tags returns categories used to group policies
+func (p *Policy) Tags() []string {
+	return []string{"transform", "strings"}
+}
+
+// RequiredEngineVersion returns the engine versions this policy works with
+func (p *Policy) RequiredEngineVersion() string {
+	return "^1.0.0"
+}
+
 // Execute runs the policy logic
 func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	// Convert input to map
 	inputMap, ok := input.(map[string]interface{})
 	if !ok {
 		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
 	}
 
-	result := make(map[string]interface{})
-	result["policy"] = p.Name()
-	result["action"] = "uppercase transformation"
-
-	// Process all string values
-	transformed := make(map[string]interface{})
-	for key, value := range inputMap {
-		switch v := value.(type) {
-		case string:
-			transformed[key] = strings.ToUpper(v)
-		case []string:
-			upper := make([]string, len(v))
-			for i, s := range v {
-				upper[i] = strings.ToUpper(s)
-			}
-			transformed[key] = upper
-		default:
-			transformed[key] = v
-		}
+	// Process all string values, at any depth, stopping if ctx is done
+	t := &transform{ctx: ctx}
+	transformed, err := t.uppercaseMap(inputMap)
+	if err != nil {
+		return nil, err
 	}
 
-	result["input"] = inputMap
-	result["output"] = transformed
-
-	return result, nil
+	return &policyresult.Result{
+		Policy: p.Name(),
+		Action: "uppercase transformation",
+		Output: transformed,
+		Data:   map[string]interface{}{"input": inputMap},
+	}, nil
 }
 
 // Validate checks if the policy configuration is valid
@@ -55,3 +70,74 @@ func (p *Policy) Validate() error {
 	// This simple policy has no configuration to validate
 	return nil
 }
+
+// checkInterval is how many values are transformed between checks for
+// cancellation
+const checkInterval = 1024
+
+// transform uppercases values while periodically checking ctx, so a
+// cancelled or timed-out caller interrupts a large transform
+type transform struct {
+	ctx   context.Context
+	count int
+}
+
+// visit counts one value and returns the context error every
+// checkInterval values
+func (t *transform) visit() error {
+	t.count++
+	if t.count%checkInterval == 0 {
+		return t.ctx.Err()
+	}
+	return nil
+}
+
+// uppercaseMap returns a copy of m with every string uppercased
+func (t *transform) uppercaseMap(m map[string]interface{}) (map[string]interface{}, error) {
+	transformed := make(map[string]interface{}, len(m))
+	for key, value := range m {
+		upper, err := t.uppercaseValue(value)
+		if err != nil {
+			return nil, err
+		}
+		transformed[key] = upper
+	}
+	return transformed, nil
+}
+
+// uppercaseValue uppercases strings and recurses into maps and slices,
+// returning new containers so the input is never modified. Values of any
+// other type are returned unchanged.
+func (t *transform) uppercaseValue(value interface{}) (interface{}, error) {
+	if err := t.visit(); err != nil {
+		return nil, err
+	}
+
+	switch v := value.(type) {
+	case string:
+		return strings.ToUpper(v), nil
+	case []string:
+		upper := make([]string, len(v))
+		for i, s := range v {
+			if err := t.visit(); err != nil {
+				return nil, err
+			}
+			upper[i] = strings.ToUpper(s)
+		}
+		return upper, nil
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			upper, err := t.uppercaseValue(item)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = upper
+		}
+		return items, nil
+	case map[string]interface{}:
+		return t.uppercaseMap(v)
+	default:
+		return v, nil
+	}
+}
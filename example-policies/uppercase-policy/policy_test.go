@@ -0,0 +1,210 @@
+package uppercasepolicy
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name  string
+		input map[string]interface{}
+		want  map[string]interface{}
+	}{
+		{
+			name:  "top-level strings",
+			input: map[string]interface{}{"name": "ada", "tags": []string{"x", "y"}},
+			want:  map[string]interface{}{"name": "ADA", "tags": []string{"X", "Y"}},
+		},
+		{
+			name: "nested maps",
+			input: map[string]interface{}{
+				"user": map[string]interface{}{
+					"name":    "ada",
+					"address": map[string]interface{}{"city": "colombo"},
+				},
+			},
+			want: map[string]interface{}{
+				"user": map[string]interface{}{
+					"name":    "ADA",
+					"address": map[string]interface{}{"city": "COLOMBO"},
+				},
+			},
+		},
+		{
+			name: "slices of interfaces",
+			input: map[string]interface{}{
+				"items": []interface{}{
+					"a",
+					[]interface{}{"b", []string{"c"}},
+					map[string]interface{}{"d": "e"},
+				},
+			},
+			want: map[string]interface{}{
+				"items": []interface{}{
+					"A",
+					[]interface{}{"B", []string{"C"}},
+					map[string]interface{}{"d": "E"},
+				},
+			},
+		},
+		{
+			name: "mixed types",
+			input: map[string]interface{}{
+				"count":  3.0,
+				"active": true,
+				"none":   nil,
+				"mixed":  []interface{}{1.0, "x", false, nil},
+			},
+			want: map[string]interface{}{
+				"count":  3.0,
+				"active": true,
+				"none":   nil,
+				"mixed":  []interface{}{1.0, "X", false, nil},
+			},
+		},
+		{
+			name:  "empty containers",
+			input: map[string]interface{}{"m": map[string]interface{}{}, "s": []interface{}{}},
+			want:  map[string]interface{}{"m": map[string]interface{}{}, "s": []interface{}{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := (&Policy{}).Execute(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if !reflect.DeepEqual(result.Output, tt.want) {
+				t.Errorf("output = %#v, want %#v", result.Output, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteLeavesInputUnchanged(t *testing.T) {
+	nested := map[string]interface{}{"city": "colombo"}
+	list := []interface{}{"a", nested}
+	strs := []string{"x"}
+	input := map[string]interface{}{"user": nested, "list": list, "strs": strs}
+
+	if _, err := (&Policy{}).Execute(context.Background(), input); err != nil {
+		t.Fatal(err)
+	}
+	if nested["city"] != "colombo" || list[0] != "a" || strs[0] != "x" {
+		t.Errorf("input modified: %v", input)
+	}
+}
+
+func TestExecuteRejectsNonObject(t *testing.T) {
+	if _, err := (&Policy{}).Execute(context.Background(), []interface{}{"a"}); err == nil {
+		t.Error("array input succeeded, want error")
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+// cancelAfter is a context that cancels itself on the checks-th call to
+// Err, simulating a caller that cancels while a transform is running
+type cancelAfter struct {
+	context.Context
+	cancel context.CancelFunc
+	checks int
+	calls  int
+}
+
+func newCancelAfter(checks int) *cancelAfter {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &cancelAfter{Context: ctx, cancel: cancel, checks: checks}
+}
+
+func (c *cancelAfter) Err() error {
+	c.calls++
+	if c.calls == c.checks {
+		c.cancel()
+	}
+	return c.Context.Err()
+}
+
+// largeObjects returns n objects, each holding one string
+func largeObjects(n int) []interface{} {
+	items := make([]interface{}, n)
+	for i := range items {
+		items[i] = map[string]interface{}{"name": "item"}
+	}
+	return items
+}
+
+// largeStrings returns n strings
+func largeStrings(n int) []string {
+	tags := make([]string, n)
+	for i := range tags {
+		tags[i] = "tag"
+	}
+	return tags
+}
+
+func TestExecuteCancelledDuringTransform(t *testing.T) {
+	tests := []struct {
+		name  string
+		input map[string]interface{}
+	}{
+		{"nested objects", map[string]interface{}{"items": largeObjects(10 * checkInterval)}},
+		{"string slice", map[string]interface{}{"tags": largeStrings(10 * checkInterval)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The first check happens before the transform starts, so
+			// cancelling on the third interrupts the transform itself
+			ctx := newCancelAfter(3)
+			result, err := (&Policy{}).Execute(ctx, tt.input)
+			if !errors.Is(err, context.Canceled) {
+				t.Fatalf("Execute error = %v, want context.Canceled", err)
+			}
+			if result != nil {
+				t.Errorf("result = %v, want nil", result)
+			}
+			if ctx.calls != 3 {
+				t.Errorf("ctx checked %d times, want the transform to stop at the cancelling check", ctx.calls)
+			}
+		})
+	}
+}
+
+func TestExecuteLargeInput(t *testing.T) {
+	ctx := newCancelAfter(-1)
+	input := map[string]interface{}{"items": largeObjects(5 * checkInterval), "tags": largeStrings(5 * checkInterval)}
+	got, err := (&Policy{}).Execute(ctx, input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := got.(*policyresult.Result).Output
+	if tags := output["tags"].([]string); tags[len(tags)-1] != "TAG" {
+		t.Errorf("last tag = %q, want TAG", tags[len(tags)-1])
+	}
+
+	// A live context is checked during the transform, but not for every
+	// value
+	if ctx.calls < 2 || ctx.calls > checkInterval {
+		t.Errorf("ctx checked %d times, want periodic checks", ctx.calls)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Policy{}).Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+}
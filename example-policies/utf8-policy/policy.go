@@ -0,0 +1,120 @@
+package utf8policy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Policy implements the policy engine interface
+// It checks that every string in the input, at any depth, is valid UTF-8.
+// In repair mode invalid byte sequences are replaced with U+FFFD so corrupt
+// text cannot break downstream JSON encoders.
+type Policy struct {
+	// Repair replaces invalid sequences instead of only reporting them
+	Repair bool `json:"repair"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "utf8-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	result := policyresult.New(p.Name(), "utf-8 validation")
+	result.Data["repair"] = p.Repair
+
+	invalid := []string{}
+	output := p.walk(inputMap, "", &invalid).(map[string]interface{})
+	sort.Strings(invalid)
+
+	result.Data["invalid_fields"] = invalid
+	if p.Repair {
+		result.Output = output
+	}
+
+	switch {
+	case len(invalid) == 0:
+		result.Status = "PASSED"
+		result.Message = "All strings are valid UTF-8"
+	case p.Repair:
+		result.Status = "REPAIRED"
+		result.Message = fmt.Sprintf("Repaired %d field(s) with invalid UTF-8", len(invalid))
+	default:
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("%d field(s) contain invalid UTF-8", len(invalid))
+	}
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	return nil
+}
+
+// walk returns a copy of value with invalid strings repaired, recording the
+// path of every invalid string it finds
+func (p *Policy) walk(value interface{}, path string, invalid *[]string) interface{} {
+	switch v := value.(type) {
+	case string:
+		if utf8.ValidString(v) {
+			return v
+		}
+		*invalid = append(*invalid, path)
+		return strings.ToValidUTF8(v, string(utf8.RuneError))
+	case []string:
+		out := make([]string, len(v))
+		for i, s := range v {
+			out[i] = p.walk(s, fmt.Sprintf("%s[%d]", path, i), invalid).(string)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = p.walk(item, fmt.Sprintf("%s[%d]", path, i), invalid)
+		}
+		return out
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			out[key] = p.walk(item, childPath, invalid)
+		}
+		return out
+	default:
+		return v
+	}
+}
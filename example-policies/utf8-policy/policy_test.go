@@ -0,0 +1,129 @@
+package utf8policy
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       map[string]interface{}
+		wantInvalid []string
+		wantRepair  map[string]interface{}
+	}{
+		{
+			name: "valid strings",
+			input: map[string]interface{}{
+				"ascii":  "hello",
+				"multi":  "héllo wörld 日本 🎉",
+				"list":   []interface{}{"a", 1, "ü"},
+				"nested": map[string]interface{}{"s": "ok"},
+				"count":  3,
+			},
+			wantInvalid: []string{},
+		},
+		{
+			name: "invalid byte sequences",
+			input: map[string]interface{}{
+				"lone continuation": "a\x80b",
+				"truncated":         "caf\xc3",
+				"overlong":          "\xc0\xafx",
+				"surrogate":         "\xed\xa0\x80",
+				"valid":             "fine",
+			},
+			wantInvalid: []string{"lone continuation", "overlong", "surrogate", "truncated"},
+			wantRepair: map[string]interface{}{
+				"lone continuation": "a�b",
+				"truncated":         "caf�",
+				"overlong":          "�x",
+				"surrogate":         "�",
+				"valid":             "fine",
+			},
+		},
+		{
+			name: "nested and slice elements",
+			input: map[string]interface{}{
+				"tags":    []string{"ok", "bad\xff"},
+				"items":   []interface{}{map[string]interface{}{"name": "\xfe"}},
+				"profile": map[string]interface{}{"bio": "x\xffy", "age": 30},
+			},
+			wantInvalid: []string{"items[0].name", "profile.bio", "tags[1]"},
+			wantRepair: map[string]interface{}{
+				"tags":    []string{"ok", "bad�"},
+				"items":   []interface{}{map[string]interface{}{"name": "�"}},
+				"profile": map[string]interface{}{"bio": "x�y", "age": 30},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Report mode
+			got, err := (&Policy{}).Execute(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if !reflect.DeepEqual(result.Data["invalid_fields"], tt.wantInvalid) {
+				t.Errorf("invalid_fields = %q, want %q", result.Data["invalid_fields"], tt.wantInvalid)
+			}
+			wantStatus := "PASSED"
+			if len(tt.wantInvalid) > 0 {
+				wantStatus = "FAILED"
+			}
+			if result.Status != wantStatus {
+				t.Errorf("status = %s, want %s", result.Status, wantStatus)
+			}
+			if result.Output != nil {
+				t.Errorf("output set outside repair mode")
+			}
+
+			// Repair mode
+			got, err = (&Policy{Repair: true}).Execute(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result = got.(*policyresult.Result)
+			wantOutput := tt.wantRepair
+			if wantOutput == nil {
+				wantOutput = tt.input
+			}
+			if !reflect.DeepEqual(result.Output, wantOutput) {
+				t.Errorf("repaired output = %q, want %q", result.Output, wantOutput)
+			}
+			if len(tt.wantInvalid) > 0 && result.Status != "REPAIRED" {
+				t.Errorf("repair status = %s, want REPAIRED", result.Status)
+			}
+		})
+	}
+}
+
+func TestExecuteDoesNotModifyInput(t *testing.T) {
+	tags := []string{"bad\xff"}
+	input := map[string]interface{}{"tags": tags, "name": "\xfe"}
+	if _, err := (&Policy{Repair: true}).Execute(context.Background(), input); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if tags[0] != "bad\xff" || input["name"] != "\xfe" {
+		t.Errorf("input modified: %q", input)
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Policy{}).Validate(); err != nil {
+		t.Errorf("zero value: %v", err)
+	}
+}
@@ -2,32 +2,89 @@ package validatorpolicy
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
+
+	"github.com/example/policies/policyresult"
 )
 
+// defaultRequiredFields are checked when no fields are configured
+var defaultRequiredFields = []string{"message", "data"}
+
 // Policy implements the policy engine interface
 // It validates that required fields are present in the input
-type Policy struct{}
+type Policy struct {
+	// RequiredFields lists the fields that must be present; when nil the
+	// policy checks "message" and "data"
+	RequiredFields []string `json:"required_fields"`
+
+	// FieldTypes maps fields to their expected type: string, number, bool,
+	// array or object. Fields are only type-checked when present.
+	FieldTypes map[string]string `json:"field_types"`
+}
+
+// NewPolicy creates a validator that requires the given fields
+func NewPolicy(requiredFields ...string) (*Policy, error) {
+	p := &Policy{RequiredFields: append([]string{}, requiredFields...)}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
 
 // Name returns the unique identifier for this policy
 func (p *Policy) Name() string {
 	return "validator-policy"
 }
 
+// Description returns a human-readable summary of the policy
+func (p *Policy) Description() string {
+	return "Validates that required fields are present in the input"
+}
+
+// Version returns the policy version
+func (p *Policy) Version() string {
+	return "1.0.0"
+}
+
+// Tags returns categories used to group policies
+func (p *Policy) Tags() []string {
+	return []string{"validation"}
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
 // Execute runs the policy logic
 func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	// Convert input to map
 	inputMap, ok := input.(map[string]interface{})
 	if !ok {
 		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
 	}
 
-	result := make(map[string]interface{})
-	result["policy"] = p.Name()
-	result["action"] = "field validation"
+	result := &policyresult.Result{
+		Policy: p.Name(),
+		Action: "field validation",
+		Data:   make(map[string]interface{}),
+	}
 
-	// Define required fields
-	requiredFields := []string{"message", "data"}
+	requiredFields := p.requiredFields()
 
 	// Validate presence of required fields
 	missingFields := []string{}
@@ -41,16 +98,48 @@ func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, e
 		}
 	}
 
-	result["required_fields"] = requiredFields
-	result["valid_fields"] = validFields
-	result["missing_fields"] = missingFields
+	// Check the type of every typed field that is present
+	typeErrors := []map[string]interface{}{}
+	typedFields := make([]string, 0, len(p.FieldTypes))
+	for field := range p.FieldTypes {
+		typedFields = append(typedFields, field)
+	}
+	sort.Strings(typedFields)
+
+	for _, field := range typedFields {
+		value, exists := inputMap[field]
+		if !exists {
+			continue
+		}
+		if actual := typeOf(value); actual != p.FieldTypes[field] {
+			typeErrors = append(typeErrors, map[string]interface{}{
+				"field":    field,
+				"expected": p.FieldTypes[field],
+				"actual":   actual,
+			})
+		}
+	}
+
+	result.Data["required_fields"] = requiredFields
+	result.Data["valid_fields"] = validFields
+	result.Data["missing_fields"] = missingFields
+	if len(p.FieldTypes) > 0 {
+		result.Data["type_errors"] = typeErrors
+	}
 
-	if len(missingFields) > 0 {
-		result["status"] = "FAILED"
-		result["message"] = fmt.Sprintf("Missing required fields: %v", missingFields)
-	} else {
-		result["status"] = "PASSED"
-		result["message"] = "All required fields present"
+	switch {
+	case len(missingFields) > 0 && len(typeErrors) > 0:
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("Missing required fields: %v; type mismatches in %d field(s)", missingFields, len(typeErrors))
+	case len(missingFields) > 0:
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("Missing required fields: %v", missingFields)
+	case len(typeErrors) > 0:
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("Type mismatches in %d field(s)", len(typeErrors))
+	default:
+		result.Status = "PASSED"
+		result.Message = "All required fields present"
 	}
 
 	return result, nil
@@ -58,6 +147,47 @@ func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, e
 
 // Validate checks if the policy configuration is valid
 func (p *Policy) Validate() error {
-	// This simple policy has no configuration to validate
+	if p.RequiredFields != nil && len(p.RequiredFields) == 0 {
+		return fmt.Errorf("required_fields must list at least one field")
+	}
+	for _, field := range p.RequiredFields {
+		if field == "" {
+			return fmt.Errorf("required_fields must not contain an empty name")
+		}
+	}
+	for field, expected := range p.FieldTypes {
+		switch expected {
+		case "string", "number", "bool", "array", "object":
+		default:
+			return fmt.Errorf("field %q: unsupported type %q, expected string, number, bool, array or object", field, expected)
+		}
+	}
 	return nil
 }
+
+func (p *Policy) requiredFields() []string {
+	if p.RequiredFields == nil {
+		return defaultRequiredFields
+	}
+	return p.RequiredFields
+}
+
+// typeOf returns the JSON type name of a decoded value
+func typeOf(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case float64, float32, int, int32, int64, uint, uint32, uint64, json.Number:
+		return "number"
+	case bool:
+		return "bool"
+	case []interface{}, []string:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
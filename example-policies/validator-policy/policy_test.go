@@ -0,0 +1,250 @@
+package validatorpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+func TestExecuteRequiredFields(t *testing.T) {
+	tests := []struct {
+		name        string
+		required    []string
+		input       map[string]interface{}
+		wantStatus  string
+		wantMissing []string
+		wantValid   []string
+	}{
+		{
+			name:        "default fields present",
+			input:       map[string]interface{}{"message": "hi", "data": nil},
+			wantStatus:  "PASSED",
+			wantMissing: []string{},
+			wantValid:   []string{"message", "data"},
+		},
+		{
+			name:        "default fields missing",
+			input:       map[string]interface{}{"message": "hi"},
+			wantStatus:  "FAILED",
+			wantMissing: []string{"data"},
+			wantValid:   []string{"message"},
+		},
+		{
+			name:        "custom fields present",
+			required:    []string{"id", "email"},
+			input:       map[string]interface{}{"id": 1.0, "email": "a@x"},
+			wantStatus:  "PASSED",
+			wantMissing: []string{},
+			wantValid:   []string{"id", "email"},
+		},
+		{
+			name:        "custom fields ignore the defaults",
+			required:    []string{"id"},
+			input:       map[string]interface{}{"id": 1.0},
+			wantStatus:  "PASSED",
+			wantMissing: []string{},
+			wantValid:   []string{"id"},
+		},
+		{
+			name:        "custom fields missing",
+			required:    []string{"id", "email", "phone"},
+			input:       map[string]interface{}{"email": "a@x", "message": "hi", "data": 1.0},
+			wantStatus:  "FAILED",
+			wantMissing: []string{"id", "phone"},
+			wantValid:   []string{"email"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Policy{RequiredFields: tt.required}
+			got, err := p.Execute(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if result.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", result.Status, tt.wantStatus)
+			}
+			if !reflect.DeepEqual(result.Data["missing_fields"], tt.wantMissing) {
+				t.Errorf("missing_fields = %v, want %v", result.Data["missing_fields"], tt.wantMissing)
+			}
+			if !reflect.DeepEqual(result.Data["valid_fields"], tt.wantValid) {
+				t.Errorf("valid_fields = %v, want %v", result.Data["valid_fields"], tt.wantValid)
+			}
+		})
+	}
+}
+
+func TestExecuteFieldTypes(t *testing.T) {
+	types := map[string]string{"message": "string", "data": "array", "count": "number", "ok": "bool", "meta": "object"}
+
+	tests := []struct {
+		name           string
+		input          map[string]interface{}
+		wantStatus     string
+		wantMessage    string
+		wantTypeErrors []map[string]interface{}
+	}{
+		{
+			name: "correct types",
+			input: map[string]interface{}{
+				"message": "hi",
+				"data":    []interface{}{1.0},
+				"count":   3.0,
+				"ok":      true,
+				"meta":    map[string]interface{}{},
+			},
+			wantStatus:     "PASSED",
+			wantMessage:    "All required fields present",
+			wantTypeErrors: []map[string]interface{}{},
+		},
+		{
+			name:        "wrong types",
+			input:       map[string]interface{}{"message": 42.0, "data": "list", "count": nil},
+			wantStatus:  "FAILED",
+			wantMessage: "Type mismatches in 3 field(s)",
+			wantTypeErrors: []map[string]interface{}{
+				{"field": "count", "expected": "number", "actual": "null"},
+				{"field": "data", "expected": "array", "actual": "string"},
+				{"field": "message", "expected": "string", "actual": "number"},
+			},
+		},
+		{
+			name:        "missing fields and wrong types",
+			input:       map[string]interface{}{"message": []interface{}{}},
+			wantStatus:  "FAILED",
+			wantMessage: "Missing required fields: [data]; type mismatches in 1 field(s)",
+			wantTypeErrors: []map[string]interface{}{
+				{"field": "message", "expected": "string", "actual": "array"},
+			},
+		},
+		{
+			name:           "missing fields only",
+			input:          map[string]interface{}{"message": "hi"},
+			wantStatus:     "FAILED",
+			wantMessage:    "Missing required fields: [data]",
+			wantTypeErrors: []map[string]interface{}{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Policy{FieldTypes: types}
+			got, err := p.Execute(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if result.Status != tt.wantStatus || result.Message != tt.wantMessage {
+				t.Errorf("status = %s, message = %q; want %s, %q", result.Status, result.Message, tt.wantStatus, tt.wantMessage)
+			}
+			if !reflect.DeepEqual(result.Data["type_errors"], tt.wantTypeErrors) {
+				t.Errorf("type_errors = %v, want %v", result.Data["type_errors"], tt.wantTypeErrors)
+			}
+		})
+	}
+}
+
+func TestExecuteWithoutTypes(t *testing.T) {
+	got, err := (&Policy{}).Execute(context.Background(), map[string]interface{}{"message": 1.0, "data": "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := got.(*policyresult.Result)
+	if result.Status != "PASSED" {
+		t.Errorf("status = %s, want PASSED when no types are configured", result.Status)
+	}
+	if _, ok := result.Data["type_errors"]; ok {
+		t.Error("type_errors reported without configured types")
+	}
+}
+
+func TestTypeOf(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  string
+	}{
+		{nil, "null"},
+		{"s", "string"},
+		{1.5, "number"},
+		{7, "number"},
+		{json.Number("1"), "number"},
+		{false, "bool"},
+		{[]interface{}{}, "array"},
+		{[]string{}, "array"},
+		{map[string]interface{}{}, "object"},
+		{struct{}{}, "struct {}"},
+	}
+
+	for _, tt := range tests {
+		if got := typeOf(tt.value); got != tt.want {
+			t.Errorf("typeOf(%#v) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestConfigureRequiredFields(t *testing.T) {
+	p := &Policy{}
+	if err := p.Configure(map[string]interface{}{"required_fields": []string{"order_id"}}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := p.Execute(context.Background(), map[string]interface{}{"message": "hi", "data": 1.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := got.(*policyresult.Result); result.Status != "FAILED" || result.Message != "Missing required fields: [order_id]" {
+		t.Errorf("status = %s, message = %q; want order_id reported missing", result.Status, result.Message)
+	}
+}
+
+func TestNewPolicy(t *testing.T) {
+	p, err := NewPolicy("id", "email")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(p.RequiredFields, []string{"id", "email"}) {
+		t.Errorf("RequiredFields = %v, want [id email]", p.RequiredFields)
+	}
+	if _, err := NewPolicy(); err == nil {
+		t.Error("NewPolicy() with no fields succeeded, want error")
+	}
+	if _, err := NewPolicy("id", ""); err == nil {
+		t.Error("NewPolicy with an empty name succeeded, want error")
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr bool
+	}{
+		{"zero value", map[string]interface{}{}, false},
+		{"custom fields", map[string]interface{}{"required_fields": []string{"id"}}, false},
+		{"empty field list", map[string]interface{}{"required_fields": []string{}}, true},
+		{"empty field name", map[string]interface{}{"required_fields": []string{""}}, true},
+		{"field types", map[string]interface{}{"field_types": map[string]string{"a": "string", "b": "object"}}, false},
+		{"unknown type", map[string]interface{}{"field_types": map[string]string{"a": "integer"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := (&Policy{}).Configure(tt.config); (err != nil) != tt.wantErr {
+				t.Errorf("Configure(%v) = %v, wantErr %v", tt.config, err, tt.wantErr)
+			}
+		})
+	}
+}
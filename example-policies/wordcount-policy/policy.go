@@ -0,0 +1,127 @@
+package wordcountpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/example/policies/policyresult"
+)
+
+// Policy implements the policy engine interface
+// It counts the words in configured string fields and checks the counts
+// against a minimum and maximum, as used for bio or description length
+// limits. Words are runs of non-whitespace characters separated by Unicode
+// whitespace, so multibyte text is counted the same way as ASCII.
+type Policy struct {
+	// Fields lists the input fields to count; missing fields are skipped
+	Fields []string `json:"fields"`
+
+	// MinWords is the minimum number of words; zero means no minimum
+	MinWords int `json:"min_words"`
+
+	// MaxWords is the maximum number of words; zero means no maximum
+	MaxWords int `json:"max_words"`
+}
+
+// Name returns the unique identifier for this policy
+func (p *Policy) Name() string {
+	return "wordcount-policy"
+}
+
+// Configure applies the given configuration to the policy
+func (p *Policy) Configure(config map[string]interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return p.Validate()
+}
+
+// Execute runs the policy logic
+func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert input to map
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
+	}
+
+	counts := make(map[string]interface{}, len(p.Fields))
+	violations := []map[string]interface{}{}
+	for _, field := range p.Fields {
+		value, exists := inputMap[field]
+		if !exists {
+			continue
+		}
+
+		text, ok := value.(string)
+		if !ok {
+			violations = append(violations, map[string]interface{}{
+				"field":  field,
+				"reason": fmt.Sprintf("expected string, got %T", value),
+			})
+			continue
+		}
+
+		count := len(strings.Fields(text))
+		counts[field] = count
+
+		if reason := p.check(count); reason != "" {
+			violations = append(violations, map[string]interface{}{
+				"field":  field,
+				"count":  count,
+				"reason": reason,
+			})
+		}
+	}
+
+	result := policyresult.New(p.Name(), "word count validation")
+	result.Data["counts"] = counts
+	result.Data["violations"] = violations
+
+	if len(violations) > 0 {
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("%d field(s) outside the word limits", len(violations))
+	} else {
+		result.Status = "PASSED"
+		result.Message = "All fields within the word limits"
+	}
+
+	return result, nil
+}
+
+// Validate checks if the policy configuration is valid
+func (p *Policy) Validate() error {
+	if p.MinWords < 0 || p.MaxWords < 0 {
+		return fmt.Errorf("word limits must not be negative")
+	}
+	if p.MaxWords > 0 && p.MinWords > p.MaxWords {
+		return fmt.Errorf("min_words %d exceeds max_words %d", p.MinWords, p.MaxWords)
+	}
+	for i, field := range p.Fields {
+		if field == "" {
+			return fmt.Errorf("field %d: name is required", i)
+		}
+	}
+	return nil
+}
+
+// check returns why count breaks the limits, or "" if it is within them
+func (p *Policy) check(count int) string {
+	if p.MinWords > 0 && count < p.MinWords {
+		return fmt.Sprintf("has %d word(s), minimum is %d", count, p.MinWords)
+	}
+	if p.MaxWords > 0 && count > p.MaxWords {
+		return fmt.Sprintf("has %d word(s), maximum is %d", count, p.MaxWords)
+	}
+	return ""
+}
@@ -0,0 +1,102 @@
+package wordcountpolicy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name       string
+		bio        interface{}
+		wantCount  interface{}
+		wantReason string
+	}{
+		{name: "within range", bio: "Go developer from Colombo", wantCount: 4},
+		{name: "extra whitespace", bio: "  Go\tdeveloper\n\nfrom   Colombo ", wantCount: 4},
+		{name: "multibyte words", bio: "ශ්‍රී ලංකාවේ සංවර්ධකයෙක්", wantCount: 3},
+		{name: "unicode whitespace", bio: "東京　大阪 名古屋", wantCount: 3},
+		{name: "too few", bio: "Developer", wantCount: 1, wantReason: "has 1 word(s), minimum is 2"},
+		{name: "empty", bio: "   ", wantCount: 0, wantReason: "has 0 word(s), minimum is 2"},
+		{name: "too many", bio: "one two three four five six", wantCount: 6, wantReason: "has 6 word(s), maximum is 5"},
+		{name: "too many multibyte", bio: "один два три четыре пять шесть", wantCount: 6, wantReason: "has 6 word(s), maximum is 5"},
+		{name: "not a string", bio: 42.0, wantReason: "expected string, got float64"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Policy{Fields: []string{"bio"}, MinWords: 2, MaxWords: 5}
+			got, err := p.Execute(context.Background(), map[string]interface{}{"bio": tt.bio})
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			result := got.(*policyresult.Result)
+			if count := result.Data["counts"].(map[string]interface{})["bio"]; count != tt.wantCount {
+				t.Errorf("count = %v, want %v", count, tt.wantCount)
+			}
+			violations := result.Data["violations"].([]map[string]interface{})
+
+			if tt.wantReason == "" {
+				if result.Status != "PASSED" || len(violations) != 0 {
+					t.Errorf("status = %s, violations = %v; want PASSED", result.Status, violations)
+				}
+				return
+			}
+			if result.Status != "FAILED" || len(violations) != 1 {
+				t.Fatalf("status = %s, violations = %v; want one violation", result.Status, violations)
+			}
+			if violations[0]["reason"] != tt.wantReason {
+				t.Errorf("reason = %v, want %q", violations[0]["reason"], tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestExecuteWithoutLimits(t *testing.T) {
+	p := &Policy{Fields: []string{"bio", "missing"}}
+	got, err := p.Execute(context.Background(), map[string]interface{}{"bio": ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := got.(*policyresult.Result)
+	if result.Status != "PASSED" {
+		t.Errorf("status = %s, want PASSED without limits", result.Status)
+	}
+	if counts := result.Data["counts"].(map[string]interface{}); len(counts) != 1 {
+		t.Errorf("counts = %v, want only bio", counts)
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr bool
+	}{
+		{"zero value", map[string]interface{}{}, false},
+		{"limits", map[string]interface{}{"fields": []string{"bio"}, "min_words": 1, "max_words": 50}, false},
+		{"minimum only", map[string]interface{}{"min_words": 10}, false},
+		{"negative limit", map[string]interface{}{"max_words": -1}, true},
+		{"min above max", map[string]interface{}{"min_words": 10, "max_words": 5}, true},
+		{"empty field name", map[string]interface{}{"fields": []string{""}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := (&Policy{}).Configure(tt.config); (err != nil) != tt.wantErr {
+				t.Errorf("Configure(%v) = %v, wantErr %v", tt.config, err, tt.wantErr)
+			}
+		})
+	}
+}
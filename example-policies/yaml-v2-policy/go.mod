@@ -2,4 +2,9 @@ module github.com/example/policies/yaml-v2-policy
 
 go 1.21
 
-require gopkg.in/yaml.v3 v3.0.0
+require (
+	github.com/example/policies/policyresult v0.0.0
+	gopkg.in/yaml.v3 v3.0.0
+)
+
+replace github.com/example/policies/policyresult => ../policyresult
@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/example/policies/policyresult"
 )
 
 // Policy implements the policy engine interface using YAML v2
@@ -15,19 +17,34 @@ func (p *Policy) Name() string {
 	return "yaml-v2-policy"
 }
 
+// Description returns a human-readable summary of the policy
+func (p *Policy) Description() string {
+	return "Round-trips the input through YAML using gopkg.in/yaml.v2"
+}
+
+// Version returns the policy version
+func (p *Policy) Version() string {
+	return "1.0.0"
+}
+
+// Tags returns categories used to group policies
+func (p *Policy) Tags() []string {
+	return []string{"yaml", "serialization"}
+}
+
 // Execute runs the policy logic using yaml.v2
 func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, error) {
+	// Stop early if the caller has already cancelled or timed out
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	// Convert input to map
 	inputMap, ok := input.(map[string]interface{})
 	if !ok {
 		return nil, fmt.Errorf("expected map[string]interface{}, got %T", input)
 	}
 
-	result := make(map[string]interface{})
-	result["policy"] = p.Name()
-	result["action"] = "yaml v2 parsing"
-	result["library"] = "gopkg.in/yaml.v2"
-
 	// Marshal and unmarshal using yaml.v2 to demonstrate library usage
 	yamlData, err := yaml.Marshal(inputMap)
 	if err != nil {
@@ -40,11 +57,16 @@ func (p *Policy) Execute(ctx context.Context, input interface{}) (interface{}, e
 		return nil, fmt.Errorf("failed to unmarshal with yaml.v2: %w", err)
 	}
 
-	result["input"] = inputMap
-	result["yaml_output"] = string(yamlData)
-	result["parsed"] = parsed
-
-	return result, nil
+	return &policyresult.Result{
+		Policy: p.Name(),
+		Action: "yaml v2 parsing",
+		Data: map[string]interface{}{
+			"library":     "gopkg.in/yaml.v2",
+			"input":       inputMap,
+			"yaml_output": string(yamlData),
+			"parsed":      parsed,
+		},
+	}, nil
 }
 
 // Validate checks if the policy configuration is valid
@@ -0,0 +1,77 @@
+package yamlv2policy
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/example/policies/policyresult"
+)
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      map[string]interface{}
+		wantYAML   string
+		wantParsed map[string]interface{}
+	}{
+		{
+			name:       "flat",
+			input:      map[string]interface{}{"name": "ada", "age": 36},
+			wantYAML:   "age: 36\nname: ada\n",
+			wantParsed: map[string]interface{}{"name": "ada", "age": 36},
+		},
+		{
+			name:       "nested",
+			input:      map[string]interface{}{"user": map[string]interface{}{"tags": []interface{}{"a", "b"}}, "ok": true},
+			wantYAML:   "ok: true\nuser:\n    tags:\n        - a\n        - b\n",
+			wantParsed: map[string]interface{}{"user": map[string]interface{}{"tags": []interface{}{"a", "b"}}, "ok": true},
+		},
+		{
+			name:       "empty",
+			input:      map[string]interface{}{},
+			wantYAML:   "{}\n",
+			wantParsed: map[string]interface{}{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := (&Policy{}).Execute(context.Background(), tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result := got.(*policyresult.Result)
+			if result.Policy != "yaml-v2-policy" || result.Data["library"] != "gopkg.in/yaml.v2" {
+				t.Errorf("policy, library = %s, %v", result.Policy, result.Data["library"])
+			}
+			if result.Data["yaml_output"] != tt.wantYAML {
+				t.Errorf("yaml_output = %q, want %q", result.Data["yaml_output"], tt.wantYAML)
+			}
+			if parsed := result.Data["parsed"]; !reflect.DeepEqual(parsed, tt.wantParsed) {
+				t.Errorf("parsed = %#v, want %#v", parsed, tt.wantParsed)
+			}
+		})
+	}
+}
+
+func TestExecuteRejectsNonObject(t *testing.T) {
+	if _, err := (&Policy{}).Execute(context.Background(), []interface{}{"a"}); err == nil {
+		t.Error("array input succeeded, want error")
+	}
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Policy{}).Execute(ctx, map[string]interface{}{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Policy{}).Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+}